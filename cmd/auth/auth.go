@@ -1,21 +1,47 @@
 package auth
 
 import (
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/oauth"
 	"github.com/spf13/cobra"
 )
 
+// Flags shared by the OAuth logins
+var (
+	callbackPort int
+	noBrowser    bool
+	testOnly     bool
+)
+
+// authorizeTimeout is how long a login waits for the browser redirect
+const authorizeTimeout = 5 * time.Minute
+
 // AuthCmd represents the auth command group
 var AuthCmd = &cobra.Command{
 	Use:   "auth",
-	Short: "Test API authentication and connections",
-	Long: `Test authentication and connectivity for various API integrations:
+	Short: "Authorize and test API connections",
+	Long: `Authorize Gagipress with the social platforms and test API connections:
   - OpenAI API
-  - Instagram Graph API (requires OAuth)
-  - TikTok API (requires OAuth)`,
+  - Instagram Graph API (OAuth login)
+  - TikTok API (OAuth login)
+  - Bluesky (app password)
+
+The OAuth logins open the platform's consent page in a browser and catch the
+redirect on http://localhost:8585/callback, which has to be registered as a
+redirect URI of your app. Tokens are saved to ~/.gagipress/config.yaml and
+refreshed automatically before they expire.`,
 }
 
 func init() {
+	for _, cmd := range []*cobra.Command{instagramCmd, tiktokCmd} {
+		cmd.Flags().IntVar(&callbackPort, "port", oauth.DefaultPort, "Localhost port for the OAuth redirect")
+		cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Print the authorization URL instead of opening a browser")
+		cmd.Flags().BoolVar(&testOnly, "test", false, "Only test the saved token, without logging in")
+	}
+
 	AuthCmd.AddCommand(openaiCmd)
 	AuthCmd.AddCommand(instagramCmd)
 	AuthCmd.AddCommand(tiktokCmd)
+	AuthCmd.AddCommand(blueskyCmd)
 }
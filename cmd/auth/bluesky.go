@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/social"
+	"github.com/spf13/cobra"
+)
+
+var (
+	blueskyHandle      string
+	blueskyAppPassword string
+	blueskyPDS         string
+)
+
+var blueskyCmd = &cobra.Command{
+	Use:   "bluesky",
+	Short: "Save a Bluesky app password and test the connection",
+	Long: `Log in to Bluesky with an app password and test the connection.
+
+Create an app password in the Bluesky app under Settings > Privacy and
+security > App passwords. The handle and password given with the flags are
+saved to the config (bluesky.handle and bluesky.app_password); without
+flags, the saved ones are tested.
+
+Accounts on a self-hosted PDS need --pds.`,
+	RunE: runBlueskyAuth,
+}
+
+func init() {
+	blueskyCmd.Flags().StringVar(&blueskyHandle, "handle", "", "Bluesky handle, e.g. gagipress.bsky.social (saved to the config)")
+	blueskyCmd.Flags().StringVar(&blueskyAppPassword, "app-password", "", "Bluesky app password (saved to the config)")
+	blueskyCmd.Flags().StringVar(&blueskyPDS, "pds", "", "PDS URL, if not "+social.BlueskyPDSURL+" (saved to the config)")
+}
+
+func runBlueskyAuth(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	changed := blueskyHandle != "" || blueskyAppPassword != "" || blueskyPDS != ""
+	if blueskyHandle != "" {
+		cfg.Bluesky.Handle = blueskyHandle
+	}
+	if blueskyAppPassword != "" {
+		cfg.Bluesky.AppPassword = blueskyAppPassword
+	}
+	if blueskyPDS != "" {
+		cfg.Bluesky.PDSURL = blueskyPDS
+	}
+	if cfg.Bluesky.Handle == "" || cfg.Bluesky.AppPassword == "" {
+		return fmt.Errorf("bluesky handle and app password are not configured. Pass --handle and --app-password or set them in ~/.gagipress/config.yaml")
+	}
+
+	fmt.Println("🦋 Testing Bluesky connection...")
+
+	// Test connection
+	fmt.Print("   Logging in... ")
+	session, err := social.NewBlueskyClient(&cfg.Bluesky).TestConnection(ctx)
+	if err != nil {
+		fmt.Println("❌ FAILED")
+		fmt.Println("\n⚠️  Check your Bluesky settings:")
+		fmt.Println("   1. Use your full handle, e.g. name.bsky.social")
+		fmt.Println("   2. Use an app password, not a revoked one")
+		fmt.Println("   3. Set --pds if the account is not hosted on bsky.social")
+		return err
+	}
+	fmt.Println("✅ OK")
+	fmt.Printf("   Logged in as @%s (%s)\n", session.Handle, session.DID)
+
+	if changed {
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Println("   Saved to ~/.gagipress/config.yaml")
+	}
+
+	fmt.Println("\n✅ Bluesky is configured correctly!")
+	fmt.Println("   Publish with it by setting publishing.platforms.bluesky: bluesky")
+
+	return nil
+}
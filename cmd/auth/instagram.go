@@ -1,42 +1,96 @@
 package auth
 
 import (
+	"context"
 	"fmt"
+	"os"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/oauth"
 	"github.com/gagipress/gagipress-cli/internal/social"
 	"github.com/spf13/cobra"
 )
 
+var (
+	instagramAppID     string
+	instagramAppSecret string
+)
+
 var instagramCmd = &cobra.Command{
 	Use:   "instagram",
-	Short: "Test Instagram API connection",
-	Long:  `Test Instagram Graph API authentication and connection.`,
-	RunE:  runInstagramAuth,
+	Short: "Log in to Instagram and test the connection",
+	Long: `Log in to the Instagram Graph API with Facebook Login and test the connection.
+
+Needs a Meta app (instagram.app_id and instagram.app_secret, or the flags
+below) with http://localhost:8585/callback as a valid OAuth redirect URI.
+The short-lived token from the login is exchanged for a long-lived one
+(60 days), which is refreshed automatically in its last week. When
+instagram.account_id is empty, the Instagram professional account linked to
+your first Facebook Page is used.
+
+Use --test to only check the saved token.`,
+	RunE: runInstagramAuth,
+}
+
+func init() {
+	instagramCmd.Flags().StringVar(&instagramAppID, "app-id", "", "Meta app ID (saved to the config)")
+	instagramCmd.Flags().StringVar(&instagramAppSecret, "app-secret", "", "Meta app secret (saved to the config)")
 }
 
 func runInstagramAuth(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
-
-	fmt.Println("📸 Testing Instagram API connection...")
+	if instagramAppID != "" {
+		cfg.Instagram.AppID = instagramAppID
+	}
+	if instagramAppSecret != "" {
+		cfg.Instagram.AppSecret = instagramAppSecret
+	}
 
 	// Create Instagram client
 	client := social.NewInstagramClient(&cfg.Instagram)
+	client.OnTokenRefresh = func() error { return config.Save(cfg) }
+
+	if !testOnly {
+		if cfg.Instagram.AppID == "" || cfg.Instagram.AppSecret == "" {
+			return fmt.Errorf("instagram app ID and secret are not configured. Pass --app-id and --app-secret or set them in ~/.gagipress/config.yaml")
+		}
+
+		fmt.Println("📸 Logging in to Instagram...")
+		authCtx, cancel := context.WithTimeout(ctx, authorizeTimeout)
+		defer cancel()
+		grant, err := oauth.Authorize(authCtx, client.OAuthProvider(), callbackPort, !noBrowser, os.Stdout)
+		if err != nil {
+			return fmt.Errorf("instagram authorization failed: %w", err)
+		}
+		if err := client.ExchangeCode(ctx, grant); err != nil {
+			return err
+		}
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✅ Logged in to account %s\n", cfg.Instagram.AccountID)
+		if !cfg.Instagram.TokenExpiresAt.IsZero() {
+			fmt.Printf("   Token expires %s and is refreshed automatically\n", cfg.Instagram.TokenExpiresAt.Local().Format("2006-01-02 15:04"))
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("📸 Testing Instagram API connection...")
 
 	// Test connection
 	fmt.Print("   Testing connection... ")
-	if err := client.TestConnection(); err != nil {
+	if err := client.TestConnection(ctx); err != nil {
 		fmt.Println("❌ FAILED")
-		fmt.Println("\n⚠️  Instagram OAuth flow not yet implemented.")
-		fmt.Println("   This will be available in Week 3 of implementation.")
-		fmt.Println("   Required steps:")
-		fmt.Println("   1. Create Facebook App")
-		fmt.Println("   2. Connect Instagram Business Account")
-		fmt.Println("   3. Complete OAuth flow")
+		fmt.Println("\n⚠️  Check your Instagram Graph API settings:")
+		fmt.Println("   1. Connect an Instagram professional account to a Facebook Page")
+		fmt.Println("   2. Add http://localhost:8585/callback as a redirect URI of your Meta app")
+		fmt.Println("   3. Log in again with 'gagipress auth instagram'")
 		return err
 	}
 
@@ -16,6 +16,8 @@ var openaiCmd = &cobra.Command{
 }
 
 func runOpenAIAuth(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -35,7 +37,7 @@ func runOpenAIAuth(cmd *cobra.Command, args []string) error {
 
 	// Test connection
 	fmt.Print("   Sending test request... ")
-	if err := client.TestConnection(); err != nil {
+	if err := client.TestConnection(ctx); err != nil {
 		fmt.Println("❌ FAILED")
 		return fmt.Errorf("OpenAI connection test failed: %w", err)
 	}
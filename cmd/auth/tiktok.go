@@ -1,42 +1,97 @@
 package auth
 
 import (
+	"context"
 	"fmt"
+	"os"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/oauth"
 	"github.com/gagipress/gagipress-cli/internal/social"
 	"github.com/spf13/cobra"
 )
 
+var (
+	tiktokClientKey    string
+	tiktokClientSecret string
+)
+
 var tiktokCmd = &cobra.Command{
 	Use:   "tiktok",
-	Short: "Test TikTok API connection",
-	Long:  `Test TikTok API authentication and connection.`,
-	RunE:  runTikTokAuth,
+	Short: "Log in to TikTok and test the connection",
+	Long: `Log in to TikTok with Login Kit and test the connection.
+
+Needs a TikTok app (tiktok.client_key and tiktok.client_secret, or the flags
+below) with the Content Posting API and Display API products and
+http://localhost:8585/callback as a redirect URI. The login asks for the
+user.info.basic, video.publish, video.upload and video.list scopes.
+
+Access tokens last 24 hours and are refreshed automatically with the refresh
+token, which lasts a year; after that, log in again.
+
+Use --test to only check the saved token.`,
+	RunE: runTikTokAuth,
+}
+
+func init() {
+	tiktokCmd.Flags().StringVar(&tiktokClientKey, "client-key", "", "TikTok app client key (saved to the config)")
+	tiktokCmd.Flags().StringVar(&tiktokClientSecret, "client-secret", "", "TikTok app client secret (saved to the config)")
 }
 
 func runTikTokAuth(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
-
-	fmt.Println("🎵 Testing TikTok API connection...")
+	if tiktokClientKey != "" {
+		cfg.TikTok.ClientKey = tiktokClientKey
+	}
+	if tiktokClientSecret != "" {
+		cfg.TikTok.ClientSecret = tiktokClientSecret
+	}
 
 	// Create TikTok client
 	client := social.NewTikTokClient(&cfg.TikTok)
+	client.OnTokenRefresh = func() error { return config.Save(cfg) }
+
+	if !testOnly {
+		if cfg.TikTok.ClientKey == "" || cfg.TikTok.ClientSecret == "" {
+			return fmt.Errorf("tiktok client key and secret are not configured. Pass --client-key and --client-secret or set them in ~/.gagipress/config.yaml")
+		}
+
+		fmt.Println("🎵 Logging in to TikTok...")
+		authCtx, cancel := context.WithTimeout(ctx, authorizeTimeout)
+		defer cancel()
+		grant, err := oauth.Authorize(authCtx, client.OAuthProvider(), callbackPort, !noBrowser, os.Stdout)
+		if err != nil {
+			return fmt.Errorf("tiktok authorization failed: %w", err)
+		}
+		if err := client.ExchangeCode(ctx, grant); err != nil {
+			return err
+		}
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✅ Logged in as %s\n", cfg.TikTok.AccountID)
+		fmt.Printf("   Access token expires %s and is refreshed automatically until %s\n",
+			cfg.TikTok.TokenExpiresAt.Local().Format("2006-01-02 15:04"),
+			cfg.TikTok.RefreshExpiresAt.Local().Format("2006-01-02"))
+		fmt.Println()
+	}
+
+	fmt.Println("🎵 Testing TikTok API connection...")
 
 	// Test connection
 	fmt.Print("   Testing connection... ")
-	if err := client.TestConnection(); err != nil {
+	if err := client.TestConnection(ctx); err != nil {
 		fmt.Println("❌ FAILED")
-		fmt.Println("\n⚠️  TikTok OAuth flow not yet implemented.")
-		fmt.Println("   This will be available in Week 3 of implementation.")
-		fmt.Println("   Required steps:")
-		fmt.Println("   1. Create TikTok Developer Account")
-		fmt.Println("   2. Create App in TikTok Developer Portal")
-		fmt.Println("   3. Complete OAuth flow")
+		fmt.Println("\n⚠️  Check your TikTok API settings:")
+		fmt.Println("   1. Add the Content Posting API and Display API products to your app")
+		fmt.Println("   2. Add http://localhost:8585/callback as a redirect URI")
+		fmt.Println("   3. Log in again with 'gagipress auth tiktok'")
 		return err
 	}
 
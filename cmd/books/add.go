@@ -9,7 +9,7 @@ import (
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +21,8 @@ var addCmd = &cobra.Command{
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -87,8 +89,12 @@ func runAdd(cmd *cobra.Command, args []string) error {
 
 	// Save to database
 	fmt.Println("\n💾 Saving book...")
-	repo := repository.NewBooksRepository(&cfg.Supabase)
-	book, err := repo.Create(input)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	repo := stores.Books
+	book, err := repo.Create(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to save book: %w", err)
 	}
@@ -7,7 +7,7 @@ import (
 	"strings"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +20,8 @@ var deleteCmd = &cobra.Command{
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	bookID := args[0]
 
 	// Load configuration
@@ -28,10 +30,14 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	repo := repository.NewBooksRepository(&cfg.Supabase)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	repo := stores.Books
 
 	// Resolve ID prefix to full book
-	book, err := repo.GetBookByIDPrefix(bookID)
+	book, err := repo.GetBookByIDPrefix(ctx, bookID)
 	if err != nil {
 		return fmt.Errorf("failed to get book: %w", err)
 	}
@@ -55,7 +61,7 @@ func runDelete(cmd *cobra.Command, args []string) error {
 
 	// Delete book
 	fmt.Print("\n🗑️  Deleting book... ")
-	if err := repo.Delete(bookID); err != nil {
+	if err := repo.Delete(ctx, bookID); err != nil {
 		fmt.Println("❌ FAILED")
 		return fmt.Errorf("failed to delete book: %w", err)
 	}
@@ -9,7 +9,7 @@ import (
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -22,6 +22,8 @@ var editCmd = &cobra.Command{
 }
 
 func runEdit(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	bookID := args[0]
 
 	// Load configuration
@@ -30,14 +32,18 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	repo := repository.NewBooksRepository(&cfg.Supabase)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	repo := stores.Books
 
 	// Get existing book
 	fmt.Println("📚 Edit Book")
 	fmt.Println("════════════")
 	fmt.Print("Loading book... ")
 
-	book, err := repo.GetBookByIDPrefix(bookID)
+	book, err := repo.GetBookByIDPrefix(ctx, bookID)
 	if err != nil {
 		fmt.Println("❌ FAILED")
 		return fmt.Errorf("failed to get book: %w", err)
@@ -134,7 +140,7 @@ func runEdit(cmd *cobra.Command, args []string) error {
 
 	// Update in database
 	fmt.Println("\n💾 Updating book...")
-	updatedBook, err := repo.Update(bookID, input)
+	updatedBook, err := repo.Update(ctx, bookID, input)
 	if err != nil {
 		return fmt.Errorf("failed to update book: %w", err)
 	}
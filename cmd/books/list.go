@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -17,6 +17,8 @@ var listCmd = &cobra.Command{
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -24,8 +26,12 @@ func runList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get all books
-	repo := repository.NewBooksRepository(&cfg.Supabase)
-	books, err := repo.GetAll()
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	repo := stores.Books
+	books, err := repo.GetAll(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get books: %w", err)
 	}
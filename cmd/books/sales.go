@@ -8,6 +8,7 @@ import (
 	"github.com/gagipress/gagipress-cli/internal/models"
 	"github.com/gagipress/gagipress-cli/internal/parser"
 	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -25,8 +26,12 @@ var importCmd = &cobra.Command{
 The importer will:
   - Parse the CSV file
   - Match books by ASIN or title
-  - Create daily sales records
-  - Update total sales counts
+  - Sum rows for the same book and day (one per marketplace)
+  - Create or update daily sales records in bulk
+
+Re-importing a report is safe: days already imported with the same
+figures are reported as unchanged, and days whose figures changed are
+updated.
 
 Supports various KDP report formats.`,
 	Args: cobra.ExactArgs(1),
@@ -39,6 +44,8 @@ func init() {
 }
 
 func runImport(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	csvFile := args[0]
 
 	// Load configuration
@@ -69,8 +76,12 @@ func runImport(cmd *cobra.Command, args []string) error {
 	fmt.Printf("✅ Parsed %d rows\n\n", len(rows))
 
 	// Get books from database
-	booksRepo := repository.NewBooksRepository(&cfg.Supabase)
-	books, err := booksRepo.GetAll()
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	booksRepo := stores.Books
+	books, err := booksRepo.GetAll(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get books: %w", err)
 	}
@@ -85,13 +96,11 @@ func runImport(cmd *cobra.Command, args []string) error {
 		booksByTitle[book.Title] = book
 	}
 
-	// Process rows and create sales records
-	salesRepo := repository.NewSalesRepository(&cfg.Supabase)
-	imported := 0
+	// Match rows to books
+	var inputs []*models.BookSaleInput
+	titles := make(map[string]string)
 	skipped := 0
 
-	fmt.Println("💾 Importing sales data...")
-
 	for _, row := range rows {
 		// Find matching book
 		var book *models.Book
@@ -116,7 +125,6 @@ func runImport(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		// Create sale record
 		saleInput := &models.BookSaleInput{
 			BookID:    book.ID,
 			SaleDate:  models.Date{Time: row.OrderDate},
@@ -131,27 +139,45 @@ func runImport(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		_, err := salesRepo.CreateSale(saleInput)
-		if err != nil {
-			// Likely duplicate - skip silently
-			skipped++
-			continue
-		}
+		inputs = append(inputs, saleInput)
+		titles[book.ID] = book.Title
+	}
+
+	// One record per book per day: marketplace rows are summed
+	inputs = models.MergeDailySales(inputs)
 
-		imported++
+	fmt.Printf("💾 Importing %d daily sales records...\n", len(inputs))
+	results, err := stores.Sales.UpsertSales(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("failed to import sales: %w", err)
 	}
 
+	for _, r := range results {
+		if r.Status == repository.RowFailed {
+			in := inputs[r.Index]
+			fmt.Printf("❌ Failed: '%s' on %s: %v\n", titles[in.BookID], in.SaleDate.Format(models.DateFormat), r.Err)
+		}
+	}
+	summary := repository.Summarize(results)
+
 	fmt.Println()
 	fmt.Println("═══════════════════")
 	fmt.Printf("✅ Import Complete!\n")
-	fmt.Printf("   Imported: %d sales\n", imported)
-	fmt.Printf("   Skipped:  %d rows\n\n", skipped)
+	fmt.Printf("   Created:   %d sales\n", summary.Created)
+	fmt.Printf("   Updated:   %d sales\n", summary.Updated)
+	fmt.Printf("   Unchanged: %d sales (already imported)\n", summary.Duplicate)
+	fmt.Printf("   Failed:    %d sales\n", summary.Failed)
+	fmt.Printf("   Skipped:   %d rows\n\n", skipped)
 
+	imported := summary.Created + summary.Updated
 	if imported > 0 {
 		fmt.Println("Next steps:")
 		fmt.Println("  • View sales: gagipress stats show")
 		fmt.Println("  • Analyze correlation: gagipress stats correlate")
 	}
 
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d sales records failed to import", summary.Failed)
+	}
 	return nil
 }
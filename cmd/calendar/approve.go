@@ -8,7 +8,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gagipress/gagipress-cli/internal/config"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -27,6 +27,8 @@ For each pending entry, you can:
 
 
 func runApprove(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -36,8 +38,12 @@ func runApprove(cmd *cobra.Command, args []string) error {
 	fmt.Println(ui.StyleHeader.Render("✅ Calendar Approval"))
 
 	// Get pending entries
-	calendarRepo := repository.NewCalendarRepository(&cfg.Supabase)
-	entries, err := calendarRepo.GetEntries("pending_approval", 0)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	calendarRepo := stores.Calendar
+	entries, err := calendarRepo.GetEntries(ctx, "pending_approval", 0)
 	if err != nil {
 		return fmt.Errorf("failed to get entries: %w", err)
 	}
@@ -95,7 +101,7 @@ func runApprove(cmd *cobra.Command, args []string) error {
 
 		switch action {
 		case "A":
-			if err := calendarRepo.UpdateEntryStatus(entry.ID, "approved"); err != nil {
+			if err := calendarRepo.UpdateEntryStatus(ctx, entry.ID, "approved"); err != nil {
 				fmt.Printf("%s\n\n", ui.StyleError.Render("❌ Failed to approve: "+err.Error()))
 				continue
 			}
@@ -103,7 +109,7 @@ func runApprove(cmd *cobra.Command, args []string) error {
 			approved++
 
 		case "R":
-			if err := calendarRepo.DeleteEntry(entry.ID); err != nil {
+			if err := calendarRepo.DeleteEntry(ctx, entry.ID); err != nil {
 				fmt.Printf("%s\n\n", ui.StyleError.Render("❌ Failed to reject: "+err.Error()))
 				continue
 			}
@@ -10,6 +10,7 @@ var CalendarCmd = &cobra.Command{
 	Short: "Manage content calendar and scheduling",
 	Long: `Manage your content publishing calendar:
   - Plan weekly content schedule
+  - See the best posting times learned from past posts
   - View scheduled posts
   - Approve or modify schedule
   - Force publish immediately`,
@@ -17,6 +18,7 @@ var CalendarCmd = &cobra.Command{
 
 func init() {
 	CalendarCmd.AddCommand(planCmd)
+	CalendarCmd.AddCommand(heatmapCmd)
 	CalendarCmd.AddCommand(showCmd)
 	CalendarCmd.AddCommand(approveCmd)
 	CalendarCmd.AddCommand(statusCmd)
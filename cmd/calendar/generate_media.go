@@ -2,7 +2,6 @@ package calendar
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,7 +9,7 @@ import (
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/spf13/cobra"
 	"google.golang.org/genai"
 )
@@ -71,10 +70,12 @@ var (
 func init() {
 	generateMediaCmd.Flags().IntVar(&generateMediaLimit, "limit", 10, "Maximum number of images to generate")
 	generateMediaCmd.Flags().BoolVar(&generateMediaDryRun, "dry-run", false, "Show what would be generated without doing it")
-	generateMediaCmd.Flags().StringVar(&generateMediaPlatform, "platform", "", "Filter by platform: tiktok or instagram")
+	generateMediaCmd.Flags().StringVar(&generateMediaPlatform, "platform", "", "Filter by platform (tiktok, instagram, youtube, ...)")
 }
 
 func runGenerateMedia(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -90,9 +91,13 @@ func runGenerateMedia(cmd *cobra.Command, args []string) error {
 		supabaseServiceKey = cfg.Supabase.AnonKey
 	}
 
-	calendarRepo := repository.NewCalendarRepository(&cfg.Supabase)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	calendarRepo := stores.Calendar
 
-	entries, err := calendarRepo.GetEntriesNeedingMedia()
+	entries, err := calendarRepo.GetEntriesNeedingMedia(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch entries: %w", err)
 	}
@@ -142,7 +147,6 @@ func runGenerateMedia(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to set GOOGLE_API_KEY: %w", err)
 	}
 
-	ctx := context.Background()
 	genaiClient, err := genai.NewClient(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create Gemini client: %w", err)
@@ -153,6 +157,11 @@ func runGenerateMedia(cmd *cobra.Command, args []string) error {
 	failed := 0
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			fmt.Println("\n⚠️  Cancelled, stopping batch")
+			break
+		}
+
 		// Extract book from the nested join chain
 		var book *models.Book
 		if entry.Script != nil && entry.Script.Idea != nil {
@@ -275,7 +284,7 @@ func runGenerateMedia(cmd *cobra.Command, args []string) error {
 
 		publicURL := fmt.Sprintf("%s/storage/v1/object/public/campaign-media/%s", cfg.Supabase.URL, fileName)
 
-		if err := calendarRepo.UpdateMediaURL(entry.ID, publicURL); err != nil {
+		if err := calendarRepo.UpdateMediaURL(ctx, entry.ID, publicURL); err != nil {
 			fmt.Printf("FAILED (db update): %v\n", err)
 			failed++
 			continue
@@ -286,15 +295,13 @@ func runGenerateMedia(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("\nDone. Generated: %d, Failed: %d\n", generated, failed)
-	return nil
+	return ctx.Err()
 }
 
 func buildImagePrompt(platform string, script *models.ContentScriptWithIdea, book *models.Book) string {
 	platformDesc := "TikTok/Instagram Reels"
-	if platform == "instagram" {
-		platformDesc = "Instagram Reels"
-	} else if platform == "tiktok" {
-		platformDesc = "TikTok"
+	if platform != "" {
+		platformDesc = models.PlatformLabel(platform)
 	}
 
 	hook := ""
@@ -0,0 +1,201 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/scheduler"
+	"github.com/gagipress/gagipress-cli/internal/storage"
+	"github.com/gagipress/gagipress-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	heatmapPlatform string
+	heatmapHistory  int
+	heatmapTop      int
+)
+
+// heatmapShades go from the worst to the best score of a heatmap
+var heatmapShades = []string{"··", "░░", "▒▒", "▓▓", "██"}
+
+var heatmapCmd = &cobra.Command{
+	Use:   "heatmap",
+	Short: "Show the learned engagement by weekday and hour",
+	Long: `Show the engagement heatmap 'calendar plan' picks posting times from.
+
+Each platform gets a grid of weekdays by hour, learned from the latest
+metrics of posts published in the last --history days, by the time they
+were scheduled for, on the audience's clock (planner.timezone and
+planner.timezones in the config). Slots with few posts are pulled toward the default peak
+hours, so darker cells are the best bets, not just the luckiest posts.
+
+Posts need views to have an engagement rate; Bluesky reports none, so its
+heatmap keeps the defaults. Run 'gagipress stats collect' to gather metrics.`,
+	RunE: runHeatmap,
+}
+
+func init() {
+	heatmapCmd.Flags().StringVar(&heatmapPlatform, "platform", "", "Show this platform only (default: every platform with data)")
+	heatmapCmd.Flags().IntVar(&heatmapHistory, "history", scheduler.DefaultHistoryDays, "Learn from posts of the last N days (0 for all)")
+	heatmapCmd.Flags().IntVar(&heatmapTop, "top", 5, "Number of best slots to list")
+}
+
+func runHeatmap(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if heatmapPlatform != "" {
+		if err := models.ValidatePlatform(heatmapPlatform); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println(ui.StyleHeader.Render("🔥 Posting Time Heatmap"))
+
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+
+	var since time.Time
+	if heatmapHistory > 0 {
+		since = time.Now().AddDate(0, 0, -heatmapHistory)
+	}
+	optimizer, err := audienceOptimizer(cfg)
+	if err != nil {
+		return err
+	}
+	analyzed, err := optimizer.LoadHistory(ctx, stores.Calendar, stores.Metrics, since)
+	if err != nil {
+		return fmt.Errorf("failed to load historical metrics: %w", err)
+	}
+	fmt.Printf("Learned from %d published posts\n\n", analyzed)
+
+	platforms := optimizer.Platforms()
+	if heatmapPlatform != "" {
+		platforms = []string{heatmapPlatform}
+	}
+	if len(platforms) == 0 {
+		ui.Warning("No metrics yet, showing the default peak hours. Run 'gagipress stats collect' after publishing.")
+		platforms = []string{""}
+	}
+
+	for i, platform := range platforms {
+		if i > 0 {
+			fmt.Println()
+		}
+		printHeatmap(optimizer.Heatmap(platform), optimizer.Location(platform))
+	}
+
+	return nil
+}
+
+// printHeatmap prints the grid of a heatmap, in its audience's timezone,
+// and its best slots
+func printHeatmap(heatmap *scheduler.Heatmap, loc *time.Location) {
+	name := heatmap.Platform
+	if name == "" {
+		name = "all platforms"
+	}
+	name += ", " + loc.String()
+	if heatmap.Posts == 0 {
+		fmt.Println(ui.StyleHeader.Render(name + " (no posts with views yet, showing the defaults)"))
+	} else {
+		fmt.Println(ui.StyleHeader.Render(fmt.Sprintf("%s (%d posts, %.2f%% mean engagement)", name, heatmap.Posts, heatmap.Baseline)))
+	}
+
+	low, high := scoreRange(heatmap)
+
+	// Hours header, labelled every three hours
+	var header strings.Builder
+	header.WriteString("     ")
+	for hour := 0; hour < 24; hour += 3 {
+		fmt.Fprintf(&header, "%-6d", hour)
+	}
+	fmt.Println(strings.TrimRight(header.String(), " "))
+
+	for _, day := range weekdays() {
+		var row strings.Builder
+		fmt.Fprintf(&row, "%-5s", day.String()[:3])
+		for hour := 0; hour < 24; hour++ {
+			row.WriteString(heatmapShade(heatmap.Cells[day][hour].Score, low, high))
+		}
+		fmt.Println(row.String())
+	}
+	fmt.Printf("     %s low  %s high\n\n", heatmapShades[0], heatmapShades[len(heatmapShades)-1])
+
+	type slot struct {
+		day  time.Weekday
+		hour int
+		cell scheduler.HeatmapCell
+	}
+	var slots []slot
+	for _, day := range weekdays() {
+		for hour, cell := range heatmap.Cells[day] {
+			slots = append(slots, slot{day, hour, cell})
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].cell.Score > slots[j].cell.Score
+	})
+
+	var rows [][]string
+	for _, s := range slots[:min(heatmapTop, len(slots))] {
+		mean, score := "-", "default"
+		if s.cell.Posts > 0 {
+			mean = fmt.Sprintf("%.2f%%", s.cell.Mean)
+		}
+		if heatmap.Posts > 0 {
+			score = fmt.Sprintf("%.2f%%", s.cell.Score)
+		}
+		rows = append(rows, []string{
+			s.day.String(),
+			fmt.Sprintf("%02d:00", s.hour),
+			fmt.Sprintf("%d", s.cell.Posts),
+			mean,
+			score,
+		})
+	}
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"Day", "Time", "Posts", "Engagement", "Score"},
+		Rows:     rows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+}
+
+// weekdays returns the days of the week starting on Monday
+func weekdays() []time.Weekday {
+	return []time.Weekday{
+		time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+		time.Friday, time.Saturday, time.Sunday,
+	}
+}
+
+// scoreRange returns the lowest and highest score of a heatmap
+func scoreRange(heatmap *scheduler.Heatmap) (low, high float64) {
+	low, high = heatmap.Cells[0][0].Score, heatmap.Cells[0][0].Score
+	for _, cells := range heatmap.Cells {
+		for _, cell := range cells {
+			low, high = min(low, cell.Score), max(high, cell.Score)
+		}
+	}
+	return low, high
+}
+
+// heatmapShade returns the shade of a score between low and high
+func heatmapShade(score, low, high float64) string {
+	if high <= low {
+		return heatmapShades[len(heatmapShades)/2]
+	}
+	i := int((score - low) / (high - low) * float64(len(heatmapShades)-1))
+	return heatmapShades[min(i, len(heatmapShades)-1)]
+}
@@ -1,17 +1,25 @@
 package calendar
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
 	"github.com/gagipress/gagipress-cli/internal/repository"
 	"github.com/gagipress/gagipress-cli/internal/scheduler"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/spf13/cobra"
 )
 
 var (
-	days        int
-	postsPerDay int
+	days            int
+	postsPerDay     int
+	planPlatforms   []string
+	planHistory     int
+	planNoHistory   bool
+	planConstraints string
 )
 
 var planCmd = &cobra.Command{
@@ -27,16 +35,77 @@ The planner will:
     * Platform-specific peak times
   - Balance content types (educational, entertainment, etc.)
   - Rotate between books
-  - Save to calendar with pending_approval status`,
+  - Save to calendar with pending_approval status
+
+Scripts are picked to match the content mix, 25% educational and
+entertainment, 20% ugc and 15% bts and trend by default, without the same
+type or book twice in a row. Set the mix in ~/.gagipress/config.yaml, for
+all books or per book ID:
+
+  planner:
+    content_mix: {educational: 0.4, entertainment: 0.3, trend: 0.3}
+    books:
+      3f2a9c1e:
+        content_mix: {ugc: 0.5, bts: 0.5}
+
+Posts go to TikTok, or Instagram for longer scripts. Use --platform to plan
+for other platforms, such as bluesky; several platforms take turns.
+
+Posting times come from each platform's engagement heatmap, learned from
+the metrics of posts published in the last --history days (0 for all).
+Until a slot has a few posts, it leans on the default peak hours (7:00,
+12:00, 19:00 and 21:00); --no-history uses only those. See the heatmaps
+with 'gagipress calendar heatmap'.
+
+Times are the audience's wall-clock times, so a 19:00 slot stays at 19:00
+for them across DST changes. Set the audience timezone with planner.timezone
+in ~/.gagipress/config.yaml, and planner.timezones for platforms whose
+audience lives elsewhere; both default to this machine's timezone.
+
+A constraints file (--constraints, or planner.constraints in the config)
+adds rules the plan must follow:
+
+  blackouts:                       # no posts in these windows
+    - name: no Sundays
+      weekdays: [sunday]
+    - name: TikTok only on weekdays
+      platforms: [tiktok]
+      weekdays: [saturday, sunday]
+    - name: holidays
+      from: 2026-12-24
+      to: 2026-12-26
+  spacing:
+    min_hours: 3                   # between any two posts
+    same_book_hours: 12            # between posts of one book
+  caps:
+    per_day: 3
+    per_book_per_day: 1
+    platforms:
+      tiktok: {per_day: 1, per_week: 5}
+  quotas:                          # at least this many posts of a book
+    - name: launch week
+      book: 3f2a9c1e
+      from: 2026-11-02
+      to: 2026-11-08
+      posts: 3
+
+Blackouts, spacing and caps are never broken, so slots they rule out stay
+empty. The plan lists every quota and day it falls short of, and why.`,
 	RunE: runPlan,
 }
 
 func init() {
 	planCmd.Flags().IntVar(&days, "days", 7, "Number of days to plan")
 	planCmd.Flags().IntVar(&postsPerDay, "posts", 2, "Posts per day")
+	planCmd.Flags().StringSliceVar(&planPlatforms, "platform", nil, "Platforms to plan for, in turn (e.g. bluesky or tiktok,bluesky)")
+	planCmd.Flags().StringVar(&planConstraints, "constraints", "", "YAML file of planning constraints (default planner.constraints from the config)")
+	planCmd.Flags().IntVar(&planHistory, "history", scheduler.DefaultHistoryDays, "Learn posting times from posts of the last N days (0 for all)")
+	planCmd.Flags().BoolVar(&planNoHistory, "no-history", false, "Use the default peak times only, without learning from past posts")
 }
 
 func runPlan(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -49,18 +118,74 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Planning: %d days, %d posts/day = %d total posts\n\n", days, postsPerDay, days*postsPerDay)
 
 	// Create planner
-	contentRepo := repository.NewContentRepository(&cfg.Supabase)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	contentRepo := stores.Content
 	planner := scheduler.NewPlanner(contentRepo)
+	if err := planner.SetPlatforms(planPlatforms); err != nil {
+		return err
+	}
+	mix, bookMix, err := contentMix(ctx, cfg, stores.Books)
+	if err != nil {
+		return err
+	}
+	planner.SetContentMix(mix, bookMix)
+	constraintsPath := planConstraints
+	if constraintsPath == "" {
+		constraintsPath = cfg.Planner.Constraints
+	}
+	if constraintsPath != "" {
+		constraints, err := scheduler.LoadConstraints(constraintsPath)
+		if err != nil {
+			return err
+		}
+		if err := constraints.ResolveBooks(ctx, stores.Books); err != nil {
+			return fmt.Errorf("invalid constraints: %w", err)
+		}
+		planner.SetConstraints(constraints)
+		fmt.Printf("Following constraints from %s\n\n", constraintsPath)
+	}
 
 	// Generate plan
 	fmt.Println("⏳ Analyzing available content...")
 	fmt.Println("⏳ Calculating optimal posting times...")
+	optimizer, err := audienceOptimizer(cfg)
+	if err != nil {
+		return err
+	}
+	if !planNoHistory {
+		var since time.Time
+		if planHistory > 0 {
+			since = time.Now().AddDate(0, 0, -planHistory)
+		}
+		analyzed, err := optimizer.LoadHistory(ctx, stores.Calendar, stores.Metrics, since)
+		if err != nil {
+			fmt.Printf("⚠️  Could not load historical metrics, using default peak times: %v\n", err)
+		} else {
+			fmt.Printf("   Learned from %d published posts\n", analyzed)
+		}
+	}
+	planner.SetOptimizer(optimizer)
+
+	// Posts already on the calendar count toward caps, spacing and quotas
+	from, to := planner.ExistingWindow(days)
+	existing, err := entriesBetween(ctx, stores.Calendar, from, to)
+	if err != nil {
+		return err
+	}
+	planner.SetExisting(existing)
+	if len(existing) > 0 {
+		fmt.Printf("   Counting %d posts already on the calendar\n", len(existing))
+	}
 	fmt.Println("⏳ Balancing content mix...")
 
-	calendarEntries, err := planner.PlanWeek(days, postsPerDay)
+	plan, err := planner.Plan(ctx, days, postsPerDay)
 	if err != nil {
 		return fmt.Errorf("failed to create plan: %w", err)
 	}
+	calendarEntries := plan.Entries
 
 	fmt.Printf("\n✅ Plan created: %d posts scheduled\n\n", len(calendarEntries))
 
@@ -76,7 +201,7 @@ func runPlan(cmd *cobra.Command, args []string) error {
 
 		fmt.Printf("%2d. %s | %-10s | Script: %s\n",
 			i+1,
-			entry.ScheduledFor.Format("Mon Jan 02, 15:04"),
+			entry.ScheduledFor.Format("Mon Jan 02, 15:04 MST"),
 			entry.Platform,
 			scriptID,
 		)
@@ -84,24 +209,40 @@ func runPlan(cmd *cobra.Command, args []string) error {
 
 	fmt.Println(repeatStr("─", 70))
 
+	if len(plan.Unsatisfied) > 0 {
+		fmt.Println("\n⚠️  Constraints not met:")
+		for _, u := range plan.Unsatisfied {
+			fmt.Printf("  • %s: %s\n", u.Constraint, u.Reason)
+		}
+	}
+	if len(calendarEntries) == 0 {
+		fmt.Println("\n⚠️  Nothing to save: no slot fits the constraints")
+		return nil
+	}
+
 	// Save to database
 	fmt.Print("\n💾 Saving calendar... ")
 
-	calendarRepo := repository.NewCalendarRepository(&cfg.Supabase)
-	savedCount := 0
+	var inputs []*models.ContentCalendarInput
 	for _, entry := range calendarEntries {
 		if err := entry.Validate(); err != nil {
 			fmt.Printf("\n⚠️  Skipping invalid entry: %v\n", err)
 			continue
 		}
-		if _, err := calendarRepo.CreateEntry(entry); err != nil {
-			fmt.Printf("\n⚠️  Failed to save entry: %v\n", err)
-			continue
+		inputs = append(inputs, entry)
+	}
+
+	results, err := stores.Calendar.CreateEntries(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("failed to save calendar: %w", err)
+	}
+	for _, r := range results {
+		if r.Status == repository.RowFailed {
+			fmt.Printf("\n⚠️  Failed to save entry: %v\n", r.Err)
 		}
-		savedCount++
 	}
 
-	fmt.Printf("✅ OK (%d entries)\n", savedCount)
+	fmt.Printf("✅ OK (%d entries)\n", repository.Summarize(results).Created)
 
 	fmt.Println("\n✅ Calendar plan created successfully!")
 	fmt.Println("\nNext steps:")
@@ -111,6 +252,43 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// audienceOptimizer returns an optimizer set to the configured audience
+// timezones
+func audienceOptimizer(cfg *config.Config) (*scheduler.Optimizer, error) {
+	optimizer := scheduler.NewOptimizer()
+	if err := optimizer.SetTimezones(cfg.Planner.Timezone, cfg.Planner.Timezones); err != nil {
+		return nil, fmt.Errorf("planner timezone: %w", err)
+	}
+	return optimizer, nil
+}
+
+// contentMix returns the configured content mix and the mixes of books that
+// set their own, keyed by full book ID
+func contentMix(ctx context.Context, cfg *config.Config, books repository.BookStore) (scheduler.ContentMixStrategy, map[string]scheduler.ContentMixStrategy, error) {
+	mix := scheduler.DefaultMixStrategy()
+	if len(cfg.Planner.ContentMix) > 0 {
+		var err error
+		if mix, err = scheduler.ParseMixStrategy(cfg.Planner.ContentMix); err != nil {
+			return mix, nil, fmt.Errorf("planner.content_mix: %w", err)
+		}
+	}
+
+	bookMix := make(map[string]scheduler.ContentMixStrategy)
+	for prefix, bookCfg := range cfg.Planner.Books {
+		if len(bookCfg.ContentMix) == 0 {
+			continue
+		}
+		book, err := books.GetBookByIDPrefix(ctx, prefix)
+		if err != nil {
+			return mix, nil, fmt.Errorf("planner.books.%s: %w", prefix, err)
+		}
+		if bookMix[book.ID], err = scheduler.ParseMixStrategy(bookCfg.ContentMix); err != nil {
+			return mix, nil, fmt.Errorf("planner.books.%s.content_mix: %w", prefix, err)
+		}
+	}
+	return mix, bookMix, nil
+}
+
 func repeatStr(s string, count int) string {
 	result := ""
 	for i := 0; i < count; i++ {
@@ -118,3 +296,21 @@ func repeatStr(s string, count int) string {
 	}
 	return result
 }
+
+// entriesBetween returns the calendar entries scheduled from from up to to
+func entriesBetween(ctx context.Context, calendarRepo repository.CalendarStore, from, to time.Time) ([]models.ContentCalendar, error) {
+	var entries []models.ContentCalendar
+	for entry, err := range repository.IterEntries(ctx, calendarRepo, "") {
+		if err != nil {
+			return nil, fmt.Errorf("failed to get calendar entries: %w", err)
+		}
+		// Entries come in scheduled order
+		if !entry.ScheduledFor.Before(to) {
+			break
+		}
+		if !entry.ScheduledFor.Before(from) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
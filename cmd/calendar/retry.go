@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -17,15 +17,21 @@ var retryCmd = &cobra.Command{
 }
 
 func runRetry(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	calendarRepo := repository.NewCalendarRepository(&cfg.Supabase)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	calendarRepo := stores.Calendar
 
 	// Check how many are currently failed before resetting
-	counts, err := calendarRepo.GetStatusCounts()
+	counts, err := calendarRepo.GetStatusCounts(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get status counts: %w", err)
 	}
@@ -38,7 +44,7 @@ func runRetry(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Retrying %s failed entries...\n", ui.StyleWarning.Render(fmt.Sprintf("%d", failedCount)))
 
-	retried, err := calendarRepo.RetryFailed()
+	retried, err := calendarRepo.RetryFailed(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to retry entries: %w", err)
 	}
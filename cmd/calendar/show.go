@@ -5,8 +5,8 @@ import (
 	"time"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
-	"github.com/gagipress/gagipress-cli/internal/models"
 	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -19,16 +19,21 @@ var (
 var showCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show scheduled content calendar",
-	Long:  `Display the content calendar with all scheduled posts.`,
-	RunE:  runShow,
+	Long: `Display the content calendar with all scheduled posts.
+
+Days follow the audience timezone (planner.timezone in the config) and each
+post's time is shown in its platform's audience timezone.`,
+	RunE: runShow,
 }
 
 func init() {
-	showCmd.Flags().StringVar(&statusFilter, "status", "", "Filter by status (pending_approval, approved, published)")
-	showCmd.Flags().IntVar(&daysAhead, "days", 14, "Show next N days")
+	showCmd.Flags().StringVar(&statusFilter, "status", "", "Filter by status (pending_approval, approved, submitted, published)")
+	showCmd.Flags().IntVar(&daysAhead, "days", 14, "Show next N days (0 for all)")
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -43,86 +48,86 @@ func runShow(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Showing schedule for next %d days\n\n", daysAhead)
 
 	// Get calendar entries from database
-	calendarRepo := repository.NewCalendarRepository(&cfg.Supabase)
-	entries, err := calendarRepo.GetEntries(statusFilter, 0)
+	stores, err := storage.Open(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get calendar entries: %w", err)
-	}
-
-	if len(entries) == 0 {
-		fmt.Println("No scheduled posts found.")
-		fmt.Println("\nCreate a plan with: gagipress calendar plan")
-		return nil
+		return fmt.Errorf("failed to open storage: %w", err)
 	}
+	calendarRepo := stores.Calendar
 
-	// Group by date
-	byDate := make(map[string][]models.ContentCalendar)
-	for _, entry := range entries {
-		dateKey := entry.ScheduledFor.Format("2006-01-02")
-		byDate[dateKey] = append(byDate[dateKey], entry)
+	// Days and times are shown on the audience's clock
+	optimizer, err := audienceOptimizer(cfg)
+	if err != nil {
+		return err
 	}
+	location := optimizer.Location("")
 
-	// Display calendar
+	// Entries arrive in scheduled order, so each day can be printed as soon as
+	// it starts and the walk can stop at the end of the window.
+	horizon := time.Now().AddDate(0, 0, daysAhead)
+	total, pending, approved, published := 0, 0, 0, 0
 	currentDate := ""
-	for date, dayEntries := range byDate {
-		parsedDate, _ := time.Parse("2006-01-02", date)
 
-		if date != currentDate {
-			currentDate = date
-			dateHeader := ui.StyleHeader.Render(
-				"📆 " + parsedDate.Format("Monday, January 2, 2006"),
-			)
-			fmt.Println(dateHeader)
+	for entry, err := range repository.IterEntries(ctx, calendarRepo, statusFilter) {
+		if err != nil {
+			return fmt.Errorf("failed to get calendar entries: %w", err)
+		}
+		if daysAhead > 0 && entry.ScheduledFor.After(horizon) {
+			break
 		}
 
-		for _, entry := range dayEntries {
-			// Format status with color
-			var status string
-			switch entry.Status {
-			case "pending_approval":
-				status = ui.FormatStatus("pending")
-			case "approved":
-				status = ui.FormatStatus("approved")
-			case "published":
-				status = ui.StyleSuccess.Render("published")
-			case "failed":
-				status = ui.StyleError.Render("failed")
-			default:
-				status = entry.Status
-			}
-
-			time := ui.StyleMuted.Render(entry.ScheduledFor.Format("15:04"))
-			entryID := entry.ID
-			if len(entryID) > 8 {
-				entryID = entryID[:8] + "…"
+		scheduledFor := entry.ScheduledFor.In(location)
+		if date := scheduledFor.Format("2006-01-02"); date != currentDate {
+			if currentDate != "" {
+				fmt.Println()
 			}
-
-			fmt.Printf("  %s | %s | %s | %s\n",
-				time,
-				entry.Platform,
-				status,
-				entryID,
+			currentDate = date
+			dateHeader := ui.StyleHeader.Render(
+				"📆 " + scheduledFor.Format("Monday, January 2, 2006"),
 			)
+			fmt.Println(dateHeader)
 		}
-		fmt.Println()
-	}
-
-	// Summary
-	total := len(entries)
-	pending := 0
-	approved := 0
-	published := 0
 
-	for _, entry := range entries {
+		// Format status with color
+		var status string
 		switch entry.Status {
 		case "pending_approval":
+			status = ui.FormatStatus("pending")
 			pending++
 		case "approved":
+			status = ui.FormatStatus("approved")
 			approved++
+		case "submitted":
+			status = ui.StyleWarning.Render("submitted")
 		case "published":
+			status = ui.StyleSuccess.Render("published")
 			published++
+		case "failed":
+			status = ui.StyleError.Render("failed")
+		default:
+			status = entry.Status
+		}
+		total++
+
+		time := ui.StyleMuted.Render(entry.ScheduledFor.In(optimizer.Location(entry.Platform)).Format("15:04 MST"))
+		entryID := entry.ID
+		if len(entryID) > 8 {
+			entryID = entryID[:8] + "…"
 		}
+
+		fmt.Printf("  %s | %s | %s | %s\n",
+			time,
+			entry.Platform,
+			status,
+			entryID,
+		)
+	}
+
+	if total == 0 {
+		fmt.Println("No scheduled posts found.")
+		fmt.Println("\nCreate a plan with: gagipress calendar plan")
+		return nil
 	}
+	fmt.Println()
 
 	fmt.Println(ui.StyleHeader.Render("Summary"))
 	summaryText := fmt.Sprintf("Total: %d posts | Pending: %s | Approved: %s | Published: %s",
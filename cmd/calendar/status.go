@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -12,11 +12,13 @@ import (
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show publishing status summary",
-	Long:  `Display a count of calendar entries by status (approved, publishing, published, failed).`,
+	Long:  `Display a count of calendar entries by status (approved, publishing, submitted, published, failed).`,
 	RunE:  runStatus,
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -24,8 +26,12 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	fmt.Println(ui.StyleHeader.Render("📊 Calendar Status"))
 
-	calendarRepo := repository.NewCalendarRepository(&cfg.Supabase)
-	counts, err := calendarRepo.GetStatusCounts()
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	calendarRepo := stores.Calendar
+	counts, err := calendarRepo.GetStatusCounts(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get status counts: %w", err)
 	}
@@ -48,6 +54,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		{"pending_approval", "Pending approval"},
 		{"approved", "Approved (queued)"},
 		{"publishing", "Publishing (in-flight)"},
+		{"submitted", "Submitted (awaiting delivery)"},
 		{"published", "Published"},
 		{"failed", "Failed"},
 	}
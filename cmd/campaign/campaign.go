@@ -0,0 +1,21 @@
+package campaign
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// CampaignCmd represents the campaign command group
+var CampaignCmd = &cobra.Command{
+	Use:   "campaign",
+	Short: "Plan book launch campaigns",
+	Long: `Plan the posts around a book launch, when social posting moves the KDP
+rank the most.
+
+A campaign has a teaser before the launch, the launch day itself and a
+review push after it. Its ideas, scripts and calendar entries carry the
+campaign ID so they can be reported on together.`,
+}
+
+func init() {
+	CampaignCmd.AddCommand(createCmd)
+}
@@ -0,0 +1,442 @@
+package campaign
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/ai"
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/generator"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/pool"
+	"github.com/gagipress/gagipress-cli/internal/prompts"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/scheduler"
+	"github.com/gagipress/gagipress-cli/internal/storage"
+	"github.com/gagipress/gagipress-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createBook        string
+	createLaunch      string
+	createName        string
+	createPlatforms   []string
+	createTeaserDays  int
+	createReviewDays  int
+	createLaunchPosts int
+	createUseGemini   bool
+	createConcurrency int
+	createDryRun      bool
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Plan a book launch campaign",
+	Long: `Plan the posts around a book launch in three phases:
+
+  teaser       the days before the launch, ramping up from 1 post a day
+  launch       the launch day, with the most posts (--launch-posts)
+  review_push  the days after, asking readers for reviews while tapering off
+
+Each phase gets ideas of the types that fit it (behind the scenes and trends
+for the teaser, reviews and user content for the review push), generated
+for the book and scripted for the platform of their post. Posts go to the
+best hours learned from past engagement, with the platforms taking turns,
+and land in the calendar pending approval.
+
+The launch defaults to the book's publication date. Days that are already
+past are left out. The ideas and calendar entries are tagged with the ID of
+the campaign, which also goes into the utm_campaign of the Amazon links.
+
+Use --dry-run to see the plan without generating anything.`,
+	Example: `  gagipress campaign create --book 3f2a9c1e --launch 2026-11-01
+  gagipress campaign create --book 3f2a9c1e --platform tiktok,instagram,bluesky --dry-run`,
+	RunE: runCreate,
+}
+
+func init() {
+	createCmd.Flags().StringVar(&createBook, "book", "", "Book ID or ID prefix (required)")
+	createCmd.Flags().StringVar(&createLaunch, "launch", "", "Launch date, YYYY-MM-DD (default the book's publication date)")
+	createCmd.Flags().StringVar(&createName, "name", "", "Campaign name (default \"<book title> launch\")")
+	createCmd.Flags().StringSliceVar(&createPlatforms, "platform", []string{"tiktok", "instagram"}, "Platforms to post on, in turn")
+	createCmd.Flags().IntVar(&createTeaserDays, "teaser-days", 7, "Days of teaser before the launch")
+	createCmd.Flags().IntVar(&createReviewDays, "review-days", 7, "Days of review push after the launch")
+	createCmd.Flags().IntVar(&createLaunchPosts, "launch-posts", 3, "Posts on the launch day (at least 2)")
+	createCmd.Flags().BoolVar(&createUseGemini, "gemini", false, "Use only Gemini instead of the configured AI providers")
+	createCmd.Flags().IntVar(&createConcurrency, "concurrency", 4, "Number of scripts to generate at once")
+	createCmd.Flags().BoolVar(&createDryRun, "dry-run", false, "Show the plan without generating or saving anything")
+	createCmd.MarkFlagRequired("book")
+}
+
+// campaignPost is a post of the campaign and what was made for it
+type campaignPost struct {
+	slot     scheduler.CampaignSlot
+	idea     *models.ContentIdea
+	scriptID string
+	entryID  string
+	err      error
+}
+
+// scriptResult is the outcome of generating the script of one post
+type scriptResult struct {
+	scriptID string
+	err      error
+	log      string // generator output, printed under the progress line
+}
+
+func runCreate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	for _, platform := range createPlatforms {
+		if err := models.ValidatePlatform(platform); err != nil {
+			return fmt.Errorf("invalid platform: %w", err)
+		}
+	}
+	if createConcurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d (must be at least 1)", createConcurrency)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	fmt.Println(ui.StyleHeader.Render("🚀 Launch Campaign Planner"))
+
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+
+	book, err := stores.Books.GetBookByIDPrefix(ctx, createBook)
+	if err != nil {
+		return fmt.Errorf("failed to get book: %w", err)
+	}
+	launch, err := launchDate(book)
+	if err != nil {
+		return err
+	}
+	name := createName
+	if name == "" {
+		name = book.Title + " launch"
+	}
+
+	phases, err := scheduler.CampaignPhases(launch, createTeaserDays, createReviewDays, createLaunchPosts)
+	if err != nil {
+		return fmt.Errorf("invalid campaign: %w", err)
+	}
+
+	optimizer := scheduler.NewOptimizer()
+	if err := optimizer.SetTimezones(cfg.Planner.Timezone, cfg.Planner.Timezones); err != nil {
+		return fmt.Errorf("planner timezone: %w", err)
+	}
+	since := time.Now().AddDate(0, 0, -scheduler.DefaultHistoryDays)
+	if _, err := optimizer.LoadHistory(ctx, stores.Calendar, stores.Metrics, since); err != nil {
+		fmt.Printf("⚠️  Could not load historical metrics, using default peak times: %v\n", err)
+	}
+	slots, skipped := optimizer.ScheduleCampaign(phases, createPlatforms)
+
+	fmt.Printf("📖 Book: %s\n", book.Title)
+	fmt.Printf("📅 Launch: %s | Platforms: %s\n\n", launch.Format("Mon Jan 02, 2006"), strings.Join(createPlatforms, ", "))
+	printPhases(phases, slots)
+	if skipped > 0 {
+		fmt.Printf("\n⏭️  %d posts fall on days that are already past and are left out\n", skipped)
+	}
+	if len(slots) == 0 {
+		return fmt.Errorf("the campaign is over: its last day is past")
+	}
+
+	if createDryRun {
+		fmt.Println()
+		printSchedule(slots)
+		fmt.Println("\nDry run: nothing was generated or saved.")
+		return nil
+	}
+
+	var providers []ai.Provider
+	if createUseGemini {
+		providers, err = ai.NewChain(cfg, ai.ProviderGemini)
+	} else {
+		providers, err = ai.NewChain(cfg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set up AI providers: %w", err)
+	}
+	ledger := generator.NewLedger(stores.Usage, ai.NewPriceTable(cfg.AI.Prices), "campaign create")
+
+	campaign, err := stores.Campaigns.CreateCampaign(ctx, &models.CampaignInput{
+		BookID:     book.ID,
+		Name:       name,
+		LaunchDate: launch,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n🏷️  Campaign: %s (%s)\n\n", campaign.Name, campaign.ID)
+
+	posts, err := generateIdeas(ctx, stores.Content, providers, ledger, book, campaign, phases, slots)
+	if err != nil {
+		return err
+	}
+	if err := generateScripts(ctx, stores.Content, providers, ledger, book, campaign, posts); err != nil {
+		fmt.Println("\n⚠️  Cancelled, stopping campaign")
+	}
+	if err := saveEntries(ctx, stores.Calendar, campaign, posts); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	printPosts(posts)
+
+	planned := 0
+	for _, post := range posts {
+		if post.entryID != "" {
+			planned++
+		}
+	}
+	fmt.Printf("\nPlanned %d of %d posts for campaign %s\n", planned, len(slots), campaign.ID)
+	if planned > 0 {
+		fmt.Println("\nNext steps:")
+		fmt.Println("  • Review schedule: gagipress calendar show")
+		fmt.Println("  • Approve posts: gagipress calendar approve")
+	}
+
+	return ctx.Err()
+}
+
+// launchDate returns the --launch date, or the book's publication date
+func launchDate(book *models.Book) (models.Date, error) {
+	if createLaunch != "" {
+		t, err := time.Parse(models.DateFormat, createLaunch)
+		if err != nil {
+			return models.Date{}, fmt.Errorf("invalid launch date %q, want YYYY-MM-DD", createLaunch)
+		}
+		return models.Date{Time: t}, nil
+	}
+	if book.PublicationDate == nil || book.PublicationDate.IsZero() {
+		return models.Date{}, fmt.Errorf("no launch date: pass --launch or set the book's publication date with 'gagipress books edit'")
+	}
+	return *book.PublicationDate, nil
+}
+
+// generateIdeas generates and saves the ideas of each phase, one per post
+// still to come, and pairs them with the phase's posts so types take turns
+func generateIdeas(ctx context.Context, contentRepo repository.ContentStore, providers []ai.Provider, ledger *generator.Ledger, book *models.Book, campaign *models.Campaign, phases []scheduler.CampaignPhase, slots []scheduler.CampaignSlot) ([]*campaignPost, error) {
+	gen := generator.NewIdeaGenerator(providers, contentRepo, ledger)
+	gen.SetCampaign(campaign.ID)
+	niche := prompts.NicheFromGenre(book.Genre)
+
+	var posts []*campaignPost
+	for _, phase := range phases {
+		var phaseSlots []scheduler.CampaignSlot
+		for _, slot := range slots {
+			if slot.Phase == phase.Name {
+				phaseSlots = append(phaseSlots, slot)
+			}
+		}
+		if len(phaseSlots) == 0 {
+			continue
+		}
+
+		spinner := ui.NewSpinner(fmt.Sprintf("Generating %d %s ideas...", len(phaseSlots), phase.Name))
+		spinner.Start()
+		generated, err := gen.GenerateCampaignIdeas(ctx, book.ID, book.Title, book.Genre, book.TargetAudience, niche,
+			len(phaseSlots), phase.Name, campaign.LaunchDate.String(), phase.Types)
+		spinner.Stop()
+		if ctx.Err() != nil {
+			return posts, ctx.Err()
+		}
+
+		var ideas []models.ContentIdea
+		if err != nil {
+			ui.Error(fmt.Sprintf("%s ideas failed: %v", phase.Name, err))
+		} else if ideas, err = gen.SaveIdeas(ctx, generated, &book.ID); err != nil {
+			ui.Error(fmt.Sprintf("Saving %s ideas failed: %v", phase.Name, err))
+		} else {
+			ui.Success(fmt.Sprintf("Saved %d %s ideas", len(ideas), phase.Name))
+		}
+
+		ideas = scheduler.InterleaveIdeas(ideas)
+		for i, slot := range phaseSlots {
+			post := &campaignPost{slot: slot}
+			if i < len(ideas) {
+				post.idea = &ideas[i]
+			} else {
+				post.err = fmt.Errorf("no idea")
+			}
+			posts = append(posts, post)
+		}
+	}
+	return posts, nil
+}
+
+// generateScripts generates the script of every post that has an idea,
+// for the platform of the post
+func generateScripts(ctx context.Context, contentRepo repository.ContentStore, providers []ai.Provider, ledger *generator.Ledger, book *models.Book, campaign *models.Campaign, posts []*campaignPost) error {
+	var todo []*campaignPost
+	for _, post := range posts {
+		if post.idea != nil {
+			todo = append(todo, post)
+		}
+	}
+	if len(todo) == 0 {
+		return nil
+	}
+	fmt.Printf("\nGenerating %d scripts...\n", len(todo))
+
+	generate := func(ctx context.Context, i int) scriptResult {
+		post := todo[i]
+		amazonURL := ""
+		if book.KDPASIN != "" {
+			// Build Amazon URL with UTM tracking parameters
+			amazonURL = fmt.Sprintf("https://www.amazon.it/dp/%s?tag=gagipress-21&utm_source=%s&utm_medium=social&utm_campaign=%s",
+				book.KDPASIN, post.slot.Platform, campaign.ID)
+		}
+
+		// Each worker has its own generator so its output can be buffered
+		var log bytes.Buffer
+		gen := generator.NewScriptGenerator(providers, contentRepo, ledger)
+		gen.SetOutput(&log)
+
+		script, err := gen.GenerateScript(ctx, post.idea, book.Title, post.slot.Platform, amazonURL)
+		if err != nil {
+			return scriptResult{err: fmt.Errorf("generation error: %w", err), log: log.String()}
+		}
+		saved, err := gen.SaveScript(ctx, script, post.idea.ID)
+		if err != nil {
+			return scriptResult{err: fmt.Errorf("save error: %w", err), log: log.String()}
+		}
+		return scriptResult{scriptID: saved.ID, log: log.String()}
+	}
+
+	return pool.Run(ctx, len(todo), createConcurrency, generate, func(i int, r scriptResult) {
+		post := todo[i]
+		fmt.Printf("[%d/%d] %s script for idea %s... ", i+1, len(todo), post.slot.Platform, post.idea.ID[:8])
+		if r.err != nil {
+			fmt.Printf("❌ Failed (%v)\n", r.err)
+			post.err = r.err
+		} else {
+			fmt.Printf("✅ Success\n")
+			post.scriptID = r.scriptID
+		}
+		for _, line := range strings.Split(strings.TrimSpace(r.log), "\n") {
+			if line != "" {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	})
+}
+
+// saveEntries adds the scripted posts to the calendar, tagged with the
+// campaign
+func saveEntries(ctx context.Context, calendarRepo repository.CalendarStore, campaign *models.Campaign, posts []*campaignPost) error {
+	var inputs []*models.ContentCalendarInput
+	var saved []*campaignPost
+	for _, post := range posts {
+		if post.scriptID == "" {
+			continue
+		}
+		input := &models.ContentCalendarInput{
+			ScriptID:     &post.scriptID,
+			ScheduledFor: post.slot.Time,
+			Platform:     post.slot.Platform,
+			PostType:     "reel",
+			CampaignID:   &campaign.ID,
+		}
+		if err := input.Validate(); err != nil {
+			post.err = err
+			continue
+		}
+		inputs = append(inputs, input)
+		saved = append(saved, post)
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	results, err := calendarRepo.CreateEntries(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("failed to save calendar: %w", err)
+	}
+	for i, r := range results {
+		if r.Status == repository.RowFailed {
+			saved[i].err = fmt.Errorf("failed to save entry: %w", r.Err)
+			continue
+		}
+		saved[i].entryID = r.Row.ID
+	}
+	return nil
+}
+
+// printPhases prints the days, posts and idea types of each phase
+func printPhases(phases []scheduler.CampaignPhase, slots []scheduler.CampaignSlot) {
+	var rows [][]string
+	for _, phase := range phases {
+		upcoming := 0
+		for _, slot := range slots {
+			if slot.Phase == phase.Name {
+				upcoming++
+			}
+		}
+		days := phase.Days[0].Date.Format("Jan 02")
+		if len(phase.Days) > 1 {
+			days += " – " + phase.Days[len(phase.Days)-1].Date.Format("Jan 02")
+		}
+		perDay := make([]string, len(phase.Days))
+		for i, day := range phase.Days {
+			perDay[i] = fmt.Sprint(day.Posts)
+		}
+		rows = append(rows, []string{
+			phase.Name,
+			days,
+			strings.Join(perDay, " "),
+			fmt.Sprintf("%d of %d", upcoming, phase.Posts()),
+			strings.Join(phase.Types, ", "),
+		})
+	}
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"Phase", "Days", "Posts per day", "Upcoming", "Idea types"},
+		Rows:     rows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+}
+
+// printSchedule prints the posting time of every post
+func printSchedule(slots []scheduler.CampaignSlot) {
+	rows := make([][]string, len(slots))
+	for i, slot := range slots {
+		rows[i] = []string{slot.Time.Format("Mon Jan 02, 15:04 MST"), slot.Phase, slot.Platform}
+	}
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"When", "Phase", "Platform"},
+		Rows:     rows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+}
+
+// printPosts prints what was made for every post
+func printPosts(posts []*campaignPost) {
+	rows := make([][]string, len(posts))
+	for i, post := range posts {
+		idea, status := "", "✅ scheduled "+post.entryID[:min(8, len(post.entryID))]
+		if post.idea != nil {
+			idea = post.idea.Type + ": " + post.idea.BriefDescription
+		}
+		switch {
+		case post.err != nil:
+			status = "❌ " + post.err.Error()
+		case post.entryID == "":
+			status = "⏸️  not started"
+		}
+		rows[i] = []string{post.slot.Time.Format("Mon Jan 02, 15:04 MST"), post.slot.Phase, post.slot.Platform, idea, status}
+	}
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"When", "Phase", "Platform", "Idea", "Status"},
+		Rows:     rows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -39,6 +40,15 @@ func runMigrate() error {
 		return fmt.Errorf("failed to load configuration: %w\nRun 'gagipress init' first", err)
 	}
 
+	// Local backends apply their schema when opened
+	if cfg.Storage.Backend != "" && cfg.Storage.Backend != storage.BackendSupabase {
+		if _, err := storage.Open(cfg); err != nil {
+			return fmt.Errorf("failed to open storage: %w", err)
+		}
+		fmt.Printf("✨ %s storage is ready, no migrations to apply\n", cfg.Storage.Backend)
+		return nil
+	}
+
 	// Validate Supabase config
 	if cfg.Supabase.URL == "" || cfg.Supabase.AnonKey == "" {
 		return fmt.Errorf("supabase configuration is incomplete\nRun 'gagipress init' to configure")
@@ -1,20 +1,29 @@
 package generate
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/generator"
+	"github.com/gagipress/gagipress-cli/internal/jobs"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/pool"
 	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	batchPlatform  string
-	batchUseGemini bool
-	batchLimit     int
+	batchPlatform    string
+	batchUseGemini   bool
+	batchLimit       int
+	batchConcurrency int
+	batchResume      string
 )
 
 var batchCmd = &cobra.Command{
@@ -27,22 +36,67 @@ The batch generator will:
   - Automatically fetch book metadata and ASIN for each idea
   - Generate complete scripts (hook, content, CTA, hashtags)
   - Save scripts to the database
-  - Update idea status to 'scripted'`,
+  - Update idea status to 'scripted'
+
+Up to --concurrency scripts are generated at once. Calls are paced per
+provider by ai.rate_limits (requests and tokens per minute) and held back
+for as long as a 429 response's Retry-After asks. Progress is printed in
+idea order, followed by a per-idea report.
+
+Every batch is recorded in the job journal (see 'gagipress jobs list'). If a
+batch is interrupted, --resume <job-id> picks up the ideas it did not finish
+with the same platform and provider settings; ideas that were scripted in the
+meantime are skipped.`,
 	RunE: runBatch,
 }
 
 func init() {
-	batchCmd.Flags().StringVar(&batchPlatform, "platform", "tiktok", "Target platform for all scripts (tiktok or instagram)")
-	batchCmd.Flags().BoolVar(&batchUseGemini, "gemini", false, "Use Gemini instead of OpenAI")
+	batchCmd.Flags().StringVar(&batchPlatform, "platform", "tiktok", "Target platform for all scripts (tiktok, instagram, youtube, ...)")
+	batchCmd.Flags().BoolVar(&batchUseGemini, "gemini", false, "Use only Gemini instead of the configured AI providers")
 	batchCmd.Flags().IntVar(&batchLimit, "limit", 10, "Maximum number of scripts to generate in this batch")
+	batchCmd.Flags().IntVar(&batchConcurrency, "concurrency", 4, "Number of scripts to generate at once")
+	batchCmd.Flags().StringVar(&batchResume, "resume", "", "Resume an interrupted batch by job ID")
 
 	GenerateCmd.AddCommand(batchCmd)
 }
 
+// batchResult is the outcome of generating the script for one idea
+type batchResult struct {
+	scriptID string
+	err      error
+	log      string // generator output, printed under the progress line
+
+	journalErr error // failure to record the outcome in the job journal
+}
+
 func runBatch(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	journal, err := jobs.Open(jobs.DefaultDir)
+	if err != nil {
+		return err
+	}
+
+	// A resumed job keeps the settings it was started with
+	var job *jobs.Job
+	if batchResume != "" {
+		job, err = journal.Get(batchResume)
+		if err != nil {
+			return err
+		}
+		if job.Kind != jobs.KindGenerateBatch {
+			return fmt.Errorf("job %s is a %q job, not %q", job.ID, job.Kind, jobs.KindGenerateBatch)
+		}
+		batchPlatform = job.Args["platform"]
+		batchUseGemini = job.Args["gemini"] == "true"
+	}
+
 	// Validate platform
-	if batchPlatform != "tiktok" && batchPlatform != "instagram" {
-		return fmt.Errorf("invalid platform: %s (must be tiktok or instagram)", batchPlatform)
+	if err := models.ValidatePlatform(batchPlatform); err != nil {
+		return fmt.Errorf("invalid platform: %w", err)
+	}
+	if batchConcurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d (must be at least 1)", batchConcurrency)
 	}
 
 	// Load configuration
@@ -52,38 +106,75 @@ func runBatch(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println(ui.StyleHeader.Render("📝 Batch Script Generator"))
-	fmt.Printf("Platform: %s | Max Scripts: %d\n\n", batchPlatform, batchLimit)
-
-	contentRepo := repository.NewContentRepository(&cfg.Supabase)
-	booksRepo := repository.NewBooksRepository(&cfg.Supabase)
+	fmt.Printf("Platform: %s | Max Scripts: %d | Concurrency: %d\n\n", batchPlatform, batchLimit, batchConcurrency)
 
-	// Get approved ideas
-	ideas, err := contentRepo.GetIdeas("approved", batchLimit)
+	stores, err := storage.Open(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get approved ideas: %w", err)
+		return fmt.Errorf("failed to open storage: %w", err)
 	}
+	contentRepo := stores.Content
 
-	if len(ideas) == 0 {
-		ui.Warning("No approved ideas found. Create ideas with 'gagipress generate ideas' and approve them first.")
-		return nil
-	}
+	var ideas []models.ContentIdea
+	if job != nil {
+		ideas, err = unfinishedIdeas(ctx, contentRepo, job)
+		if err != nil {
+			return err
+		}
+		if len(ideas) == 0 {
+			ui.Success(fmt.Sprintf("Job %s has nothing left to do.", job.ID))
+			return nil
+		}
+		fmt.Printf("Resuming job %s: %d of %d ideas left.\n\n", job.ID, len(ideas), len(job.Items))
+	} else {
+		// Get approved ideas
+		ideas, err = contentRepo.GetIdeas(ctx, "approved", batchLimit)
+		if err != nil {
+			return fmt.Errorf("failed to get approved ideas: %w", err)
+		}
 
-	fmt.Printf("Found %d approved ideas ready for script generation.\n\n", len(ideas))
+		if len(ideas) == 0 {
+			ui.Warning("No approved ideas found. Create ideas with 'gagipress generate ideas' and approve them first.")
+			return nil
+		}
 
-	gen := generator.NewScriptGenerator(cfg, batchUseGemini)
+		keys := make([]string, len(ideas))
+		for i, idea := range ideas {
+			keys[i] = idea.ID
+		}
+		job, err = journal.Create(jobs.KindGenerateBatch, map[string]string{
+			"platform": batchPlatform,
+			"gemini":   strconv.FormatBool(batchUseGemini),
+		}, keys)
+		if err != nil {
+			return err
+		}
 
-	successCount := 0
-	failedCount := 0
+		fmt.Printf("Found %d approved ideas ready for script generation.\n", len(ideas))
+		fmt.Printf("Job: %s (resume with --resume %s)\n\n", job.ID, job.ID)
+	}
 
-	for i, idea := range ideas {
-		fmt.Printf("[%d/%d] Generating script for idea: %s... ", i+1, len(ideas), idea.ID[:8])
+	// Book info for every idea, fetched once up front
+	books, err := stores.Books.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get books: %w", err)
+	}
+	booksByID := make(map[string]models.Book, len(books))
+	for _, b := range books {
+		booksByID[b.ID] = b
+	}
 
+	providers, err := providerChain(cfg, batchUseGemini)
+	if err != nil {
+		return fmt.Errorf("failed to set up AI providers: %w", err)
+	}
+	ledger := usageLedger(cmd, cfg, stores)
+
+	generateOne := func(ctx context.Context, idea models.ContentIdea) batchResult {
 		// 1. Get book info
 		bookTitle := "Your Book" // default
 		amazonURL := ""
 		if idea.BookID != nil {
-			book, err := booksRepo.GetByID(*idea.BookID)
-			if err == nil {
+			if book, ok := booksByID[*idea.BookID]; ok {
 				bookTitle = book.Title
 				if book.KDPASIN != "" {
 					// Build Amazon URL with UTM tracking parameters
@@ -93,34 +184,126 @@ func runBatch(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		// Each worker has its own generator so its output can be buffered
+		var log bytes.Buffer
+		gen := generator.NewScriptGenerator(providers, contentRepo, ledger)
+		gen.SetOutput(&log)
+
 		// 2. Generate script
-		script, err := gen.GenerateScript(&idea, bookTitle, batchPlatform, amazonURL)
+		script, err := gen.GenerateScript(ctx, &idea, bookTitle, batchPlatform, amazonURL)
 		if err != nil {
-			fmt.Printf("❌ Failed (generation error: %v)\n", err)
-			failedCount++
-			continue
+			return batchResult{err: fmt.Errorf("generation error: %w", err), log: log.String()}
 		}
 
 		// 3. Save to database
-		_, err = gen.SaveScript(script, idea.ID)
+		saved, err := gen.SaveScript(ctx, script, idea.ID)
 		if err != nil {
-			fmt.Printf("❌ Failed (save error: %v)\n", err)
+			return batchResult{err: fmt.Errorf("save error: %w", err), log: log.String()}
+		}
+		return batchResult{scriptID: saved.ID, log: log.String()}
+	}
+
+	// The journal is updated from the workers, so an item is recorded as
+	// done even if the batch dies before its progress line is printed
+	generate := func(ctx context.Context, i int) batchResult {
+		idea := ideas[i]
+		if err := job.Update(idea.ID, jobs.StateRunning, "", ""); err != nil {
+			return batchResult{err: err}
+		}
+		r := generateOne(ctx, idea)
+		if r.err != nil {
+			r.journalErr = job.Update(idea.ID, jobs.StateFailed, "", r.err.Error())
+		} else {
+			r.journalErr = job.Update(idea.ID, jobs.StateDone, r.scriptID, "")
+		}
+		return r
+	}
+
+	results := make([]*batchResult, len(ideas))
+	successCount := 0
+	failedCount := 0
+
+	err = pool.Run(ctx, len(ideas), batchConcurrency, generate, func(i int, r batchResult) {
+		results[i] = &r
+		fmt.Printf("[%d/%d] Script for idea %s... ", i+1, len(ideas), ideas[i].ID[:8])
+		if r.err != nil {
+			fmt.Printf("❌ Failed (%v)\n", r.err)
 			failedCount++
-			continue
+		} else {
+			fmt.Printf("✅ Success\n")
+			successCount++
+		}
+		if r.journalErr != nil {
+			fmt.Printf("    ⚠️  Warning: %v\n", r.journalErr)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(r.log), "\n") {
+			if line != "" {
+				fmt.Printf("    %s\n", line)
+			}
 		}
+	})
+	if err != nil {
+		fmt.Println("\n⚠️  Cancelled, stopping batch")
+	}
 
-		fmt.Printf("✅ Success\n")
-		successCount++
+	// Per-idea report
+	rows := make([][]string, len(ideas))
+	for i, idea := range ideas {
+		status, detail := "⏸️  pending", "not started"
+		if r := results[i]; r != nil && r.err != nil {
+			status, detail = "❌ failed", r.err.Error()
+		} else if r != nil {
+			status, detail = "✅ done", "script "+r.scriptID[:8]
+		}
+		rows[i] = []string{idea.ID[:8], idea.BriefDescription, status, detail}
 	}
+	fmt.Println()
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"Idea", "Description", "Status", "Result"},
+		Rows:     rows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
 
 	fmt.Println("\n" + strings.Repeat("═", 60))
 	fmt.Printf("Batch generation complete!\n")
-	fmt.Printf("Total: %d | Success: %d | Failed: %d\n", len(ideas), successCount, failedCount)
+	fmt.Printf("Total: %d | Success: %d | Failed: %d", len(ideas), successCount, failedCount)
+	if pending := len(ideas) - successCount - failedCount; pending > 0 {
+		fmt.Printf(" | Not started: %d", pending)
+	}
+	fmt.Println()
+	if !job.Complete() {
+		fmt.Printf("\nResume with: gagipress generate batch --resume %s\n", job.ID)
+	}
 
 	if successCount > 0 {
 		fmt.Println("\nNext steps:")
 		fmt.Println("  • Plan schedule: gagipress calendar plan")
 	}
 
-	return nil
+	return ctx.Err()
+}
+
+// unfinishedIdeas returns the ideas of job that are not done yet. Ideas that
+// are no longer approved, e.g. because the interrupted run scripted them
+// after all, are marked skipped instead.
+func unfinishedIdeas(ctx context.Context, contentRepo repository.ContentStore, job *jobs.Job) ([]models.ContentIdea, error) {
+	var ideas []models.ContentIdea
+	for _, item := range job.Items {
+		if item.State == jobs.StateDone || item.State == jobs.StateSkipped {
+			continue
+		}
+		idea, err := contentRepo.GetIdeaByIDPrefix(ctx, item.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idea %s: %w", item.Key, err)
+		}
+		if idea.Status != "approved" {
+			fmt.Printf("⏭️  Skipping idea %s: it is %s now\n", idea.ID[:8], idea.Status)
+			if err := job.Update(item.Key, jobs.StateSkipped, "", "idea is "+idea.Status); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		ideas = append(ideas, *idea)
+	}
+	return ideas, nil
 }
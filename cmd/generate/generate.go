@@ -1,6 +1,12 @@
 package generate
 
 import (
+	"strings"
+
+	"github.com/gagipress/gagipress-cli/internal/ai"
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/generator"
+	"github.com/gagipress/gagipress-cli/internal/repository"
 	"github.com/spf13/cobra"
 )
 
@@ -17,3 +23,19 @@ var GenerateCmd = &cobra.Command{
 func init() {
 	GenerateCmd.AddCommand(ideasCmd)
 }
+
+// providerChain returns the AI fallback chain from ai.providers in the
+// config, or Gemini alone when --gemini is set.
+func providerChain(cfg *config.Config, geminiOnly bool) ([]ai.Provider, error) {
+	if geminiOnly {
+		return ai.NewChain(cfg, ai.ProviderGemini)
+	}
+	return ai.NewChain(cfg)
+}
+
+// usageLedger records the AI calls of cmd in the ai_usage table, priced with
+// the built-in table plus ai.prices from the config.
+func usageLedger(cmd *cobra.Command, cfg *config.Config, stores *repository.Stores) *generator.Ledger {
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	return generator.NewLedger(stores.Usage, ai.NewPriceTable(cfg.AI.Prices), command)
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/generator"
 	"github.com/gagipress/gagipress-cli/internal/prompts"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -21,7 +21,8 @@ var ideasCmd = &cobra.Command{
 	Use:   "ideas",
 	Short: "Generate content ideas using AI",
 	Long: `Generate 20-30 content ideas for TikTok/Instagram Reels.
-Uses OpenAI API as primary, with automatic fallback to Gemini.
+Uses the AI providers listed under ai.providers in the config, trying each
+in order (default: OpenAI with automatic fallback to Gemini).
 
 The generator will:
   - Read books from your catalog
@@ -35,10 +36,12 @@ The generator will:
 func init() {
 	ideasCmd.Flags().IntVar(&count, "count", 20, "Number of ideas to generate")
 	ideasCmd.Flags().StringVar(&bookID, "book", "", "Book ID (optional, generates for all books if not specified)")
-	ideasCmd.Flags().BoolVar(&useGemini, "gemini", false, "Use Gemini instead of OpenAI")
+	ideasCmd.Flags().BoolVar(&useGemini, "gemini", false, "Use only Gemini instead of the configured AI providers")
 }
 
 func runGenerateIdeas(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -49,7 +52,11 @@ func runGenerateIdeas(cmd *cobra.Command, args []string) error {
 	fmt.Println("══════════════════════════")
 
 	// Get books
-	booksRepo := repository.NewBooksRepository(&cfg.Supabase)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	booksRepo := stores.Books
 
 	var books []struct {
 		id    string
@@ -60,7 +67,7 @@ func runGenerateIdeas(cmd *cobra.Command, args []string) error {
 
 	if bookID != "" {
 		// Single book
-		book, err := booksRepo.GetByID(bookID)
+		book, err := booksRepo.GetByID(ctx, bookID)
 		if err != nil {
 			return fmt.Errorf("failed to get book: %w", err)
 		}
@@ -72,7 +79,7 @@ func runGenerateIdeas(cmd *cobra.Command, args []string) error {
 		}{book.ID, book.Title, book.Genre, book.TargetAudience})
 	} else {
 		// All books
-		allBooks, err := booksRepo.GetAll()
+		allBooks, err := booksRepo.GetAll(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get books: %w", err)
 		}
@@ -94,7 +101,11 @@ func runGenerateIdeas(cmd *cobra.Command, args []string) error {
 	fmt.Printf("🎯 Target: %d ideas per book\n\n", count)
 
 	// Create generator
-	gen := generator.NewIdeaGenerator(cfg, useGemini)
+	providers, err := providerChain(cfg, useGemini)
+	if err != nil {
+		return fmt.Errorf("failed to set up AI providers: %w", err)
+	}
+	gen := generator.NewIdeaGenerator(providers, stores.Content, usageLedger(cmd, cfg, stores))
 
 	totalGenerated := 0
 	totalSaved := 0
@@ -104,13 +115,13 @@ func runGenerateIdeas(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   Genre: %s\n", book.genre)
 
 		// Determine niche from genre
-		niche := determineNiche(book.genre)
+		niche := prompts.NicheFromGenre(book.genre)
 		fmt.Printf("   Niche: %s\n\n", niche)
 
 		// Generate ideas
 		spinner := ui.NewSpinner(fmt.Sprintf("Generating %d ideas...", count))
 		spinner.Start()
-		ideas, err := gen.GenerateIdeas(book.title, book.genre, book.audience, niche, count)
+		ideas, err := gen.GenerateIdeas(ctx, book.id, book.title, book.genre, book.audience, niche, count)
 		spinner.Stop()
 
 		if err != nil {
@@ -125,7 +136,7 @@ func runGenerateIdeas(cmd *cobra.Command, args []string) error {
 		// Save to database
 		spinner = ui.NewSpinner("Saving to database...")
 		spinner.Start()
-		savedIdeas, err := gen.SaveIdeas(ideas, &book.id)
+		savedIdeas, err := gen.SaveIdeas(ctx, ideas, &book.id)
 		spinner.Stop()
 
 		if err != nil {
@@ -151,38 +162,3 @@ func runGenerateIdeas(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
-
-// determineNiche determines the book niche from genre
-func determineNiche(genre string) prompts.BookNiche {
-	genreLower := genre
-	if len(genreLower) > 0 {
-		genreLower = genre[:1] + genre[1:]
-	}
-
-	switch {
-	case contains(genreLower, "children", "bambini", "kids"):
-		return prompts.ChildrenBooks
-	case contains(genreLower, "puzzle", "enigmi", "quiz"):
-		return prompts.Puzzles
-	case contains(genreLower, "dialect", "dialetto", "milanese"):
-		return prompts.DialectPuzzles
-	case contains(genreLower, "saving", "risparmio", "money"):
-		return prompts.Savings
-	default:
-		return prompts.Puzzles // default
-	}
-}
-
-// contains checks if str contains any of the substrings
-func contains(str string, substrings ...string) bool {
-	for _, substr := range substrings {
-		if len(str) >= len(substr) {
-			for i := 0; i <= len(str)-len(substr); i++ {
-				if str[i:i+len(substr)] == substr {
-					return true
-				}
-			}
-		}
-	}
-	return false
-}
@@ -6,7 +6,7 @@ import (
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/generator"
 	"github.com/gagipress/gagipress-cli/internal/models"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -35,14 +35,19 @@ The idea must be in "approved" status to generate a script.`,
 }
 
 func init() {
-	scriptCmd.Flags().StringVar(&platform, "platform", "tiktok", "Target platform (tiktok or instagram)")
-	scriptCmd.Flags().BoolVar(&scriptUseGemini, "gemini", false, "Use Gemini instead of OpenAI")
+	scriptCmd.Flags().StringVar(&platform, "platform", "tiktok", "Target platform (tiktok, instagram, youtube, ...)")
+	scriptCmd.Flags().BoolVar(&scriptUseGemini, "gemini", false, "Use only Gemini instead of the configured AI providers")
 
 	GenerateCmd.AddCommand(scriptCmd)
 }
 
 func runGenerateScript(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	ideaID := args[0]
+	if err := models.ValidatePlatform(platform); err != nil {
+		return fmt.Errorf("invalid platform: %w", err)
+	}
 
 	// Load configuration
 	cfg, err := config.Load()
@@ -54,11 +59,15 @@ func runGenerateScript(cmd *cobra.Command, args []string) error {
 	fmt.Println("═══════════════════")
 
 	// Get idea from database
-	contentRepo := repository.NewContentRepository(&cfg.Supabase)
-	booksRepo := repository.NewBooksRepository(&cfg.Supabase)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	contentRepo := stores.Content
+	booksRepo := stores.Books
 
 	fmt.Print("Loading idea... ")
-	ideas, err := contentRepo.GetIdeas("", 0)
+	ideas, err := contentRepo.GetIdeas(ctx, "", 0)
 	if err != nil {
 		fmt.Println("❌ FAILED")
 		return fmt.Errorf("failed to get ideas: %w", err)
@@ -91,7 +100,7 @@ func runGenerateScript(cmd *cobra.Command, args []string) error {
 	bookTitle := "Your Book" // default
 	amazonURL := ""
 	if idea.BookID != nil {
-		book, err := booksRepo.GetByID(*idea.BookID)
+		book, err := booksRepo.GetByID(ctx, *idea.BookID)
 		if err == nil {
 			bookTitle = book.Title
 			if book.KDPASIN != "" {
@@ -103,11 +112,15 @@ func runGenerateScript(cmd *cobra.Command, args []string) error {
 	}
 
 	// Generate script
-	gen := generator.NewScriptGenerator(cfg, scriptUseGemini)
+	providers, err := providerChain(cfg, scriptUseGemini)
+	if err != nil {
+		return fmt.Errorf("failed to set up AI providers: %w", err)
+	}
+	gen := generator.NewScriptGenerator(providers, stores.Content, usageLedger(cmd, cfg, stores))
 
 	spinner := ui.NewSpinner("Generating script with AI...")
 	spinner.Start()
-	script, err := gen.GenerateScript(idea, bookTitle, platform, amazonURL)
+	script, err := gen.GenerateScript(ctx, idea, bookTitle, platform, amazonURL)
 	spinner.Stop()
 
 	if err != nil {
@@ -155,7 +168,7 @@ func runGenerateScript(cmd *cobra.Command, args []string) error {
 
 	// Save to database
 	fmt.Print("\n💾 Saving script... ")
-	savedScript, err := gen.SaveScript(script, idea.ID)
+	savedScript, err := gen.SaveScript(ctx, script, idea.ID)
 	if err != nil {
 		fmt.Println("❌ FAILED")
 		return fmt.Errorf("failed to save script: %w", err)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -23,10 +24,12 @@ var listCmd = &cobra.Command{
 
 func init() {
 	listCmd.Flags().StringVar(&statusFilter, "status", "", "Filter by status (pending, approved, rejected, scripted)")
-	listCmd.Flags().IntVar(&limitList, "limit", 50, "Maximum number of ideas to show")
+	listCmd.Flags().IntVar(&limitList, "limit", 50, "Maximum number of ideas to show (0 for all)")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -34,39 +37,49 @@ func runList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get ideas
-	repo := repository.NewContentRepository(&cfg.Supabase)
-	ideas, err := repo.GetIdeas(statusFilter, limitList)
+	stores, err := storage.Open(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get ideas: %w", err)
-	}
-
-	if len(ideas) == 0 {
-		fmt.Println("No ideas found. Generate some with 'gagipress generate ideas'")
-		return nil
+		return fmt.Errorf("failed to open storage: %w", err)
 	}
+	repo := stores.Content
+
+	// Walk ideas page by page so large catalogs are not cut off by the API row cap
+	var rows [][]string
+	pendingCount := 0
+	for idea, err := range repository.IterIdeas(ctx, repo, "", statusFilter) {
+		if err != nil {
+			return fmt.Errorf("failed to get ideas: %w", err)
+		}
 
-	// Build table rows
-	rows := make([][]string, len(ideas))
-	for i, idea := range ideas {
 		// Format score
 		score := "N/A"
 		if idea.RelevanceScore != nil {
 			score = fmt.Sprintf("%d", *idea.RelevanceScore)
 		}
 
-		// Format status with color
-		status := ui.FormatStatus(idea.Status)
+		if idea.Status == "pending" {
+			pendingCount++
+		}
 
 		// No manual truncation - let table handle it
-		rows[i] = []string{
+		rows = append(rows, []string{
 			idea.ID, // Full UUID for copy-paste and approval
 			idea.Type,
-			status,
+			ui.FormatStatus(idea.Status),
 			idea.BriefDescription, // Full description
 			score,
+		})
+
+		if limitList > 0 && len(rows) >= limitList {
+			break
 		}
 	}
 
+	if len(rows) == 0 {
+		fmt.Println("No ideas found. Generate some with 'gagipress generate ideas'")
+		return nil
+	}
+
 	// Render table
 	table := ui.RenderTable(ui.TableConfig{
 		Headers:  []string{"ID", "Type", "Status", "Description", "Score"},
@@ -77,15 +90,9 @@ func runList(cmd *cobra.Command, args []string) error {
 	fmt.Println(ui.StyleHeader.Render("💡 Content Ideas"))
 	fmt.Println(table)
 
-	fmt.Printf("\nTotal ideas: %d\n", len(ideas))
+	fmt.Printf("\nTotal ideas: %d\n", len(rows))
 
 	if statusFilter == "" || statusFilter == "pending" {
-		pendingCount := 0
-		for _, idea := range ideas {
-			if idea.Status == "pending" {
-				pendingCount++
-			}
-		}
 		if pendingCount > 0 {
 			fmt.Printf("\n💡 %d pending ideas awaiting approval\n", pendingCount)
 			fmt.Println("   Use 'gagipress ideas approve <id>' to approve")
@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +18,8 @@ var rejectCmd = &cobra.Command{
 }
 
 func runReject(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	ideaID := args[0]
 
 	// Load configuration
@@ -29,11 +31,15 @@ func runReject(cmd *cobra.Command, args []string) error {
 	fmt.Println(ui.StyleHeader.Render("❌ Rejecting Idea"))
 	fmt.Println()
 
-	repo := repository.NewContentRepository(&cfg.Supabase)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	repo := stores.Content
 
 	// Resolve ID prefix to full UUID
 	fmt.Print(ui.StyleMuted.Render("Resolving idea ID... "))
-	idea, err := repo.GetIdeaByIDPrefix(ideaID)
+	idea, err := repo.GetIdeaByIDPrefix(ctx, ideaID)
 	if err != nil {
 		fmt.Println(ui.StyleError.Render("✗ FAILED"))
 		return fmt.Errorf("failed to resolve idea ID: %w", err)
@@ -43,7 +49,7 @@ func runReject(cmd *cobra.Command, args []string) error {
 
 	// Update status
 	fmt.Print(ui.StyleMuted.Render("Updating status... "))
-	if err := repo.UpdateIdeaStatus(ideaID, "rejected"); err != nil {
+	if err := repo.UpdateIdeaStatus(ctx, ideaID, "rejected"); err != nil {
 		fmt.Println(ui.StyleError.Render("✗ FAILED"))
 		return fmt.Errorf("failed to reject idea: %w", err)
 	}
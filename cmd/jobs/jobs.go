@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// JobsCmd represents the jobs command group
+var JobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Inspect batch jobs",
+	Long: `Inspect the journal of 'generate batch' and 'publish batch' runs.
+
+Every batch records the state of each of its items in ~/.gagipress/jobs, so an
+interrupted batch can be resumed with --resume <job-id>.`,
+}
+
+func init() {
+	JobsCmd.AddCommand(listCmd)
+	JobsCmd.AddCommand(showCmd)
+}
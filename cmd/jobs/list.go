@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"fmt"
+
+	"github.com/gagipress/gagipress-cli/internal/jobs"
+	"github.com/gagipress/gagipress-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var listLimit int
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List batch jobs, newest first",
+	RunE:  runList,
+}
+
+func init() {
+	listCmd.Flags().IntVar(&listLimit, "limit", 20, "Maximum number of jobs to show (0 for all)")
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	journal, err := jobs.Open(jobs.DefaultDir)
+	if err != nil {
+		return err
+	}
+	all, err := journal.List()
+	if err != nil {
+		return err
+	}
+
+	if len(all) == 0 {
+		fmt.Println("No batch jobs yet. They are created by 'gagipress generate batch' and 'gagipress publish batch'.")
+		return nil
+	}
+	if listLimit > 0 && len(all) > listLimit {
+		all = all[:listLimit]
+	}
+
+	rows := make([][]string, len(all))
+	for i, job := range all {
+		counts := job.Counts()
+		status := "✅ complete"
+		if !job.Complete() {
+			status = "⏸️  incomplete"
+		}
+		rows[i] = []string{
+			job.ID,
+			job.Kind,
+			job.CreatedAt.Local().Format("2006-01-02 15:04"),
+			status,
+			fmt.Sprintf("%d/%d", counts[jobs.StateDone]+counts[jobs.StateSkipped], len(job.Items)),
+			fmt.Sprint(counts[jobs.StateFailed] + counts[jobs.StateUnknown]),
+		}
+	}
+
+	fmt.Println(ui.StyleHeader.Render("🗂️  Batch Jobs"))
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"ID", "Kind", "Started", "Status", "Finished", "Failed"},
+		Rows:     rows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+
+	fmt.Println("\nDetails: gagipress jobs show <job-id>")
+	return nil
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gagipress/gagipress-cli/internal/jobs"
+	"github.com/gagipress/gagipress-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <job-id>",
+	Short: "Show the state of every item of a batch job",
+	Long: `Show the state of every item of a batch job. The job ID may be
+abbreviated to any unique prefix.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShow,
+}
+
+// stateIcons decorates item states in the table
+var stateIcons = map[string]string{
+	jobs.StatePending:   "⏸️ ",
+	jobs.StateRunning:   "🔄",
+	jobs.StateSubmitted: "📤",
+	jobs.StateDone:      "✅",
+	jobs.StateFailed:    "❌",
+	jobs.StateSkipped:   "⏭️ ",
+	jobs.StateUnknown:   "❓",
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	journal, err := jobs.Open(jobs.DefaultDir)
+	if err != nil {
+		return err
+	}
+	job, err := journal.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ui.StyleHeader.Render("🗂️  Job " + job.ID))
+	fmt.Printf("Kind:    %s\n", job.Kind)
+	fmt.Printf("Started: %s\n", job.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("Updated: %s\n", job.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+	if len(job.Args) > 0 {
+		var settings []string
+		for k, v := range job.Args {
+			settings = append(settings, k+"="+v)
+		}
+		sort.Strings(settings)
+		fmt.Printf("Settings: %s\n", strings.Join(settings, " "))
+	}
+	fmt.Println()
+
+	rows := make([][]string, len(job.Items))
+	for i, item := range job.Items {
+		rows[i] = []string{
+			item.Key,
+			stateIcons[item.State] + " " + item.State,
+			item.Result,
+			item.Error,
+			item.UpdatedAt.Local().Format("2006-01-02 15:04:05"),
+		}
+	}
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"Item", "State", "Result", "Error", "Updated"},
+		Rows:     rows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+
+	counts := job.Counts()
+	fmt.Printf("\nDone: %d | Skipped: %d | Failed: %d | Unknown: %d | Left: %d\n",
+		counts[jobs.StateDone], counts[jobs.StateSkipped], counts[jobs.StateFailed], counts[jobs.StateUnknown],
+		counts[jobs.StatePending]+counts[jobs.StateRunning]+counts[jobs.StateSubmitted])
+
+	if !job.Complete() {
+		fmt.Printf("\nResume with: gagipress %s --resume %s\n", job.Kind, job.ID)
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/jobs"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/social"
+	"github.com/gagipress/gagipress-cli/internal/storage"
+	"github.com/gagipress/gagipress-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	batchLimit   int
+	batchResume  string
+	retryUnknown bool
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Publish/schedule all approved calendar entries",
+	Long: `Publish or schedule every approved calendar entry through the publisher
+configured for its platform (see 'gagipress publish --help'). Entries for
+publishers that cannot schedule are left for a later run until they are due,
+so run it from cron.
+
+Every batch is recorded in the job journal (see 'gagipress jobs list'), and
+each post is journaled before and after it is sent. This makes batches safe
+to rerun:
+  - a post the publisher accepted is never submitted again, even if saving
+    its submission ID on the entry failed; the rerun only retries that update
+  - entries that already have a submission ID are skipped
+  - a post whose submission was cut off midway is reported as unknown and
+    left alone until --retry-unknown is given
+
+--resume <job-id> retries the entries of an interrupted or partly failed
+batch with the settings it was started with.`,
+	RunE: runBatchPublish,
+}
+
+func init() {
+	batchCmd.Flags().BoolVar(&withMedia, "with-media", false, "Generate media for each post before publishing")
+	batchCmd.Flags().IntVar(&batchLimit, "limit", 10, "Maximum number of posts to submit in this batch")
+	batchCmd.Flags().StringVar(&batchResume, "resume", "", "Resume an interrupted batch by job ID")
+	batchCmd.Flags().BoolVar(&retryUnknown, "retry-unknown", false, "Resubmit posts whose earlier submission has an unknown outcome (check the platform first)")
+	PublishCmd.AddCommand(batchCmd)
+}
+
+func runBatchPublish(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	journal, err := jobs.Open(jobs.DefaultDir)
+	if err != nil {
+		return err
+	}
+
+	// A resumed job keeps the settings it was started with
+	var job *jobs.Job
+	if batchResume != "" {
+		job, err = journal.Get(batchResume)
+		if err != nil {
+			return err
+		}
+		if job.Kind != jobs.KindPublishBatch {
+			return fmt.Errorf("job %s is a %q job, not %q", job.ID, job.Kind, jobs.KindPublishBatch)
+		}
+		withMedia = job.Args["with_media"] == "true"
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if withMedia && cfg.Blotato.APIKey == "" {
+		return fmt.Errorf("blotato API key is not configured but --with-media was requested")
+	}
+
+	fmt.Println(ui.StyleHeader.Render("🚀 Batch Publish Posts"))
+
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	calendarRepo := stores.Calendar
+	contentRepo := stores.Content
+	publishers := social.NewPublisherSet(cfg)
+	blotatoClient := social.NewBlotatoClient(cfg.Blotato.APIKey) // media generation
+
+	var entries []models.ContentCalendar
+	if job != nil {
+		entries, err = unfinishedEntries(ctx, calendarRepo, job)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			ui.Success(fmt.Sprintf("Job %s has nothing left to do.", job.ID))
+			return nil
+		}
+		fmt.Printf("Resuming job %s: %d of %d posts left.\n\n", job.ID, len(entries), len(job.Items))
+	} else {
+		spinner := ui.NewSpinner("Fetching approved calendar entries...")
+		spinner.Start()
+		entries, err = calendarRepo.GetEntries(ctx, "approved", batchLimit)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get calendar entries: %w", err)
+		}
+
+		if len(entries) == 0 {
+			ui.Success("No approved posts ready to be scheduled/published.")
+			return nil
+		}
+
+		keys := make([]string, len(entries))
+		for i, entry := range entries {
+			keys[i] = entry.ID
+		}
+		job, err = journal.Create(jobs.KindPublishBatch, map[string]string{
+			"with_media": strconv.FormatBool(withMedia),
+		}, keys)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Found %d approved posts to process.\n", len(entries))
+		fmt.Printf("Job: %s (resume with --resume %s)\n\n", job.ID, job.ID)
+	}
+
+	// What earlier jobs did with each entry, read once for the whole batch
+	lastRuns, err := journal.LastRuns(jobs.KindPublishBatch)
+	if err != nil {
+		return fmt.Errorf("failed to read job journal: %w", err)
+	}
+
+	successCount := 0
+	failedCount := 0
+	pendingCount := 0 // submitted, but the local status update failed
+	skippedCount := 0
+
+	// fail records a failed entry in the journal and the counters
+	fail := func(entry models.ContentCalendar, msg string) {
+		fmt.Printf("❌ %s\n", msg)
+		failedCount++
+		if err := job.Update(entry.ID, jobs.StateFailed, "", msg); err != nil {
+			fmt.Printf("   ⚠️  Warning: %v\n", err)
+		}
+	}
+
+	// finish marks an entry the publisher has accepted as submitted,
+	// journaling each step so a rerun never submits it again
+	finish := func(entry models.ContentCalendar, publisher string, status *social.PostStatus) {
+		submissionID := status.PostSubmissionID
+		if err := job.Update(entry.ID, jobs.StateSubmitted, submissionID, ""); err != nil {
+			fmt.Printf("   ⚠️  Warning: %v\n", err)
+		}
+		if err := recordSubmission(ctx, calendarRepo, &entry, publisher, status); err != nil {
+			fmt.Printf("⚠️  Submitted (ID: %s) but failed to update local status: %v\n", submissionID, err)
+			pendingCount++
+			return
+		}
+		if err := job.Update(entry.ID, jobs.StateDone, submissionID, ""); err != nil {
+			fmt.Printf("   ⚠️  Warning: %v\n", err)
+		}
+		fmt.Printf("✅ Success via %s (Submission ID: %s)\n", publisher, submissionID)
+		successCount++
+	}
+
+	for i, entry := range entries {
+		if ctx.Err() != nil {
+			fmt.Println("\n⚠️  Cancelled, stopping batch")
+			break
+		}
+
+		fmt.Printf("[%d/%d] Submitting entry: %s (Platform: %s)\n", i+1, len(entries), entry.ID[:8], entry.Platform)
+
+		// Never resubmit a post that a publisher may already have
+		if entry.SubmissionID != nil {
+			fmt.Printf("⏭️  Skipped: already submitted (ID: %s)\n", *entry.SubmissionID)
+			if err := job.Update(entry.ID, jobs.StateSkipped, *entry.SubmissionID, ""); err != nil {
+				fmt.Printf("   ⚠️  Warning: %v\n", err)
+			}
+			skippedCount++
+			continue
+		}
+		publisherName, publisher, err := publishers.For(entry.Platform)
+		if err != nil {
+			fail(entry, fmt.Sprintf("No publisher: %v", err))
+			continue
+		}
+		if prev, ok := lastRuns[entry.ID]; ok {
+			switch prev.Item.State {
+			case jobs.StateSubmitted:
+				fmt.Printf("   ↩️  Already submitted by job %s, updating local status only\n", prev.Job.ID)
+				finish(entry, publisherName, &social.PostStatus{PostSubmissionID: prev.Item.Result, Status: social.PostStatusInProgress})
+				continue
+			case jobs.StateRunning, jobs.StateUnknown:
+				if !retryUnknown {
+					msg := fmt.Sprintf("job %s was cut off while submitting this post; check %s, then rerun with --retry-unknown if it was not posted", prev.Job.ID, publisherName)
+					fmt.Printf("❓ Outcome unknown: %s\n", msg)
+					failedCount++
+					if err := job.Update(entry.ID, jobs.StateUnknown, "", msg); err != nil {
+						fmt.Printf("   ⚠️  Warning: %v\n", err)
+					}
+					continue
+				}
+			}
+		}
+
+		// Publishers that cannot schedule post when the entry is due
+		capabilities := publisher.Capabilities()
+		if !capabilities.Schedule && entry.ScheduledFor.After(time.Now()) {
+			msg := fmt.Sprintf("not due until %s (%s cannot schedule)", entry.ScheduledFor.Local().Format("2006-01-02 15:04"), publisherName)
+			fmt.Printf("⏭️  Skipped: %s\n", msg)
+			if err := job.Update(entry.ID, jobs.StateSkipped, "", msg); err != nil {
+				fmt.Printf("   ⚠️  Warning: %v\n", err)
+			}
+			skippedCount++
+			continue
+		}
+
+		if entry.ScriptID == nil {
+			fail(entry, "Failed: no script attached")
+			continue
+		}
+
+		script, err := contentRepo.GetScriptByID(ctx, *entry.ScriptID)
+		if err != nil {
+			fail(entry, fmt.Sprintf("Failed to get script: %v", err))
+			continue
+		}
+
+		// Media
+		var mediaUrls []string
+		if entry.MediaURL != nil {
+			mediaUrls = append(mediaUrls, *entry.MediaURL)
+		}
+		if withMedia {
+			prompt := fmt.Sprintf("Create a promotional visual for a book post.\nHook: %s\nMain topic: %s", script.Hook, script.FullScript)
+			creationID, err := blotatoClient.GenerateVisual(ctx, cfg.Blotato.TemplateID, prompt)
+			if err != nil {
+				fail(entry, fmt.Sprintf("Failed to request visual: %v", err))
+				recordPublishError(ctx, calendarRepo, entry.ID, "", "media", err)
+				continue
+			}
+			mediaURL, err := blotatoClient.WaitForVisualCreation(ctx, creationID)
+			if err != nil {
+				fail(entry, fmt.Sprintf("Failed to generate visual: %v", err))
+				recordPublishError(ctx, calendarRepo, entry.ID, "", "media", err)
+				continue
+			}
+			mediaUrls = append(mediaUrls, mediaURL)
+			fmt.Printf("   🖼️  Media generated: %s\n", mediaURL)
+		}
+
+		req := &social.PublishRequest{
+			Platform:  entry.Platform,
+			PostType:  entry.PostType,
+			Text:      postText(script),
+			MediaURLs: mediaUrls,
+		}
+		if capabilities.Schedule {
+			req.ScheduledFor = entry.ScheduledFor
+		}
+		if err := capabilities.Check(req); err != nil {
+			fail(entry, fmt.Sprintf("Not submitted: %v", err))
+			continue
+		}
+
+		// Submit, journaling the attempt first so that a crash mid-request
+		// leaves the post marked as unknown rather than unsent
+		if err := job.Update(entry.ID, jobs.StateRunning, "", ""); err != nil {
+			fail(entry, fmt.Sprintf("Not submitted: %v", err))
+			continue
+		}
+		status, err := publisher.Publish(ctx, req)
+		if err != nil {
+			fail(entry, fmt.Sprintf("Failed to submit to %s: %v", publisherName, err))
+			recordPublishError(ctx, calendarRepo, entry.ID, "failed", "submit", err)
+			continue
+		}
+
+		finish(entry, publisherName, status)
+	}
+
+	fmt.Println("\n" + strings.Repeat("═", 60))
+	fmt.Printf("Batch publish complete!\n")
+	fmt.Printf("Total: %d | Success: %d | Failed: %d", len(entries), successCount, failedCount)
+	if skippedCount > 0 {
+		fmt.Printf(" | Skipped: %d", skippedCount)
+	}
+	if pendingCount > 0 {
+		fmt.Printf(" | Awaiting status update: %d", pendingCount)
+	}
+	fmt.Println()
+	if !job.Complete() {
+		fmt.Printf("\nDetails: gagipress jobs show %s\n", job.ID)
+		fmt.Printf("Resume with: gagipress publish batch --resume %s\n", job.ID)
+	}
+
+	return ctx.Err()
+}
+
+// unfinishedEntries returns the calendar entries of job that are not done
+// yet. Entries that were submitted in the meantime are marked skipped,
+// unless the job itself submitted them and only has to record that.
+func unfinishedEntries(ctx context.Context, calendarRepo repository.CalendarStore, job *jobs.Job) ([]models.ContentCalendar, error) {
+	var entries []models.ContentCalendar
+	for _, item := range job.Items {
+		if item.State == jobs.StateDone || item.State == jobs.StateSkipped {
+			continue
+		}
+		entry, err := calendarRepo.GetEntryByID(ctx, item.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get calendar entry %s: %w", item.Key, err)
+		}
+		if entry.Status == "published" || entry.SubmissionID != nil {
+			state := jobs.StateSkipped
+			if item.State == jobs.StateSubmitted {
+				state = jobs.StateDone
+			}
+			if err := job.Update(item.Key, state, item.Result, ""); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
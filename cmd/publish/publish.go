@@ -1,48 +1,54 @@
 package publish
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
 	"github.com/gagipress/gagipress-cli/internal/repository"
 	"github.com/gagipress/gagipress-cli/internal/social"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
 	withMedia  bool
-	batchLimit int
+	publishNow bool
 )
 
 // PublishCmd represents the publish command group
 var PublishCmd = &cobra.Command{
 	Use:   "publish [calendar-entry-id]",
-	Short: "Publish or schedule a post using Blotato",
-	Long: `Publish or schedule a post on social media using Blotato's API.
-This command takes a scheduled post from the content calendar and
-sends it to Blotato for publishing or scheduling on the target platform.
+	Short: "Publish or schedule a post",
+	Long: `Publish or schedule a post from the content calendar on its platform.
+
+The post goes through the publisher configured for the platform in
+publishing.platforms (blotato by default, or a native one such as
+instagram, tiktok or bluesky). Blotato queues posts until their scheduled time;
+native publishers cannot schedule and post right away, so they only take
+entries whose time has come unless --now is given.
+
+The entry is marked 'submitted' with the publisher's submission ID, and an
+entry that already has one is never submitted again. It only becomes
+'published' once the publisher confirms the post went live, right away or
+through 'publish status' and 'publish reconcile'.
 If no arguments are provided, it can run as a parent command for subcommands like 'batch'.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runPublish,
 }
 
-var batchCmd = &cobra.Command{
-	Use:   "batch",
-	Short: "Publish/schedule all approved calendar entries",
-	RunE:  runBatchPublish,
-}
-
 func init() {
 	PublishCmd.Flags().BoolVar(&withMedia, "with-media", false, "Generate media using Blotato template before publishing (requires TemplateID in config)")
-
-	batchCmd.Flags().BoolVar(&withMedia, "with-media", false, "Generate media for each post before publishing")
-	batchCmd.Flags().IntVar(&batchLimit, "limit", 10, "Maximum number of posts to submit in this batch")
-	PublishCmd.AddCommand(batchCmd)
+	PublishCmd.Flags().BoolVar(&publishNow, "now", false, "Post entries scheduled later right away with publishers that cannot schedule")
 }
 
 func runPublish(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	if len(args) == 0 {
 		return cmd.Help()
 	}
@@ -54,25 +60,28 @@ func runPublish(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if cfg.Blotato.APIKey == "" {
-		return fmt.Errorf("blotato API key is not configured. Please run 'gagipress config set blotato.api_key YOUR_KEY'")
-	}
-
-	fmt.Println(ui.StyleHeader.Render("🚀 Publish Post via Blotato"))
+	fmt.Println(ui.StyleHeader.Render("🚀 Publish Post"))
 
 	// Initialize repositories
-	calendarRepo := repository.NewCalendarRepository(&cfg.Supabase)
-	contentRepo := repository.NewContentRepository(&cfg.Supabase)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	calendarRepo := stores.Calendar
+	contentRepo := stores.Content
 
 	// 1. Get calendar entry
 	spinner := ui.NewSpinner("Fetching calendar entry...")
 	spinner.Start()
-	entry, err := calendarRepo.GetEntryByID(entryID)
+	entry, err := calendarRepo.GetEntryByID(ctx, entryID)
 	spinner.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to get calendar entry: %w", err)
 	}
 
+	if entry.SubmissionID != nil {
+		return fmt.Errorf("calendar entry was already submitted to %s (submission %s); check it with 'gagipress publish status %s'", social.EntryPublisher(entry), *entry.SubmissionID, entry.ID)
+	}
 	if entry.Status == "published" {
 		ui.Warning("This post has already been published!")
 		return nil
@@ -84,58 +93,56 @@ func runPublish(cmd *cobra.Command, args []string) error {
 	// 2. Get script
 	spinner = ui.NewSpinner("Fetching associated script...")
 	spinner.Start()
-	script, err := contentRepo.GetScriptByID(*entry.ScriptID)
+	script, err := contentRepo.GetScriptByID(ctx, *entry.ScriptID)
 	spinner.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to get script: %w", err)
 	}
 
 	// 3. Build post text
-	var postText strings.Builder
-	postText.WriteString(script.Hook)
-	postText.WriteString("\n\n")
-	postText.WriteString(script.FullScript)
-	postText.WriteString("\n\n")
-	postText.WriteString(script.CTA)
+	text := postText(script)
 
-	if len(script.Hashtags) > 0 {
-		postText.WriteString("\n\n")
-		postText.WriteString(strings.Join(script.Hashtags, " "))
+	// 4. Pick the publisher for the platform
+	publisherName, publisher, err := social.NewPublisherSet(cfg).For(entry.Platform)
+	if err != nil {
+		return err
 	}
+	capabilities := publisher.Capabilities()
 
 	fmt.Printf("\nTarget Platform: %s\n", entry.Platform)
+	fmt.Printf("Publisher: %s\n", publisherName)
 	fmt.Printf("Scheduled For: %s\n", entry.ScheduledFor.Format("2006-01-02 15:04:05"))
 
-	// 4. Initialize Blotato Client
-	blotatoClient := social.NewBlotatoClient(cfg.Blotato.APIKey)
-
-	// 5. Get Account ID for platform
-	spinner = ui.NewSpinner(fmt.Sprintf("Fetching Blotato account ID for %s...", entry.Platform))
-	spinner.Start()
-	accountID, err := blotatoClient.GetAccountID(entry.Platform)
-	spinner.Stop()
-	if err != nil {
-		return fmt.Errorf("failed to get Blotato account ID: %w", err)
+	if !capabilities.Schedule && entry.ScheduledFor.After(time.Now()) && !publishNow {
+		return fmt.Errorf("the %s publisher cannot schedule posts and this entry is due %s; publish it then (e.g. with 'publish batch' from cron) or pass --now to post it right away",
+			publisherName, entry.ScheduledFor.Local().Format("2006-01-02 15:04"))
 	}
-	fmt.Printf("✅ Found Blotato Account ID: %s\n", accountID)
 
-	// 6. Optional: Generate Media
+	// 5. Media: the entry's own, plus an optional Blotato visual
 	var mediaUrls []string
+	if entry.MediaURL != nil {
+		mediaUrls = append(mediaUrls, *entry.MediaURL)
+	}
 	if withMedia {
+		if cfg.Blotato.APIKey == "" {
+			return fmt.Errorf("blotato API key is not configured but --with-media was requested")
+		}
 		if cfg.Blotato.TemplateID == "" {
 			return fmt.Errorf("blotato TemplateID is not configured but --with-media was requested")
 		}
 
 		// Media generation logic
 		// We pass the script as a prompt for the AI template generator
+		blotatoClient := social.NewBlotatoClient(cfg.Blotato.APIKey)
 		spinner = ui.NewSpinner(fmt.Sprintf("Requesting Blotato visual creation (Template %s)...", cfg.Blotato.TemplateID))
 		spinner.Start()
 
 		prompt := fmt.Sprintf("Create a promotional visual for a book post.\nHook: %s\nMain topic: %s", script.Hook, script.FullScript)
-		creationID, err := blotatoClient.GenerateVisual(cfg.Blotato.TemplateID, prompt)
+		creationID, err := blotatoClient.GenerateVisual(ctx, cfg.Blotato.TemplateID, prompt)
 		spinner.Stop()
 
 		if err != nil {
+			recordPublishError(ctx, calendarRepo, entry.ID, "", "media", err)
 			return fmt.Errorf("failed to start visual generation: %w", err)
 		}
 
@@ -143,10 +150,11 @@ func runPublish(cmd *cobra.Command, args []string) error {
 
 		spinner = ui.NewSpinner("Waiting for Blotato visual render...")
 		spinner.Start()
-		mediaURL, err := blotatoClient.WaitForVisualCreation(creationID)
+		mediaURL, err := blotatoClient.WaitForVisualCreation(ctx, creationID)
 		spinner.Stop()
 
 		if err != nil {
+			recordPublishError(ctx, calendarRepo, entry.ID, "", "media", err)
 			return fmt.Errorf("failed during visual generation polling: %w", err)
 		}
 
@@ -154,150 +162,84 @@ func runPublish(cmd *cobra.Command, args []string) error {
 		mediaUrls = append(mediaUrls, mediaURL)
 	}
 
-	// 7. Publish/Schedule Post
-	spinner = ui.NewSpinner("Sending to Blotato...")
+	// 6. Publish/Schedule Post
+	req := &social.PublishRequest{
+		Platform:  entry.Platform,
+		PostType:  entry.PostType,
+		Text:      text,
+		MediaURLs: mediaUrls,
+	}
+	if capabilities.Schedule {
+		req.ScheduledFor = entry.ScheduledFor
+	}
+	if err := capabilities.Check(req); err != nil {
+		return fmt.Errorf("cannot publish with %s: %w", publisherName, err)
+	}
+
+	spinner = ui.NewSpinner(fmt.Sprintf("Sending to %s...", publisherName))
 	spinner.Start()
-	submissionID, err := blotatoClient.PublishPost(accountID, entry.Platform, postText.String(), mediaUrls, &entry.ScheduledFor)
+	status, err := publisher.Publish(ctx, req)
 	spinner.Stop()
 
 	if err != nil {
 		// If it failed, we can mark it as failed in our DB
-		_ = calendarRepo.UpdateEntryStatus(entry.ID, "failed")
-		return fmt.Errorf("blotato publish failed: %w", err)
+		recordPublishError(ctx, calendarRepo, entry.ID, "failed", "submit", err)
+		return fmt.Errorf("%s publish failed: %w", publisherName, err)
 	}
 
-	fmt.Printf("\n✅ Successfully submitted to Blotato!\n")
-	fmt.Printf("Submission ID: %s\n", submissionID)
+	fmt.Printf("\n✅ Successfully submitted to %s!\n", publisherName)
+	fmt.Printf("Submission ID: %s\n", status.PostSubmissionID)
 
-	// 8. Update DB status
-	err = calendarRepo.UpdateEntryStatus(entry.ID, "published")
-	if err != nil {
-		ui.Warning(fmt.Sprintf("Post submitted to Blotato, but failed to update local status: %v", err))
+	// 7. Save the submission and update DB status
+	if err := recordSubmission(ctx, calendarRepo, entry, publisherName, status); err != nil {
+		ui.Warning(fmt.Sprintf("Post submitted to %s, but failed to update local status: %v", publisherName, err))
+		ui.Warning("Do not publish this entry again; the post is already with the publisher.")
+		return nil
+	}
+	if status.Status == social.PostStatusPublished {
+		fmt.Printf("✅ Published: %s\n", status.PublicURL)
 	} else {
-		fmt.Printf("✅ Local status updated to 'published'\n")
+		fmt.Printf("✅ Local status updated to 'submitted'\n")
+		fmt.Printf("Check delivery with: gagipress publish status %s\n", entry.ID)
 	}
 
 	return nil
 }
 
-func runBatchPublish(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-
-	if cfg.Blotato.APIKey == "" {
-		return fmt.Errorf("blotato API key is not configured")
-	}
-
-	fmt.Println(ui.StyleHeader.Render("🚀 Batch Publish Posts via Blotato"))
-
-	calendarRepo := repository.NewCalendarRepository(&cfg.Supabase)
-	contentRepo := repository.NewContentRepository(&cfg.Supabase)
-	blotatoClient := social.NewBlotatoClient(cfg.Blotato.APIKey)
-
-	spinner := ui.NewSpinner("Fetching approved calendar entries...")
-	spinner.Start()
-	entries, err := calendarRepo.GetEntries("approved", batchLimit)
-	spinner.Stop()
+// recordSubmission saves a post the publisher accepted on its entry. The
+// entry is 'submitted' until its delivery is confirmed, which publishers
+// that post right away already report in status.
+func recordSubmission(ctx context.Context, calendarRepo repository.CalendarStore, entry *models.ContentCalendar, publisher string, status *social.PostStatus) error {
+	submissionID := status.PostSubmissionID
+	err := calendarRepo.UpdatePublishState(ctx, entry.ID, &models.PublishUpdate{Status: "submitted", SubmissionID: &submissionID, Publisher: publisher})
 	if err != nil {
-		return fmt.Errorf("failed to get calendar entries: %w", err)
+		return err
 	}
+	entry.Status, entry.SubmissionID, entry.Publisher = "submitted", &submissionID, &publisher
+	return recordPostStatus(ctx, calendarRepo, entry, status)
+}
 
-	if len(entries) == 0 {
-		ui.Success("No approved posts ready to be scheduled/published.")
-		return nil
-	}
-
-	fmt.Printf("Found %d approved posts to process.\n\n", len(entries))
-
-	successCount := 0
-	failedCount := 0
-
-	// Cache Account IDs to avoid multiple API calls
-	accountIDs := make(map[string]string)
-
-	for i, entry := range entries {
-		fmt.Printf("[%d/%d] Submitting entry: %s (Platform: %s)\n", i+1, len(entries), entry.ID[:8], entry.Platform)
-
-		if entry.ScriptID == nil {
-			fmt.Println("❌ Failed: no script attached")
-			failedCount++
-			continue
-		}
-
-		script, err := contentRepo.GetScriptByID(*entry.ScriptID)
-		if err != nil {
-			fmt.Printf("❌ Failed to get script: %v\n", err)
-			failedCount++
-			continue
-		}
-
-		// Account caching
-		if accountIDs[entry.Platform] == "" {
-			accID, err := blotatoClient.GetAccountID(entry.Platform)
-			if err != nil {
-				fmt.Printf("❌ Failed to get Blotato account for %s: %v\n", entry.Platform, err)
-				failedCount++
-				continue
-			}
-			accountIDs[entry.Platform] = accID
-		}
-		accountID := accountIDs[entry.Platform]
-
-		// Build text
-		var postText strings.Builder
-		postText.WriteString(script.Hook)
-		postText.WriteString("\n\n")
-		postText.WriteString(script.FullScript)
-		postText.WriteString("\n\n")
-		postText.WriteString(script.CTA)
-		if len(script.Hashtags) > 0 {
-			postText.WriteString("\n\n")
-			postText.WriteString(strings.Join(script.Hashtags, " "))
-		}
-
-		// Media
-		var mediaUrls []string
-		if withMedia {
-			prompt := fmt.Sprintf("Create a promotional visual for a book post.\nHook: %s\nMain topic: %s", script.Hook, script.FullScript)
-			creationID, err := blotatoClient.GenerateVisual(cfg.Blotato.TemplateID, prompt)
-			if err != nil {
-				fmt.Printf("❌ Failed to request visual: %v\n", err)
-				failedCount++
-				continue
-			}
-			mediaURL, err := blotatoClient.WaitForVisualCreation(creationID)
-			if err != nil {
-				fmt.Printf("❌ Failed to generate visual: %v\n", err)
-				failedCount++
-				continue
-			}
-			mediaUrls = append(mediaUrls, mediaURL)
-			fmt.Printf("   🖼️  Media generated: %s\n", mediaURL)
-		}
-
-		// Submit
-		submissionID, err := blotatoClient.PublishPost(accountID, entry.Platform, postText.String(), mediaUrls, &entry.ScheduledFor)
-		if err != nil {
-			fmt.Printf("❌ Failed to submit to Blotato: %v\n", err)
-			_ = calendarRepo.UpdateEntryStatus(entry.ID, "failed")
-			failedCount++
-			continue
-		}
+// recordPublishError appends a failed attempt at stage to the entry's
+// publish_errors, and sets its status unless status is empty. It is best
+// effort: the original error is what gets reported.
+func recordPublishError(ctx context.Context, calendarRepo repository.CalendarStore, entryID, status, stage string, cause error) {
+	_ = calendarRepo.UpdatePublishState(ctx, entryID, &models.PublishUpdate{
+		Status: status,
+		Error:  &models.PublishError{At: time.Now().UTC(), Stage: stage, Message: cause.Error()},
+	})
+}
 
-		err = calendarRepo.UpdateEntryStatus(entry.ID, "published")
-		if err != nil {
-			fmt.Printf("⚠️ Submitted (ID: %s) but failed to update local status: %v\n", submissionID, err)
-		} else {
-			fmt.Printf("✅ Success (Submission ID: %s)\n", submissionID)
-			successCount++
-		}
+// postText builds the caption of a post from its script
+func postText(script *models.ContentScript) string {
+	var text strings.Builder
+	text.WriteString(script.Hook)
+	text.WriteString("\n\n")
+	text.WriteString(script.FullScript)
+	text.WriteString("\n\n")
+	text.WriteString(script.CTA)
+	if len(script.Hashtags) > 0 {
+		text.WriteString("\n\n")
+		text.WriteString(strings.Join(script.Hashtags, " "))
 	}
-
-	fmt.Println("\n" + strings.Repeat("═", 60))
-	fmt.Printf("Batch publish complete!\n")
-	fmt.Printf("Total: %d | Success: %d | Failed: %d\n", len(entries), successCount, failedCount)
-
-	return nil
+	return text.String()
 }
@@ -0,0 +1,123 @@
+package publish
+
+import (
+	"fmt"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/social"
+	"github.com/gagipress/gagipress-cli/internal/storage"
+	"github.com/gagipress/gagipress-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var reconcileLimit int
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Confirm the delivery of every submitted post",
+	Long: `Check every 'submitted' calendar entry with its publisher and record the outcome.
+
+Posts are 'submitted' once a publisher accepts them. reconcile asks the
+publisher of each one what became of it, like 'publish status' does for a
+single entry:
+  - delivered posts become 'published', with their live post URL
+  - posts the platform rejected become 'failed', with the reason in
+    publish_errors, and can be retried with 'gagipress calendar retry'
+  - posts still queued or scheduled are left for the next run
+
+Run it regularly, e.g. from cron, after 'publish batch'.`,
+	RunE: runReconcile,
+}
+
+func init() {
+	reconcileCmd.Flags().IntVar(&reconcileLimit, "limit", 0, "Maximum number of entries to check (0 for all)")
+	PublishCmd.AddCommand(reconcileCmd)
+}
+
+func runReconcile(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	fmt.Println(ui.StyleHeader.Render("🔄 Reconcile Submitted Posts"))
+
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	calendarRepo := stores.Calendar
+
+	entries, err := repository.Collect(repository.IterEntries(ctx, calendarRepo, "submitted"), reconcileLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get submitted entries: %w", err)
+	}
+	if len(entries) == 0 {
+		ui.Success("No submitted posts awaiting confirmation.")
+		return nil
+	}
+	fmt.Printf("Checking %d submitted posts...\n\n", len(entries))
+
+	publishers := social.NewPublisherSet(cfg)
+
+	var rows [][]string
+	published, failed, waiting, errored := 0, 0, 0, 0
+	for i := range entries {
+		if ctx.Err() != nil {
+			fmt.Println("⚠️  Cancelled, stopping reconcile")
+			break
+		}
+		entry := &entries[i]
+		row := []string{ui.FormatUUID(entry.ID, 8), entry.Platform, entry.ScheduledFor.Local().Format("2006-01-02 15:04")}
+
+		if entry.SubmissionID == nil {
+			errored++
+			rows = append(rows, append(row, "⚠️  error", "no submission ID recorded"))
+			continue
+		}
+
+		publisher, err := publishers.Named(social.EntryPublisher(entry))
+		if err != nil {
+			errored++
+			rows = append(rows, append(row, "⚠️  error", err.Error()))
+			continue
+		}
+		status, err := publisher.Status(ctx, *entry.SubmissionID)
+		if err == nil {
+			err = recordPostStatus(ctx, calendarRepo, entry, status)
+		}
+		if err != nil {
+			errored++
+			rows = append(rows, append(row, "⚠️  error", err.Error()))
+			continue
+		}
+
+		switch status.Status {
+		case social.PostStatusPublished:
+			published++
+			rows = append(rows, append(row, "✅ published", status.PublicURL))
+		case social.PostStatusFailed:
+			failed++
+			rows = append(rows, append(row, "❌ failed", deliveryError(status)))
+		default:
+			waiting++
+			rows = append(rows, append(row, "⏳ "+status.Status, ""))
+		}
+	}
+
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"Entry", "Platform", "Scheduled", "Outcome", "Details"},
+		Rows:     rows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+
+	fmt.Printf("\nPublished: %d | Failed: %d | Still in progress: %d | Errors: %d\n", published, failed, waiting, errored)
+	if failed > 0 {
+		fmt.Println("\nRetry failed posts with: gagipress calendar retry")
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,158 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/social"
+	"github.com/gagipress/gagipress-cli/internal/storage"
+	"github.com/gagipress/gagipress-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status <calendar-entry-id>",
+	Short: "Check the delivery outcome of a submitted post",
+	Long: `Ask the publisher what became of a post submitted for a calendar entry.
+
+A publisher accepting a post only means it was queued: a scheduled post can
+still fail on the platform's side when its time comes. Once the publisher
+reports the outcome it is saved on the entry: it becomes 'published' with the live
+post URL, or 'failed' with a delivery error. A failed delivery releases the
+submission, so the entry can be retried with 'gagipress calendar retry' and
+published again.
+
+To check every submitted entry at once, use 'gagipress publish reconcile'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStatus,
+}
+
+func init() {
+	PublishCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	calendarRepo := stores.Calendar
+
+	entry, err := calendarRepo.GetEntryByID(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get calendar entry: %w", err)
+	}
+
+	fmt.Println(ui.StyleHeader.Render("📡 Post Delivery Status"))
+	fmt.Printf("Entry:         %s\n", entry.ID)
+	fmt.Printf("Platform:      %s\n", entry.Platform)
+	fmt.Printf("Scheduled For: %s\n", entry.ScheduledFor.Local().Format("2006-01-02 15:04"))
+
+	if entry.SubmissionID == nil {
+		fmt.Printf("Status:        %s\n", entry.Status)
+		if n := len(entry.PublishErrors); n > 0 {
+			last := entry.PublishErrors[n-1]
+			fmt.Printf("Last Error:    [%s] %s (%s)\n", last.Stage, last.Message, last.At.Local().Format("2006-01-02 15:04"))
+		}
+		fmt.Println()
+		ui.Info("This entry has no submission to check.")
+		return nil
+	}
+	publisherName := social.EntryPublisher(entry)
+	fmt.Printf("Publisher:     %s\n", publisherName)
+	fmt.Printf("Submission ID: %s\n\n", *entry.SubmissionID)
+
+	publisher, err := social.NewPublisherSet(cfg).Named(publisherName)
+	if err != nil {
+		return err
+	}
+
+	spinner := ui.NewSpinner(fmt.Sprintf("Checking with %s...", publisherName))
+	spinner.Start()
+	status, err := publisher.Status(ctx, *entry.SubmissionID)
+	spinner.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to get post status: %w", err)
+	}
+
+	if err := recordPostStatus(ctx, calendarRepo, entry, status); err != nil {
+		return err
+	}
+
+	switch status.Status {
+	case social.PostStatusPublished:
+		ui.Success("Published")
+		if status.PublicURL != "" {
+			fmt.Printf("Post URL: %s\n", status.PublicURL)
+		}
+	case social.PostStatusFailed:
+		ui.Error(fmt.Sprintf("Delivery failed: %s", deliveryError(status)))
+		fmt.Println("\nThe entry is marked failed. Retry it with 'gagipress calendar retry'.")
+	default:
+		ui.Info(fmt.Sprintf("Still %s at %s; check again later.", status.Status, publisherName))
+	}
+
+	return nil
+}
+
+// recordPostStatus saves the delivery outcome a publisher reported for a
+// submitted entry: the live URL once published, or a delivery error once
+// failed. A failed delivery also clears the submission, so the entry can be
+// submitted again. Posts still in progress leave the entry unchanged.
+func recordPostStatus(ctx context.Context, calendarRepo repository.CalendarStore, entry *models.ContentCalendar, status *social.PostStatus) error {
+	var update *models.PublishUpdate
+	switch status.Status {
+	case social.PostStatusPublished:
+		update = &models.PublishUpdate{Status: "published"}
+		if status.PublicURL != "" {
+			update.PostURL = &status.PublicURL
+		}
+		// Publishers do not say when the post went out; a scheduled post
+		// goes out at its slot, anything else about now
+		if entry.PublishedAt == nil {
+			at := time.Now()
+			if entry.ScheduledFor.Before(at) {
+				at = entry.ScheduledFor
+			}
+			update.PublishedAt = &at
+		}
+	case social.PostStatusFailed:
+		none := ""
+		update = &models.PublishUpdate{
+			Status:       "failed",
+			SubmissionID: &none,
+			Error: &models.PublishError{
+				At:           time.Now().UTC(),
+				Stage:        "delivery",
+				Message:      deliveryError(status),
+				SubmissionID: *entry.SubmissionID,
+			},
+		}
+	default:
+		return nil
+	}
+
+	if err := calendarRepo.UpdatePublishState(ctx, entry.ID, update); err != nil {
+		return fmt.Errorf("failed to save post status: %w", err)
+	}
+	return nil
+}
+
+// deliveryError returns the reason the publisher gave for a failed post
+func deliveryError(status *social.PostStatus) string {
+	if status.ErrorMessage == "" {
+		return "the platform rejected the post (no reason given)"
+	}
+	return status.ErrorMessage
+}
@@ -1,15 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gagipress/gagipress-cli/cmd/auth"
 	"github.com/gagipress/gagipress-cli/cmd/books"
 	"github.com/gagipress/gagipress-cli/cmd/calendar"
+	"github.com/gagipress/gagipress-cli/cmd/campaign"
 	"github.com/gagipress/gagipress-cli/cmd/db"
 	"github.com/gagipress/gagipress-cli/cmd/generate"
 	"github.com/gagipress/gagipress-cli/cmd/ideas"
+	"github.com/gagipress/gagipress-cli/cmd/jobs"
 	"github.com/gagipress/gagipress-cli/cmd/publish"
 	"github.com/gagipress/gagipress-cli/cmd/stats"
 	"github.com/gagipress/gagipress-cli/cmd/test"
@@ -17,7 +24,11 @@ import (
 	"github.com/spf13/viper"
 )
 
-var cfgFile string
+var (
+	cfgFile       string
+	timeout       time.Duration
+	cancelTimeout context.CancelFunc = func() {}
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -32,22 +43,48 @@ Features:
   • Automated publishing via cron jobs
   • Performance analytics with KDP sales correlation
   • Self-hosted with minimal recurring costs`,
+	PersistentPreRunE: applyTimeout,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// Ctrl-C and SIGTERM cancel the command context so in-flight requests stop cleanly.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	cancelTimeout()
+	stop()
+
+	if err != nil {
+		switch {
+		case errors.Is(err, context.Canceled):
+			fmt.Fprintln(os.Stderr, "Interrupted")
+		case errors.Is(err, context.DeadlineExceeded):
+			fmt.Fprintf(os.Stderr, "Timed out after %s\n", timeout)
+		default:
+			fmt.Fprintln(os.Stderr, err)
+		}
 		os.Exit(1)
 	}
 }
 
+// applyTimeout bounds the command context by --timeout when it is set.
+func applyTimeout(cmd *cobra.Command, args []string) error {
+	if timeout <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	cancelTimeout = cancel
+	cmd.SetContext(ctx)
+	return nil
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.gagipress/config.yaml)")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "abort the command after this long (e.g. 30s, 5m; 0 = no limit)")
 
 	// Add subcommands
 	rootCmd.AddCommand(db.DbCmd)
@@ -57,8 +94,10 @@ func init() {
 	rootCmd.AddCommand(generate.GenerateCmd)
 	rootCmd.AddCommand(ideas.IdeasCmd)
 	rootCmd.AddCommand(calendar.CalendarCmd)
+	rootCmd.AddCommand(campaign.CampaignCmd)
 	rootCmd.AddCommand(stats.StatsCmd)
 	rootCmd.AddCommand(publish.PublishCmd)
+	rootCmd.AddCommand(jobs.JobsCmd)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -0,0 +1,267 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
+	"github.com/gagipress/gagipress-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var costsDays int
+
+var aiCostsCmd = &cobra.Command{
+	Use:   "ai-costs",
+	Short: "Show AI generation spend",
+	Long: `Show what AI generation cost, from the ai_usage ledger.
+
+Spend is broken down per day, per book and per content type. For each book
+and content type it is compared with the posts published and, per book, with
+the KDP royalty earned in the same period.
+
+Costs are estimates from the price table (built-in list prices, overridable
+with ai.prices in the config). Calls to models without a price count as free
+and are reported separately.`,
+	RunE: runAICosts,
+}
+
+func init() {
+	aiCostsCmd.Flags().IntVar(&costsDays, "days", 30, "Days to analyze (0 for all time)")
+}
+
+// costBucket accumulates spend for one row of a breakdown
+type costBucket struct {
+	calls     int
+	tokens    int
+	cost      float64
+	published int
+	royalty   float64
+}
+
+func runAICosts(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+
+	var from time.Time
+	to := time.Now()
+	if costsDays > 0 {
+		from = to.AddDate(0, 0, -costsDays)
+	}
+
+	fmt.Println(ui.StyleHeader.Render("💸 AI Generation Costs"))
+	if costsDays > 0 {
+		fmt.Printf("Period: Last %d days\n\n", costsDays)
+	} else {
+		fmt.Printf("Period: All time\n\n")
+	}
+
+	usage, err := stores.Usage.GetUsage(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get AI usage: %w", err)
+	}
+	if len(usage) == 0 {
+		fmt.Println("⚠️  No AI usage recorded for this period.")
+		fmt.Println("\nUsage is recorded by 'gagipress generate ideas|script|batch'.")
+		return nil
+	}
+
+	books, err := stores.Books.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get books: %w", err)
+	}
+	titles := make(map[string]string, len(books))
+	for _, b := range books {
+		titles[b.ID] = b.Title
+	}
+
+	// Published posts are attributed through script → idea → book
+	ideas, err := repository.Collect(repository.IterIdeas(ctx, stores.Content, "", ""), 0)
+	if err != nil {
+		return fmt.Errorf("failed to get ideas: %w", err)
+	}
+	ideaByID := make(map[string]models.ContentIdea, len(ideas))
+	for _, idea := range ideas {
+		ideaByID[idea.ID] = idea
+	}
+	scripts, err := repository.Collect(repository.IterScripts(ctx, stores.Content), 0)
+	if err != nil {
+		return fmt.Errorf("failed to get scripts: %w", err)
+	}
+	ideaOfScript := make(map[string]string, len(scripts))
+	for _, s := range scripts {
+		ideaOfScript[s.ID] = s.IdeaID
+	}
+	published, err := repository.Collect(repository.IterEntries(ctx, stores.Calendar, "published"), 0)
+	if err != nil {
+		return fmt.Errorf("failed to get published posts: %w", err)
+	}
+
+	sales, err := stores.Sales.GetAllSales(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get sales: %w", err)
+	}
+
+	var total costBucket
+	unpriced := 0
+	byDay := make(map[string]*costBucket)
+	byBook := make(map[string]*costBucket)
+	byType := make(map[string]*costBucket)
+	bucket := func(m map[string]*costBucket, key string) *costBucket {
+		if m[key] == nil {
+			m[key] = &costBucket{}
+		}
+		return m[key]
+	}
+
+	for _, u := range usage {
+		bookKey := "(no book)"
+		if u.BookID != nil {
+			bookKey = *u.BookID
+		}
+		typeKey := "(idea generation)"
+		if u.ContentType != nil {
+			typeKey = *u.ContentType
+		}
+		for _, b := range []*costBucket{&total, bucket(byDay, u.CreatedAt.Local().Format("2006-01-02")), bucket(byBook, bookKey), bucket(byType, typeKey)} {
+			b.calls++
+			b.tokens += u.TotalTokens()
+			b.cost += u.CostUSD
+		}
+		if u.CostUSD == 0 && u.Provider != "ollama" && u.Provider != "llamacpp" {
+			unpriced++
+		}
+	}
+
+	for _, entry := range published {
+		at := entry.ScheduledFor
+		if entry.PublishedAt != nil {
+			at = *entry.PublishedAt
+		}
+		if at.Before(from) || at.After(to) || entry.ScriptID == nil {
+			continue
+		}
+		idea, ok := ideaByID[ideaOfScript[*entry.ScriptID]]
+		if !ok {
+			continue
+		}
+		total.published++
+		bucket(byType, idea.Type).published++
+		if idea.BookID != nil {
+			bucket(byBook, *idea.BookID).published++
+		}
+	}
+
+	for _, sale := range sales {
+		total.royalty += sale.Royalty
+		bucket(byBook, sale.BookID).royalty += sale.Royalty
+	}
+
+	fmt.Printf("Total spend:     $%.4f\n", total.cost)
+	fmt.Printf("AI calls:        %s (%s tokens)\n", ui.FormatNumber(total.calls), ui.FormatNumber(total.tokens))
+	fmt.Printf("Posts published: %d", total.published)
+	if total.published > 0 {
+		fmt.Printf(" ($%.4f per post)", total.cost/float64(total.published))
+	}
+	fmt.Printf("\nKDP royalty:     $%.2f\n", total.royalty)
+	if unpriced > 0 {
+		ui.Warning(fmt.Sprintf("%d call(s) used models without a price; add them to ai.prices", unpriced))
+	}
+	fmt.Println()
+
+	// Per day, oldest first
+	var dayRows [][]string
+	for _, day := range sortedKeys(byDay) {
+		b := byDay[day]
+		dayRows = append(dayRows, []string{day, fmt.Sprint(b.calls), ui.FormatNumber(b.tokens), fmt.Sprintf("$%.4f", b.cost)})
+	}
+	fmt.Println(ui.StyleHeader.Render("📅 Per Day"))
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"Date", "Calls", "Tokens", "Cost"},
+		Rows:     dayRows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+	fmt.Println()
+
+	// Per book, most expensive first
+	var bookRows [][]string
+	for _, id := range keysByCost(byBook) {
+		b := byBook[id]
+		if b.calls == 0 {
+			continue // sales only
+		}
+		title := titles[id]
+		if title == "" {
+			title = id
+		}
+		share := "-"
+		if b.royalty > 0 {
+			share = fmt.Sprintf("%.2f%%", b.cost/b.royalty*100)
+		}
+		bookRows = append(bookRows, []string{title, fmt.Sprint(b.calls), fmt.Sprintf("$%.4f", b.cost),
+			fmt.Sprint(b.published), perPost(b), fmt.Sprintf("$%.2f", b.royalty), share})
+	}
+	fmt.Println(ui.StyleHeader.Render("📚 Per Book"))
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"Book", "Calls", "Cost", "Published", "Cost/Post", "Royalty", "Cost/Royalty"},
+		Rows:     bookRows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+	fmt.Println()
+
+	// Per content type, most expensive first
+	var typeRows [][]string
+	for _, kind := range keysByCost(byType) {
+		b := byType[kind]
+		typeRows = append(typeRows, []string{kind, fmt.Sprint(b.calls), fmt.Sprintf("$%.4f", b.cost),
+			fmt.Sprint(b.published), perPost(b)})
+	}
+	fmt.Println(ui.StyleHeader.Render("🏷️  Per Content Type"))
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"Type", "Calls", "Cost", "Published", "Cost/Post"},
+		Rows:     typeRows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+
+	return nil
+}
+
+// perPost formats the spend per published post of a bucket
+func perPost(b *costBucket) string {
+	if b.published == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("$%.4f", b.cost/float64(b.published))
+}
+
+func sortedKeys(m map[string]*costBucket) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// keysByCost orders keys by descending cost, then by name
+func keysByCost(m map[string]*costBucket) []string {
+	keys := sortedKeys(m)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return m[keys[i]].cost > m[keys[j]].cost
+	})
+	return keys
+}
@@ -0,0 +1,180 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/social"
+	"github.com/gagipress/gagipress-cli/internal/storage"
+	"github.com/gagipress/gagipress-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	collectDays     int
+	collectLimit    int
+	collectPlatform string
+)
+
+var collectCmd = &cobra.Command{
+	Use:   "collect",
+	Short: "Record the current metrics of published posts",
+	Long: `Fetch views, likes, comments, shares and saves of published posts and
+record them as a snapshot in post_metrics.
+
+Each post is read through the publisher that posted it. Posts that went
+through Blotato, which reports no metrics, are read with the platform's
+native publisher (instagram, tiktok or bluesky) when it is set up, by their
+post URL. Counts a platform does not report are recorded as zero.
+
+Every run adds a snapshot per post, so metrics build up a time series;
+'stats show' uses the latest snapshot of each post. Run it daily, e.g. from
+cron. Posts published more than --days ago are left alone, as their counts
+have mostly settled.`,
+	RunE: runCollect,
+}
+
+func init() {
+	collectCmd.Flags().IntVar(&collectDays, "days", 30, "Collect posts published in the last N days (0 for all)")
+	collectCmd.Flags().IntVar(&collectLimit, "limit", 0, "Maximum number of posts to collect (0 for all)")
+	collectCmd.Flags().StringVar(&collectPlatform, "platform", "", "Only collect posts on this platform")
+}
+
+func runCollect(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	fmt.Println(ui.StyleHeader.Render("📥 Collect Post Metrics"))
+
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+
+	var since time.Time
+	if collectDays > 0 {
+		since = time.Now().AddDate(0, 0, -collectDays)
+	}
+	var entries []models.ContentCalendar
+	for entry, err := range repository.IterEntries(ctx, stores.Calendar, "published") {
+		if err != nil {
+			return fmt.Errorf("failed to get published entries: %w", err)
+		}
+		if collectPlatform != "" && entry.Platform != collectPlatform {
+			continue
+		}
+		if !since.IsZero() && publishedAt(&entry).Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+		if collectLimit > 0 && len(entries) >= collectLimit {
+			break
+		}
+	}
+	if len(entries) == 0 {
+		ui.Success("No published posts to collect metrics for.")
+		return nil
+	}
+	fmt.Printf("Collecting metrics of %d published posts...\n\n", len(entries))
+
+	publishers := social.NewPublisherSet(cfg)
+
+	var rows [][]string
+	collected, skipped, errored := 0, 0, 0
+	for i := range entries {
+		if ctx.Err() != nil {
+			fmt.Println("⚠️  Cancelled, stopping collection")
+			break
+		}
+		entry := &entries[i]
+		row := []string{ui.FormatUUID(entry.ID, 8), entry.Platform}
+
+		metric, err := collectMetric(ctx, stores.Metrics, publishers, entry)
+		switch {
+		case err != nil:
+			errored++
+			rows = append(rows, append(row, "", "", "", "", "⚠️  "+err.Error()))
+		case metric == nil:
+			skipped++
+			rows = append(rows, append(row, "", "", "", "", "skipped: "+social.EntryPublisher(entry)+" reports no metrics"))
+		default:
+			collected++
+			rows = append(rows, append(row,
+				ui.FormatNumber(metric.Views),
+				ui.FormatNumber(metric.Likes),
+				ui.FormatNumber(metric.Comments),
+				ui.FormatNumber(metric.Shares),
+				fmt.Sprintf("%.2f%%", metric.EngagementRate),
+			))
+		}
+	}
+
+	fmt.Println(ui.RenderTable(ui.TableConfig{
+		Headers:  []string{"Entry", "Platform", "Views", "Likes", "Comments", "Shares", "Engagement"},
+		Rows:     rows,
+		MaxWidth: ui.GetTerminalWidth(),
+	}))
+
+	fmt.Printf("\nCollected: %d | Skipped: %d | Errors: %d\n", collected, skipped, errored)
+	if skipped > 0 {
+		fmt.Println("\nSet up the native publisher of skipped platforms with 'gagipress auth' to read their metrics.")
+	}
+
+	return ctx.Err()
+}
+
+// collectMetric reads the metrics of a published entry and records them as
+// a snapshot. It returns nil without an error if no publisher can read
+// them.
+func collectMetric(ctx context.Context, metricsRepo repository.MetricsStore, publishers *social.PublisherSet, entry *models.ContentCalendar) (*models.PostMetric, error) {
+	reader, byURL, err := publishers.MetricsReader(social.EntryPublisher(entry), entry.Platform)
+	if err != nil || reader == nil {
+		return nil, err
+	}
+
+	post := &social.PublishedPost{}
+	if entry.PostURL != nil {
+		post.URL = *entry.PostURL
+	}
+	if !byURL && entry.SubmissionID != nil {
+		post.SubmissionID = *entry.SubmissionID
+	}
+	if post.SubmissionID == "" && post.URL == "" {
+		return nil, fmt.Errorf("no submission ID or post URL recorded")
+	}
+
+	counts, err := reader.PostMetrics(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	input := &models.PostMetricInput{
+		CalendarID: entry.ID,
+		Platform:   entry.Platform,
+		Views:      counts.Views,
+		Likes:      counts.Likes,
+		Comments:   counts.Comments,
+		Shares:     counts.Shares,
+		Saves:      counts.Saves,
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	return metricsRepo.CreateMetric(ctx, input)
+}
+
+// publishedAt returns when an entry went live, or its scheduled time for
+// entries published before that was recorded
+func publishedAt(entry *models.ContentCalendar) time.Time {
+	if entry.PublishedAt != nil {
+		return *entry.PublishedAt
+	}
+	return entry.ScheduledFor
+}
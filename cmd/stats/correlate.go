@@ -3,13 +3,15 @@ package stats
 import (
 	"fmt"
 	"math"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -41,6 +43,8 @@ func init() {
 }
 
 func runCorrelate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -51,8 +55,12 @@ func runCorrelate(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Get book info
-	booksRepo := repository.NewBooksRepository(&cfg.Supabase)
-	book, err := booksRepo.GetByID(bookID)
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	booksRepo := stores.Books
+	book, err := booksRepo.GetByID(ctx, bookID)
 	if err != nil {
 		return fmt.Errorf("failed to get book: %w", err)
 	}
@@ -65,8 +73,8 @@ func runCorrelate(cmd *cobra.Command, args []string) error {
 	from := to.AddDate(0, 0, -days)
 
 	// Get sales data
-	salesRepo := repository.NewSalesRepository(&cfg.Supabase)
-	sales, err := salesRepo.GetSalesByBook(bookID, from, to)
+	salesRepo := stores.Sales
+	sales, err := salesRepo.GetSalesByBook(ctx, bookID, from, to)
 	if err != nil {
 		return fmt.Errorf("failed to get sales: %w", err)
 	}
@@ -78,8 +86,8 @@ func runCorrelate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get metrics data
-	metricsRepo := repository.NewMetricsRepository(&cfg.Supabase)
-	metrics, err := metricsRepo.GetMetrics("", from, to)
+	metricsRepo := stores.Metrics
+	metrics, err := metricsRepo.GetMetrics(ctx, "", from, to)
 	if err != nil {
 		return fmt.Errorf("failed to get metrics: %w", err)
 	}
@@ -87,6 +95,7 @@ func runCorrelate(cmd *cobra.Command, args []string) error {
 	if len(metrics) == 0 {
 		fmt.Println("⚠️  No social metrics available for this period.")
 		fmt.Println("\nNote: Correlation requires both sales and social metrics data.")
+		fmt.Println("Collect metrics with: gagipress stats collect")
 		return nil
 	}
 
@@ -210,16 +219,21 @@ func aggregateByDay(sales []models.BookSale, metrics []models.PostMetric) []mode
 		dataMap[dateKey].Royalty += sale.Royalty
 	}
 
-	// Add metrics
-	for _, metric := range metrics {
+	// Add metrics. Snapshots hold running totals, so a day gets the views
+	// each post gained since its previous snapshot.
+	sorted := slices.Clone(metrics)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CollectedAt.Before(sorted[j].CollectedAt) })
+	lastViews := make(map[string]int)
+	for _, metric := range sorted {
 		dateKey := metric.CollectedAt.Format("2006-01-02")
 		if _, ok := dataMap[dateKey]; !ok {
 			dataMap[dateKey] = &models.CorrelationPoint{
 				Date: metric.CollectedAt,
 			}
 		}
-		dataMap[dateKey].Views += metric.Views
+		dataMap[dateKey].Views += max(metric.Views-lastViews[metric.CalendarID], 0)
 		dataMap[dateKey].Engagement += metric.EngagementRate
+		lastViews[metric.CalendarID] = metric.Views
 	}
 
 	// Convert to slice
@@ -8,6 +8,7 @@ import (
 	"github.com/gagipress/gagipress-cli/internal/models"
 	"github.com/gagipress/gagipress-cli/internal/parser"
 	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -22,7 +23,10 @@ Required columns in the CSV:
   - ASIN
   - Royalty
   - Units Sold
-  - Date`,
+  - Date
+
+Rows for the same book and day are summed. Re-importing a report updates
+changed days and reports unchanged ones instead of failing.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runImport,
 }
@@ -32,6 +36,8 @@ func init() {
 }
 
 func runImport(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	csvPath := args[0]
 
 	// Load configuration
@@ -67,50 +73,72 @@ func runImport(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("✅ Parsed %d rows of sales data.\n\n", len(rows))
 
-	salesRepo := repository.NewSalesRepository(&cfg.Supabase)
-	booksRepo := repository.NewBooksRepository(&cfg.Supabase)
-
-	spinner = ui.NewSpinner("Saving sales data to database...")
-	spinner.Start()
-
-	successCount := 0
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
 
 	// Cache ASIN to BookID mapping
 	asinMap := make(map[string]string)
-	books, err := booksRepo.GetAll()
-	if err == nil {
-		for _, b := range books {
-			if b.KDPASIN != "" {
-				asinMap[b.KDPASIN] = b.ID
-			}
+	titles := make(map[string]string)
+	books, err := stores.Books.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get books: %w", err)
+	}
+	for _, b := range books {
+		titles[b.ID] = b.Title
+		if b.KDPASIN != "" {
+			asinMap[b.KDPASIN] = b.ID
 		}
 	}
 
+	var inputs []*models.BookSaleInput
+	unmatched := make(map[string]bool)
 	for _, row := range rows {
 		bookID := asinMap[row.ASIN]
 		if bookID == "" {
 			// Book not found in our catalog, skip
+			unmatched[row.ASIN] = true
 			continue
 		}
 
-		input := &models.BookSaleInput{
+		inputs = append(inputs, &models.BookSaleInput{
 			BookID:    bookID,
 			SaleDate:  models.Date{Time: row.OrderDate},
 			UnitsSold: row.UnitsSold,
 			Royalty:   row.Royalty,
 			PageReads: row.PageReads,
-		}
+		})
+	}
+	// One record per book per day: marketplace rows are summed
+	inputs = models.MergeDailySales(inputs)
 
-		_, err = salesRepo.CreateSale(input)
-		if err == nil {
-			successCount++
+	spinner = ui.NewSpinner(fmt.Sprintf("Saving %d daily sales records...", len(inputs)))
+	spinner.Start()
+	results, err := stores.Sales.UpsertSales(ctx, inputs)
+	spinner.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to save sales: %w", err)
+	}
+
+	for _, r := range results {
+		if r.Status == repository.RowFailed {
+			in := inputs[r.Index]
+			fmt.Printf("❌ %s on %s: %v\n", titles[in.BookID], in.SaleDate.Format(models.DateFormat), r.Err)
 		}
 	}
-	spinner.Stop()
+	summary := repository.Summarize(results)
 
-	fmt.Printf("✅ Imported %d new sales records.\n", successCount)
+	fmt.Printf("✅ Imported sales: %d new, %d updated, %d unchanged, %d failed.\n",
+		summary.Created, summary.Updated, summary.Duplicate, summary.Failed)
+	if len(unmatched) > 0 {
+		ui.Warning(fmt.Sprintf("%d ASINs in the report are not in your catalog and were skipped.", len(unmatched)))
+	}
 	fmt.Println("\nNext steps:")
 	fmt.Println("  • Run correlation analysis: gagipress stats correlate")
 
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d sales records failed to import", summary.Failed)
+	}
 	return nil
 }
@@ -2,11 +2,13 @@ package stats
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gagipress/gagipress-cli/internal/config"
-	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/storage"
 	"github.com/gagipress/gagipress-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -32,10 +34,12 @@ Shows:
 
 func init() {
 	showCmd.Flags().StringVar(&period, "period", "30d", "Time period (7d, 30d, 90d, all)")
-	showCmd.Flags().StringVar(&platform, "platform", "", "Filter by platform (instagram, tiktok)")
+	showCmd.Flags().StringVar(&platform, "platform", "", "Filter by platform (instagram, tiktok, youtube, ...)")
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -66,15 +70,19 @@ func runShow(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Period: %s\n\n", period)
 
 	// Get metrics
-	metricsRepo := repository.NewMetricsRepository(&cfg.Supabase)
-	agg, err := metricsRepo.GetAggregateMetrics(platform, from, time.Now())
+	stores, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	metricsRepo := stores.Metrics
+	agg, err := metricsRepo.GetAggregateMetrics(ctx, platform, from, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to get metrics: %w", err)
 	}
 
 	if agg.TotalPosts == 0 {
 		fmt.Println("No metrics data available.")
-		fmt.Println("\nAdd metrics manually or sync from social platforms.")
+		fmt.Println("\nCollect them from the publishers with: gagipress stats collect")
 		return nil
 	}
 
@@ -126,18 +134,20 @@ func runShow(cmd *cobra.Command, args []string) error {
 
 	// Platform breakdown
 	if platform == "" {
-		// Get TikTok metrics
-		tiktokAgg, _ := metricsRepo.GetAggregateMetrics("tiktok", from, time.Now())
-
-		// Get Instagram metrics
-		igAgg, _ := metricsRepo.GetAggregateMetrics("instagram", from, time.Now())
-
-		platformContent := fmt.Sprintf(
-			"TikTok:    %d posts | %.2f%% avg engagement\n"+
-				"Instagram: %d posts | %.2f%% avg engagement",
-			tiktokAgg.TotalPosts, tiktokAgg.AvgEngagement,
-			igAgg.TotalPosts, igAgg.AvgEngagement,
-		)
+		// Every registered platform that has posts in the period
+		var lines []string
+		for _, name := range models.Platforms() {
+			platformAgg, err := metricsRepo.GetAggregateMetrics(ctx, name, from, time.Now())
+			if err != nil || platformAgg.TotalPosts == 0 {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%-20s %d posts | %.2f%% avg engagement",
+				models.PlatformLabel(name)+":", platformAgg.TotalPosts, platformAgg.AvgEngagement))
+		}
+		platformContent := "No posts in this period"
+		if len(lines) > 0 {
+			platformContent = strings.Join(lines, "\n")
+		}
 
 		fmt.Println(ui.StyleHeader.Render("📱 Platform Breakdown"))
 		fmt.Println(sectionStyle.Render(platformContent))
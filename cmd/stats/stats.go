@@ -9,13 +9,17 @@ var StatsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "View analytics and performance statistics",
 	Long: `View performance analytics and insights:
+  - Metrics collection from the publishers
   - Social media metrics dashboard
   - Sales data visualization
   - Social → Sales correlation analysis
+  - AI generation spend
   - Performance trends`,
 }
 
 func init() {
 	StatsCmd.AddCommand(showCmd)
 	StatsCmd.AddCommand(correlateCmd)
+	StatsCmd.AddCommand(aiCostsCmd)
+	StatsCmd.AddCommand(collectCmd)
 }
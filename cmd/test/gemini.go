@@ -4,47 +4,57 @@ import (
 	"fmt"
 
 	"github.com/gagipress/gagipress-cli/internal/ai"
+	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/spf13/cobra"
 )
 
 var (
-	headless bool
+	jsonOutput bool
 )
 
 var geminiCmd = &cobra.Command{
 	Use:   "gemini [prompt]",
-	Short: "Test Gemini browser automation",
-	Long: `Test Gemini browser automation by sending a prompt and retrieving the response.
+	Short: "Test the Gemini API text provider",
+	Long: `Test the Gemini API text provider by sending a prompt and printing the response.
+
+Uses gemini.api_key from the config (or the GEMINI_API_KEY environment variable)
+and gemini.model (default gemini-2.5-flash).
 
 Example:
   gagipress test gemini "Ciao, come stai?"
-  gagipress test gemini --headless=false "Scrivi una storia breve"`,
+  gagipress test gemini --json "Elenca tre idee per un reel, in JSON"`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runGeminiTest,
 }
 
 func init() {
-	geminiCmd.Flags().BoolVar(&headless, "headless", true, "Run browser in headless mode")
+	geminiCmd.Flags().BoolVar(&jsonOutput, "json", false, "Request structured JSON output")
 }
 
 func runGeminiTest(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	prompt := args[0]
 
-	fmt.Println("🤖 Testing Gemini browser automation...")
-	fmt.Printf("   Prompt: %s\n", prompt)
-	fmt.Printf("   Headless: %v\n", headless)
-	fmt.Println()
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
 
-	// Create Gemini client
-	client := ai.NewGeminiClient(headless)
+	client := ai.NewGeminiClient(&cfg.Gemini)
 
-	// Send prompt
-	fmt.Println("🌐 Launching browser...")
-	fmt.Println("📝 Sending prompt to Gemini...")
-	fmt.Println("⏳ Waiting for response (this may take a few seconds)...")
+	fmt.Println("🤖 Testing Gemini API...")
+	fmt.Printf("   Model: %s\n", client.Model())
+	fmt.Printf("   Prompt: %s\n", prompt)
+	fmt.Printf("   JSON: %v\n", jsonOutput)
 	fmt.Println()
 
-	response, err := client.GenerateText(prompt)
+	req := ai.Prompt(prompt, 0.7)
+	req.JSON = jsonOutput
+
+	fmt.Println("⏳ Waiting for response...")
+	resp, err := client.Complete(ctx, req)
 	if err != nil {
 		return fmt.Errorf("Gemini test failed: %w", err)
 	}
@@ -52,8 +62,9 @@ func runGeminiTest(cmd *cobra.Command, args []string) error {
 	fmt.Println("✅ Response received!")
 	fmt.Println()
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println(response)
+	fmt.Println(resp.Text)
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("Model: %s | Tokens: %d in, %d out\n", resp.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
 
 	return nil
 }
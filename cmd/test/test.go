@@ -8,7 +8,7 @@ import (
 var TestCmd = &cobra.Command{
 	Use:   "test",
 	Short: "Test various integrations and features",
-	Long:  `Test command group for testing browser automation, APIs, and other features.`,
+	Long:  `Test command group for testing AI providers, APIs, and other features.`,
 }
 
 func init() {
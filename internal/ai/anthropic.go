@@ -0,0 +1,179 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+)
+
+// anthropicVersion is the Messages API version sent with every request
+const anthropicVersion = "2023-06-01"
+
+// AnthropicClient talks to the Anthropic Messages API or a compatible gateway
+type AnthropicClient struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+type anthropicRequest struct {
+	Model       string        `json:"model"`
+	System      string        `json:"system,omitempty"`
+	Messages    []ChatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature float64       `json:"temperature"`
+}
+
+type anthropicResponse struct {
+	Model   string `json:"model"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+type anthropicError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewAnthropicClient creates a new Anthropic client
+func NewAnthropicClient(cfg *config.LLMEndpointConfig) *AnthropicClient {
+	model := cfg.Model
+	if model == "" {
+		model = "claude-3-5-haiku-latest"
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com"
+	}
+
+	return &AnthropicClient{
+		apiKey:  cfg.APIKey,
+		model:   model,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+// Name returns "anthropic".
+func (c *AnthropicClient) Name() string {
+	return ProviderAnthropic
+}
+
+// Model returns the model requests are sent to.
+func (c *AnthropicClient) Model() string {
+	return c.model
+}
+
+// Complete implements Provider. System messages in req.Messages are moved to
+// the top-level system prompt, which is where the Messages API expects them.
+func (c *AnthropicClient) Complete(ctx context.Context, req Request) (*Response, error) {
+	var system []string
+	if req.System != "" {
+		system = append(system, req.System)
+	}
+	var messages []ChatMessage
+	for _, m := range req.Messages {
+		if m.Role == "system" {
+			system = append(system, m.Content)
+			continue
+		}
+		messages = append(messages, m)
+	}
+	if req.wantsJSON() {
+		system = append(system, req.schemaInstruction())
+	}
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = DefaultMaxTokens
+	}
+
+	jsonData, err := json.Marshal(anthropicRequest{
+		Model:       c.model,
+		System:      strings.Join(system, "\n\n"),
+		Messages:    messages,
+		MaxTokens:   maxTokens,
+		Temperature: req.Temperature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		httpErr := &HTTPError{
+			StatusCode: httpResp.StatusCode,
+			Message:    string(body),
+			RetryAfter: parseRetryAfter(httpResp.Header, time.Now()),
+		}
+		var errResp anthropicError
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Message != "" {
+			httpErr.Message = errResp.Error.Message
+		}
+		return nil, httpErr
+	}
+
+	var resp anthropicResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return nil, fmt.Errorf("no response from Anthropic")
+	}
+
+	model := resp.Model
+	if model == "" {
+		model = c.model
+	}
+	return &Response{
+		Text:     text.String(),
+		Provider: ProviderAnthropic,
+		Model:    model,
+		Usage: Usage{
+			PromptTokens:     resp.Usage.InputTokens,
+			CompletionTokens: resp.Usage.OutputTokens,
+		},
+	}, nil
+}
@@ -2,83 +2,164 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
-	"github.com/chromedp/chromedp"
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"google.golang.org/genai"
 )
 
-// GeminiClient handles browser automation for Gemini
+// GeminiClient generates text with the Gemini API
 type GeminiClient struct {
-	headless bool
+	apiKey  string
+	model   string
+	baseURL string // overrides the API endpoint, for tests
+
+	once   sync.Once
+	client *genai.Client
+	err    error
 }
 
-// NewGeminiClient creates a new Gemini browser automation client
-func NewGeminiClient(headless bool) *GeminiClient {
+// NewGeminiClient creates a new Gemini API client. An empty gemini.api_key
+// falls back to the GEMINI_API_KEY or GOOGLE_API_KEY environment variables.
+func NewGeminiClient(cfg *config.GeminiConfig) *GeminiClient {
+	model := cfg.Model
+	if model == "" {
+		model = "gemini-2.5-flash"
+	}
+
 	return &GeminiClient{
-		headless: headless,
+		apiKey: cfg.APIKey,
+		model:  model,
 	}
 }
 
-// GenerateText sends a prompt to Gemini and retrieves the response
-func (g *GeminiClient) GenerateText(prompt string) (string, error) {
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
-	defer cancel()
-
-	// Setup browser options
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", g.headless),
-		chromedp.Flag("disable-blink-features", "AutomationControlled"),
-		chromedp.UserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
-	)
-
-	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer allocCancel()
-
-	// Create browser context
-	browserCtx, browserCancel := chromedp.NewContext(allocCtx)
-	defer browserCancel()
-
-	var response string
-
-	// Automate browser interaction
-	err := chromedp.Run(browserCtx,
-		// Navigate to Gemini
-		chromedp.Navigate("https://gemini.google.com"),
+// Name returns "gemini".
+func (g *GeminiClient) Name() string {
+	return ProviderGemini
+}
 
-		// Wait for page to load
-		chromedp.WaitVisible(`textarea[placeholder*="Enter a prompt"]`, chromedp.ByQuery),
-		chromedp.Sleep(2*time.Second),
+// Model returns the model requests are sent to.
+func (g *GeminiClient) Model() string {
+	return g.model
+}
 
-		// Enter prompt
-		chromedp.SendKeys(`textarea[placeholder*="Enter a prompt"]`, prompt, chromedp.ByQuery),
-		chromedp.Sleep(500*time.Millisecond),
+// genaiClient creates the SDK client on first use, so a chain that never
+// reaches Gemini does not need an API key.
+func (g *GeminiClient) genaiClient(ctx context.Context) (*genai.Client, error) {
+	g.once.Do(func() {
+		cc := &genai.ClientConfig{APIKey: g.apiKey, Backend: genai.BackendGeminiAPI}
+		if g.baseURL != "" {
+			cc.HTTPOptions.BaseURL = g.baseURL
+		}
+		g.client, g.err = genai.NewClient(ctx, cc)
+		if g.err != nil {
+			g.err = fmt.Errorf("failed to create Gemini client (set gemini.api_key or GEMINI_API_KEY): %w", g.err)
+		}
+	})
+	return g.client, g.err
+}
 
-		// Submit (press Enter)
-		chromedp.SendKeys(`textarea[placeholder*="Enter a prompt"]`, "\n", chromedp.ByQuery),
+// Complete implements Provider. In JSON mode the API is asked for an
+// application/json response, so the text is JSON without markdown fences;
+// req.Schema is passed as the response JSON schema.
+func (g *GeminiClient) Complete(ctx context.Context, req Request) (*Response, error) {
+	client, err := g.genaiClient(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		// Wait for response (this is a placeholder - actual selector depends on Gemini's UI)
-		chromedp.Sleep(5*time.Second),
-		chromedp.WaitVisible(`div[data-test-id="conversation-turn-2"]`, chromedp.ByQuery),
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = DefaultMaxTokens
+	}
+	genCfg := &genai.GenerateContentConfig{
+		Temperature:     genai.Ptr(float32(req.Temperature)),
+		MaxOutputTokens: int32(maxTokens),
+	}
+	if req.wantsJSON() {
+		genCfg.ResponseMIMEType = "application/json"
+	}
+	if req.Schema != nil {
+		genCfg.ResponseJsonSchema = req.Schema
+	}
 
-		// Extract response text
-		chromedp.Text(`div[data-test-id="conversation-turn-2"]`, &response, chromedp.ByQuery),
-	)
+	var system []*genai.Part
+	if req.System != "" {
+		system = append(system, genai.NewPartFromText(req.System))
+	}
+	var contents []*genai.Content
+	for _, m := range req.Messages {
+		switch m.Role {
+		case "system":
+			system = append(system, genai.NewPartFromText(m.Content))
+		case "assistant":
+			contents = append(contents, genai.NewContentFromText(m.Content, genai.RoleModel))
+		default:
+			contents = append(contents, genai.NewContentFromText(m.Content, genai.RoleUser))
+		}
+	}
+	if len(system) > 0 {
+		genCfg.SystemInstruction = genai.NewContentFromParts(system, genai.RoleUser)
+	}
 
+	resp, err := client.Models.GenerateContent(ctx, g.model, contents, genCfg)
 	if err != nil {
-		return "", fmt.Errorf("browser automation failed: %w", err)
+		var apiErr genai.APIError
+		if errors.As(err, &apiErr) {
+			return nil, &HTTPError{StatusCode: apiErr.Code, Message: apiErr.Message, RetryAfter: geminiRetryDelay(apiErr.Details)}
+		}
+		return nil, fmt.Errorf("Gemini API call failed: %w", err)
+	}
+
+	text := resp.Text()
+	if text == "" {
+		reason := "empty response"
+		if len(resp.Candidates) > 0 && resp.Candidates[0].FinishReason != "" {
+			reason = "finish reason " + string(resp.Candidates[0].FinishReason)
+		}
+		return nil, fmt.Errorf("no response from Gemini (%s)", reason)
 	}
 
-	if response == "" {
-		return "", fmt.Errorf("empty response from Gemini")
+	out := &Response{Text: text, Provider: ProviderGemini, Model: g.model}
+	if resp.ModelVersion != "" {
+		out.Model = resp.ModelVersion
 	}
+	if u := resp.UsageMetadata; u != nil {
+		out.Usage = Usage{
+			PromptTokens:     int(u.PromptTokenCount),
+			CompletionTokens: int(u.CandidatesTokenCount + u.ThoughtsTokenCount),
+		}
+	}
+	return out, nil
+}
 
-	return response, nil
+// GenerateText is a convenience method for simple text generation
+func (g *GeminiClient) GenerateText(ctx context.Context, prompt string) (string, error) {
+	resp, err := g.Complete(ctx, Prompt(prompt, 0.7))
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
 }
 
-// TestConnection tests the Gemini browser automation
-func (g *GeminiClient) TestConnection() error {
-	_, err := g.GenerateText("Say 'OK' if you can read this.")
+// TestConnection tests the Gemini API connection
+func (g *GeminiClient) TestConnection(ctx context.Context) error {
+	_, err := g.GenerateText(ctx, "Say 'OK' if you can read this.")
 	return err
 }
+
+// geminiRetryDelay reads the retryDelay of a google.rpc.RetryInfo error
+// detail (e.g. "27s"), which is how the Gemini API says when to retry a 429.
+func geminiRetryDelay(details []map[string]any) time.Duration {
+	for _, d := range details {
+		if delay, ok := d["retryDelay"].(string); ok {
+			if wait, err := time.ParseDuration(delay); err == nil {
+				return wait
+			}
+		}
+	}
+	return 0
+}
@@ -2,17 +2,22 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 )
 
-// OpenAIClient wraps OpenAI API interactions
+// OpenAIClient wraps OpenAI API interactions. It also talks to any server
+// that implements the OpenAI chat completions API (Ollama, llama.cpp, hosted
+// OpenAI-compatible gateways).
 type OpenAIClient struct {
+	name       string
 	apiKey     string
 	model      string
 	httpClient *http.Client
@@ -27,10 +32,25 @@ type ChatMessage struct {
 
 // ChatCompletionRequest represents the request to OpenAI API
 type ChatCompletionRequest struct {
-	Model       string        `json:"model"`
-	Messages    []ChatMessage `json:"messages"`
-	Temperature float64       `json:"temperature,omitempty"`
-	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Model          string          `json:"model"`
+	Messages       []ChatMessage   `json:"messages"`
+	Temperature    float64         `json:"temperature,omitempty"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ResponseFormat selects JSON mode ({"type": "json_object"}) or structured
+// output ({"type": "json_schema", "json_schema": {...}})
+type ResponseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+// JSONSchema is the named schema of a json_schema response format
+type JSONSchema struct {
+	Name   string  `json:"name"`
+	Schema *Schema `json:"schema"`
+	Strict bool    `json:"strict"`
 }
 
 // ChatCompletionResponse represents the response from OpenAI API
@@ -66,33 +86,113 @@ func NewOpenAIClient(cfg *config.OpenAIConfig) *OpenAIClient {
 	if model == "" {
 		model = "gpt-4o-mini" // default model
 	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
 
+	return NewOpenAICompatibleClient(ProviderOpenAI, baseURL, cfg.APIKey, model)
+}
+
+// NewOpenAICompatibleClient creates a client for a server exposing the
+// OpenAI chat completions API at baseURL (e.g. http://localhost:11434/v1).
+// apiKey may be empty for local servers.
+func NewOpenAICompatibleClient(name, baseURL, apiKey, model string) *OpenAIClient {
 	return &OpenAIClient{
-		apiKey:  cfg.APIKey,
+		name:    name,
+		apiKey:  apiKey,
 		model:   model,
-		baseURL: "https://api.openai.com/v1",
+		baseURL: strings.TrimSuffix(baseURL, "/"),
 		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
+			// Local models can be slow to answer on modest hardware
+			Timeout: 120 * time.Second,
 		},
 	}
 }
 
+// Name returns the provider name this client was configured as.
+func (c *OpenAIClient) Name() string {
+	return c.name
+}
+
+// Model returns the model requests are sent to.
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
+
+// Complete implements Provider.
+func (c *OpenAIClient) Complete(ctx context.Context, req Request) (*Response, error) {
+	messages := req.Messages
+	if req.System != "" {
+		messages = append([]ChatMessage{{Role: "system", Content: req.System}}, messages...)
+	}
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = DefaultMaxTokens
+	}
+
+	chatReq := ChatCompletionRequest{
+		Model:       c.model,
+		Messages:    messages,
+		Temperature: req.Temperature,
+		MaxTokens:   maxTokens,
+	}
+	switch {
+	case req.Schema != nil:
+		name := req.SchemaName
+		if name == "" {
+			name = "response"
+		}
+		chatReq.ResponseFormat = &ResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: &JSONSchema{Name: name, Schema: req.Schema, Strict: true},
+		}
+	case req.JSON:
+		chatReq.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	}
+
+	resp, err := c.send(ctx, chatReq)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from %s", c.name)
+	}
+
+	model := resp.Model
+	if model == "" {
+		model = c.model
+	}
+	return &Response{
+		Text:     resp.Choices[0].Message.Content,
+		Provider: c.name,
+		Model:    model,
+		Usage: Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+		},
+	}, nil
+}
+
 // ChatCompletion sends a chat completion request with retry logic
-func (c *OpenAIClient) ChatCompletion(messages []ChatMessage, temperature float64, maxTokens int) (*ChatCompletionResponse, error) {
-	req := ChatCompletionRequest{
+func (c *OpenAIClient) ChatCompletion(ctx context.Context, messages []ChatMessage, temperature float64, maxTokens int) (*ChatCompletionResponse, error) {
+	return c.send(ctx, ChatCompletionRequest{
 		Model:       c.model,
 		Messages:    messages,
 		Temperature: temperature,
 		MaxTokens:   maxTokens,
-	}
+	})
+}
 
+// send posts req, retrying server and network errors with exponential backoff
+func (c *OpenAIClient) send(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
 	var resp *ChatCompletionResponse
 	var err error
 
 	// Retry logic with exponential backoff
 	maxRetries := 3
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		resp, err = c.makeRequest(req)
+		resp, err = c.makeRequest(ctx, req)
 		if err == nil {
 			return resp, nil
 		}
@@ -105,7 +205,11 @@ func (c *OpenAIClient) ChatCompletion(messages []ChatMessage, temperature float6
 		// Exponential backoff: 1s, 2s, 4s
 		if attempt < maxRetries-1 {
 			backoff := time.Duration(1<<uint(attempt)) * time.Second
-			time.Sleep(backoff)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
 		}
 	}
 
@@ -113,19 +217,21 @@ func (c *OpenAIClient) ChatCompletion(messages []ChatMessage, temperature float6
 }
 
 // makeRequest performs the actual HTTP request to OpenAI API
-func (c *OpenAIClient) makeRequest(req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+func (c *OpenAIClient) makeRequest(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
 	jsonData, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", c.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
 
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -140,16 +246,19 @@ func (c *OpenAIClient) makeRequest(req ChatCompletionRequest) (*ChatCompletionRe
 
 	// Check for HTTP errors
 	if httpResp.StatusCode != http.StatusOK {
+		retryAfter := parseRetryAfter(httpResp.Header, time.Now())
 		var errResp ErrorResponse
 		if err := json.Unmarshal(body, &errResp); err != nil {
 			return nil, &HTTPError{
 				StatusCode: httpResp.StatusCode,
 				Message:    string(body),
+				RetryAfter: retryAfter,
 			}
 		}
 		return nil, &HTTPError{
 			StatusCode: httpResp.StatusCode,
 			Message:    errResp.Error.Message,
+			RetryAfter: retryAfter,
 		}
 	}
 
@@ -162,29 +271,17 @@ func (c *OpenAIClient) makeRequest(req ChatCompletionRequest) (*ChatCompletionRe
 }
 
 // GenerateText is a convenience method for simple text generation
-func (c *OpenAIClient) GenerateText(prompt string, temperature float64) (string, error) {
-	messages := []ChatMessage{
-		{
-			Role:    "user",
-			Content: prompt,
-		},
-	}
-
-	resp, err := c.ChatCompletion(messages, temperature, 2000)
+func (c *OpenAIClient) GenerateText(ctx context.Context, prompt string, temperature float64) (string, error) {
+	resp, err := c.Complete(ctx, Prompt(prompt, temperature))
 	if err != nil {
 		return "", err
 	}
-
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
-	}
-
-	return resp.Choices[0].Message.Content, nil
+	return resp.Text, nil
 }
 
 // TestConnection tests the OpenAI API connection
-func (c *OpenAIClient) TestConnection() error {
-	_, err := c.GenerateText("Say 'OK' if you can read this.", 0.0)
+func (c *OpenAIClient) TestConnection(ctx context.Context) error {
+	_, err := c.GenerateText(ctx, "Say 'OK' if you can read this.", 0.0)
 	return err
 }
 
@@ -192,6 +289,7 @@ func (c *OpenAIClient) TestConnection() error {
 type HTTPError struct {
 	StatusCode int
 	Message    string
+	RetryAfter time.Duration // from the Retry-After header of a 429 or 503, zero if absent
 }
 
 func (e *HTTPError) Error() string {
@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"strings"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+)
+
+// DefaultPrices are list prices in USD per million tokens for the default
+// models. Override or extend them with ai.prices in the config.
+var DefaultPrices = []config.ModelPrice{
+	{Model: "gpt-4o-mini", Input: 0.15, Output: 0.60},
+	{Model: "gpt-4o", Input: 2.50, Output: 10.00},
+	{Model: "gpt-4.1-mini", Input: 0.40, Output: 1.60},
+	{Model: "gpt-4.1", Input: 2.00, Output: 8.00},
+	{Model: "claude-3-5-haiku", Input: 0.80, Output: 4.00},
+	{Model: "claude-3-5-sonnet", Input: 3.00, Output: 15.00},
+	{Model: "gemini-2.5-flash", Input: 0.30, Output: 2.50},
+	{Model: "gemini-2.5-pro", Input: 1.25, Output: 10.00},
+	{Model: "gemini-2.0-flash", Input: 0.10, Output: 0.40},
+}
+
+// PriceTable estimates the cost of AI calls.
+type PriceTable struct {
+	prices map[string]config.ModelPrice
+}
+
+// NewPriceTable builds the table from DefaultPrices with overrides applied.
+func NewPriceTable(overrides []config.ModelPrice) *PriceTable {
+	t := &PriceTable{prices: make(map[string]config.ModelPrice)}
+	for _, p := range DefaultPrices {
+		t.prices[p.Model] = p
+	}
+	for _, p := range overrides {
+		t.prices[p.Model] = p
+	}
+	return t
+}
+
+// Cost returns the estimated cost in USD of usage on model. Local backends
+// (Ollama, llama.cpp) are free. ok is false when the model has no price, in
+// which case the cost is reported as zero.
+func (t *PriceTable) Cost(provider, model string, usage Usage) (cost float64, ok bool) {
+	if provider == ProviderOllama || provider == ProviderLlamaCpp {
+		return 0, true
+	}
+
+	model = strings.TrimPrefix(model, "models/") // Gemini resource names
+
+	// The longest matching prefix wins, so gpt-4o-mini is not priced as gpt-4o
+	var best config.ModelPrice
+	for name, p := range t.prices {
+		if strings.HasPrefix(model, name) && len(name) > len(best.Model) {
+			best = p
+		}
+	}
+	if best.Model == "" {
+		return 0, false
+	}
+
+	return (float64(usage.PromptTokens)*best.Input + float64(usage.CompletionTokens)*best.Output) / 1e6, true
+}
@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+)
+
+// Provider is a chat-completion backend. Implementations translate a
+// Request to their own API and report token usage when the API returns it.
+type Provider interface {
+	// Name identifies the backend, e.g. "openai" or "ollama".
+	Name() string
+	// Model is the model requests are sent to.
+	Model() string
+	// Complete sends a single chat completion request.
+	Complete(ctx context.Context, req Request) (*Response, error)
+}
+
+// Request is a provider-neutral chat completion request.
+type Request struct {
+	System      string // optional system prompt
+	Messages    []ChatMessage
+	Temperature float64
+	MaxTokens   int  // zero means DefaultMaxTokens
+	JSON        bool // ask the backend for a JSON-only response
+
+	// Schema, when set, asks for JSON conforming to it. SchemaName labels it
+	// for backends that require a name (OpenAI json_schema).
+	Schema     *Schema
+	SchemaName string
+}
+
+// wantsJSON reports whether the request asks for a JSON response
+func (r Request) wantsJSON() bool {
+	return r.JSON || r.Schema != nil
+}
+
+// schemaInstruction describes the schema in the prompt, for backends without
+// native schema support.
+func (r Request) schemaInstruction() string {
+	if r.Schema == nil {
+		return jsonInstruction
+	}
+	schema, _ := json.Marshal(r.Schema)
+	return jsonInstruction + " The JSON must match this JSON schema:\n" + string(schema)
+}
+
+// Usage counts the tokens a request consumed.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// TotalTokens returns prompt plus completion tokens.
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// Response is the answer to a Request.
+type Response struct {
+	Text     string
+	Provider string
+	Model    string
+	Usage    Usage
+}
+
+// DefaultMaxTokens caps completions when a Request sets no limit.
+const DefaultMaxTokens = 2000
+
+// jsonInstruction asks for JSON in backends that have no JSON mode switch
+const jsonInstruction = "Respond with valid JSON only, with no text before or after it."
+
+// Prompt builds a request with a single user message.
+func Prompt(prompt string, temperature float64) Request {
+	return Request{
+		Messages:    []ChatMessage{{Role: "user", Content: prompt}},
+		Temperature: temperature,
+	}
+}
+
+// Provider names accepted in ai.providers.
+const (
+	ProviderOpenAI           = "openai"
+	ProviderAnthropic        = "anthropic"
+	ProviderOllama           = "ollama"
+	ProviderLlamaCpp         = "llamacpp"
+	ProviderOpenAICompatible = "openai_compatible"
+	ProviderGemini           = "gemini"
+)
+
+// DefaultChain is the fallback order used when ai.providers is not set.
+var DefaultChain = []string{ProviderOpenAI, ProviderGemini}
+
+// NewProvider builds the named backend from configuration.
+func NewProvider(cfg *config.Config, name string) (Provider, error) {
+	switch name {
+	case ProviderOpenAI:
+		return NewOpenAIClient(&cfg.OpenAI), nil
+	case ProviderAnthropic:
+		return NewAnthropicClient(&cfg.AI.Anthropic), nil
+	case ProviderOllama:
+		return newLocalClient(ProviderOllama, &cfg.AI.Ollama, "http://localhost:11434/v1", "llama3.1"), nil
+	case ProviderLlamaCpp:
+		return newLocalClient(ProviderLlamaCpp, &cfg.AI.LlamaCpp, "http://localhost:8080/v1", "default"), nil
+	case ProviderOpenAICompatible:
+		c := cfg.AI.OpenAICompatible
+		if c.BaseURL == "" || c.Model == "" {
+			return nil, fmt.Errorf("openai_compatible: ai.openai_compatible.base_url and model are required")
+		}
+		return NewOpenAICompatibleClient(ProviderOpenAICompatible, c.BaseURL, c.APIKey, c.Model), nil
+	case ProviderGemini:
+		return NewGeminiClient(&cfg.Gemini), nil
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q (use openai, anthropic, ollama, llamacpp, openai_compatible or gemini)", name)
+	}
+}
+
+// NewChain builds providers in the given order, or in the ai.providers order
+// from configuration when names is empty. Each provider is paced by its own
+// RateLimiter using ai.rate_limits, so a chain can be shared by concurrent
+// workers.
+func NewChain(cfg *config.Config, names ...string) ([]Provider, error) {
+	if len(names) == 0 {
+		names = cfg.AI.Providers
+	}
+	if len(names) == 0 {
+		names = DefaultChain
+	}
+
+	chain := make([]Provider, 0, len(names))
+	for _, name := range names {
+		p, err := NewProvider(cfg, name)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, Limit(p, NewRateLimiter(cfg.AI.RateLimits[name])))
+	}
+	return chain, nil
+}
+
+// newLocalClient configures a local server that speaks the OpenAI API
+// (Ollama and llama.cpp both serve /v1/chat/completions).
+func newLocalClient(name string, cfg *config.LLMEndpointConfig, baseURL, model string) *OpenAIClient {
+	if cfg.BaseURL != "" {
+		baseURL = cfg.BaseURL
+	}
+	if cfg.Model != "" {
+		model = cfg.Model
+	}
+	return NewOpenAICompatibleClient(name, baseURL, cfg.APIKey, model)
+}
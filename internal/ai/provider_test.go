@@ -0,0 +1,260 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+)
+
+func TestOpenAICompatible_JSONModeAndUsage(t *testing.T) {
+	var got ChatCompletionRequest
+	var gotAuth, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"model":"llama3.1:8b","choices":[{"message":{"role":"assistant","content":"{\"ok\":true}"}}],
+			"usage":{"prompt_tokens":12,"completion_tokens":5,"total_tokens":17}}`))
+	}))
+	defer server.Close()
+
+	p, err := NewProvider(&config.Config{AI: config.AIConfig{
+		Ollama: config.LLMEndpointConfig{BaseURL: server.URL + "/v1/"},
+	}}, ProviderOllama)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := Prompt("hi", 0.2)
+	req.System = "be brief"
+	req.JSON = true
+	resp, err := p.Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/v1/chat/completions" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotAuth != "" {
+		t.Errorf("Authorization = %q, want none for a local server without a key", gotAuth)
+	}
+	if got.Model != "llama3.1" || got.ResponseFormat == nil || got.ResponseFormat.Type != "json_object" {
+		t.Errorf("request = %+v", got)
+	}
+	if len(got.Messages) != 2 || got.Messages[0].Role != "system" {
+		t.Errorf("messages = %+v, want system then user", got.Messages)
+	}
+	if resp.Text != `{"ok":true}` || resp.Provider != ProviderOllama || resp.Model != "llama3.1:8b" {
+		t.Errorf("response = %+v", resp)
+	}
+	if resp.Usage.TotalTokens() != 17 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
+
+func TestOpenAI_JSONSchema(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"n\":1}"}}]}`))
+	}))
+	defer server.Close()
+
+	p := NewOpenAIClient(&config.OpenAIConfig{BaseURL: server.URL, APIKey: "sk"})
+	req := Prompt("hi", 0)
+	req.Schema = Object(map[string]*Schema{"n": Integer("", 0, 10), "s": String("", 1)})
+	req.SchemaName = "thing"
+	if _, err := p.Complete(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	format, _ := got["response_format"].(map[string]any)
+	spec, _ := format["json_schema"].(map[string]any)
+	if format["type"] != "json_schema" || spec["name"] != "thing" || spec["strict"] != true {
+		t.Fatalf("response_format = %v", format)
+	}
+	schema, _ := spec["schema"].(map[string]any)
+	if schema["additionalProperties"] != false || len(schema["required"].([]any)) != 2 {
+		t.Errorf("schema = %v, want a closed object with every field required", schema)
+	}
+	props := schema["properties"].(map[string]any)
+	if _, ok := props["s"].(map[string]any)["MinLength"]; ok {
+		t.Error("MinLength must not be sent")
+	}
+}
+
+func TestAnthropic_Complete(t *testing.T) {
+	var got anthropicRequest
+	var gotKey, gotVersion string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("x-api-key")
+		gotVersion = r.Header.Get("anthropic-version")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"model":"claude-x","content":[{"type":"text","text":"[1,2]"}],
+			"usage":{"input_tokens":30,"output_tokens":4}}`))
+	}))
+	defer server.Close()
+
+	p := NewAnthropicClient(&config.LLMEndpointConfig{BaseURL: server.URL, APIKey: "sk-test"})
+	req := Request{
+		Messages: []ChatMessage{{Role: "system", Content: "sys"}, {Role: "user", Content: "hi"}},
+		JSON:     true,
+	}
+	resp, err := p.Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotKey != "sk-test" || gotVersion != anthropicVersion {
+		t.Errorf("headers: key=%q version=%q", gotKey, gotVersion)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Role != "user" {
+		t.Errorf("messages = %+v, want only the user message", got.Messages)
+	}
+	if !strings.HasPrefix(got.System, "sys") || !strings.Contains(got.System, "JSON") {
+		t.Errorf("system = %q", got.System)
+	}
+	if got.MaxTokens != DefaultMaxTokens {
+		t.Errorf("max_tokens = %d, want %d", got.MaxTokens, DefaultMaxTokens)
+	}
+	if resp.Text != "[1,2]" || resp.Usage.PromptTokens != 30 || resp.Usage.CompletionTokens != 4 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestAnthropic_ErrorMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+	}))
+	defer server.Close()
+
+	p := NewAnthropicClient(&config.LLMEndpointConfig{BaseURL: server.URL})
+	_, err := p.Complete(context.Background(), Prompt("hi", 0))
+
+	httpErr, ok := err.(*HTTPError)
+	if !ok || httpErr.StatusCode != http.StatusUnauthorized || httpErr.Message != "invalid x-api-key" {
+		t.Errorf("err = %v, want HTTP 401 with the API message", err)
+	}
+}
+
+func TestNewChain(t *testing.T) {
+	cfg := &config.Config{}
+
+	chain, err := NewChain(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chain) != 2 || chain[0].Name() != ProviderOpenAI || chain[1].Name() != ProviderGemini {
+		t.Errorf("default chain = %v, want openai then gemini", names(chain))
+	}
+
+	cfg.AI.Providers = []string{ProviderAnthropic, ProviderLlamaCpp}
+	chain, err = NewChain(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chain) != 2 || chain[0].Name() != ProviderAnthropic || chain[1].Name() != ProviderLlamaCpp {
+		t.Errorf("configured chain = %v", names(chain))
+	}
+
+	if _, err := NewChain(cfg, "nope"); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+	if _, err := NewChain(cfg, ProviderOpenAICompatible); err == nil {
+		t.Error("expected an error for openai_compatible without base_url")
+	}
+}
+
+func names(chain []Provider) []string {
+	var out []string
+	for _, p := range chain {
+		out = append(out, p.Name())
+	}
+	return out
+}
+
+func TestGemini_Complete(t *testing.T) {
+	var got struct {
+		Contents          []map[string]any `json:"contents"`
+		SystemInstruction struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"systemInstruction"`
+		GenerationConfig struct {
+			ResponseMIMEType   string         `json:"responseMimeType"`
+			ResponseJSONSchema map[string]any `json:"responseJsonSchema"`
+			MaxOutputTokens    int            `json:"maxOutputTokens"`
+		} `json:"generationConfig"`
+	}
+	var gotPath, gotKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("x-goog-api-key")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"{\"ok\":true}"}]},"finishReason":"STOP"}],
+			"usageMetadata":{"promptTokenCount":20,"candidatesTokenCount":6,"thoughtsTokenCount":4},
+			"modelVersion":"gemini-2.5-flash-001"}`))
+	}))
+	defer server.Close()
+
+	g := NewGeminiClient(&config.GeminiConfig{APIKey: "g-key"})
+	g.baseURL = server.URL
+
+	req := Prompt("hi", 0.3)
+	req.System = "be brief"
+	req.Schema = Object(map[string]*Schema{"ok": {Type: "boolean"}})
+	resp, err := g.Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasSuffix(gotPath, "/models/gemini-2.5-flash:generateContent") {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotKey != "g-key" {
+		t.Errorf("api key header = %q", gotKey)
+	}
+	if got.GenerationConfig.ResponseMIMEType != "application/json" || got.GenerationConfig.MaxOutputTokens != DefaultMaxTokens {
+		t.Errorf("generationConfig = %+v", got.GenerationConfig)
+	}
+	if got.GenerationConfig.ResponseJSONSchema["type"] != "object" {
+		t.Errorf("responseJsonSchema = %v", got.GenerationConfig.ResponseJSONSchema)
+	}
+	if len(got.SystemInstruction.Parts) != 1 || got.SystemInstruction.Parts[0].Text != "be brief" {
+		t.Errorf("systemInstruction = %+v", got.SystemInstruction)
+	}
+	if len(got.Contents) != 1 {
+		t.Errorf("contents = %+v, want the user message only", got.Contents)
+	}
+	if resp.Text != `{"ok":true}` || resp.Provider != ProviderGemini || resp.Model != "gemini-2.5-flash-001" {
+		t.Errorf("response = %+v", resp)
+	}
+	if resp.Usage.PromptTokens != 20 || resp.Usage.CompletionTokens != 10 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
+
+func TestGemini_EmptyResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates":[{"content":{"parts":[]},"finishReason":"SAFETY"}]}`))
+	}))
+	defer server.Close()
+
+	g := NewGeminiClient(&config.GeminiConfig{APIKey: "g-key"})
+	g.baseURL = server.URL
+
+	_, err := g.Complete(context.Background(), Prompt("hi", 0))
+	if err == nil || !strings.Contains(err.Error(), "SAFETY") {
+		t.Errorf("err = %v, want the finish reason", err)
+	}
+}
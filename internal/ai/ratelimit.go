@@ -0,0 +1,188 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+)
+
+// RateLimiter keeps calls to one provider under its requests-per-minute and
+// tokens-per-minute limits, and holds every caller back after a 429 for as
+// long as the server's Retry-After asked. It is safe for concurrent use.
+type RateLimiter struct {
+	mu          sync.Mutex
+	requests    *bucket // nil when RPM is unlimited
+	tokens      *bucket // nil when TPM is unlimited
+	pausedUntil time.Time
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRateLimiter creates a limiter for limits. Zero RPM or TPM means no limit
+// on that axis; Retry-After pauses apply either way.
+func NewRateLimiter(limits config.RateLimitConfig) *RateLimiter {
+	l := &RateLimiter{now: time.Now, sleep: sleepCtx}
+	if limits.RPM > 0 {
+		l.requests = newBucket(float64(limits.RPM), l.now())
+	}
+	if limits.TPM > 0 {
+		l.tokens = newBucket(float64(limits.TPM), l.now())
+	}
+	return l
+}
+
+// Wait blocks until a request estimated at tokens prompt tokens may be sent,
+// then takes its share of both budgets.
+func (l *RateLimiter) Wait(ctx context.Context, tokens int) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		wait := l.pausedUntil.Sub(now)
+		if l.requests != nil {
+			wait = max(wait, l.requests.wait(1, now))
+		}
+		if l.tokens != nil {
+			wait = max(wait, l.tokens.wait(float64(tokens), now))
+		}
+		if wait <= 0 {
+			if l.requests != nil {
+				l.requests.take(1)
+			}
+			if l.tokens != nil {
+				l.tokens.take(float64(tokens))
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		if err := l.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Charge takes tokens that were only known after the call (the completion)
+// from the token budget. The budget may go negative, which delays the next
+// callers until it has refilled.
+func (l *RateLimiter) Charge(tokens int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.tokens != nil {
+		l.tokens.refill(l.now())
+		l.tokens.take(float64(tokens))
+	}
+}
+
+// Pause holds back every caller for d, e.g. after a 429 with Retry-After.
+func (l *RateLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := l.now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// bucket is a token bucket holding up to one minute of budget
+type bucket struct {
+	capacity float64
+	level    float64
+	perSec   float64
+	last     time.Time
+}
+
+func newBucket(perMinute float64, now time.Time) *bucket {
+	return &bucket{capacity: perMinute, level: perMinute, perSec: perMinute / 60, last: now}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.level = min(b.capacity, b.level+elapsed*b.perSec)
+		b.last = now
+	}
+}
+
+// wait returns how long until n can be taken. Requests larger than the whole
+// bucket only wait for a full bucket, so they cannot block forever.
+func (b *bucket) wait(n float64, now time.Time) time.Duration {
+	b.refill(now)
+	n = min(n, b.capacity)
+	if b.level >= n {
+		return 0
+	}
+	return time.Duration((n - b.level) / b.perSec * float64(time.Second))
+}
+
+func (b *bucket) take(n float64) {
+	b.level -= n
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date. It returns zero when the header is missing or malformed.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second))
+	}
+	if at, err := http.ParseTime(v); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// limitedProvider passes every call of a Provider through a RateLimiter
+type limitedProvider struct {
+	Provider
+	limiter *RateLimiter
+}
+
+// Limit returns p with its calls paced by limiter.
+func Limit(p Provider, limiter *RateLimiter) Provider {
+	return &limitedProvider{Provider: p, limiter: limiter}
+}
+
+// Complete waits for the limiter, then records the completion tokens and any
+// Retry-After the backend asked for.
+func (p *limitedProvider) Complete(ctx context.Context, req Request) (*Response, error) {
+	if err := p.limiter.Wait(ctx, estimateTokens(req)); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.Provider.Complete(ctx, req)
+	if err != nil {
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == http.StatusTooManyRequests && httpErr.RetryAfter > 0 {
+			p.limiter.Pause(httpErr.RetryAfter)
+		}
+		return nil, err
+	}
+	p.limiter.Charge(resp.Usage.CompletionTokens)
+	return resp, nil
+}
+
+// estimateTokens guesses the prompt size of req at four characters a token
+func estimateTokens(req Request) int {
+	chars := len(req.System)
+	for _, m := range req.Messages {
+		chars += len(m.Content)
+	}
+	return chars/4 + 1
+}
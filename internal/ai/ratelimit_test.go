@@ -0,0 +1,176 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+)
+
+// fakeClock makes a limiter sleep instantly, recording how long it waited
+func fakeClock(l *RateLimiter) *[]time.Duration {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	l.now = func() time.Time { return now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		now = now.Add(d)
+		return nil
+	}
+	if l.requests != nil {
+		l.requests.last = now
+	}
+	if l.tokens != nil {
+		l.tokens.last = now
+	}
+	return &slept
+}
+
+func TestRateLimiter_RequestsPerMinute(t *testing.T) {
+	l := NewRateLimiter(config.RateLimitConfig{RPM: 2})
+	slept := fakeClock(l)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx, 1); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+
+	// Two requests fit the bucket, the third waits for half a minute
+	if len(*slept) != 1 || (*slept)[0] != 30*time.Second {
+		t.Errorf("slept %v, want [30s]", *slept)
+	}
+}
+
+func TestRateLimiter_ChargesCompletionTokens(t *testing.T) {
+	l := NewRateLimiter(config.RateLimitConfig{TPM: 600}) // 10 tokens a second
+	slept := fakeClock(l)
+	ctx := context.Background()
+
+	if err := l.Wait(ctx, 100); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	l.Charge(600) // budget is now 100 in debt
+
+	if err := l.Wait(ctx, 100); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 20*time.Second {
+		t.Errorf("slept %v, want [20s]", *slept)
+	}
+}
+
+func TestRateLimiter_PauseWithoutLimits(t *testing.T) {
+	l := NewRateLimiter(config.RateLimitConfig{})
+	slept := fakeClock(l)
+
+	l.Pause(7 * time.Second)
+	l.Pause(2 * time.Second) // a shorter pause does not cut the first one
+	if err := l.Wait(context.Background(), 1000000); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 7*time.Second {
+		t.Errorf("slept %v, want [7s]", *slept)
+	}
+}
+
+func TestRateLimiter_WaitHonoursCancellation(t *testing.T) {
+	l := NewRateLimiter(config.RateLimitConfig{})
+	l.Pause(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx, 1); err != context.Canceled {
+		t.Errorf("Wait = %v, want context.Canceled", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"12", 12 * time.Second},
+		{"0.5", 500 * time.Millisecond},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.value != "" {
+			h.Set("Retry-After", tt.value)
+		}
+		if got := parseRetryAfter(h, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLimit_PausesAfterTooManyRequests(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"message":"slow down","type":"rate_limit"}}`))
+			return
+		}
+		w.Write([]byte(`{"model":"m","choices":[{"message":{"role":"assistant","content":"ok"}}],
+			"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	p, err := NewProvider(&config.Config{AI: config.AIConfig{
+		Ollama: config.LLMEndpointConfig{BaseURL: server.URL + "/v1"},
+	}}, ProviderOllama)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	limiter := NewRateLimiter(config.RateLimitConfig{})
+	slept := fakeClock(limiter)
+	limited := Limit(p, limiter)
+
+	_, err = limited.Complete(context.Background(), Prompt("hi", 0))
+	httpErr, ok := err.(*HTTPError)
+	if !ok || httpErr.StatusCode != http.StatusTooManyRequests || httpErr.RetryAfter != 3*time.Second {
+		t.Fatalf("err = %#v, want a 429 with a 3s Retry-After", err)
+	}
+
+	if _, err := limited.Complete(context.Background(), Prompt("hi", 0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 3*time.Second {
+		t.Errorf("slept %v, want [3s] before the second call", *slept)
+	}
+	if limited.Name() != ProviderOllama {
+		t.Errorf("Name() = %q, want the wrapped provider's", limited.Name())
+	}
+}
+
+func TestGemini_RetryDelay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"code":429,"message":"quota exceeded","status":"RESOURCE_EXHAUSTED",
+			"details":[{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"27s"}]}}`))
+	}))
+	defer server.Close()
+
+	g := NewGeminiClient(&config.GeminiConfig{APIKey: "g-key"})
+	g.baseURL = server.URL
+
+	_, err := g.Complete(context.Background(), Prompt("hi", 0))
+	httpErr, ok := err.(*HTTPError)
+	if !ok || httpErr.StatusCode != http.StatusTooManyRequests || httpErr.RetryAfter != 27*time.Second {
+		t.Errorf("err = %#v, want a 429 with a 27s retry delay", err)
+	}
+}
@@ -0,0 +1,227 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used to describe structured output.
+// It is sent to backends that accept a response schema (OpenAI json_schema,
+// Gemini responseJsonSchema) and checked locally with Validate, since not
+// every backend enforces it.
+type Schema struct {
+	Type                 string             `json:"type"` // object, array, string, integer, number, boolean
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+
+	// MinLength is only checked locally: strict OpenAI schemas reject it
+	MinLength int `json:"-"`
+}
+
+// Object builds a closed object schema. Every property is required, which
+// is what OpenAI strict mode expects.
+func Object(props map[string]*Schema) *Schema {
+	required := make([]string, 0, len(props))
+	for name := range props {
+		required = append(required, name)
+	}
+	sort.Strings(required)
+	closed := false
+	return &Schema{Type: "object", Properties: props, Required: required, AdditionalProperties: &closed}
+}
+
+// Array builds an array schema with at least minItems items of type items.
+func Array(items *Schema, minItems int) *Schema {
+	return &Schema{Type: "array", Items: items, MinItems: &minItems}
+}
+
+// String builds a string schema that must have at least minLength characters.
+func String(description string, minLength int) *Schema {
+	return &Schema{Type: "string", Description: description, MinLength: minLength}
+}
+
+// Enum builds a string schema restricted to values.
+func Enum(description string, values ...string) *Schema {
+	return &Schema{Type: "string", Description: description, Enum: values}
+}
+
+// Integer builds an integer schema bounded by min and max.
+func Integer(description string, min, max float64) *Schema {
+	return &Schema{Type: "integer", Description: description, Minimum: &min, Maximum: &max}
+}
+
+// TrimJSON removes surrounding whitespace and a markdown code fence, which
+// some backends add even in JSON mode. Anything else is left for Validate to
+// reject.
+func TrimJSON(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") || !strings.HasSuffix(text, "```") || len(text) < 6 {
+		return text
+	}
+	text = strings.TrimSuffix(text, "```")
+	if nl := strings.IndexByte(text, '\n'); nl >= 0 {
+		text = text[nl+1:] // drops ```json
+	} else {
+		text = strings.TrimPrefix(text, "```")
+	}
+	return strings.TrimSpace(text)
+}
+
+// ValidateJSON decodes text and checks it against s. It returns one message
+// per problem, prefixed with the path of the offending value (e.g.
+// "ideas[2].type: ..."), or nil when text conforms.
+func (s *Schema) ValidateJSON(text string) []string {
+	var v any
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		return []string{fmt.Sprintf("response is not valid JSON: %v", err)}
+	}
+	return s.Validate(v)
+}
+
+// Validate checks a value decoded by encoding/json against s.
+func (s *Schema) Validate(v any) []string {
+	var problems []string
+	s.validate("$", v, &problems)
+	return problems
+}
+
+func (s *Schema) validate(path string, v any, problems *[]string) {
+	fail := func(format string, args ...any) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fail("expected an object, got %s", kind(v))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				fail("missing required field %q", name)
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					fail("unexpected field %q", name)
+				}
+				continue
+			}
+			prop.validate(childPath(path, name), obj[name], problems)
+		}
+
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			fail("expected an array, got %s", kind(v))
+			return
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			fail("expected at least %d items, got %d", *s.MinItems, len(arr))
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			fail("expected a string, got %s", kind(v))
+			return
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			fail("must be one of %s, got %q", strings.Join(s.Enum, ", "), str)
+		}
+		if len(strings.TrimSpace(str)) < s.MinLength {
+			if s.MinLength == 1 {
+				fail("must not be empty")
+			} else {
+				fail("must be at least %d characters", s.MinLength)
+			}
+		}
+
+	case "integer", "number":
+		n, ok := v.(float64)
+		if !ok {
+			fail("expected %s %s, got %s", article(s.Type), s.Type, kind(v))
+			return
+		}
+		if s.Type == "integer" && n != math.Trunc(n) {
+			fail("expected an integer, got %v", n)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			fail("must be >= %v, got %v", *s.Minimum, n)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			fail("must be <= %v, got %v", *s.Maximum, n)
+		}
+
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("expected a boolean, got %s", kind(v))
+		}
+	}
+}
+
+func childPath(path, name string) string {
+	if path == "$" {
+		return name
+	}
+	return path + "." + name
+}
+
+// kind names the JSON type of a decoded value for error messages
+func kind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func article(word string) string {
+	if strings.IndexByte("aeiou", word[0]) >= 0 {
+		return "an"
+	}
+	return "a"
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchema_ValidateJSON(t *testing.T) {
+	schema := Object(map[string]*Schema{
+		"items": Array(Object(map[string]*Schema{
+			"kind":  Enum("", "a", "b"),
+			"name":  String("", 1),
+			"score": Integer("", 0, 100),
+		}), 1),
+	})
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "valid",
+			text: `{"items":[{"kind":"a","name":"x","score":10}]}`,
+		},
+		{
+			name: "not JSON",
+			text: `Here you go: {"items":[]}`,
+			want: []string{"response is not valid JSON: invalid character 'H' looking for beginning of value"},
+		},
+		{
+			name: "top level array",
+			text: `[{"kind":"a"}]`,
+			want: []string{"$: expected an object, got an array"},
+		},
+		{
+			name: "item problems",
+			text: `{"items":[{"kind":"c","name":" ","score":"90","extra":1}]}`,
+			want: []string{
+				`items[0]: unexpected field "extra"`,
+				`items[0].kind: must be one of a, b, got "c"`,
+				`items[0].name: must not be empty`,
+				`items[0].score: expected an integer, got a string`,
+			},
+		},
+		{
+			name: "bounds and required",
+			text: `{"items":[{"kind":"b","score":100.5}]}`,
+			want: []string{
+				`items[0]: missing required field "name"`,
+				`items[0].score: expected an integer, got 100.5`,
+				`items[0].score: must be <= 100, got 100.5`,
+			},
+		},
+		{
+			name: "empty array",
+			text: `{"items":[]}`,
+			want: []string{"items: expected at least 1 items, got 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := schema.ValidateJSON(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateJSON() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimJSON(t *testing.T) {
+	tests := map[string]string{
+		"  {\"a\":1}\n":           `{"a":1}`,
+		"```json\n{\"a\":1}\n```": `{"a":1}`,
+		"```\n[1]\n```":           `[1]`,
+		"Sure! ```json\n{}\n```":  "Sure! ```json\n{}\n```",
+		"{\"code\":\"```\"}":      "{\"code\":\"```\"}",
+	}
+	for in, want := range tests {
+		if got := TrimJSON(in); got != want {
+			t.Errorf("TrimJSON(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,11 +13,17 @@ import (
 type Config struct {
 	Supabase  SupabaseConfig  `mapstructure:"supabase"`
 	OpenAI    OpenAIConfig    `mapstructure:"openai"`
+	AI        AIConfig        `mapstructure:"ai"`
 	Instagram InstagramConfig `mapstructure:"instagram"`
 	TikTok    TikTokConfig    `mapstructure:"tiktok"`
+	Bluesky   BlueskyConfig   `mapstructure:"bluesky"`
 	Amazon    AmazonConfig    `mapstructure:"amazon"`
 	Blotato   BlotatoConfig   `mapstructure:"blotato"`
 	Gemini    GeminiConfig    `mapstructure:"gemini"`
+	Storage   StorageConfig   `mapstructure:"storage"`
+
+	Publishing PublishingConfig `mapstructure:"publishing"`
+	Planner    PlannerConfig    `mapstructure:"planner"`
 }
 
 // SupabaseConfig holds Supabase connection details
@@ -28,20 +35,79 @@ type SupabaseConfig struct {
 
 // OpenAIConfig holds OpenAI API configuration
 type OpenAIConfig struct {
-	APIKey string `mapstructure:"api_key" yaml:"api_key"`
-	Model  string `mapstructure:"model" yaml:"model"`
+	APIKey  string `mapstructure:"api_key" yaml:"api_key"`
+	Model   string `mapstructure:"model" yaml:"model"`
+	BaseURL string `mapstructure:"base_url" yaml:"base_url"` // defaults to https://api.openai.com/v1
+}
+
+// AIConfig selects the LLM backends used for generation and the order they
+// are tried in. OpenAI and Gemini keep their own top-level sections.
+type AIConfig struct {
+	Providers        []string          `mapstructure:"providers" yaml:"providers"` // e.g. [openai, anthropic, ollama]; default [openai, gemini]
+	Anthropic        LLMEndpointConfig `mapstructure:"anthropic" yaml:"anthropic"`
+	Ollama           LLMEndpointConfig `mapstructure:"ollama" yaml:"ollama"`
+	LlamaCpp         LLMEndpointConfig `mapstructure:"llamacpp" yaml:"llamacpp"`
+	OpenAICompatible LLMEndpointConfig `mapstructure:"openai_compatible" yaml:"openai_compatible"`
+	Prices           []ModelPrice      `mapstructure:"prices" yaml:"prices"` // overrides the built-in price table
+	// RateLimits caps calls per provider name, e.g. {openai: {rpm: 500, tpm: 200000}}
+	RateLimits map[string]RateLimitConfig `mapstructure:"rate_limits" yaml:"rate_limits"`
+}
+
+// RateLimitConfig holds a provider's requests and tokens per minute limits.
+// Zero means no limit.
+type RateLimitConfig struct {
+	RPM int `mapstructure:"rpm" yaml:"rpm"`
+	TPM int `mapstructure:"tpm" yaml:"tpm"`
 }
 
-// InstagramConfig holds Instagram API configuration
+// ModelPrice is the price of a model in USD per million tokens. Model
+// matches exactly or as a prefix, so "gpt-4o-mini" also prices
+// "gpt-4o-mini-2024-07-18".
+type ModelPrice struct {
+	Model  string  `mapstructure:"model" yaml:"model"`
+	Input  float64 `mapstructure:"input" yaml:"input"`
+	Output float64 `mapstructure:"output" yaml:"output"`
+}
+
+// LLMEndpointConfig points at an LLM HTTP API
+type LLMEndpointConfig struct {
+	BaseURL string `mapstructure:"base_url" yaml:"base_url"`
+	APIKey  string `mapstructure:"api_key" yaml:"api_key"`
+	Model   string `mapstructure:"model" yaml:"model"`
+}
+
+// InstagramConfig holds Instagram API configuration. 'gagipress auth
+// instagram' fills in the token fields through OAuth with the app's ID and
+// secret; a pasted access token without an expiry works too.
 type InstagramConfig struct {
-	AccessToken string `mapstructure:"access_token" yaml:"access_token"`
-	AccountID   string `mapstructure:"account_id" yaml:"account_id"`
+	AccessToken    string    `mapstructure:"access_token" yaml:"access_token"`
+	AccountID      string    `mapstructure:"account_id" yaml:"account_id"`
+	AppID          string    `mapstructure:"app_id" yaml:"app_id"`
+	AppSecret      string    `mapstructure:"app_secret" yaml:"app_secret"`
+	TokenExpiresAt time.Time `mapstructure:"token_expires_at" yaml:"token_expires_at,omitempty"`
 }
 
-// TikTokConfig holds TikTok API configuration
+// TikTokConfig holds TikTok API configuration. 'gagipress auth tiktok'
+// fills in the token fields through OAuth with the app's client key and
+// secret.
 type TikTokConfig struct {
-	AccessToken string `mapstructure:"access_token" yaml:"access_token"`
-	AccountID   string `mapstructure:"account_id" yaml:"account_id"`
+	AccessToken      string    `mapstructure:"access_token" yaml:"access_token"`
+	AccountID        string    `mapstructure:"account_id" yaml:"account_id"`       // open_id
+	PrivacyLevel     string    `mapstructure:"privacy_level" yaml:"privacy_level"` // direct posts, default PUBLIC_TO_EVERYONE; unaudited apps need SELF_ONLY
+	ClientKey        string    `mapstructure:"client_key" yaml:"client_key"`
+	ClientSecret     string    `mapstructure:"client_secret" yaml:"client_secret"`
+	RefreshToken     string    `mapstructure:"refresh_token" yaml:"refresh_token"`
+	TokenExpiresAt   time.Time `mapstructure:"token_expires_at" yaml:"token_expires_at,omitempty"`
+	RefreshExpiresAt time.Time `mapstructure:"refresh_expires_at" yaml:"refresh_expires_at,omitempty"`
+}
+
+// BlueskyConfig holds the Bluesky account posts go to. Create an app
+// password under Settings > Privacy and security > App passwords; the
+// account password works too but should not be stored.
+type BlueskyConfig struct {
+	Handle      string `mapstructure:"handle" yaml:"handle"` // e.g. gagipress.bsky.social, or the account's DID
+	AppPassword string `mapstructure:"app_password" yaml:"app_password"`
+	PDSURL      string `mapstructure:"pds_url" yaml:"pds_url,omitempty"` // default https://bsky.social
 }
 
 // AmazonConfig holds Amazon KDP credentials
@@ -54,11 +120,50 @@ type AmazonConfig struct {
 type BlotatoConfig struct {
 	APIKey     string `mapstructure:"api_key" yaml:"api_key"`
 	TemplateID string `mapstructure:"template_id" yaml:"template_id"`
+
+	// Targets some platforms need through Blotato
+	FacebookPageID   string `mapstructure:"facebook_page_id" yaml:"facebook_page_id,omitempty"`
+	PinterestBoardID string `mapstructure:"pinterest_board_id" yaml:"pinterest_board_id,omitempty"`
+	YouTubePrivacy   string `mapstructure:"youtube_privacy" yaml:"youtube_privacy,omitempty"` // public (default), unlisted or private
+}
+
+// PublishingConfig chooses the publisher of each platform. Platforms not
+// listed go through Default, which is blotato unless set.
+type PublishingConfig struct {
+	Default   string            `mapstructure:"default" yaml:"default,omitempty"`
+	Platforms map[string]string `mapstructure:"platforms" yaml:"platforms,omitempty"` // platform: publisher, e.g. instagram: instagram
+}
+
+// PlannerConfig tunes 'calendar plan'. A content mix gives the share of
+// each idea type (educational, entertainment, bts, ugc, trend); shares are
+// relative, so they need not add up to one.
+//
+// Posting times are the audience's wall-clock times, in Timezone unless a
+// platform's audience lives elsewhere. Both default to the local timezone.
+type PlannerConfig struct {
+	Timezone    string             `mapstructure:"timezone" yaml:"timezone,omitempty"`       // IANA name, e.g. America/New_York
+	Timezones   map[string]string  `mapstructure:"timezones" yaml:"timezones,omitempty"`     // platform: IANA name, e.g. bluesky: Europe/Rome
+	Constraints string             `mapstructure:"constraints" yaml:"constraints,omitempty"` // YAML file of blackouts, spacing, caps and quotas
+	ContentMix  map[string]float64 `mapstructure:"content_mix" yaml:"content_mix,omitempty"` // default mix, e.g. {educational: 0.4, trend: 0.2}
+	// Books overrides the defaults for a book, keyed by its ID or an ID prefix
+	Books map[string]BookPlannerConfig `mapstructure:"books" yaml:"books,omitempty"`
+}
+
+// BookPlannerConfig holds the planner settings of one book
+type BookPlannerConfig struct {
+	ContentMix map[string]float64 `mapstructure:"content_mix" yaml:"content_mix,omitempty"`
 }
 
 // GeminiConfig holds Google Gemini/Imagen API configuration
 type GeminiConfig struct {
 	APIKey string `mapstructure:"api_key" yaml:"api_key"`
+	Model  string `mapstructure:"model" yaml:"model"` // text generation model, default gemini-2.5-flash
+}
+
+// StorageConfig selects where repositories keep their data
+type StorageConfig struct {
+	Backend string `mapstructure:"backend" yaml:"backend"` // supabase (default), sqlite, json or memory
+	Path    string `mapstructure:"path" yaml:"path"`       // data file for the sqlite and json backends
 }
 
 // Load loads configuration from file
@@ -86,11 +191,16 @@ func Save(cfg *Config) error {
 
 	viper.Set("supabase", cfg.Supabase)
 	viper.Set("openai", cfg.OpenAI)
+	viper.Set("ai", cfg.AI)
 	viper.Set("instagram", cfg.Instagram)
 	viper.Set("tiktok", cfg.TikTok)
+	viper.Set("bluesky", cfg.Bluesky)
 	viper.Set("amazon", cfg.Amazon)
 	viper.Set("blotato", cfg.Blotato)
 	viper.Set("gemini", cfg.Gemini)
+	viper.Set("storage", cfg.Storage)
+	viper.Set("publishing", cfg.Publishing)
+	viper.Set("planner", cfg.Planner)
 
 	if err := viper.WriteConfigAs(configFile); err != nil {
 		return fmt.Errorf("unable to write config file: %w", err)
@@ -101,6 +211,9 @@ func Save(cfg *Config) error {
 
 // Validate validates the configuration
 func (c *Config) Validate() error {
+	if c.Storage.Backend != "" && c.Storage.Backend != "supabase" {
+		return nil // local backends need no credentials
+	}
 	if c.Supabase.URL == "" {
 		return fmt.Errorf("supabase URL is required")
 	}
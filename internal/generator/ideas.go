@@ -2,13 +2,13 @@ package generator
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/gagipress/gagipress-cli/internal/ai"
-	"github.com/gagipress/gagipress-cli/internal/config"
-	"github.com/gagipress/gagipress-cli/internal/errors"
 	"github.com/gagipress/gagipress-cli/internal/models"
 	"github.com/gagipress/gagipress-cli/internal/prompts"
 	"github.com/gagipress/gagipress-cli/internal/repository"
@@ -16,24 +16,34 @@ import (
 
 // IdeaGenerator generates content ideas using AI
 type IdeaGenerator struct {
-	openaiClient  *ai.OpenAIClient
-	geminiClient  *ai.GeminiClient
-	contentRepo   *repository.ContentRepository
-	useGemini     bool
-	geminiHeadless bool
+	providers   []ai.Provider
+	contentRepo repository.ContentStore
+	ledger      *Ledger
+	out         io.Writer
+	campaignID  *string
 }
 
-// NewIdeaGenerator creates a new idea generator
-func NewIdeaGenerator(cfg *config.Config, useGemini bool) *IdeaGenerator {
+// NewIdeaGenerator creates a new idea generator. providers is the fallback
+// chain: each one is tried in order until one answers. ledger may be nil.
+func NewIdeaGenerator(providers []ai.Provider, contentRepo repository.ContentStore, ledger *Ledger) *IdeaGenerator {
 	return &IdeaGenerator{
-		openaiClient:   ai.NewOpenAIClient(&cfg.OpenAI),
-		geminiClient:   ai.NewGeminiClient(true), // headless by default
-		contentRepo:    repository.NewContentRepository(&cfg.Supabase),
-		useGemini:      useGemini,
-		geminiHeadless: true,
+		providers:   providers,
+		contentRepo: contentRepo,
+		ledger:      ledger,
+		out:         os.Stdout,
 	}
 }
 
+// SetOutput redirects progress and warnings, which go to stdout by default.
+func (g *IdeaGenerator) SetOutput(w io.Writer) {
+	g.out = w
+}
+
+// SetCampaign tags the ideas saved from now on with a launch campaign.
+func (g *IdeaGenerator) SetCampaign(campaignID string) {
+	g.campaignID = &campaignID
+}
+
 // GeneratedIdea represents a generated content idea from AI
 type GeneratedIdea struct {
 	Type           string `json:"type"`
@@ -42,103 +52,102 @@ type GeneratedIdea struct {
 	Hook           string `json:"hook"`
 	CTA            string `json:"cta"`
 	RelevanceScore int    `json:"relevance_score"`
+
+	Phase string `json:"-"` // launch campaign phase, if any
 }
 
-// GenerateIdeas generates content ideas for a book
-func (g *IdeaGenerator) GenerateIdeas(bookTitle, genre, targetAudience string, niche prompts.BookNiche, count int) ([]GeneratedIdea, error) {
+// GenerateIdeas generates content ideas for a book. The response is
+// validated against ideaSchema and repaired by re-prompting when needed.
+// bookID links the AI usage to the book; it may be empty.
+func (g *IdeaGenerator) GenerateIdeas(ctx context.Context, bookID, bookTitle, genre, targetAudience string, niche prompts.BookNiche, count int) ([]GeneratedIdea, error) {
 	// Build prompt
 	prompt := prompts.IdeaPromptTemplate(bookTitle, genre, targetAudience, niche, count)
+	return g.generate(ctx, bookID, prompt)
+}
 
-	var responseText string
-	var err error
-
-	ctx := context.Background()
-
-	// Try OpenAI first with retry logic unless explicitly using Gemini
-	if !g.useGemini {
-		fmt.Println("🤖 Using OpenAI for generation...")
-
-		retryErr := errors.Retry(ctx, errors.DefaultRetryConfig(), func() error {
-			responseText, err = g.openaiClient.GenerateText(prompt, 0.8)
-			if err != nil {
-				return errors.Wrap(err, errors.ErrorTypeAPI, "OpenAI API call failed")
-			}
-			return nil
-		})
-
-		if retryErr != nil {
-			fmt.Printf("⚠️  OpenAI failed after retries: %v\n", retryErr)
-			fmt.Println("🔄 Falling back to Gemini...")
-			g.useGemini = true
-		}
+// GenerateCampaignIdeas generates ideas for one phase of a book launch
+// campaign. Ideas of other types than the phase asks for are dropped with a
+// warning, so fewer than count may come back.
+func (g *IdeaGenerator) GenerateCampaignIdeas(ctx context.Context, bookID, bookTitle, genre, targetAudience string, niche prompts.BookNiche, count int, phase, launchDate string, types []string) ([]GeneratedIdea, error) {
+	prompt := prompts.CampaignIdeaPromptTemplate(bookTitle, genre, targetAudience, niche, count, phase, launchDate, types)
+	ideas, err := g.generate(ctx, bookID, prompt)
+	if err != nil {
+		return nil, err
 	}
 
-	// Fallback to Gemini if OpenAI failed or explicitly requested
-	if g.useGemini {
-		fmt.Println("🤖 Using Gemini for generation...")
-		responseText, err = g.geminiClient.GenerateText(prompt)
-		if err != nil {
-			return nil, errors.Wrap(err, errors.ErrorTypeAPI, "both OpenAI and Gemini failed")
+	var kept []GeneratedIdea
+	for _, idea := range ideas {
+		if !slices.Contains(types, idea.Type) {
+			fmt.Fprintf(g.out, "⚠️  Dropping %s idea %q: the %s phase takes %s\n", idea.Type, idea.Title, phase, strings.Join(types, ", "))
+			continue
 		}
+		idea.Phase = phase
+		kept = append(kept, idea)
 	}
-
-	// Parse JSON response
-	ideas, err := g.parseIdeasFromResponse(responseText)
-	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrorTypeValidation, "failed to parse AI response")
-	}
-
-	return ideas, nil
+	return kept, nil
 }
 
-// parseIdeasFromResponse parses the AI response into structured ideas
-func (g *IdeaGenerator) parseIdeasFromResponse(response string) ([]GeneratedIdea, error) {
-	// Extract JSON array from response (AI might add text around it)
-	start := strings.Index(response, "[")
-	end := strings.LastIndex(response, "]")
+// generate asks the providers for the ideas of a prompt
+func (g *IdeaGenerator) generate(ctx context.Context, bookID, prompt string) ([]GeneratedIdea, error) {
+	req := ai.Prompt(prompt, 0.8)
+	req.Schema = ideaSchema
+	req.SchemaName = "content_ideas"
 
-	if start == -1 || end == -1 || start > end {
-		return nil, fmt.Errorf("no JSON array found in response")
+	var out struct {
+		Ideas []GeneratedIdea `json:"ideas"`
 	}
-
-	jsonStr := response[start : end+1]
-
-	var ideas []GeneratedIdea
-	if err := json.Unmarshal([]byte(jsonStr), &ideas); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	var link usageLink
+	if bookID != "" {
+		link.bookID = &bookID
+	}
+	record := func(resp *ai.Response) { g.ledger.record(ctx, g.out, resp, link) }
+	if _, err := completeJSON(ctx, g.out, g.providers, req, &out, record); err != nil {
+		return nil, err
 	}
 
-	return ideas, nil
+	return out.Ideas, nil
 }
 
-// SaveIdeas saves generated ideas to the database
-func (g *IdeaGenerator) SaveIdeas(ideas []GeneratedIdea, bookID *string) ([]models.ContentIdea, error) {
-	var savedIdeas []models.ContentIdea
-
+// SaveIdeas saves generated ideas to the database in a single bulk write.
+// Invalid or rejected ideas are reported and left out of the result.
+func (g *IdeaGenerator) SaveIdeas(ctx context.Context, ideas []GeneratedIdea, bookID *string) ([]models.ContentIdea, error) {
+	var inputs []*models.ContentIdeaInput
 	for _, idea := range ideas {
+		metadata := map[string]string{
+			"hook": idea.Hook,
+			"cta":  idea.CTA,
+		}
+		if idea.Phase != "" {
+			metadata["campaign_phase"] = idea.Phase
+		}
 		input := &models.ContentIdeaInput{
 			Type:             idea.Type,
 			BriefDescription: idea.Title + ": " + idea.Description,
 			RelevanceScore:   &idea.RelevanceScore,
 			BookID:           bookID,
-			Metadata: map[string]string{
-				"hook": idea.Hook,
-				"cta":  idea.CTA,
-			},
+			Metadata:         metadata,
+			CampaignID:       g.campaignID,
 		}
 
 		if err := input.Validate(); err != nil {
-			fmt.Printf("⚠️  Skipping invalid idea: %v\n", err)
+			fmt.Fprintf(g.out, "⚠️  Skipping invalid idea: %v\n", err)
 			continue
 		}
+		inputs = append(inputs, input)
+	}
 
-		savedIdea, err := g.contentRepo.CreateIdea(input)
-		if err != nil {
-			fmt.Printf("⚠️  Failed to save idea: %v\n", err)
+	results, err := g.contentRepo.CreateIdeas(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	var savedIdeas []models.ContentIdea
+	for _, r := range results {
+		if r.Status == repository.RowFailed {
+			fmt.Fprintf(g.out, "⚠️  Failed to save idea: %v\n", r.Err)
 			continue
 		}
-
-		savedIdeas = append(savedIdeas, *savedIdea)
+		savedIdeas = append(savedIdeas, *r.Row)
 	}
 
 	return savedIdeas, nil
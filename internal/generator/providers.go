@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/gagipress/gagipress-cli/internal/ai"
+	"github.com/gagipress/gagipress-cli/internal/errors"
+)
+
+// retryConfig is how often each provider is retried before falling back
+var retryConfig = errors.DefaultRetryConfig()
+
+// complete sends req to each provider of the chain in order, retrying
+// transient failures, and returns the first successful response. Progress
+// is written to out.
+func complete(ctx context.Context, out io.Writer, providers []ai.Provider, req ai.Request) (*ai.Response, error) {
+	if len(providers) == 0 {
+		return nil, errors.New(errors.ErrorTypeValidation, "no AI providers configured")
+	}
+
+	var lastErr error
+	for i, p := range providers {
+		if i == 0 {
+			fmt.Fprintf(out, "🤖 Using %s (%s) for generation...\n", p.Name(), p.Model())
+		} else {
+			fmt.Fprintf(out, "🔄 Falling back to %s (%s)...\n", p.Name(), p.Model())
+		}
+
+		var resp *ai.Response
+		retryErr := errors.Retry(ctx, retryConfig, func() error {
+			var err error
+			resp, err = p.Complete(ctx, req)
+			if err != nil {
+				return errors.Wrap(err, errors.ErrorTypeAPI, p.Name()+" API call failed")
+			}
+			return nil
+		})
+		if retryErr == nil {
+			return resp, nil
+		}
+
+		// Don't fall back when the user cancelled or the deadline passed
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		fmt.Fprintf(out, "⚠️  %s failed after retries: %v\n", p.Name(), retryErr)
+		lastErr = retryErr
+	}
+
+	return nil, errors.Wrap(lastErr, errors.ErrorTypeAPI, "all AI providers failed")
+}
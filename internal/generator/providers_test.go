@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/ai"
+)
+
+func init() {
+	retryConfig.InitialWait = time.Millisecond
+}
+
+// fakeProvider answers with text, or fails every call when text is empty.
+type fakeProvider struct {
+	name  string
+	text  string
+	calls int
+}
+
+func (f *fakeProvider) Name() string  { return f.name }
+func (f *fakeProvider) Model() string { return "fake" }
+
+func (f *fakeProvider) Complete(ctx context.Context, req ai.Request) (*ai.Response, error) {
+	f.calls++
+	if f.text == "" {
+		return nil, &ai.HTTPError{StatusCode: 400, Message: "bad request"}
+	}
+	return &ai.Response{Text: f.text, Provider: f.name}, nil
+}
+
+func TestComplete_FallsBackInOrder(t *testing.T) {
+	first := &fakeProvider{name: "first"}
+	second := &fakeProvider{name: "second", text: "ok"}
+	third := &fakeProvider{name: "third", text: "unused"}
+
+	resp, err := complete(context.Background(), io.Discard, []ai.Provider{first, second, third}, ai.Prompt("p", 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Provider != "second" {
+		t.Errorf("answered by %s, want second", resp.Provider)
+	}
+	if third.calls != 0 {
+		t.Errorf("third provider called %d times, want 0", third.calls)
+	}
+}
+
+func TestComplete_StopsOnCancel(t *testing.T) {
+	first := &fakeProvider{name: "first"}
+	second := &fakeProvider{name: "second", text: "ok"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := complete(ctx, io.Discard, []ai.Provider{first, second}, ai.Prompt("p", 0))
+	if err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if second.calls != 0 {
+		t.Error("fell back after the context was cancelled")
+	}
+}
+
+func TestComplete_EmptyChain(t *testing.T) {
+	if _, err := complete(context.Background(), io.Discard, nil, ai.Prompt("p", 0)); err == nil {
+		t.Error("expected an error for an empty chain")
+	}
+}
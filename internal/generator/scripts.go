@@ -2,13 +2,11 @@ package generator
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strings"
+	"io"
+	"os"
 
 	"github.com/gagipress/gagipress-cli/internal/ai"
-	"github.com/gagipress/gagipress-cli/internal/config"
-	"github.com/gagipress/gagipress-cli/internal/errors"
 	"github.com/gagipress/gagipress-cli/internal/models"
 	"github.com/gagipress/gagipress-cli/internal/prompts"
 	"github.com/gagipress/gagipress-cli/internal/repository"
@@ -16,22 +14,31 @@ import (
 
 // ScriptGenerator generates content scripts from ideas
 type ScriptGenerator struct {
-	openaiClient *ai.OpenAIClient
-	geminiClient *ai.GeminiClient
-	contentRepo  *repository.ContentRepository
-	useGemini    bool
+	providers   []ai.Provider
+	contentRepo repository.ContentStore
+	ledger      *Ledger
+	out         io.Writer
 }
 
-// NewScriptGenerator creates a new script generator
-func NewScriptGenerator(cfg *config.Config, useGemini bool) *ScriptGenerator {
+// NewScriptGenerator creates a new script generator. providers is the
+// fallback chain: each one is tried in order until one answers. ledger may
+// be nil.
+func NewScriptGenerator(providers []ai.Provider, contentRepo repository.ContentStore, ledger *Ledger) *ScriptGenerator {
 	return &ScriptGenerator{
-		openaiClient: ai.NewOpenAIClient(&cfg.OpenAI),
-		geminiClient: ai.NewGeminiClient(true),
-		contentRepo:  repository.NewContentRepository(&cfg.Supabase),
-		useGemini:    useGemini,
+		providers:   providers,
+		contentRepo: contentRepo,
+		ledger:      ledger,
+		out:         os.Stdout,
 	}
 }
 
+// SetOutput redirects progress and warnings, which go to stdout by default.
+// Concurrent callers give each generator its own writer so that the output
+// of different scripts does not interleave.
+func (g *ScriptGenerator) SetOutput(w io.Writer) {
+	g.out = w
+}
+
 // GeneratedScript represents a generated script from AI
 type GeneratedScript struct {
 	Hook            string   `json:"hook"`
@@ -41,98 +48,41 @@ type GeneratedScript struct {
 	MusicSuggestion string   `json:"music_suggestion"`
 	VideoNotes      string   `json:"video_notes"`
 	EstimatedLength int      `json:"estimated_length"`
+
+	usageIDs []string // ledger rows to link once the script is saved
 }
 
 // GenerateScript generates a complete script from an idea.
 // amazonURL is the direct Amazon link for the CTA (empty string if no ASIN).
-func (g *ScriptGenerator) GenerateScript(idea *models.ContentIdea, bookTitle, platform, amazonURL string) (*GeneratedScript, error) {
+// The response is validated against scriptSchema and repaired by
+// re-prompting when needed.
+func (g *ScriptGenerator) GenerateScript(ctx context.Context, idea *models.ContentIdea, bookTitle, platform, amazonURL string) (*GeneratedScript, error) {
 	// Build prompt
 	ideaDescription := idea.BriefDescription
 	prompt := prompts.ScriptPromptTemplate(ideaDescription, bookTitle, platform, amazonURL)
 
-	var responseText string
-	var err error
-
-	ctx := context.Background()
-
-	// Try OpenAI first with retry logic unless explicitly using Gemini
-	if !g.useGemini {
-		fmt.Println("🤖 Using OpenAI for script generation...")
-
-		retryErr := errors.Retry(ctx, errors.DefaultRetryConfig(), func() error {
-			responseText, err = g.openaiClient.GenerateText(prompt, 0.7)
-			if err != nil {
-				return errors.Wrap(err, errors.ErrorTypeAPI, "OpenAI API call failed")
-			}
-			return nil
-		})
-
-		if retryErr != nil {
-			fmt.Printf("⚠️  OpenAI failed after retries: %v\n", retryErr)
-			fmt.Println("🔄 Falling back to Gemini...")
-			g.useGemini = true
-		}
-	}
-
-	// Fallback to Gemini if OpenAI failed or explicitly requested
-	if g.useGemini {
-		fmt.Println("🤖 Using Gemini for script generation...")
-		responseText, err = g.geminiClient.GenerateText(prompt)
-		if err != nil {
-			return nil, errors.Wrap(err, errors.ErrorTypeAPI, "both OpenAI and Gemini failed")
-		}
-	}
-
-	// Parse JSON response
-	script, err := g.parseScriptFromResponse(responseText)
-	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrorTypeValidation, "failed to parse AI response")
-	}
-
-	return script, nil
-}
-
-// parseScriptFromResponse parses the AI response into a structured script
-func (g *ScriptGenerator) parseScriptFromResponse(response string) (*GeneratedScript, error) {
-	// Extract JSON object from response
-	start := strings.Index(response, "{")
-	end := strings.LastIndex(response, "}")
-
-	if start == -1 || end == -1 || start > end {
-		return nil, fmt.Errorf("no JSON object found in response")
-	}
-
-	jsonStr := response[start : end+1]
+	req := ai.Prompt(prompt, 0.7)
+	req.Schema = scriptSchema
+	req.SchemaName = "content_script"
 
 	var script GeneratedScript
-	if err := json.Unmarshal([]byte(jsonStr), &script); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
-	}
-
-	// Validate required fields
-	if script.Hook == "" {
-		return nil, fmt.Errorf("missing required field: hook")
-	}
-	if script.MainContent == "" {
-		return nil, fmt.Errorf("missing required field: main_content")
-	}
-	if script.CTA == "" {
-		return nil, fmt.Errorf("missing required field: cta")
-	}
-
-	// Set defaults if missing
-	if script.EstimatedLength == 0 {
-		script.EstimatedLength = 45 // default 45 seconds
+	link := usageLink{bookID: idea.BookID, ideaID: &idea.ID, contentType: &idea.Type}
+	var usageIDs []string
+	record := func(resp *ai.Response) {
+		if id := g.ledger.record(ctx, g.out, resp, link); id != "" {
+			usageIDs = append(usageIDs, id)
+		}
 	}
-	if len(script.Hashtags) == 0 {
-		script.Hashtags = []string{"#booktok", "#bookstagram"} // minimal defaults
+	if _, err := completeJSON(ctx, g.out, g.providers, req, &script, record); err != nil {
+		return nil, err
 	}
+	script.usageIDs = usageIDs
 
 	return &script, nil
 }
 
 // SaveScript saves generated script to the database
-func (g *ScriptGenerator) SaveScript(script *GeneratedScript, ideaID string) (*models.ContentScript, error) {
+func (g *ScriptGenerator) SaveScript(ctx context.Context, script *GeneratedScript, ideaID string) (*models.ContentScript, error) {
 	input := &models.ContentScriptInput{
 		IdeaID:            ideaID,
 		Hook:              script.Hook,
@@ -146,14 +96,16 @@ func (g *ScriptGenerator) SaveScript(script *GeneratedScript, ideaID string) (*m
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	savedScript, err := g.contentRepo.CreateScript(input)
+	savedScript, err := g.contentRepo.CreateScript(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save script: %w", err)
 	}
 
+	g.ledger.linkScript(ctx, g.out, script.usageIDs, savedScript.ID)
+
 	// Update idea status to "scripted"
-	if err := g.contentRepo.UpdateIdeaStatus(ideaID, "scripted"); err != nil {
-		fmt.Printf("⚠️  Warning: failed to update idea status: %v\n", err)
+	if err := g.contentRepo.UpdateIdeaStatus(ctx, ideaID, "scripted"); err != nil {
+		fmt.Fprintf(g.out, "⚠️  Warning: failed to update idea status: %v\n", err)
 	}
 
 	return savedScript, nil
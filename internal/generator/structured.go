@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gagipress/gagipress-cli/internal/ai"
+	"github.com/gagipress/gagipress-cli/internal/errors"
+)
+
+// maxRepairs is how often a non-conforming answer is sent back to the model
+// with its validation errors before generation gives up.
+var maxRepairs = 2
+
+// ideaTypes are the idea types accepted by the content_ideas table
+var ideaTypes = []string{"educational", "entertainment", "bts", "ugc", "trend"}
+
+// ideaSchema describes the response to prompts.IdeaPromptTemplate. The
+// ideas are wrapped in an object because strict structured output requires
+// an object at the top level.
+var ideaSchema = ai.Object(map[string]*ai.Schema{
+	"ideas": ai.Array(ai.Object(map[string]*ai.Schema{
+		"type":            ai.Enum("content category", ideaTypes...),
+		"title":           ai.String("catchy title, max 10 words", 1),
+		"description":     ai.String("2-3 sentence description", 1),
+		"hook":            ai.String("opening hook", 1),
+		"cta":             ai.String("closing call to action", 1),
+		"relevance_score": ai.Integer("relevance for the book, 0-100", 0, 100),
+	}), 1),
+})
+
+// scriptSchema describes a GeneratedScript
+var scriptSchema = ai.Object(map[string]*ai.Schema{
+	"hook":             ai.String("hook for the first 3-5 seconds", 1),
+	"main_content":     ai.String("main content, in paragraphs", 1),
+	"cta":              ai.String("call to action", 1),
+	"hashtags":         ai.Array(ai.String("hashtag starting with #", 2), 1),
+	"music_suggestion": ai.String("trending track or audio", 0),
+	"video_notes":      ai.String("editing notes", 0),
+	"estimated_length": ai.Integer("length in seconds", 5, 180),
+})
+
+// completeJSON asks the chain for JSON conforming to req.Schema and decodes
+// it into out. An answer that is not valid JSON or fails validation is sent
+// back with the exact problems, up to maxRepairs times. onResponse, when
+// set, sees every answer including rejected ones, so their usage can be
+// recorded. The returned response is the accepted one, with the usage of
+// every attempt added up. Progress is written to w.
+func completeJSON(ctx context.Context, w io.Writer, providers []ai.Provider, req ai.Request, out any, onResponse func(*ai.Response)) (*ai.Response, error) {
+	var usage ai.Usage
+	for attempt := 0; ; attempt++ {
+		resp, err := complete(ctx, w, providers, req)
+		if err != nil {
+			return nil, err
+		}
+		if onResponse != nil {
+			onResponse(resp)
+		}
+		usage.PromptTokens += resp.Usage.PromptTokens
+		usage.CompletionTokens += resp.Usage.CompletionTokens
+
+		text := ai.TrimJSON(resp.Text)
+		problems := req.Schema.ValidateJSON(text)
+		if len(problems) == 0 {
+			if err := json.Unmarshal([]byte(text), out); err != nil {
+				problems = []string{fmt.Sprintf("response does not decode: %v", err)}
+			}
+		}
+		if len(problems) == 0 {
+			resp.Usage = usage
+			return resp, nil
+		}
+
+		if attempt == maxRepairs {
+			return nil, errors.New(errors.ErrorTypeValidation, fmt.Sprintf(
+				"AI response still invalid after %d repair attempts:\n- %s", maxRepairs, strings.Join(problems, "\n- ")))
+		}
+
+		fmt.Fprintf(w, "🔧 Response failed validation (%d problems), asking %s to fix it...\n", len(problems), resp.Provider)
+		req.Messages = append(append([]ai.ChatMessage{}, req.Messages...),
+			ai.ChatMessage{Role: "assistant", Content: resp.Text},
+			ai.ChatMessage{Role: "user", Content: repairPrompt(problems)},
+		)
+	}
+}
+
+// repairPrompt lists the validation problems of the previous answer
+func repairPrompt(problems []string) string {
+	return "Your previous answer does not match the required JSON schema:\n- " +
+		strings.Join(problems, "\n- ") +
+		"\nReply with the corrected JSON only, keeping the content that was valid."
+}
@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/ai"
+	"github.com/gagipress/gagipress-cli/internal/errors"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/prompts"
+	"github.com/gagipress/gagipress-cli/internal/repository/memory"
+)
+
+// scriptedProvider answers with replies in order and records each request
+type scriptedProvider struct {
+	replies  []string
+	requests []ai.Request
+}
+
+func (s *scriptedProvider) Name() string  { return "scripted" }
+func (s *scriptedProvider) Model() string { return "fake" }
+
+func (s *scriptedProvider) Complete(ctx context.Context, req ai.Request) (*ai.Response, error) {
+	s.requests = append(s.requests, req)
+	text := s.replies[len(s.requests)-1]
+	return &ai.Response{Text: text, Provider: "scripted", Model: "fake", Usage: ai.Usage{PromptTokens: 10, CompletionTokens: 5}}, nil
+}
+
+const validScript = `{"hook":"Lo sapevi?","main_content":"Tre trucchi.","cta":"Link in bio","hashtags":["#booktok"],
+	"music_suggestion":"","video_notes":"","estimated_length":30}`
+
+func TestCompleteJSON_RepairsWithValidationErrors(t *testing.T) {
+	p := &scriptedProvider{replies: []string{
+		`Ecco lo script: {"hook":"Lo sapevi?"}`,
+		`{"hook":"Lo sapevi?","main_content":"Tre trucchi.","cta":"","hashtags":[],"music_suggestion":"","video_notes":"","estimated_length":"30"}`,
+		"```json\n" + validScript + "\n```",
+	}}
+
+	req := ai.Prompt("write a script", 0.7)
+	req.Schema = scriptSchema
+	var script GeneratedScript
+	resp, err := completeJSON(context.Background(), io.Discard, []ai.Provider{p}, req, &script, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if script.Hook != "Lo sapevi?" || script.EstimatedLength != 30 || len(script.Hashtags) != 1 {
+		t.Errorf("script = %+v", script)
+	}
+	if resp.Usage.PromptTokens != 30 || resp.Usage.CompletionTokens != 15 {
+		t.Errorf("usage = %+v, want the sum of three attempts", resp.Usage)
+	}
+
+	// The second repair prompt carries the exact problems of the second answer
+	last := p.requests[2].Messages
+	if len(last) != 5 || last[3].Role != "assistant" || last[4].Role != "user" {
+		t.Fatalf("messages = %+v, want prompt plus two repair rounds", last)
+	}
+	for _, want := range []string{
+		"cta: must not be empty",
+		"hashtags: expected at least 1 items, got 0",
+		"estimated_length: expected an integer, got a string",
+	} {
+		if !strings.Contains(last[4].Content, want) {
+			t.Errorf("repair prompt missing %q:\n%s", want, last[4].Content)
+		}
+	}
+	if !strings.Contains(p.requests[1].Messages[2].Content, "not valid JSON") {
+		t.Errorf("first repair prompt = %q", p.requests[1].Messages[2].Content)
+	}
+}
+
+func TestCompleteJSON_GivesUp(t *testing.T) {
+	p := &scriptedProvider{replies: []string{`{}`, `{}`, `{}`, validScript}}
+
+	req := ai.Prompt("write a script", 0.7)
+	req.Schema = scriptSchema
+	var script GeneratedScript
+	_, err := completeJSON(context.Background(), io.Discard, []ai.Provider{p}, req, &script, nil)
+	if !errors.IsType(err, errors.ErrorTypeValidation) {
+		t.Fatalf("err = %v, want a validation error", err)
+	}
+	if len(p.requests) != maxRepairs+1 {
+		t.Errorf("sent %d requests, want %d", len(p.requests), maxRepairs+1)
+	}
+	if !strings.Contains(err.Error(), `missing required field "hook"`) {
+		t.Errorf("err = %v, want the remaining problems", err)
+	}
+}
+
+func TestGenerateIdeas_DecodesWrappedArray(t *testing.T) {
+	p := &scriptedProvider{replies: []string{`{"ideas":[
+		{"type":"educational","title":"T","description":"D","hook":"H","cta":"C","relevance_score":80},
+		{"type":"trend","title":"T2","description":"D2","hook":"H2","cta":"C2","relevance_score":55}]}`}}
+
+	g := NewIdeaGenerator([]ai.Provider{p}, nil, nil)
+	ideas, err := g.GenerateIdeas(context.Background(), "", "Libro", "children", "genitori", prompts.ChildrenBooks, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ideas) != 2 || ideas[1].Type != "trend" || ideas[0].RelevanceScore != 80 {
+		t.Errorf("ideas = %+v", ideas)
+	}
+	if p.requests[0].Schema != ideaSchema {
+		t.Error("request was sent without the idea schema")
+	}
+}
+
+func TestGenerateCampaignIdeas_KeepsPhaseTypesAndTagsCampaign(t *testing.T) {
+	ctx := context.Background()
+	stores := memory.New().Stores()
+	book, err := stores.Books.Create(ctx, &models.BookInput{Title: "Libro", Genre: "children"})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	launch, _ := time.Parse(models.DateFormat, "2026-11-01")
+	campaign, err := stores.Campaigns.CreateCampaign(ctx, &models.CampaignInput{BookID: book.ID, Name: "Libro launch", LaunchDate: models.Date{Time: launch}})
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+
+	p := &scriptedProvider{replies: []string{`{"ideas":[
+		{"type":"bts","title":"T","description":"D","hook":"H","cta":"C","relevance_score":80},
+		{"type":"educational","title":"T2","description":"D2","hook":"H2","cta":"C2","relevance_score":55}]}`}}
+	g := NewIdeaGenerator([]ai.Provider{p}, stores.Content, nil)
+	g.SetOutput(io.Discard)
+	g.SetCampaign(campaign.ID)
+
+	ideas, err := g.GenerateCampaignIdeas(ctx, book.ID, book.Title, book.Genre, "", prompts.ChildrenBooks, 2, "teaser", "2026-11-01", []string{"bts", "trend"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ideas) != 1 || ideas[0].Type != "bts" || ideas[0].Phase != "teaser" {
+		t.Fatalf("ideas = %+v, want the bts idea only", ideas)
+	}
+	if prompt := p.requests[0].Messages[0].Content; !strings.Contains(prompt, "bts, trend") {
+		t.Errorf("prompt does not restrict the types:\n%s", prompt)
+	}
+
+	saved, err := g.SaveIdeas(ctx, ideas, &book.ID)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if len(saved) != 1 || saved[0].CampaignID == nil || *saved[0].CampaignID != campaign.ID {
+		t.Fatalf("saved = %+v, want it tagged with campaign %s", saved, campaign.ID)
+	}
+	if metadata, _ := saved[0].Metadata.(map[string]string); metadata["campaign_phase"] != "teaser" {
+		t.Errorf("metadata = %v, want campaign_phase teaser", saved[0].Metadata)
+	}
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/gagipress/gagipress-cli/internal/ai"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+// Ledger records every AI call made by a generator in the ai_usage table,
+// with its cost estimated from the price table. A nil *Ledger records
+// nothing.
+type Ledger struct {
+	store   repository.UsageStore
+	prices  *ai.PriceTable
+	command string
+}
+
+// NewLedger creates a ledger that attributes calls to command (e.g.
+// "generate ideas").
+func NewLedger(store repository.UsageStore, prices *ai.PriceTable, command string) *Ledger {
+	return &Ledger{store: store, prices: prices, command: command}
+}
+
+// usageLink ties an AI call to the content it was made for
+type usageLink struct {
+	bookID      *string
+	ideaID      *string
+	contentType *string
+}
+
+// record stores one call and returns the ledger row ID, or "" when nothing
+// was stored. A failed write is reported on out but never fails generation.
+func (l *Ledger) record(ctx context.Context, out io.Writer, resp *ai.Response, link usageLink) string {
+	if l == nil {
+		return ""
+	}
+
+	cost, _ := l.prices.Cost(resp.Provider, resp.Model, resp.Usage)
+	row, err := l.store.RecordUsage(ctx, &models.AIUsageInput{
+		Provider:         resp.Provider,
+		Model:            resp.Model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		CostUSD:          cost,
+		Command:          l.command,
+		BookID:           link.bookID,
+		IdeaID:           link.ideaID,
+		ContentType:      link.contentType,
+	})
+	if err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: %v\n", err)
+		return ""
+	}
+	return row.ID
+}
+
+// linkScript attaches the rows recorded while generating a script to the
+// saved script.
+func (l *Ledger) linkScript(ctx context.Context, out io.Writer, ids []string, scriptID string) {
+	if l == nil || len(ids) == 0 {
+		return
+	}
+	if err := l.store.LinkUsageToScript(ctx, ids, scriptID); err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: %v\n", err)
+	}
+}
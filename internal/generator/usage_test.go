@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/ai"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository/memory"
+)
+
+func TestLedger_RecordsEveryCallAndLinksScript(t *testing.T) {
+	ctx := context.Background()
+	stores := memory.New().Stores()
+
+	book, err := stores.Books.Create(ctx, &models.BookInput{Title: "Libro", Genre: "children"})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	idea, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{Type: "trend", BriefDescription: "Idea", BookID: &book.ID})
+	if err != nil {
+		t.Fatalf("create idea: %v", err)
+	}
+
+	// One rejected answer, then a valid one: both calls are billed
+	p := &scriptedProvider{replies: []string{`{}`, validScript}}
+	ledger := NewLedger(stores.Usage, ai.NewPriceTable(nil), "generate script")
+	gen := NewScriptGenerator([]ai.Provider{p}, stores.Content, ledger)
+
+	script, err := gen.GenerateScript(ctx, idea, book.Title, "tiktok", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	saved, err := gen.SaveScript(ctx, script, idea.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := stores.Usage.GetUsage(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("recorded %d rows, want 2", len(rows))
+	}
+	for _, r := range rows {
+		if r.Command != "generate script" || r.Provider != "scripted" || r.PromptTokens != 10 {
+			t.Errorf("row = %+v", r)
+		}
+		if r.BookID == nil || *r.BookID != book.ID || r.IdeaID == nil || *r.IdeaID != idea.ID {
+			t.Errorf("row links = book %v idea %v", r.BookID, r.IdeaID)
+		}
+		if r.ScriptID == nil || *r.ScriptID != saved.ID {
+			t.Errorf("row not linked to the saved script: %v", r.ScriptID)
+		}
+		if r.ContentType == nil || *r.ContentType != "trend" {
+			t.Errorf("content type = %v", r.ContentType)
+		}
+	}
+}
+
+func TestPriceTable_Cost(t *testing.T) {
+	prices := ai.NewPriceTable(nil)
+	usage := ai.Usage{PromptTokens: 1_000_000, CompletionTokens: 500_000}
+
+	tests := []struct {
+		provider, model string
+		want            float64
+		ok              bool
+	}{
+		{"openai", "gpt-4o-mini-2024-07-18", 0.15 + 0.30, true},
+		{"openai", "gpt-4o-2024-08-06", 2.50 + 5.00, true},
+		{"gemini", "models/gemini-2.5-flash", 0.30 + 1.25, true},
+		{"ollama", "llama3.1:8b", 0, true},
+		{"openai_compatible", "mystery-model", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := prices.Cost(tt.provider, tt.model, usage)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Cost(%s, %s) = %v, %v; want %v, %v", tt.provider, tt.model, got, ok, tt.want, tt.ok)
+		}
+	}
+}
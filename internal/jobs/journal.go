@@ -0,0 +1,271 @@
+// Package jobs keeps a journal of batch runs, one JSON file per job, so an
+// interrupted batch can be resumed without redoing, or re-posting, the items
+// it already finished.
+//
+// The journal lives on local disk rather than in the storage backend on
+// purpose: it has to record that Blotato accepted a post even when the
+// write that marks the calendar entry published is the thing that failed.
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job kinds
+const (
+	KindGenerateBatch = "generate batch"
+	KindPublishBatch  = "publish batch"
+)
+
+// Item states
+const (
+	StatePending   = "pending"
+	StateRunning   = "running"   // started; the outcome is unknown if the job died here
+	StateSubmitted = "submitted" // accepted remotely, local bookkeeping not finished
+	StateDone      = "done"
+	StateFailed    = "failed"
+	StateSkipped   = "skipped"
+	StateUnknown   = "unknown" // may or may not have reached the remote service
+)
+
+// DefaultDir is where the journal is kept
+const DefaultDir = "~/.gagipress/jobs"
+
+// Item is the journaled state of one unit of work of a job
+type Item struct {
+	Key       string    `json:"key"`              // idea or calendar entry ID
+	State     string    `json:"state"`            // one of the State constants
+	Result    string    `json:"result,omitempty"` // e.g. the script or submission ID
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Job is one batch run. Every update is written to disk before it returns,
+// and a Job is safe for concurrent use by the workers of a batch.
+type Job struct {
+	ID        string            `json:"id"`
+	Kind      string            `json:"kind"`
+	Args      map[string]string `json:"args,omitempty"` // flags needed to resume
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Items     []Item            `json:"items"`
+
+	mu   sync.Mutex
+	path string
+}
+
+// Journal is a directory of job files
+type Journal struct {
+	dir string
+}
+
+// Open opens the journal in dir, creating the directory if needed. A
+// leading "~/" is expanded to the home directory.
+func Open(dir string) (*Journal, error) {
+	if strings.HasPrefix(dir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get home directory: %w", err)
+		}
+		dir = filepath.Join(home, dir[2:])
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create job journal directory: %w", err)
+	}
+	return &Journal{dir: dir}, nil
+}
+
+// Create starts a job of kind with one pending item per key
+func (jl *Journal) Create(kind string, args map[string]string, keys []string) (*Job, error) {
+	now := time.Now().UTC()
+	suffix := make([]byte, 2)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate job ID: %w", err)
+	}
+	id := now.Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+
+	job := &Job{
+		ID:        id,
+		Kind:      kind,
+		Args:      args,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Items:     make([]Item, len(keys)),
+		path:      filepath.Join(jl.dir, id+".json"),
+	}
+	for i, key := range keys {
+		job.Items[i] = Item{Key: key, State: StatePending, UpdatedAt: now}
+	}
+
+	if err := job.save(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Get loads the job with the given ID or unique ID prefix
+func (jl *Journal) Get(id string) (*Job, error) {
+	all, err := jl.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*Job
+	for _, job := range all {
+		if job.ID == id {
+			return job, nil
+		}
+		if strings.HasPrefix(job.ID, id) {
+			matches = append(matches, job)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("job not found: %s", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("job ID prefix %q is ambiguous (%d matches)", id, len(matches))
+	}
+}
+
+// List returns every job, newest first
+func (jl *Journal) List() ([]*Job, error) {
+	paths, err := filepath.Glob(filepath.Join(jl.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	jobs := make([]*Job, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job: %w", err)
+		}
+		job := &Job{path: path}
+		if err := json.Unmarshal(data, job); err != nil {
+			return nil, fmt.Errorf("failed to parse job %s: %w", filepath.Base(path), err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID > jobs[j].ID })
+	return jobs, nil
+}
+
+// LastRun is the newest job that got past pending for a key, and its item
+type LastRun struct {
+	Job  *Job
+	Item Item
+}
+
+// LastRuns reads the journal once and returns, for every key a job of kind
+// got past pending, its LastRun. Keys no job has started are absent.
+func (jl *Journal) LastRuns(kind string) (map[string]LastRun, error) {
+	all, err := jl.List()
+	if err != nil {
+		return nil, err
+	}
+	runs := make(map[string]LastRun)
+	for _, job := range all { // newest first
+		if job.Kind != kind {
+			continue
+		}
+		for _, item := range job.Items {
+			if _, seen := runs[item.Key]; !seen && item.State != StatePending {
+				runs[item.Key] = LastRun{Job: job, Item: item}
+			}
+		}
+	}
+	return runs, nil
+}
+
+// Item returns a copy of the item for key
+func (j *Job) Item(key string) (Item, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, item := range j.Items {
+		if item.Key == key {
+			return item, true
+		}
+	}
+	return Item{}, false
+}
+
+// Update sets the state of the item for key and writes the job to disk.
+// result and errMsg replace the previous values.
+func (j *Job) Update(key, state, result, errMsg string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now().UTC()
+	for i := range j.Items {
+		if j.Items[i].Key != key {
+			continue
+		}
+		j.Items[i].State = state
+		j.Items[i].Result = result
+		j.Items[i].Error = errMsg
+		j.Items[i].UpdatedAt = now
+		j.UpdatedAt = now
+		return j.save()
+	}
+	return fmt.Errorf("job %s has no item %s", j.ID, key)
+}
+
+// Counts returns the number of items in each state
+func (j *Job) Counts() map[string]int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	counts := make(map[string]int)
+	for _, item := range j.Items {
+		counts[item.State]++
+	}
+	return counts
+}
+
+// Complete reports whether every item is done or skipped
+func (j *Job) Complete() bool {
+	counts := j.Counts()
+	return counts[StateDone]+counts[StateSkipped] == len(j.Items)
+}
+
+// save writes the job atomically: to a temporary file that is synced and
+// then renamed over the old one. Callers hold j.mu, except on creation.
+func (j *Job) save() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), j.ID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write job: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write job: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write job: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write job: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		return fmt.Errorf("failed to write job: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package jobs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournal_UpdatesSurviveReload(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	job, err := journal.Create(KindGenerateBatch, map[string]string{"platform": "tiktok"}, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := job.Update("a", StateDone, "script-1", ""); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := job.Update("b", StateFailed, "", "boom"); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := job.Update("zzz", StateDone, "", ""); err == nil {
+		t.Error("Update of an unknown item succeeded")
+	}
+
+	// Only the job file is left behind, no temporary files
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(files) != 1 {
+		t.Errorf("journal dir holds %v, want one job file", files)
+	}
+
+	reopened, _ := Open(dir)
+	got, err := reopened.Get(job.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Kind != KindGenerateBatch || got.Args["platform"] != "tiktok" {
+		t.Errorf("job = %+v", got)
+	}
+	if a, _ := got.Item("a"); a.State != StateDone || a.Result != "script-1" {
+		t.Errorf("item a = %+v", a)
+	}
+	if b, _ := got.Item("b"); b.State != StateFailed || b.Error != "boom" {
+		t.Errorf("item b = %+v", b)
+	}
+	if c, _ := got.Item("c"); c.State != StatePending {
+		t.Errorf("item c = %+v", c)
+	}
+	if got.Complete() {
+		t.Error("job with failed and pending items reported complete")
+	}
+
+	got.Update("b", StateDone, "script-2", "")
+	got.Update("c", StateSkipped, "", "")
+	if !got.Complete() {
+		t.Errorf("job not complete with counts %v", got.Counts())
+	}
+}
+
+func TestJournal_GetByPrefixAndListOrder(t *testing.T) {
+	dir := t.TempDir()
+	journal, _ := Open(dir)
+
+	// Job IDs start with the creation time, so write them directly to get
+	// distinct, ordered IDs
+	for _, id := range []string{"20260101-090000-aaaa", "20260102-090000-bbbb", "20260102-100000-cccc"} {
+		job := &Job{ID: id, Kind: KindPublishBatch, path: filepath.Join(dir, id+".json")}
+		if err := job.save(); err != nil {
+			t.Fatalf("save failed: %v", err)
+		}
+	}
+
+	all, err := journal.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != "20260102-100000-cccc" || all[2].ID != "20260101-090000-aaaa" {
+		t.Errorf("List order = %v, want newest first", all)
+	}
+
+	if job, err := journal.Get("20260101"); err != nil || job.ID != "20260101-090000-aaaa" {
+		t.Errorf("Get(unique prefix) = %v, %v", job, err)
+	}
+	if _, err := journal.Get("20260102"); err == nil {
+		t.Error("Get(ambiguous prefix) succeeded")
+	}
+	if _, err := journal.Get("2030"); err == nil {
+		t.Error("Get(unknown) succeeded")
+	}
+}
+
+func TestJournal_LastRuns(t *testing.T) {
+	dir := t.TempDir()
+	journal, _ := Open(dir)
+
+	older := &Job{ID: "20260101-090000-aaaa", Kind: KindPublishBatch, path: filepath.Join(dir, "20260101-090000-aaaa.json"),
+		Items: []Item{{Key: "e1", State: StateSubmitted, Result: "sub-1"}, {Key: "e2", State: StateRunning}}}
+	newer := &Job{ID: "20260102-090000-bbbb", Kind: KindPublishBatch, path: filepath.Join(dir, "20260102-090000-bbbb.json"),
+		Items: []Item{{Key: "e1", State: StatePending}, {Key: "e2", State: StateDone, Result: "sub-2"}}}
+	other := &Job{ID: "20260103-090000-cccc", Kind: KindGenerateBatch, path: filepath.Join(dir, "20260103-090000-cccc.json"),
+		Items: []Item{{Key: "e1", State: StateFailed}}}
+	for _, job := range []*Job{older, newer, other} {
+		if err := job.save(); err != nil {
+			t.Fatalf("save failed: %v", err)
+		}
+	}
+
+	// A pending item in a newer job does not hide the submission, and jobs
+	// of another kind are ignored
+	runs, err := journal.LastRuns(KindPublishBatch)
+	if err != nil {
+		t.Fatalf("LastRuns failed: %v", err)
+	}
+	if run, ok := runs["e1"]; !ok || run.Job.ID != older.ID || run.Item.State != StateSubmitted || run.Item.Result != "sub-1" {
+		t.Errorf("runs[e1] = %v, %+v", run.Job, run.Item)
+	}
+
+	// A later outcome replaces an earlier one
+	if run, ok := runs["e2"]; !ok || run.Job.ID != newer.ID || run.Item.State != StateDone {
+		t.Errorf("runs[e2] = %v, %+v", run.Job, run.Item)
+	}
+
+	if run, ok := runs["e3"]; ok {
+		t.Errorf("runs[e3] = %v, %+v, want nothing", run.Job, run.Item)
+	}
+}
+
+func TestOpen_CreatesDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "jobs")
+	if _, err := Open(dir); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("journal directory not created: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// Campaign is a phased posting plan around a book launch. The ideas and
+// calendar entries it creates carry its ID.
+type Campaign struct {
+	ID         string    `json:"id"`
+	BookID     string    `json:"book_id"`
+	Name       string    `json:"name"`
+	LaunchDate Date      `json:"launch_date"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CampaignInput represents input for creating a campaign
+type CampaignInput struct {
+	BookID     string `json:"book_id"`
+	Name       string `json:"name"`
+	LaunchDate Date   `json:"launch_date"`
+}
+
+// Validate validates campaign input
+func (c *CampaignInput) Validate() error {
+	if c.BookID == "" {
+		return ErrInvalidInput{Field: "book_id", Message: "book ID is required"}
+	}
+	if c.Name == "" {
+		return ErrInvalidInput{Field: "name", Message: "name is required"}
+	}
+	if c.LaunchDate.IsZero() {
+		return ErrInvalidInput{Field: "launch_date", Message: "launch date is required"}
+	}
+	return nil
+}
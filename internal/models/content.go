@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -14,6 +16,34 @@ type ContentIdea struct {
 	Status           string    `json:"status"` // pending, approved, rejected, scripted
 	GeneratedAt      time.Time `json:"generated_at"`
 	Metadata         any       `json:"metadata,omitempty"` // JSONB field
+	CampaignID       *string   `json:"campaign_id,omitempty"`
+}
+
+// IdeaStatuses lists the statuses allowed by the content_ideas CHECK constraint.
+var IdeaStatuses = []string{"pending", "approved", "rejected", "scripted"}
+
+// CalendarStatuses lists the statuses allowed by the content_calendar CHECK
+// constraint (migration 004 added the transient 'publishing' lock, 012 the
+// 'submitted' state of posts Blotato accepted but has not delivered yet).
+var CalendarStatuses = []string{"pending_approval", "approved", "publishing", "submitted", "published", "failed"}
+
+// ValidateIdeaStatus checks status against IdeaStatuses.
+func ValidateIdeaStatus(status string) error {
+	return validateStatus(status, IdeaStatuses)
+}
+
+// ValidateCalendarStatus checks status against CalendarStatuses.
+func ValidateCalendarStatus(status string) error {
+	return validateStatus(status, CalendarStatuses)
+}
+
+func validateStatus(status string, allowed []string) error {
+	for _, s := range allowed {
+		if s == status {
+			return nil
+		}
+	}
+	return ErrInvalidInput{Field: "status", Message: fmt.Sprintf("invalid status %q (must be one of: %s)", status, strings.Join(allowed, ", "))}
 }
 
 // ContentIdeaInput represents input for creating a content idea
@@ -23,6 +53,7 @@ type ContentIdeaInput struct {
 	RelevanceScore   *int    `json:"relevance_score,omitempty"`
 	BookID           *string `json:"book_id,omitempty"`
 	Metadata         any     `json:"metadata,omitempty"`
+	CampaignID       *string `json:"campaign_id,omitempty"`
 }
 
 // Validate validates content idea input
@@ -87,16 +118,52 @@ func (c *ContentScriptInput) Validate() error {
 
 // ContentCalendar represents a scheduled post
 type ContentCalendar struct {
-	ID            string     `json:"id"`
-	ScriptID      *string    `json:"script_id,omitempty"`
-	ScheduledFor  time.Time  `json:"scheduled_for"`
-	Platform      string     `json:"platform"`  // instagram, tiktok
-	PostType      string     `json:"post_type"` // reel, story, feed - REQUIRED
-	Status        string     `json:"status"`    // pending_approval, approved, published, failed
-	PublishedAt   *time.Time `json:"published_at,omitempty"`
-	PublishErrors any        `json:"publish_errors,omitempty"` // JSONB field
-	GenerateMedia bool       `json:"generate_media"`
-	MediaURL      *string    `json:"media_url,omitempty"`
+	ID            string         `json:"id"`
+	ScriptID      *string        `json:"script_id,omitempty"`
+	ScheduledFor  time.Time      `json:"scheduled_for"`
+	Platform      string         `json:"platform"`  // a registered platform, see Platforms
+	PostType      string         `json:"post_type"` // reel, story, feed - REQUIRED
+	Status        string         `json:"status"`    // pending_approval, approved, submitted, published, failed
+	PublishedAt   *time.Time     `json:"published_at,omitempty"`
+	PublishErrors []PublishError `json:"publish_errors,omitempty"` // JSONB field
+	GenerateMedia bool           `json:"generate_media"`
+	MediaURL      *string        `json:"media_url,omitempty"`
+	SubmissionID  *string        `json:"submission_id,omitempty"` // ID the publisher gave the post
+	Publisher     *string        `json:"publisher,omitempty"`     // publisher the submission went to
+	PostURL       *string        `json:"post_url,omitempty"`      // live post, once delivered
+	CampaignID    *string        `json:"campaign_id,omitempty"`   // launch campaign that planned the post
+}
+
+// PublishError is one failed attempt to publish a calendar entry. Entries
+// keep every attempt in publish_errors, oldest first.
+type PublishError struct {
+	At           time.Time `json:"at"`
+	Stage        string    `json:"stage"` // submit, delivery or media
+	Message      string    `json:"message"`
+	SubmissionID string    `json:"submission_id,omitempty"`
+}
+
+// PublishUpdate changes the publishing state of a calendar entry. Empty and
+// nil fields are left unchanged; Error is appended to publish_errors. A
+// SubmissionID pointing to "" clears the submission and its publisher, so
+// that an entry whose delivery failed can be submitted again.
+type PublishUpdate struct {
+	Status       string
+	SubmissionID *string
+	Publisher    string // set with SubmissionID
+	PostURL      *string
+	PublishedAt  *time.Time
+	Error        *PublishError
+}
+
+// Validate validates a publish update
+func (u *PublishUpdate) Validate() error {
+	if u.Status != "" {
+		if err := ValidateCalendarStatus(u.Status); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ContentIdeaWithBook is a ContentIdea with its associated book.
@@ -124,6 +191,7 @@ type ContentCalendarInput struct {
 	ScheduledFor time.Time `json:"scheduled_for"`
 	Platform     string    `json:"platform"`
 	PostType     string    `json:"post_type"` // REQUIRED
+	CampaignID   *string   `json:"campaign_id,omitempty"`
 }
 
 // Validate validates content calendar input
@@ -131,8 +199,8 @@ func (c *ContentCalendarInput) Validate() error {
 	if c.ScheduledFor.IsZero() {
 		return ErrInvalidInput{Field: "scheduled_for", Message: "scheduled time is required"}
 	}
-	if c.Platform != "instagram" && c.Platform != "tiktok" {
-		return ErrInvalidInput{Field: "platform", Message: "platform must be 'instagram' or 'tiktok'"}
+	if err := ValidatePlatform(c.Platform); err != nil {
+		return err
 	}
 	validPostTypes := map[string]bool{"reel": true, "story": true, "feed": true}
 	if !validPostTypes[c.PostType] {
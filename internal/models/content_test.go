@@ -51,3 +51,40 @@ func TestContentIdeaInput_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateStatuses(t *testing.T) {
+	for _, s := range IdeaStatuses {
+		if err := ValidateIdeaStatus(s); err != nil {
+			t.Errorf("ValidateIdeaStatus(%q) = %v, want nil", s, err)
+		}
+	}
+	for _, s := range CalendarStatuses {
+		if err := ValidateCalendarStatus(s); err != nil {
+			t.Errorf("ValidateCalendarStatus(%q) = %v, want nil", s, err)
+		}
+	}
+
+	if err := ValidateIdeaStatus("published"); err == nil {
+		t.Error("expected error for calendar status on idea")
+	}
+	if err := ValidateCalendarStatus("scheduled"); err == nil {
+		t.Error("expected error for unknown calendar status")
+	}
+}
+
+func TestValidatePlatform(t *testing.T) {
+	if err := ValidatePlatform("myspace"); err == nil {
+		t.Error("expected error for an unregistered platform")
+	}
+
+	// Publishers register their platforms; known ones get a display name
+	RegisterPlatform(Platform{Name: "youtube"})
+	if err := ValidatePlatform("youtube"); err != nil || PlatformLabel("youtube") != "YouTube Shorts" {
+		t.Errorf("registered platform: %v, label %q", err, PlatformLabel("youtube"))
+	}
+
+	RegisterPlatform(Platform{Name: "vine"})
+	if err := ValidatePlatform("vine"); err != nil || PlatformLabel("vine") != "vine" {
+		t.Errorf("registered platform: %v, label %q", err, PlatformLabel("vine"))
+	}
+}
@@ -34,8 +34,8 @@ func (p *PostMetricInput) Validate() error {
 	if p.CalendarID == "" {
 		return ErrInvalidInput{Field: "calendar_id", Message: "calendar ID is required"}
 	}
-	if p.Platform != "instagram" && p.Platform != "tiktok" {
-		return ErrInvalidInput{Field: "platform", Message: "platform must be 'instagram' or 'tiktok'"}
+	if err := ValidatePlatform(p.Platform); err != nil {
+		return err
 	}
 	return nil
 }
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Platform is a social platform content can be scheduled for
+type Platform struct {
+	Name  string // as stored in content_calendar.platform
+	Label string // display name
+}
+
+var (
+	platformsMu sync.RWMutex
+	platforms   = map[string]Platform{}
+)
+
+// platformLabels are the display names of the platforms publishers are
+// known to register
+var platformLabels = map[string]string{
+	"instagram": "Instagram Reels",
+	"tiktok":    "TikTok",
+	"youtube":   "YouTube Shorts",
+	"facebook":  "Facebook Reels",
+	"pinterest": "Pinterest Idea Pins",
+	"threads":   "Threads",
+	"bluesky":   "Bluesky",
+}
+
+// RegisterPlatform adds a platform to the registry, or replaces the one with
+// the same name. Publishers register the platforms they post to, so a new
+// publisher can bring new platforms.
+func RegisterPlatform(p Platform) {
+	if p.Label == "" {
+		p.Label = platformLabels[p.Name]
+	}
+	if p.Label == "" {
+		p.Label = p.Name
+	}
+	platformsMu.Lock()
+	defer platformsMu.Unlock()
+	platforms[p.Name] = p
+}
+
+// LookupPlatform returns the registered platform called name
+func LookupPlatform(name string) (Platform, bool) {
+	platformsMu.RLock()
+	defer platformsMu.RUnlock()
+	p, ok := platforms[name]
+	return p, ok
+}
+
+// Platforms returns the names of all registered platforms, sorted
+func Platforms() []string {
+	platformsMu.RLock()
+	defer platformsMu.RUnlock()
+	names := make([]string, 0, len(platforms))
+	for name := range platforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PlatformLabel returns the display name of a platform, or the name itself
+// for unknown platforms
+func PlatformLabel(name string) string {
+	if p, ok := LookupPlatform(name); ok {
+		return p.Label
+	}
+	return name
+}
+
+// ValidatePlatform checks that platform is registered
+func ValidatePlatform(platform string) error {
+	if _, ok := LookupPlatform(platform); !ok {
+		return ErrInvalidInput{Field: "platform", Message: fmt.Sprintf("unknown platform %q (must be one of: %s)", platform, strings.Join(Platforms(), ", "))}
+	}
+	return nil
+}
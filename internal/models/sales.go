@@ -51,3 +51,25 @@ type KDPReportRow struct {
 	PageReads    int
 	Marketplace  string
 }
+
+// MergeDailySales sums inputs that share a book and date into one record,
+// keeping the order in which each (book, date) first appears. KDP reports
+// list a day once per marketplace, while sales_data keeps one row per book
+// per day.
+func MergeDailySales(inputs []*BookSaleInput) []*BookSaleInput {
+	merged := make([]*BookSaleInput, 0, len(inputs))
+	byKey := make(map[string]*BookSaleInput, len(inputs))
+	for _, in := range inputs {
+		key := in.BookID + "|" + in.SaleDate.Format(DateFormat)
+		if m, ok := byKey[key]; ok {
+			m.UnitsSold += in.UnitsSold
+			m.Royalty += in.Royalty
+			m.PageReads += in.PageReads
+			continue
+		}
+		m := *in
+		byKey[key] = &m
+		merged = append(merged, &m)
+	}
+	return merged
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMergeDailySales(t *testing.T) {
+	day1 := Date{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	day2 := Date{Time: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}
+	us := &BookSaleInput{BookID: "a", SaleDate: day1, UnitsSold: 2, Royalty: 3.5, PageReads: 100}
+	inputs := []*BookSaleInput{
+		us,
+		{BookID: "b", SaleDate: day1, UnitsSold: 1, Royalty: 1},
+		{BookID: "a", SaleDate: day1, UnitsSold: 1, Royalty: 2.25, PageReads: 20}, // another marketplace
+		{BookID: "a", SaleDate: day2, UnitsSold: 4, Royalty: 7},
+	}
+
+	merged := MergeDailySales(inputs)
+
+	if len(merged) != 3 {
+		t.Fatalf("got %d records, want 3", len(merged))
+	}
+	if merged[0].BookID != "a" || merged[1].BookID != "b" || !merged[2].SaleDate.Equal(day2.Time) {
+		t.Errorf("order not preserved: %+v %+v %+v", merged[0], merged[1], merged[2])
+	}
+	if merged[0].UnitsSold != 3 || math.Abs(merged[0].Royalty-5.75) > 1e-9 || merged[0].PageReads != 120 {
+		t.Errorf("merged record = %+v, want 3 units, 5.75 royalty, 120 page reads", merged[0])
+	}
+	if us.UnitsSold != 2 {
+		t.Errorf("input was modified: %+v", us)
+	}
+}
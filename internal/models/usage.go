@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+)
+
+// AIUsage is one AI call recorded in the ai_usage ledger
+type AIUsage struct {
+	ID               string    `json:"id"`
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	CostUSD          float64   `json:"cost_usd"` // estimated from the price table at call time
+	Command          string    `json:"command"`  // e.g. "generate ideas"
+	BookID           *string   `json:"book_id,omitempty"`
+	IdeaID           *string   `json:"idea_id,omitempty"`
+	ScriptID         *string   `json:"script_id,omitempty"`
+	ContentType      *string   `json:"content_type,omitempty"` // idea type, for script generation
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// TotalTokens returns prompt plus completion tokens
+func (u *AIUsage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// AIUsageInput represents input for recording an AI call
+type AIUsageInput struct {
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	Command          string  `json:"command"`
+	BookID           *string `json:"book_id,omitempty"`
+	IdeaID           *string `json:"idea_id,omitempty"`
+	ScriptID         *string `json:"script_id,omitempty"`
+	ContentType      *string `json:"content_type,omitempty"`
+}
+
+// Validate validates AI usage input
+func (u *AIUsageInput) Validate() error {
+	if u.Provider == "" {
+		return ErrInvalidInput{Field: "provider", Message: "provider is required"}
+	}
+	if u.Model == "" {
+		return ErrInvalidInput{Field: "model", Message: "model is required"}
+	}
+	if u.Command == "" {
+		return ErrInvalidInput{Field: "command", Message: "command is required"}
+	}
+	if u.PromptTokens < 0 || u.CompletionTokens < 0 {
+		return ErrInvalidInput{Field: "prompt_tokens", Message: "token counts cannot be negative"}
+	}
+	if u.CostUSD < 0 {
+		return ErrInvalidInput{Field: "cost_usd", Message: "cost cannot be negative"}
+	}
+	return nil
+}
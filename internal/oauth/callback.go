@@ -0,0 +1,116 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// CallbackPath is where the provider redirects to
+const CallbackPath = "/callback"
+
+// DefaultPort is the callback port. The redirect URI
+// http://localhost:<port>/callback has to be registered with the provider,
+// so the port is fixed rather than picked at random.
+const DefaultPort = 8585
+
+// CallbackServer is a temporary localhost server that receives the OAuth
+// redirect
+type CallbackServer struct {
+	server  *http.Server
+	state   string
+	results chan callbackResult
+	port    int
+}
+
+type callbackResult struct {
+	code, err string
+}
+
+// Listen starts a callback server on localhost:port for the redirect that
+// carries state. Port 0 picks a free port. It listens on both the IPv4 and
+// the IPv6 loopback, since browsers may resolve localhost to either; IPv6
+// is skipped where the machine has none.
+func Listen(port int, state string) (*CallbackServer, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the OAuth callback on port %d: %w", port, err)
+	}
+
+	s := &CallbackServer{
+		state:   state,
+		results: make(chan callbackResult, 1),
+		port:    listener.Addr().(*net.TCPAddr).Port,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(CallbackPath, s.handle)
+	s.server = &http.Server{Handler: mux}
+	go s.server.Serve(listener)
+	if listener6, err := net.Listen("tcp", net.JoinHostPort("::1", strconv.Itoa(s.port))); err == nil {
+		go s.server.Serve(listener6)
+	}
+	return s, nil
+}
+
+// RedirectURI is the redirect URI to send to the provider
+func (s *CallbackServer) RedirectURI() string {
+	return fmt.Sprintf("http://localhost:%d%s", s.port, CallbackPath)
+}
+
+func (s *CallbackServer) handle(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	// A request without the state of this login, such as a stray or
+	// forged one, must not end it
+	if q.Get("state") != s.state {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, "<h1>Unknown login</h1><p>This redirect does not belong to the login in progress.</p>")
+		return
+	}
+
+	result := callbackResult{code: q.Get("code")}
+	if e := q.Get("error"); e != "" {
+		result.err = e
+		if desc := q.Get("error_description"); desc != "" {
+			result.err += ": " + desc
+		}
+	}
+
+	if result.err != "" {
+		fmt.Fprintf(w, "<h1>Authorization failed</h1><p>%s</p><p>You can close this window.</p>", html.EscapeString(result.err))
+	} else {
+		fmt.Fprint(w, "<h1>Gagipress is authorized</h1><p>You can close this window and return to the terminal.</p>")
+	}
+
+	// Only the first redirect counts
+	select {
+	case s.results <- result:
+	default:
+	}
+}
+
+// Wait returns the authorization code of the first redirect that carries
+// the state of this login. It gives up when ctx is done.
+func (s *CallbackServer) Wait(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", fmt.Errorf("gave up waiting for the OAuth redirect: %w", ctx.Err())
+	case r := <-s.results:
+		if r.err != "" {
+			return "", fmt.Errorf("authorization denied: %s", r.err)
+		}
+		if r.code == "" {
+			return "", fmt.Errorf("the OAuth redirect carried no authorization code")
+		}
+		return r.code, nil
+	}
+}
+
+// Close stops the server
+func (s *CallbackServer) Close() error {
+	return s.server.Close()
+}
@@ -0,0 +1,144 @@
+// Package oauth runs the browser side of an OAuth 2.0 authorization-code
+// flow with PKCE for a CLI: it builds the authorization URL, opens it, and
+// catches the redirect on a temporary localhost server. Exchanging the code
+// for tokens is left to each platform's client, since every provider does
+// it differently.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Provider describes the authorization endpoint of an OAuth provider
+type Provider struct {
+	Name          string
+	AuthURL       string
+	ClientID      string
+	ClientIDParam string // query parameter for ClientID, default client_id
+	Scopes        []string
+	ScopeSep      string // default ","
+	HexChallenge  bool   // send the S256 challenge hex encoded instead of base64url
+}
+
+// ExpiresWithin reports whether a token expiring at expiresAt expires
+// within d. Tokens without an expiry never do.
+func ExpiresWithin(expiresAt time.Time, d time.Duration) bool {
+	return !expiresAt.IsZero() && time.Until(expiresAt) < d
+}
+
+// NewVerifier returns a random PKCE code verifier (43 characters)
+func NewVerifier() (string, error) {
+	return randomString(32)
+}
+
+// NewState returns a random state parameter
+func NewState() (string, error) {
+	return randomString(16)
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Challenge returns the S256 code challenge of verifier
+func (p *Provider) Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	if p.HexChallenge {
+		return hex.EncodeToString(sum[:])
+	}
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the URL the user authorizes the app at
+func (p *Provider) AuthCodeURL(state, verifier, redirectURI string) string {
+	idParam := p.ClientIDParam
+	if idParam == "" {
+		idParam = "client_id"
+	}
+	sep := p.ScopeSep
+	if sep == "" {
+		sep = ","
+	}
+
+	q := url.Values{
+		idParam:                 {p.ClientID},
+		"redirect_uri":          {redirectURI},
+		"response_type":         {"code"},
+		"scope":                 {strings.Join(p.Scopes, sep)},
+		"state":                 {state},
+		"code_challenge":        {p.Challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	return p.AuthURL + "?" + q.Encode()
+}
+
+// Grant is an authorization code caught by Authorize
+type Grant struct {
+	Code        string
+	Verifier    string
+	RedirectURI string
+}
+
+// Authorize runs the browser part of the flow: it listens for the callback
+// on localhost:port, opens the authorization URL (or only prints it to out
+// when openBrowser is false) and waits for the redirect until ctx is done.
+func Authorize(ctx context.Context, p *Provider, port int, openBrowser bool, out io.Writer) (*Grant, error) {
+	verifier, err := NewVerifier()
+	if err != nil {
+		return nil, err
+	}
+	state, err := NewState()
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := Listen(port, state)
+	if err != nil {
+		return nil, err
+	}
+	defer server.Close()
+
+	authURL := p.AuthCodeURL(state, verifier, server.RedirectURI())
+	fmt.Fprintf(out, "Open this URL to authorize Gagipress on %s:\n\n  %s\n\n", p.Name, authURL)
+	if openBrowser {
+		if err := OpenBrowser(authURL); err != nil {
+			fmt.Fprintf(out, "⚠️  Could not open a browser (%v); open the URL yourself.\n", err)
+		}
+	}
+	fmt.Fprintf(out, "Waiting for the redirect to %s ...\n", server.RedirectURI())
+
+	code, err := server.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Grant{Code: code, Verifier: verifier, RedirectURI: server.RedirectURI()}, nil
+}
+
+// OpenBrowser opens url in the default browser
+func OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,172 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthCodeURL(t *testing.T) {
+	p := &Provider{
+		AuthURL:       "https://www.tiktok.com/v2/auth/authorize/",
+		ClientID:      "client-key",
+		ClientIDParam: "client_key",
+		Scopes:        []string{"user.info.basic", "video.publish"},
+	}
+
+	u, err := url.Parse(p.AuthCodeURL("state-1", "verifier-1", "http://localhost:8585/callback"))
+	if err != nil {
+		t.Fatalf("parse URL: %v", err)
+	}
+	q := u.Query()
+	if u.Host != "www.tiktok.com" || q.Get("client_key") != "client-key" || q.Has("client_id") {
+		t.Errorf("unexpected client parameter in %s", u)
+	}
+	if q.Get("scope") != "user.info.basic,video.publish" || q.Get("state") != "state-1" || q.Get("response_type") != "code" {
+		t.Errorf("unexpected query %v", q)
+	}
+	if q.Get("redirect_uri") != "http://localhost:8585/callback" || q.Get("code_challenge_method") != "S256" {
+		t.Errorf("unexpected query %v", q)
+	}
+	if q.Get("code_challenge") != p.Challenge("verifier-1") {
+		t.Errorf("code_challenge = %q", q.Get("code_challenge"))
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	verifier, err := NewVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verifier) != 43 || strings.ContainsAny(verifier, "+/=") {
+		t.Errorf("verifier %q is not 43 unreserved characters", verifier)
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	if got := (&Provider{}).Challenge(verifier); got != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		t.Errorf("S256 challenge = %q", got)
+	}
+	if got := (&Provider{HexChallenge: true}).Challenge(verifier); got != hex.EncodeToString(sum[:]) {
+		t.Errorf("hex challenge = %q", got)
+	}
+}
+
+func redirect(t *testing.T, s *CallbackServer, query string) {
+	t.Helper()
+	resp, err := http.Get(s.RedirectURI() + "?" + query)
+	if err != nil {
+		t.Fatalf("redirect: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestCallbackServer(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		code    string
+		wantErr string
+	}{
+		{name: "code", query: "code=abc&state=s1", code: "abc"},
+		{name: "denied", query: "error=access_denied&error_description=User+cancelled&state=s1", wantErr: "access_denied: User cancelled"},
+		{name: "no code", query: "state=s1", wantErr: "no authorization code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Listen(0, "s1")
+			if err != nil {
+				t.Fatalf("Listen() error = %v", err)
+			}
+			defer s.Close()
+
+			redirect(t, s, tt.query)
+			code, err := s.Wait(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Wait() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || code != tt.code {
+				t.Errorf("Wait() = %q, %v; want %q", code, err, tt.code)
+			}
+		})
+	}
+}
+
+func TestCallbackServer_IgnoresOtherStates(t *testing.T) {
+	s, err := Listen(0, "s1")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer s.Close()
+
+	// Stray and forged redirects are turned away without ending the login
+	for _, query := range []string{"", "code=abc", "code=abc&state=forged", "error=access_denied&state=forged"} {
+		resp, err := http.Get(s.RedirectURI() + "?" + query)
+		if err != nil {
+			t.Fatalf("redirect: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("redirect ?%s: status %d, want %d", query, resp.StatusCode, http.StatusBadRequest)
+		}
+	}
+
+	redirect(t, s, "code=real&state=s1")
+	code, err := s.Wait(context.Background())
+	if err != nil || code != "real" {
+		t.Errorf("Wait() = %q, %v; want the code of this login", code, err)
+	}
+}
+
+func TestCallbackServer_IPv6Loopback(t *testing.T) {
+	s, err := Listen(0, "s1")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer s.Close()
+
+	// Browsers that resolve localhost to ::1 reach the server too
+	resp, err := http.Get(fmt.Sprintf("http://[::1]:%d%s?code=abc&state=s1", s.port, CallbackPath))
+	if err != nil {
+		t.Skipf("no IPv6 loopback: %v", err)
+	}
+	resp.Body.Close()
+	if code, err := s.Wait(context.Background()); err != nil || code != "abc" {
+		t.Errorf("Wait() = %q, %v", code, err)
+	}
+}
+
+func TestCallbackServer_Timeout(t *testing.T) {
+	s, err := Listen(0, "s1")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Wait(ctx); err == nil {
+		t.Error("Wait() returned without a redirect")
+	}
+}
+
+func TestExpiresWithin(t *testing.T) {
+	if ExpiresWithin(time.Time{}, time.Hour) {
+		t.Error("a token without expiry expires")
+	}
+	if !ExpiresWithin(time.Now().Add(time.Minute), time.Hour) {
+		t.Error("a token expiring in a minute does not expire within an hour")
+	}
+	if ExpiresWithin(time.Now().Add(2*time.Hour), time.Hour) {
+		t.Error("a token expiring in two hours expires within an hour")
+	}
+}
@@ -0,0 +1,78 @@
+// Package pool runs independent work items on a bounded number of
+// goroutines while reporting their results in order.
+package pool
+
+import (
+	"context"
+	"sync"
+)
+
+// Run calls work for the items 0..n-1 on at most concurrency goroutines.
+// Each result is handed to emit in item order, as soon as it and every
+// earlier result are done; emit runs on the calling goroutine, so it may
+// print without interleaving.
+//
+// Items are started in order. Once ctx is cancelled no new item is started,
+// the ones in flight are waited for and emitted, and Run returns ctx.Err().
+func Run[R any](ctx context.Context, n, concurrency int, work func(ctx context.Context, i int) R, emit func(i int, r R)) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	type result struct {
+		i int
+		r R
+	}
+
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := 0; i < n; i++ {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan result, n)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results <- result{i: i, r: work(ctx, i)}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Items start in order, so the ones that ran always form a prefix
+	next := 0
+	pending := make(map[int]R)
+	for res := range results {
+		pending[res.i] = res.r
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			emit(next, r)
+			next++
+		}
+	}
+
+	if next < n {
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun_EmitsInOrderWithinBound(t *testing.T) {
+	var running, peak atomic.Int32
+	var order []int
+
+	err := Run(context.Background(), 20, 4, func(ctx context.Context, i int) int {
+		now := running.Add(1)
+		for {
+			old := peak.Load()
+			if now <= old || peak.CompareAndSwap(old, now) {
+				break
+			}
+		}
+		// Later items finish first
+		time.Sleep(time.Duration(20-i) * time.Millisecond)
+		running.Add(-1)
+		return i * i
+	}, func(i int, r int) {
+		if r != i*i {
+			t.Errorf("item %d: got result %d", i, r)
+		}
+		order = append(order, i)
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(order) != 20 {
+		t.Fatalf("emitted %d results, want 20", len(order))
+	}
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("emit order = %v, want ascending", order)
+		}
+	}
+	if p := peak.Load(); p > 4 || p < 2 {
+		t.Errorf("peak concurrency = %d, want between 2 and 4", p)
+	}
+}
+
+func TestRun_StopsStartingWorkWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var started atomic.Int32
+	emitted := 0
+	err := Run(ctx, 100, 2, func(ctx context.Context, i int) bool {
+		started.Add(1)
+		if i == 5 {
+			cancel()
+		}
+		return true
+	}, func(i int, ok bool) {
+		if i != emitted {
+			t.Errorf("emitted item %d, want %d", i, emitted)
+		}
+		emitted++
+	})
+
+	if err != context.Canceled {
+		t.Fatalf("Run error = %v, want context.Canceled", err)
+	}
+	if n := started.Load(); n >= 100 || int(n) != emitted {
+		t.Errorf("started %d items and emitted %d, want the same prefix", n, emitted)
+	}
+}
+
+func TestRun_Empty(t *testing.T) {
+	err := Run(context.Background(), 0, 4, func(ctx context.Context, i int) int {
+		t.Fatal("work called for an empty batch")
+		return 0
+	}, func(int, int) {})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+}
@@ -0,0 +1,304 @@
+package postgrest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+)
+
+// Client is a minimal typed client for the Supabase PostgREST API.
+// It owns authentication headers so repositories only describe queries.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient creates a PostgREST client for the given Supabase project.
+// The service key is preferred over the anon key when both are set.
+func NewClient(cfg *config.SupabaseConfig) *Client {
+	apiKey := cfg.ServiceKey
+	if apiKey == "" {
+		apiKey = cfg.AnonKey
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(cfg.URL, "/") + "/rest/v1",
+		apiKey:     apiKey,
+		httpClient: &http.Client{},
+	}
+}
+
+// Error is returned when PostgREST answers with a non-2xx status code.
+type Error struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// From starts a query against a table or view.
+func (c *Client) From(table string) *Query {
+	return &Query{
+		client: c,
+		path:   "/" + table,
+		params: url.Values{},
+	}
+}
+
+// RPC calls a PostgreSQL function exposed under /rpc and decodes the result into out.
+func (c *Client) RPC(ctx context.Context, function string, params any, out any) error {
+	q := &Query{
+		client: c,
+		path:   "/rpc/" + function,
+		params: url.Values{},
+	}
+	return q.do(ctx, http.MethodPost, params, out)
+}
+
+// Query describes a single PostgREST request. Filter methods return the
+// query so calls can be chained; nothing is sent until a terminal method
+// (Get, Insert, Upsert, Update, Delete) is called.
+type Query struct {
+	client *Client
+	path   string
+	params url.Values
+	prefer []string
+}
+
+// Select sets the columns to return, including embedded resources,
+// e.g. "*,content_scripts(*,content_ideas(*))".
+func (q *Query) Select(columns string) *Query {
+	q.params.Set("select", columns)
+	return q
+}
+
+// Filter adds a raw "column=operator.value" filter.
+func (q *Query) Filter(column, operator, value string) *Query {
+	q.params.Add(column, operator+"."+value)
+	return q
+}
+
+// Eq filters rows where column equals value.
+func (q *Query) Eq(column, value string) *Query {
+	return q.Filter(column, "eq", value)
+}
+
+// Neq filters rows where column does not equal value.
+func (q *Query) Neq(column, value string) *Query {
+	return q.Filter(column, "neq", value)
+}
+
+// Gte filters rows where column is greater than or equal to value.
+func (q *Query) Gte(column, value string) *Query {
+	return q.Filter(column, "gte", value)
+}
+
+// Gt filters rows where column is strictly greater than value.
+func (q *Query) Gt(column, value string) *Query {
+	return q.Filter(column, "gt", value)
+}
+
+// Lte filters rows where column is less than or equal to value.
+func (q *Query) Lte(column, value string) *Query {
+	return q.Filter(column, "lte", value)
+}
+
+// Lt filters rows where column is strictly less than value.
+func (q *Query) Lt(column, value string) *Query {
+	return q.Filter(column, "lt", value)
+}
+
+// Is filters rows using IS (null, true, false).
+func (q *Query) Is(column, value string) *Query {
+	return q.Filter(column, "is", value)
+}
+
+// In filters rows where column matches any of values.
+func (q *Query) In(column string, values []string) *Query {
+	return q.Filter(column, "in", "("+strings.Join(values, ",")+")")
+}
+
+// Order appends an ordering clause. Multiple calls are combined in order.
+func (q *Query) Order(column string, ascending bool) *Query {
+	dir := "desc"
+	if ascending {
+		dir = "asc"
+	}
+	clause := column + "." + dir
+	if existing := q.params.Get("order"); existing != "" {
+		clause = existing + "," + clause
+	}
+	q.params.Set("order", clause)
+	return q
+}
+
+// Limit caps the number of returned rows. Zero or negative means no limit.
+func (q *Query) Limit(n int) *Query {
+	if n > 0 {
+		q.params.Set("limit", strconv.Itoa(n))
+	}
+	return q
+}
+
+// Offset skips the first n rows. Zero or negative means no offset.
+// Combine with Order and Limit to page through a table.
+func (q *Query) Offset(n int) *Query {
+	if n > 0 {
+		q.params.Set("offset", strconv.Itoa(n))
+	}
+	return q
+}
+
+// OnConflict names the unique columns an upsert resolves conflicts on,
+// e.g. OnConflict("book_id", "date"). The primary key is used when unset.
+func (q *Query) OnConflict(columns ...string) *Query {
+	q.params.Set("on_conflict", strings.Join(columns, ","))
+	return q
+}
+
+// Columns lists the columns a bulk insert writes. Without it PostgREST takes
+// the keys of the first object only, so keys that later objects set but the
+// first omits (omitempty fields) would be dropped.
+func (q *Query) Columns(columns ...string) *Query {
+	q.params.Set("columns", strings.Join(columns, ","))
+	return q
+}
+
+// Prefer adds a value to the Prefer header, e.g. "return=representation".
+func (q *Query) Prefer(value string) *Query {
+	q.prefer = append(q.prefer, value)
+	return q
+}
+
+// URL returns the full request URL the query would be sent to.
+func (q *Query) URL() string {
+	u := q.client.baseURL + q.path
+	if encoded := q.params.Encode(); encoded != "" {
+		u += "?" + encoded
+	}
+	return u
+}
+
+// Get runs a GET request and decodes the rows into out.
+func (q *Query) Get(ctx context.Context, out any) error {
+	return q.do(ctx, http.MethodGet, nil, out)
+}
+
+// Insert POSTs body and decodes the created rows into out (if non-nil).
+func (q *Query) Insert(ctx context.Context, body any, out any) error {
+	if out != nil {
+		q.Prefer("return=representation")
+	}
+	return q.do(ctx, http.MethodPost, body, out)
+}
+
+// Upsert POSTs body with resolution=merge-duplicates, so rows that collide
+// on the OnConflict columns are updated instead of rejected.
+func (q *Query) Upsert(ctx context.Context, body any, out any) error {
+	q.Prefer("resolution=merge-duplicates")
+	return q.Insert(ctx, body, out)
+}
+
+// Update PATCHes the rows matching the filters and decodes them into out (if non-nil).
+func (q *Query) Update(ctx context.Context, body any, out any) error {
+	if out != nil {
+		q.Prefer("return=representation")
+	}
+	return q.do(ctx, http.MethodPatch, body, out)
+}
+
+// Delete removes the rows matching the filters.
+func (q *Query) Delete(ctx context.Context) error {
+	return q.do(ctx, http.MethodDelete, nil, nil)
+}
+
+// Count returns the number of rows matching the filters without fetching
+// them, from the total PostgREST reports for Prefer: count=exact.
+func (q *Query) Count(ctx context.Context) (int, error) {
+	q.Prefer("count=exact")
+	header, err := q.send(ctx, http.MethodHead, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// Content-Range is "<first>-<last>/<total>", or "*/<total>" without rows
+	contentRange := header.Get("Content-Range")
+	_, total, ok := strings.Cut(contentRange, "/")
+	if !ok || total == "*" {
+		return 0, fmt.Errorf("no row count in Content-Range %q", contentRange)
+	}
+	n, err := strconv.Atoi(total)
+	if err != nil {
+		return 0, fmt.Errorf("invalid row count in Content-Range %q", contentRange)
+	}
+	return n, nil
+}
+
+// do sends the request, checks the status code and decodes the response.
+func (q *Query) do(ctx context.Context, method string, body any, out any) error {
+	_, err := q.send(ctx, method, body, out)
+	return err
+}
+
+// send is do that also returns the response headers.
+func (q *Query) send(ctx context.Context, method string, body any, out any) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, q.URL(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("apikey", q.client.apiKey)
+	req.Header.Set("Authorization", "Bearer "+q.client.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(q.prefer) > 0 {
+		req.Header.Set("Prefer", strings.Join(q.prefer, ","))
+	}
+
+	resp, err := q.client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &Error{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	if out == nil || len(bytes.TrimSpace(respBody)) == 0 {
+		return resp.Header, nil
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return resp.Header, nil
+}
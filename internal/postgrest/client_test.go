@@ -0,0 +1,356 @@
+package postgrest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+)
+
+func newTestClient(serverURL string) *Client {
+	return NewClient(&config.SupabaseConfig{URL: serverURL, AnonKey: "anon"})
+}
+
+// decodedQuery parses the query string of a built URL so tests do not depend
+// on percent-encoding details.
+func decodedQuery(t *testing.T, raw string) url.Values {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("invalid URL %q: %v", raw, err)
+	}
+	return u.Query()
+}
+
+func TestQueryURL_Filters(t *testing.T) {
+	c := newTestClient("http://example.test")
+
+	q := c.From("content_ideas").
+		Select("*").
+		Eq("book_id", "book-1").
+		Eq("status", "pending").
+		Gte("generated_at", "2026-01-01").
+		Order("generated_at", false).
+		Limit(10)
+
+	raw := q.URL()
+	u, _ := url.Parse(raw)
+	if u.Path != "/rest/v1/content_ideas" {
+		t.Errorf("path = %q, want /rest/v1/content_ideas", u.Path)
+	}
+
+	params := decodedQuery(t, raw)
+	tests := map[string]string{
+		"select":       "*",
+		"book_id":      "eq.book-1",
+		"status":       "eq.pending",
+		"generated_at": "gte.2026-01-01",
+		"order":        "generated_at.desc",
+		"limit":        "10",
+	}
+	for key, want := range tests {
+		if got := params.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestQueryURL_InAndEmbeds(t *testing.T) {
+	c := newTestClient("http://example.test")
+
+	raw := c.From("content_calendar").
+		Select("*,content_scripts(*,content_ideas(*))").
+		In("status", []string{"approved", "scheduled"}).
+		Is("media_url", "null").
+		URL()
+
+	params := decodedQuery(t, raw)
+	if got := params.Get("select"); got != "*,content_scripts(*,content_ideas(*))" {
+		t.Errorf("select = %q", got)
+	}
+	if got := params.Get("status"); got != "in.(approved,scheduled)" {
+		t.Errorf("status = %q, want in.(approved,scheduled)", got)
+	}
+	if got := params.Get("media_url"); got != "is.null" {
+		t.Errorf("media_url = %q, want is.null", got)
+	}
+}
+
+func TestQueryURL_RangeOnSameColumn(t *testing.T) {
+	c := newTestClient("http://example.test")
+
+	raw := c.From("sales_data").
+		Gte("date", "2026-01-01").
+		Lte("date", "2026-01-31").
+		URL()
+
+	got := decodedQuery(t, raw)["date"]
+	if len(got) != 2 || got[0] != "gte.2026-01-01" || got[1] != "lte.2026-01-31" {
+		t.Errorf("date filters = %v, want [gte.2026-01-01 lte.2026-01-31]", got)
+	}
+}
+
+func TestQueryURL_MultipleOrderAndNoLimit(t *testing.T) {
+	c := newTestClient("http://example.test")
+
+	raw := c.From("books").
+		Order("created_at", false).
+		Order("title", true).
+		Limit(0).
+		URL()
+
+	params := decodedQuery(t, raw)
+	if got := params.Get("order"); got != "created_at.desc,title.asc" {
+		t.Errorf("order = %q, want created_at.desc,title.asc", got)
+	}
+	if params.Has("limit") {
+		t.Errorf("limit should be omitted for 0, got %q", params.Get("limit"))
+	}
+}
+
+func TestQueryURL_OffsetPaging(t *testing.T) {
+	c := newTestClient("http://example.test")
+
+	params := decodedQuery(t, c.From("content_ideas").Limit(500).Offset(1000).URL())
+	if params.Get("limit") != "500" || params.Get("offset") != "1000" {
+		t.Errorf("limit/offset = %q/%q, want 500/1000", params.Get("limit"), params.Get("offset"))
+	}
+
+	params = decodedQuery(t, c.From("content_ideas").Offset(0).URL())
+	if params.Has("offset") {
+		t.Errorf("offset should be omitted for 0, got %q", params.Get("offset"))
+	}
+}
+
+func TestNewClient_PrefersServiceKey(t *testing.T) {
+	var gotKey, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("apikey")
+		gotAuth = r.Header.Get("Authorization")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	c := NewClient(&config.SupabaseConfig{URL: server.URL, AnonKey: "anon", ServiceKey: "service"})
+	var rows []map[string]any
+	if err := c.From("books").Get(context.Background(), &rows); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotKey != "service" || gotAuth != "Bearer service" {
+		t.Errorf("headers = apikey %q, auth %q; want service key", gotKey, gotAuth)
+	}
+}
+
+func TestInsert_SetsPreferAndBody(t *testing.T) {
+	var gotMethod, gotPrefer, gotContentType string
+	var gotBody map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPrefer = r.Header.Get("Prefer")
+		gotContentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &gotBody)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`[{"id":"row-1","title":"T"}]`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	var rows []map[string]string
+	err := c.From("books").
+		Prefer("resolution=merge-duplicates").
+		Insert(context.Background(), map[string]string{"title": "T"}, &rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %s, want POST", gotMethod)
+	}
+	if gotPrefer != "resolution=merge-duplicates,return=representation" {
+		t.Errorf("Prefer = %q", gotPrefer)
+	}
+	if gotContentType != "application/json" {
+		t.Errorf("Content-Type = %q", gotContentType)
+	}
+	if gotBody["title"] != "T" {
+		t.Errorf("body title = %q, want T", gotBody["title"])
+	}
+	if len(rows) != 1 || rows[0]["id"] != "row-1" {
+		t.Errorf("rows = %v", rows)
+	}
+}
+
+func TestUpsert_SetsConflictTargetAndArrayBody(t *testing.T) {
+	var gotPrefer string
+	var gotQuery url.Values
+	var gotBody []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPrefer = r.Header.Get("Prefer")
+		gotQuery = r.URL.Query()
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &gotBody)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`[{"id":"s-1"},{"id":"s-2"}]`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	body := []map[string]any{
+		{"book_id": "b-1", "date": "2026-01-01", "units_sold": 1},
+		{"book_id": "b-1", "date": "2026-01-02", "units_sold": 2},
+	}
+	var rows []map[string]string
+	err := c.From("sales_data").
+		OnConflict("book_id", "date").
+		Columns("book_id", "date", "units_sold").
+		Upsert(context.Background(), body, &rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPrefer != "resolution=merge-duplicates,return=representation" {
+		t.Errorf("Prefer = %q", gotPrefer)
+	}
+	if got := gotQuery.Get("on_conflict"); got != "book_id,date" {
+		t.Errorf("on_conflict = %q, want book_id,date", got)
+	}
+	if got := gotQuery.Get("columns"); got != "book_id,date,units_sold" {
+		t.Errorf("columns = %q", got)
+	}
+	if len(gotBody) != 2 {
+		t.Errorf("body has %d rows, want 2", len(gotBody))
+	}
+	if len(rows) != 2 {
+		t.Errorf("rows = %v", rows)
+	}
+}
+
+func TestUpdate_WithoutOutOmitsRepresentation(t *testing.T) {
+	var gotMethod, gotPrefer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPrefer = r.Header.Get("Prefer")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	if err := c.From("books").Eq("id", "b1").Update(context.Background(), map[string]string{"title": "X"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotMethod != http.MethodPatch {
+		t.Errorf("method = %s, want PATCH", gotMethod)
+	}
+	if gotPrefer != "" {
+		t.Errorf("Prefer = %q, want empty", gotPrefer)
+	}
+}
+
+func TestRPC(t *testing.T) {
+	var gotPath string
+	var gotParams map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&gotParams)
+		w.Write([]byte(`[{"id":"abc123"}]`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	var rows []map[string]string
+	if err := c.RPC(context.Background(), "find_book_by_prefix", map[string]string{"prefix_pattern": "abc123"}, &rows); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/rest/v1/rpc/find_book_by_prefix" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotParams["prefix_pattern"] != "abc123" {
+		t.Errorf("params = %v", gotParams)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected 1 row, got %d", len(rows))
+	}
+}
+
+func TestCount(t *testing.T) {
+	var gotMethod, gotPrefer, gotStatus string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPrefer, gotStatus = r.Method, r.Header.Get("Prefer"), r.URL.Query().Get("status")
+		w.Header().Set("Content-Range", "*/1234")
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	n, err := c.From("content_calendar").Eq("status", "approved").Count(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1234 {
+		t.Errorf("Count() = %d, want 1234, past the row cap", n)
+	}
+	if gotMethod != http.MethodHead || gotPrefer != "count=exact" || gotStatus != "eq.approved" {
+		t.Errorf("request = %s, Prefer %q, status %q", gotMethod, gotPrefer, gotStatus)
+	}
+}
+
+func TestCount_MissingTotal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", "0-24/*")
+	}))
+	defer server.Close()
+
+	if _, err := newTestClient(server.URL).From("books").Count(context.Background()); err == nil {
+		t.Error("Count() without a total succeeded")
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"bad filter"}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	err := c.From("books").Get(context.Background(), &[]map[string]any{})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var pgErr *Error
+	if !errors.As(err, &pgErr) {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if pgErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("StatusCode = %d, want 400", pgErr.StatusCode)
+	}
+	if err.Error() != `HTTP 400: {"message":"bad filter"}` {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestGet_CanceledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := newTestClient(server.URL)
+	err := c.From("books").Get(ctx, &[]map[string]any{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// campaignPhaseGoals says what the posts of each launch campaign phase are
+// for, by phase name
+var campaignPhaseGoals = map[string]string{
+	"teaser": `TEASER (prima dell'uscita): crea attesa e curiosità senza svelare
+tutto. Anteprime, dietro le quinte, conto alla rovescia, domande al pubblico.`,
+	"launch": `GIORNO DEL LANCIO: annuncia che il libro è disponibile oggi su
+Amazon. Energia alta, motivo chiaro per comprarlo subito, invito a condividere.`,
+	"review_push": `SPINTA RECENSIONI (settimana dopo l'uscita): chiedi a chi l'ha
+letto di lasciare una recensione su Amazon. Reazioni dei lettori, prime
+opinioni, contenuti da rifare con il libro in mano.`,
+}
+
+// CampaignIdeaPromptTemplate generates a prompt for the ideas of one phase of
+// a book launch campaign. The ideas must be of the given types only.
+func CampaignIdeaPromptTemplate(bookTitle, genre, targetAudience string, niche BookNiche, count int, phase, launchDate string, types []string) string {
+	goal, ok := campaignPhaseGoals[phase]
+	if !ok {
+		goal = phase
+	}
+
+	return IdeaPromptTemplate(bookTitle, genre, targetAudience, niche, count) + fmt.Sprintf(`
+
+CAMPAGNA DI LANCIO
+Il libro esce il %s. Queste idee sono per la fase:
+%s
+
+IMPORTANTE: usa SOLO questi tipi: %s. Ignora la distribuzione equa indicata
+sopra e adatta ogni idea all'obiettivo della fase.`, launchDate, goal, strings.Join(types, ", "))
+}
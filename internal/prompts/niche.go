@@ -0,0 +1,30 @@
+package prompts
+
+import "strings"
+
+// NicheFromGenre determines the book niche from its genre. Genres that
+// match no niche get Puzzles.
+func NicheFromGenre(genre string) BookNiche {
+	switch {
+	case containsAny(genre, "children", "bambini", "kids"):
+		return ChildrenBooks
+	case containsAny(genre, "puzzle", "enigmi", "quiz"):
+		return Puzzles
+	case containsAny(genre, "dialect", "dialetto", "milanese"):
+		return DialectPuzzles
+	case containsAny(genre, "saving", "risparmio", "money"):
+		return Savings
+	default:
+		return Puzzles // default
+	}
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrings ...string) bool {
+	for _, substr := range substrings {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
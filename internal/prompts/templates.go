@@ -85,18 +85,20 @@ Per ogni idea, fornisci:
 5. CTA finale suggerito
 6. Punteggio rilevanza 0-100
 
-Formato risposta (JSON array):
-[
-  {
-    "type": "educational",
-    "title": "Titolo idea",
-    "description": "Descrizione dettagliata dell'idea",
-    "hook": "Hook iniziale per catturare attenzione",
-    "cta": "Call-to-action finale",
-    "relevance_score": 85
-  },
-  ...
-]`
+Formato risposta (JSON):
+{
+  "ideas": [
+    {
+      "type": "educational",
+      "title": "Titolo idea",
+      "description": "Descrizione dettagliata dell'idea",
+      "hook": "Hook iniziale per catturare attenzione",
+      "cta": "Call-to-action finale",
+      "relevance_score": 85
+    },
+    ...
+  ]
+}`
 
 	return baseContext + "\n" + nicheGuidelines + "\n" + categories
 }
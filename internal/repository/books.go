@@ -1,72 +1,32 @@
 package repository
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/postgrest"
 )
 
 // BooksRepository handles book database operations
 type BooksRepository struct {
-	config *config.SupabaseConfig
-	client *http.Client
+	db *postgrest.Client
 }
 
 // NewBooksRepository creates a new books repository
 func NewBooksRepository(cfg *config.SupabaseConfig) *BooksRepository {
 	return &BooksRepository{
-		config: cfg,
-		client: &http.Client{},
+		db: postgrest.NewClient(cfg),
 	}
 }
 
 // Create creates a new book
-func (r *BooksRepository) Create(input *models.BookInput) (*models.Book, error) {
-	url := fmt.Sprintf("%s/rest/v1/books", r.config.URL)
-
-	jsonData, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal book: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create book: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create book: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
+func (r *BooksRepository) Create(ctx context.Context, input *models.BookInput) (*models.Book, error) {
 	var books []models.Book
-	if err := json.Unmarshal(body, &books); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("books").Insert(ctx, input, &books); err != nil {
+		return nil, fmt.Errorf("failed to create book: %w", err)
 	}
 
 	if len(books) == 0 {
@@ -77,80 +37,24 @@ func (r *BooksRepository) Create(input *models.BookInput) (*models.Book, error)
 }
 
 // GetAll retrieves all books
-func (r *BooksRepository) GetAll() ([]models.Book, error) {
-	url := fmt.Sprintf("%s/rest/v1/books?select=*&order=created_at.desc", r.config.URL)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := r.client.Do(req)
+func (r *BooksRepository) GetAll(ctx context.Context) ([]models.Book, error) {
+	var books []models.Book
+	err := r.db.From("books").
+		Select("*").
+		Order("created_at", false).
+		Get(ctx, &books)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get books: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get books: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	var books []models.Book
-	if err := json.Unmarshal(body, &books); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
 
 	return books, nil
 }
 
 // GetByID retrieves a book by ID
-func (r *BooksRepository) GetByID(id string) (*models.Book, error) {
-	url := fmt.Sprintf("%s/rest/v1/books?id=eq.%s&select=*", r.config.URL, id)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get book: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get book: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
+func (r *BooksRepository) GetByID(ctx context.Context, id string) (*models.Book, error) {
 	var books []models.Book
-	if err := json.Unmarshal(body, &books); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("books").Select("*").Eq("id", id).Get(ctx, &books); err != nil {
+		return nil, fmt.Errorf("failed to get book: %w", err)
 	}
 
 	if len(books) == 0 {
@@ -161,47 +65,10 @@ func (r *BooksRepository) GetByID(id string) (*models.Book, error) {
 }
 
 // Update updates a book
-func (r *BooksRepository) Update(id string, input *models.BookInput) (*models.Book, error) {
-	url := fmt.Sprintf("%s/rest/v1/books?id=eq.%s", r.config.URL, id)
-
-	jsonData, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal book: %w", err)
-	}
-
-	req, err := http.NewRequest("PATCH", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update book: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to update book: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
+func (r *BooksRepository) Update(ctx context.Context, id string, input *models.BookInput) (*models.Book, error) {
 	var books []models.Book
-	if err := json.Unmarshal(body, &books); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("books").Eq("id", id).Update(ctx, input, &books); err != nil {
+		return nil, fmt.Errorf("failed to update book: %w", err)
 	}
 
 	if len(books) == 0 {
@@ -215,54 +82,27 @@ func (r *BooksRepository) Update(id string, input *models.BookInput) (*models.Bo
 // Returns the book if exactly one match is found. Returns an error with
 // disambiguation list if multiple books match.
 // Uses the find_book_by_prefix PostgreSQL function via PostgREST RPC.
-func (r *BooksRepository) GetBookByIDPrefix(prefix string) (*models.Book, error) {
+func (r *BooksRepository) GetBookByIDPrefix(ctx context.Context, prefix string) (*models.Book, error) {
+	return ResolveBookPrefix(ctx, prefix, func(ctx context.Context, prefix string) ([]models.Book, error) {
+		var books []models.Book
+		params := map[string]string{"prefix_pattern": prefix}
+		err := r.db.RPC(ctx, "find_book_by_prefix", params, &books)
+		return books, err
+	})
+}
+
+// ResolveBookPrefix applies the shared prefix rules on top of a backend's
+// prefix lookup, so every store reports short, missing and ambiguous
+// prefixes the same way.
+func ResolveBookPrefix(ctx context.Context, prefix string, find func(context.Context, string) ([]models.Book, error)) (*models.Book, error) {
 	if len(prefix) < 6 {
 		return nil, fmt.Errorf("ID prefix too short: must be at least 6 characters (got %d)", len(prefix))
 	}
 
-	// Use PostgreSQL RPC function for UUID prefix matching
-	requestURL := fmt.Sprintf("%s/rest/v1/rpc/find_book_by_prefix", r.config.URL)
-
-	// Create request body with prefix parameter
-	reqBody := map[string]string{"prefix_pattern": prefix}
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", requestURL, strings.NewReader(string(jsonBody)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := r.client.Do(req)
+	books, err := find(ctx, prefix)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get book: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get book: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	var books []models.Book
-	if err := json.Unmarshal(body, &books); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
 
 	switch len(books) {
 	case 0:
@@ -279,32 +119,10 @@ func (r *BooksRepository) GetBookByIDPrefix(prefix string) (*models.Book, error)
 }
 
 // Delete deletes a book
-func (r *BooksRepository) Delete(id string) error {
-	url := fmt.Sprintf("%s/rest/v1/books?id=eq.%s", r.config.URL, id)
-
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := r.client.Do(req)
-	if err != nil {
+func (r *BooksRepository) Delete(ctx context.Context, id string) error {
+	if err := r.db.From("books").Eq("id", id).Delete(ctx); err != nil {
 		return fmt.Errorf("failed to delete book: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete book: HTTP %d: %s", resp.StatusCode, string(body))
-	}
 
 	return nil
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -41,7 +42,7 @@ func TestGetBookByIDPrefix_ValidPrefix_FindsUniqueBook(t *testing.T) {
 	}
 
 	repo := newTestBooksRepo(handler)
-	result, err := repo.GetBookByIDPrefix("abcdef12")
+	result, err := repo.GetBookByIDPrefix(context.Background(), "abcdef12")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -61,7 +62,7 @@ func TestGetBookByIDPrefix_MinimumLength_FindsBook(t *testing.T) {
 	}
 
 	repo := newTestBooksRepo(handler)
-	result, err := repo.GetBookByIDPrefix("abcdef")
+	result, err := repo.GetBookByIDPrefix(context.Background(), "abcdef")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -76,7 +77,7 @@ func TestGetBookByIDPrefix_PrefixTooShort_Error(t *testing.T) {
 	}
 
 	repo := newTestBooksRepo(handler)
-	_, err := repo.GetBookByIDPrefix("abc")
+	_, err := repo.GetBookByIDPrefix(context.Background(), "abc")
 	if err == nil {
 		t.Fatal("expected error for short prefix")
 	}
@@ -92,7 +93,7 @@ func TestGetBookByIDPrefix_NoMatches_Error(t *testing.T) {
 	}
 
 	repo := newTestBooksRepo(handler)
-	_, err := repo.GetBookByIDPrefix("abcdef12")
+	_, err := repo.GetBookByIDPrefix(context.Background(), "abcdef12")
 	if err == nil {
 		t.Fatal("expected error for no matches")
 	}
@@ -112,7 +113,7 @@ func TestGetBookByIDPrefix_MultipleMatches_DisambiguationError(t *testing.T) {
 	}
 
 	repo := newTestBooksRepo(handler)
-	_, err := repo.GetBookByIDPrefix("abcdef12")
+	_, err := repo.GetBookByIDPrefix(context.Background(), "abcdef12")
 	if err == nil {
 		t.Fatal("expected error for multiple matches")
 	}
@@ -134,7 +135,7 @@ func TestGetBookByIDPrefix_FullUUID_FindsBook(t *testing.T) {
 	}
 
 	repo := newTestBooksRepo(handler)
-	result, err := repo.GetBookByIDPrefix(fullID)
+	result, err := repo.GetBookByIDPrefix(context.Background(), fullID)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+)
+
+// BulkChunkSize is how many rows are sent per bulk request. Like
+// DefaultPageSize it keeps requests well inside PostgREST's limits, so a
+// 2,000-row KDP report is written in four requests.
+const BulkChunkSize = 500
+
+// RowStatus is the outcome of one row of a bulk write.
+type RowStatus string
+
+const (
+	RowCreated   RowStatus = "created"   // inserted as a new row
+	RowUpdated   RowStatus = "updated"   // replaced a stored row that had different values
+	RowDuplicate RowStatus = "duplicate" // an identical row was already stored; nothing was written
+	RowFailed    RowStatus = "failed"    // rejected; Err says why
+)
+
+// RowResult reports what happened to inputs[Index] of a bulk write.
+type RowResult[T any] struct {
+	Index  int
+	Status RowStatus
+	Row    *T    // the stored row; nil when Status is RowFailed
+	Err    error // set when Status is RowFailed
+}
+
+// BulkSummary counts the results of a bulk write by status.
+type BulkSummary struct {
+	Created   int
+	Updated   int
+	Duplicate int
+	Failed    int
+}
+
+// Summarize counts results by status.
+func Summarize[T any](results []RowResult[T]) BulkSummary {
+	var s BulkSummary
+	for _, r := range results {
+		switch r.Status {
+		case RowCreated:
+			s.Created++
+		case RowUpdated:
+			s.Updated++
+		case RowDuplicate:
+			s.Duplicate++
+		case RowFailed:
+			s.Failed++
+		}
+	}
+	return s
+}
+
+// NewResults returns one result per input row with Index filled in.
+func NewResults[T any](n int) []RowResult[T] {
+	results := make([]RowResult[T], n)
+	for i := range results {
+		results[i].Index = i
+	}
+	return results
+}
+
+// SaleKey identifies the sales_data row an input writes: UNIQUE(book_id, date).
+func SaleKey(bookID string, date models.Date) string {
+	return bookID + "|" + date.Format(models.DateFormat)
+}
+
+// SameSale reports whether stored already holds the values of input, so
+// upserting it would change nothing. Royalty is DECIMAL(10,2) in Postgres
+// and is compared in cents.
+func SameSale(stored models.BookSale, input *models.BookSaleInput) bool {
+	return stored.UnitsSold == input.UnitsSold &&
+		stored.PageReads == input.PageReads &&
+		math.Round(stored.Royalty*100) == math.Round(input.Royalty*100)
+}
+
+// UniqueSales marks rows whose (book_id, date) already appeared earlier in
+// the batch as failed and returns the indexes of the remaining rows. Postgres
+// refuses an upsert that touches the same row twice, so every backend
+// rejects these rows the same way.
+func UniqueSales(inputs []*models.BookSaleInput, results []RowResult[models.BookSale]) []int {
+	first := make(map[string]int, len(inputs))
+	idx := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		key := SaleKey(input.BookID, input.SaleDate)
+		if j, seen := first[key]; seen {
+			results[i].Status = RowFailed
+			results[i].Err = fmt.Errorf("same book and date as row %d of this batch", j+1)
+			continue
+		}
+		first[key] = i
+		idx = append(idx, i)
+	}
+	return idx
+}
+
+// writeChunks sends inputs[idx] in chunks of BulkChunkSize and stores the
+// returned rows in results, which must already hold the status each row gets
+// on success. A chunk the API rejects is retried one row at a time so a
+// single bad row only fails itself. Only context errors are returned.
+func writeChunks[In, Out any](ctx context.Context, send func(ctx context.Context, body []In, out *[]Out) error, inputs []In, idx []int, results []RowResult[Out]) error {
+	for start := 0; start < len(idx); start += BulkChunkSize {
+		chunk := idx[start:min(start+BulkChunkSize, len(idx))]
+		body := make([]In, len(chunk))
+		for i, j := range chunk {
+			body[i] = inputs[j]
+		}
+
+		var rows []Out
+		err := send(ctx, body, &rows)
+		if err == nil && len(rows) == len(chunk) {
+			for i, j := range chunk {
+				results[j].Row = &rows[i]
+			}
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		for i, j := range chunk {
+			if len(chunk) > 1 {
+				rows = nil
+				err = send(ctx, body[i:i+1], &rows)
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err == nil && len(rows) == 0 {
+				err = fmt.Errorf("no row returned from API")
+			}
+			if err != nil {
+				results[j].Status = RowFailed
+				results[j].Err = err
+				continue
+			}
+			results[j].Row = &rows[0]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+)
+
+// fakeSalesTable is a PostgREST sales_data endpoint that upserts on
+// (book_id, date) and rejects any batch containing the book "bad".
+type fakeSalesTable struct {
+	mu      sync.Mutex
+	rows    map[string]models.BookSale
+	nextID  int
+	gets    int
+	posts   int
+	prefers []string
+}
+
+func (f *fakeSalesTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodGet:
+		f.gets++
+		var sales []models.BookSale
+		for _, sale := range f.rows {
+			if strings.Contains(r.URL.Query().Get("book_id"), sale.BookID) {
+				sales = append(sales, sale)
+			}
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		sales = sales[min(offset, len(sales)):]
+		sales = sales[:min(limit, len(sales))]
+		json.NewEncoder(w).Encode(sales)
+
+	case http.MethodPost:
+		f.posts++
+		f.prefers = append(f.prefers, r.Header.Get("Prefer"))
+		if r.URL.Query().Get("on_conflict") != "book_id,date" {
+			http.Error(w, `{"message":"missing on_conflict"}`, http.StatusBadRequest)
+			return
+		}
+		var inputs []models.BookSaleInput
+		if err := json.NewDecoder(r.Body).Decode(&inputs); err != nil {
+			http.Error(w, `{"message":"body must be an array"}`, http.StatusBadRequest)
+			return
+		}
+		for _, in := range inputs {
+			if in.BookID == "bad" {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"message":"violates foreign key constraint"}`))
+				return
+			}
+		}
+
+		out := make([]models.BookSale, 0, len(inputs))
+		for _, in := range inputs {
+			key := SaleKey(in.BookID, in.SaleDate)
+			sale, ok := f.rows[key]
+			if !ok {
+				f.nextID++
+				sale = models.BookSale{ID: fmt.Sprintf("sale-%d", f.nextID), BookID: in.BookID, SaleDate: in.SaleDate}
+			}
+			sale.UnitsSold, sale.Royalty, sale.PageReads = in.UnitsSold, in.Royalty, in.PageReads
+			f.rows[key] = sale
+			out = append(out, sale)
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(out)
+	}
+}
+
+func saleInput(bookID string, day int, units int) *models.BookSaleInput {
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, day)
+	return &models.BookSaleInput{BookID: bookID, SaleDate: models.Date{Time: date}, UnitsSold: units, Royalty: float64(units) * 2.5}
+}
+
+func TestUpsertSales_KDPReportInFewRequests(t *testing.T) {
+	table := &fakeSalesTable{rows: make(map[string]models.BookSale)}
+	server := httptest.NewServer(table)
+	defer server.Close()
+	repo := NewSalesRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
+
+	// 2,000 rows: 4 books × 500 days.
+	var inputs []*models.BookSaleInput
+	for b := range 4 {
+		for d := range 500 {
+			inputs = append(inputs, saleInput(fmt.Sprintf("book-%d", b), d, 1))
+		}
+	}
+
+	results, err := repo.UpsertSales(context.Background(), inputs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := Summarize(results); got != (BulkSummary{Created: 2000}) {
+		t.Errorf("first import = %+v, want 2000 created", got)
+	}
+	if table.posts != 4 {
+		t.Errorf("first import sent %d POSTs, want 4", table.posts)
+	}
+	if table.prefers[0] != "resolution=merge-duplicates,return=representation" {
+		t.Errorf("Prefer = %q", table.prefers[0])
+	}
+
+	// Re-importing the same report changes one row and writes only that one.
+	table.posts = 0
+	inputs[7] = saleInput("book-0", 7, 3)
+	results, err = repo.UpsertSales(context.Background(), inputs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := Summarize(results); got != (BulkSummary{Updated: 1, Duplicate: 1999}) {
+		t.Errorf("re-import = %+v, want 1 updated and 1999 duplicates", got)
+	}
+	if results[7].Status != RowUpdated || results[7].Row.UnitsSold != 3 {
+		t.Errorf("row 7 = %+v, want updated to 3 units", results[7])
+	}
+	if table.posts != 1 {
+		t.Errorf("re-import sent %d POSTs, want 1", table.posts)
+	}
+}
+
+func TestUpsertSales_IsolatesFailedRows(t *testing.T) {
+	table := &fakeSalesTable{rows: make(map[string]models.BookSale)}
+	server := httptest.NewServer(table)
+	defer server.Close()
+	repo := NewSalesRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
+
+	inputs := []*models.BookSaleInput{
+		saleInput("book-1", 0, 1),
+		saleInput("bad", 0, 1),
+		saleInput("book-1", 1, 2),
+		saleInput("book-1", 0, 5), // same key as row 1
+	}
+
+	results, err := repo.UpsertSales(context.Background(), inputs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []RowStatus{RowCreated, RowFailed, RowCreated, RowFailed}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("row %d status = %s, want %s (err: %v)", i, r.Status, want[i], r.Err)
+		}
+	}
+	if !strings.Contains(results[1].Err.Error(), "foreign key") {
+		t.Errorf("row 1 error = %v, want the API message", results[1].Err)
+	}
+	if !strings.Contains(results[3].Err.Error(), "row 1") {
+		t.Errorf("row 3 error = %v, want it to name the earlier row", results[3].Err)
+	}
+	if len(table.rows) != 2 {
+		t.Errorf("stored %d rows, want 2", len(table.rows))
+	}
+}
+
+func TestCreateIdeas_SendsArrayWithColumns(t *testing.T) {
+	var bodies [][]map[string]any
+	var columns string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		columns = r.URL.Query().Get("columns")
+
+		out := make([]models.ContentIdea, len(body))
+		for i := range body {
+			out[i] = models.ContentIdea{ID: fmt.Sprintf("idea-%d", i), Type: body[i]["type"].(string)}
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+	repo := NewContentRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
+
+	bookID := "book-1"
+	results, err := repo.CreateIdeas(context.Background(), []*models.ContentIdeaInput{
+		{Type: "educational", BriefDescription: "a"},
+		{Type: "trend", BriefDescription: "b", BookID: &bookID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(bodies) != 1 || len(bodies[0]) != 2 {
+		t.Fatalf("requests = %v, want one array of 2 ideas", bodies)
+	}
+	if !strings.Contains(columns, "book_id") {
+		t.Errorf("columns = %q, want book_id listed", columns)
+	}
+	if got := Summarize(results); got != (BulkSummary{Created: 2}) {
+		t.Errorf("summary = %+v, want 2 created", got)
+	}
+	if results[1].Row.ID != "idea-1" || results[1].Row.Type != "trend" {
+		t.Errorf("row 1 = %+v", results[1].Row)
+	}
+}
+
+func TestCreateEntries_CanceledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request expected after cancel")
+	}))
+	defer server.Close()
+	repo := NewCalendarRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := repo.CreateEntries(ctx, []*models.ContentCalendarInput{
+		{ScheduledFor: time.Now(), Platform: "tiktok", PostType: "reel"},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a cancelled context")
+	}
+}
@@ -1,72 +1,31 @@
 package repository
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/postgrest"
 )
 
 // CalendarRepository handles calendar database operations
 type CalendarRepository struct {
-	config *config.SupabaseConfig
-	client *http.Client
+	db *postgrest.Client
 }
 
 // NewCalendarRepository creates a new calendar repository
 func NewCalendarRepository(cfg *config.SupabaseConfig) *CalendarRepository {
 	return &CalendarRepository{
-		config: cfg,
-		client: &http.Client{},
+		db: postgrest.NewClient(cfg),
 	}
 }
 
 // CreateEntry creates a new calendar entry
-func (r *CalendarRepository) CreateEntry(input *models.ContentCalendarInput) (*models.ContentCalendar, error) {
-	url := fmt.Sprintf("%s/rest/v1/content_calendar", r.config.URL)
-
-	jsonData, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal entry: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create entry: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create entry: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
+func (r *CalendarRepository) CreateEntry(ctx context.Context, input *models.ContentCalendarInput) (*models.ContentCalendar, error) {
 	var entries []models.ContentCalendar
-	if err := json.Unmarshal(body, &entries); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("content_calendar").Insert(ctx, input, &entries); err != nil {
+		return nil, fmt.Errorf("failed to create entry: %w", err)
 	}
 
 	if len(entries) == 0 {
@@ -76,88 +35,62 @@ func (r *CalendarRepository) CreateEntry(input *models.ContentCalendarInput) (*m
 	return &entries[0], nil
 }
 
-// GetEntries retrieves calendar entries with optional filters
-func (r *CalendarRepository) GetEntries(status string, limit int) ([]models.ContentCalendar, error) {
-	url := fmt.Sprintf("%s/rest/v1/content_calendar?select=*&order=scheduled_for.asc", r.config.URL)
-
-	if status != "" {
-		url += fmt.Sprintf("&status=eq.%s", status)
-	}
-	if limit > 0 {
-		url += fmt.Sprintf("&limit=%d", limit)
+// CreateEntries creates calendar entries in bulk, BulkChunkSize rows per
+// request, and reports the outcome of every row.
+func (r *CalendarRepository) CreateEntries(ctx context.Context, inputs []*models.ContentCalendarInput) ([]RowResult[models.ContentCalendar], error) {
+	results := NewResults[models.ContentCalendar](len(inputs))
+	idx := make([]int, len(inputs))
+	for i := range inputs {
+		idx[i] = i
+		results[i].Status = RowCreated
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	send := func(ctx context.Context, body []*models.ContentCalendarInput, out *[]models.ContentCalendar) error {
+		return r.db.From("content_calendar").
+			Columns("script_id", "scheduled_for", "platform", "post_type", "campaign_id").
+			Insert(ctx, body, out)
 	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
+	if err := writeChunks(ctx, send, inputs, idx, results); err != nil {
+		return nil, fmt.Errorf("failed to create entries: %w", err)
 	}
 
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+	return results, nil
+}
 
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get entries: %w", err)
-	}
-	defer resp.Body.Close()
+// GetEntries retrieves calendar entries with optional filters. A limit of
+// zero or less returns every matching entry.
+func (r *CalendarRepository) GetEntries(ctx context.Context, status string, limit int) ([]models.ContentCalendar, error) {
+	return collectPages(ctx, limit, func(ctx context.Context, offset, limit int) ([]models.ContentCalendar, error) {
+		return r.GetEntriesPage(ctx, status, offset, limit)
+	})
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+// GetEntriesPage retrieves one page of calendar entries in scheduled order.
+func (r *CalendarRepository) GetEntriesPage(ctx context.Context, status string, offset, limit int) ([]models.ContentCalendar, error) {
+	q := r.db.From("content_calendar").
+		Select("*").
+		Order("scheduled_for", true).
+		Order("id", true). // tie-breaker so pages do not overlap
+		Limit(limit).
+		Offset(offset)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get entries: HTTP %d: %s", resp.StatusCode, string(body))
+	if status != "" {
+		q.Eq("status", status)
 	}
 
 	var entries []models.ContentCalendar
-	if err := json.Unmarshal(body, &entries); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := q.Get(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to get entries: %w", err)
 	}
 
 	return entries, nil
 }
 
 // GetEntryByID gets a specific calendar entry by its ID
-func (r *CalendarRepository) GetEntryByID(id string) (*models.ContentCalendar, error) {
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	url := fmt.Sprintf("%s/rest/v1/content_calendar?id=eq.%s&select=*", r.config.URL, id)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get entry: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get entry: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
+func (r *CalendarRepository) GetEntryByID(ctx context.Context, id string) (*models.ContentCalendar, error) {
 	var entries []models.ContentCalendar
-	if err := json.Unmarshal(body, &entries); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("content_calendar").Select("*").Eq("id", id).Get(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to get entry: %w", err)
 	}
 
 	if len(entries) == 0 {
@@ -168,132 +101,81 @@ func (r *CalendarRepository) GetEntryByID(id string) (*models.ContentCalendar, e
 }
 
 // UpdateEntryStatus updates the status of a calendar entry
-func (r *CalendarRepository) UpdateEntryStatus(id string, status string) error {
-	url := fmt.Sprintf("%s/rest/v1/content_calendar?id=eq.%s", r.config.URL, id)
-
+func (r *CalendarRepository) UpdateEntryStatus(ctx context.Context, id string, status string) error {
 	data := map[string]string{"status": status}
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
-	}
-
-	req, err := http.NewRequest("PATCH", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
+	if err := r.db.From("content_calendar").Eq("id", id).Update(ctx, data, nil); err != nil {
 		return fmt.Errorf("failed to update entry: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to update entry: HTTP %d: %s", resp.StatusCode, string(body))
-	}
 
 	return nil
 }
 
-// GetStatusCounts returns a count of calendar entries grouped by status.
-func (r *CalendarRepository) GetStatusCounts() (map[string]int, error) {
-	url := fmt.Sprintf("%s/rest/v1/content_calendar?select=status", r.config.URL)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// UpdatePublishState records the outcome of a publish attempt. An error is
+// appended to the entry's publish_errors list, which takes a read first.
+func (r *CalendarRepository) UpdatePublishState(ctx context.Context, id string, update *models.PublishUpdate) error {
+	if err := update.Validate(); err != nil {
+		return fmt.Errorf("failed to update publish state: %w", err)
 	}
 
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
+	data := map[string]any{}
+	if update.Status != "" {
+		data["status"] = update.Status
 	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get status counts: %w", err)
+	if update.SubmissionID != nil {
+		data["submission_id"] = nil
+		data["publisher"] = nil
+		if *update.SubmissionID != "" {
+			data["submission_id"] = *update.SubmissionID
+			if update.Publisher != "" {
+				data["publisher"] = update.Publisher
+			}
+		}
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	if update.PostURL != nil {
+		data["post_url"] = *update.PostURL
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get status counts: HTTP %d: %s", resp.StatusCode, string(body))
+	if update.PublishedAt != nil {
+		data["published_at"] = update.PublishedAt.UTC()
+	}
+	if update.Error != nil {
+		entry, err := r.GetEntryByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to update publish state: %w", err)
+		}
+		data["publish_errors"] = append(entry.PublishErrors, *update.Error)
 	}
 
-	var rows []map[string]string
-	if err := json.Unmarshal(body, &rows); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("content_calendar").Eq("id", id).Update(ctx, data, nil); err != nil {
+		return fmt.Errorf("failed to update publish state: %w", err)
 	}
+	return nil
+}
 
+// GetStatusCounts returns a count of calendar entries grouped by status.
+// Each status is counted by the server, so no rows are fetched; statuses
+// without entries are left out.
+func (r *CalendarRepository) GetStatusCounts(ctx context.Context) (map[string]int, error) {
 	counts := make(map[string]int)
-	for _, row := range rows {
-		counts[row["status"]]++
+	for _, status := range models.CalendarStatuses {
+		n, err := r.db.From("content_calendar").Eq("status", status).Count(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get status counts: %w", err)
+		}
+		if n > 0 {
+			counts[status] = n
+		}
 	}
 	return counts, nil
 }
 
 // RetryFailed resets all calendar entries with status 'failed' back to 'approved'
 // so the cron job will pick them up again. Returns the number of entries reset.
-func (r *CalendarRepository) RetryFailed() (int, error) {
-	url := fmt.Sprintf("%s/rest/v1/content_calendar?status=eq.failed", r.config.URL)
-
+func (r *CalendarRepository) RetryFailed(ctx context.Context) (int, error) {
 	data := map[string]string{"status": "approved"}
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return 0, fmt.Errorf("failed to marshal data: %w", err)
-	}
-
-	req, err := http.NewRequest("PATCH", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return 0, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return 0, fmt.Errorf("failed to retry failed entries: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return 0, fmt.Errorf("failed to retry entries: HTTP %d: %s", resp.StatusCode, string(body))
-	}
 
 	var updated []models.ContentCalendar
-	if err := json.Unmarshal(body, &updated); err != nil {
-		return 0, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("content_calendar").Eq("status", "failed").Update(ctx, data, &updated); err != nil {
+		return 0, fmt.Errorf("failed to retry entries: %w", err)
 	}
 
 	return len(updated), nil
@@ -301,113 +183,43 @@ func (r *CalendarRepository) RetryFailed() (int, error) {
 
 // GetEntriesNeedingMedia returns approved/scheduled entries that have generate_media=true
 // and no media_url set yet, joined with their script data for prompt building.
-func (r *CalendarRepository) GetEntriesNeedingMedia() ([]models.ContentCalendarWithScript, error) {
-	url := fmt.Sprintf(
-		"%s/rest/v1/content_calendar?status=in.(approved,scheduled)&generate_media=eq.true&media_url=is.null&select=*,content_scripts(*,content_ideas(*,books(*)))",
-		r.config.URL,
-	)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := r.client.Do(req)
+func (r *CalendarRepository) GetEntriesNeedingMedia(ctx context.Context) ([]models.ContentCalendarWithScript, error) {
+	entries, err := collectPages(ctx, 0, func(ctx context.Context, offset, limit int) ([]models.ContentCalendarWithScript, error) {
+		var page []models.ContentCalendarWithScript
+		err := r.db.From("content_calendar").
+			Select("*,content_scripts(*,content_ideas(*,books(*)))").
+			In("status", []string{"approved", "scheduled"}).
+			Eq("generate_media", "true").
+			Is("media_url", "null").
+			Order("scheduled_for", true).
+			Order("id", true). // tie-breaker so pages do not overlap
+			Limit(limit).
+			Offset(offset).
+			Get(ctx, &page)
+		return page, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get entries needing media: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get entries needing media: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	var entries []models.ContentCalendarWithScript
-	if err := json.Unmarshal(body, &entries); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
 
 	return entries, nil
 }
 
 // UpdateMediaURL sets the media_url for a calendar entry.
-func (r *CalendarRepository) UpdateMediaURL(entryID, mediaURL string) error {
-	url := fmt.Sprintf("%s/rest/v1/content_calendar?id=eq.%s", r.config.URL, entryID)
-
+func (r *CalendarRepository) UpdateMediaURL(ctx context.Context, entryID, mediaURL string) error {
 	data := map[string]string{"media_url": mediaURL}
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
-	}
-
-	req, err := http.NewRequest("PATCH", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := r.client.Do(req)
-	if err != nil {
+	if err := r.db.From("content_calendar").Eq("id", entryID).Update(ctx, data, nil); err != nil {
 		return fmt.Errorf("failed to update media URL: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to update media URL: HTTP %d: %s", resp.StatusCode, string(body))
-	}
 
 	return nil
 }
 
 // DeleteEntry deletes a calendar entry
-func (r *CalendarRepository) DeleteEntry(id string) error {
-	url := fmt.Sprintf("%s/rest/v1/content_calendar?id=eq.%s", r.config.URL, id)
-
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := r.client.Do(req)
-	if err != nil {
+func (r *CalendarRepository) DeleteEntry(ctx context.Context, id string) error {
+	if err := r.db.From("content_calendar").Eq("id", id).Delete(ctx); err != nil {
 		return fmt.Errorf("failed to delete entry: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete entry: HTTP %d: %s", resp.StatusCode, string(body))
-	}
 
 	return nil
 }
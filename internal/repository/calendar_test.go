@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -11,49 +13,38 @@ import (
 	"github.com/gagipress/gagipress-cli/internal/models"
 )
 
-// TestGetStatusCounts verifies that GetStatusCounts returns the correct count
-// per status by reading only the status column from content_calendar.
+// TestGetStatusCounts verifies that GetStatusCounts asks the server for an
+// exact count per status instead of fetching the rows themselves.
 func TestGetStatusCounts(t *testing.T) {
-	// Simulate rows with mixed statuses
-	rows := []map[string]string{
-		{"status": "approved"},
-		{"status": "approved"},
-		{"status": "failed"},
-		{"status": "published"},
-		{"status": "published"},
-		{"status": "published"},
-	}
+	totals := map[string]int{"approved": 2, "failed": 1, "published": 1200}
 
-	var capturedPath string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		capturedPath = r.URL.RawQuery
-		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodHead {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+		if got := r.Header.Get("Prefer"); !strings.Contains(got, "count=exact") {
+			t.Errorf("Prefer = %q, want count=exact", got)
+		}
+		status := strings.TrimPrefix(r.URL.Query().Get("status"), "eq.")
+		w.Header().Set("Content-Range", "*/"+strconv.Itoa(totals[status]))
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(rows)
 	}))
 	defer server.Close()
 
 	repo := NewCalendarRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
 
-	counts, err := repo.GetStatusCounts()
+	counts, err := repo.GetStatusCounts(context.Background())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// Verify counts
-	if counts["approved"] != 2 {
-		t.Errorf("expected approved=2, got %d", counts["approved"])
+	if len(counts) != len(totals) {
+		t.Errorf("counts = %v, want only the statuses with entries %v", counts, totals)
 	}
-	if counts["failed"] != 1 {
-		t.Errorf("expected failed=1, got %d", counts["failed"])
-	}
-	if counts["published"] != 3 {
-		t.Errorf("expected published=3, got %d", counts["published"])
-	}
-
-	// Verify only the status column is requested (keep payload small)
-	if capturedPath == "" {
-		t.Error("expected a URL query string, got empty")
+	for status, want := range totals {
+		if counts[status] != want {
+			t.Errorf("expected %s=%d, got %d", status, want, counts[status])
+		}
 	}
 }
 
@@ -87,7 +78,7 @@ func TestGetEntriesNeedingMedia(t *testing.T) {
 
 	repo := NewCalendarRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
 
-	result, err := repo.GetEntriesNeedingMedia()
+	result, err := repo.GetEntriesNeedingMedia(context.Background())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -122,7 +113,7 @@ func TestUpdateMediaURL(t *testing.T) {
 
 	repo := NewCalendarRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
 
-	err := repo.UpdateMediaURL("entry-42", "https://example.com/image.jpg")
+	err := repo.UpdateMediaURL(context.Background(), "entry-42", "https://example.com/image.jpg")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -164,7 +155,7 @@ func TestRetryFailed(t *testing.T) {
 
 	repo := NewCalendarRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
 
-	count, err := repo.RetryFailed()
+	count, err := repo.RetryFailed(context.Background())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -189,3 +180,56 @@ func TestRetryFailed(t *testing.T) {
 		t.Errorf("expected body status=approved, got %q", capturedBody["status"])
 	}
 }
+
+// TestUpdatePublishState verifies that an error is appended to the stored
+// publish_errors list and sent with the other fields in one PATCH.
+func TestUpdatePublishState(t *testing.T) {
+	existing := models.PublishError{Stage: "submit", Message: "first"}
+	var patch map[string]json.RawMessage
+	var patchQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode([]models.ContentCalendar{{ID: "entry-1", PublishErrors: []models.PublishError{existing}}})
+		case http.MethodPatch:
+			patchQuery = r.URL.RawQuery
+			json.NewDecoder(r.Body).Decode(&patch)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	repo := NewCalendarRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
+	err := repo.UpdatePublishState(context.Background(), "entry-1", &models.PublishUpdate{
+		Status: "failed",
+		Error:  &models.PublishError{Stage: "delivery", Message: "second"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(patchQuery, "id=eq.entry-1") {
+		t.Errorf("PATCH query = %q", patchQuery)
+	}
+	if string(patch["status"]) != `"failed"` {
+		t.Errorf("status = %s", patch["status"])
+	}
+	var errs []models.PublishError
+	json.Unmarshal(patch["publish_errors"], &errs)
+	if len(errs) != 2 || errs[0].Message != "first" || errs[1].Stage != "delivery" {
+		t.Errorf("publish_errors = %s, want the old error followed by the new one", patch["publish_errors"])
+	}
+	if _, ok := patch["submission_id"]; ok {
+		t.Error("unset fields must not be sent")
+	}
+
+	// The publisher is sent with the submission
+	sub := "sub-1"
+	if err := repo.UpdatePublishState(context.Background(), "entry-1", &models.PublishUpdate{SubmissionID: &sub, Publisher: "instagram"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(patch["submission_id"]) != `"sub-1"` || string(patch["publisher"]) != `"instagram"` {
+		t.Errorf("submission_id, publisher = %s, %s", patch["submission_id"], patch["publisher"])
+	}
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/postgrest"
+)
+
+// CampaignsRepository handles launch campaign database operations
+type CampaignsRepository struct {
+	db *postgrest.Client
+}
+
+// NewCampaignsRepository creates a new campaigns repository
+func NewCampaignsRepository(cfg *config.SupabaseConfig) *CampaignsRepository {
+	return &CampaignsRepository{
+		db: postgrest.NewClient(cfg),
+	}
+}
+
+// CreateCampaign creates a new campaign
+func (r *CampaignsRepository) CreateCampaign(ctx context.Context, input *models.CampaignInput) (*models.Campaign, error) {
+	var campaigns []models.Campaign
+	if err := r.db.From("campaigns").Insert(ctx, input, &campaigns); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	if len(campaigns) == 0 {
+		return nil, fmt.Errorf("no campaign returned from API")
+	}
+
+	return &campaigns[0], nil
+}
@@ -1,72 +1,32 @@
 package repository
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/postgrest"
 )
 
 // ContentRepository handles content database operations
 type ContentRepository struct {
-	config *config.SupabaseConfig
-	client *http.Client
+	db *postgrest.Client
 }
 
 // NewContentRepository creates a new content repository
 func NewContentRepository(cfg *config.SupabaseConfig) *ContentRepository {
 	return &ContentRepository{
-		config: cfg,
-		client: &http.Client{},
+		db: postgrest.NewClient(cfg),
 	}
 }
 
 // CreateIdea creates a new content idea
-func (r *ContentRepository) CreateIdea(input *models.ContentIdeaInput) (*models.ContentIdea, error) {
-	url := fmt.Sprintf("%s/rest/v1/content_ideas", r.config.URL)
-
-	jsonData, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal idea: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create idea: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create idea: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
+func (r *ContentRepository) CreateIdea(ctx context.Context, input *models.ContentIdeaInput) (*models.ContentIdea, error) {
 	var ideas []models.ContentIdea
-	if err := json.Unmarshal(body, &ideas); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("content_ideas").Insert(ctx, input, &ideas); err != nil {
+		return nil, fmt.Errorf("failed to create idea: %w", err)
 	}
 
 	if len(ideas) == 0 {
@@ -76,88 +36,72 @@ func (r *ContentRepository) CreateIdea(input *models.ContentIdeaInput) (*models.
 	return &ideas[0], nil
 }
 
-// GetIdeas retrieves content ideas with optional filters
-func (r *ContentRepository) GetIdeas(status string, limit int) ([]models.ContentIdea, error) {
-	url := fmt.Sprintf("%s/rest/v1/content_ideas?select=*&order=generated_at.desc", r.config.URL)
+// CreateIdeas creates content ideas in bulk, BulkChunkSize rows per request,
+// and reports the outcome of every row.
+func (r *ContentRepository) CreateIdeas(ctx context.Context, inputs []*models.ContentIdeaInput) ([]RowResult[models.ContentIdea], error) {
+	results := NewResults[models.ContentIdea](len(inputs))
+	idx := make([]int, len(inputs))
+	for i := range inputs {
+		idx[i] = i
+		results[i].Status = RowCreated
+	}
 
-	if status != "" {
-		url += fmt.Sprintf("&status=eq.%s", status)
+	send := func(ctx context.Context, body []*models.ContentIdeaInput, out *[]models.ContentIdea) error {
+		return r.db.From("content_ideas").
+			Columns("type", "brief_description", "relevance_score", "book_id", "metadata", "campaign_id").
+			Insert(ctx, body, out)
 	}
-	if limit > 0 {
-		url += fmt.Sprintf("&limit=%d", limit)
+	if err := writeChunks(ctx, send, inputs, idx, results); err != nil {
+		return nil, fmt.Errorf("failed to create ideas: %w", err)
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	return results, nil
+}
 
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
+// GetIdeas retrieves content ideas with optional filters
+func (r *ContentRepository) GetIdeas(ctx context.Context, status string, limit int) ([]models.ContentIdea, error) {
+	return r.GetIdeasByBook(ctx, "", status, limit)
+}
 
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+// GetIdeasByBook retrieves content ideas for a book, optionally filtered by status.
+// An empty bookID matches ideas for every book. A limit of zero or less returns
+// every matching idea, fetched page by page so none are lost to the row cap.
+func (r *ContentRepository) GetIdeasByBook(ctx context.Context, bookID, status string, limit int) ([]models.ContentIdea, error) {
+	return collectPages(ctx, limit, func(ctx context.Context, offset, limit int) ([]models.ContentIdea, error) {
+		return r.GetIdeasPage(ctx, bookID, status, offset, limit)
+	})
+}
 
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get ideas: %w", err)
-	}
-	defer resp.Body.Close()
+// GetIdeasPage retrieves one page of content ideas, newest first.
+func (r *ContentRepository) GetIdeasPage(ctx context.Context, bookID, status string, offset, limit int) ([]models.ContentIdea, error) {
+	q := r.db.From("content_ideas").
+		Select("*").
+		Order("generated_at", false).
+		Order("id", false). // tie-breaker so pages do not overlap
+		Limit(limit).
+		Offset(offset)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	if bookID != "" {
+		q.Eq("book_id", bookID)
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get ideas: HTTP %d: %s", resp.StatusCode, string(body))
+	if status != "" {
+		q.Eq("status", status)
 	}
 
 	var ideas []models.ContentIdea
-	if err := json.Unmarshal(body, &ideas); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := q.Get(ctx, &ideas); err != nil {
+		return nil, fmt.Errorf("failed to get ideas: %w", err)
 	}
 
 	return ideas, nil
 }
 
 // UpdateIdeaStatus updates the status of a content idea
-func (r *ContentRepository) UpdateIdeaStatus(id string, status string) error {
-	url := fmt.Sprintf("%s/rest/v1/content_ideas?id=eq.%s", r.config.URL, id)
-
+func (r *ContentRepository) UpdateIdeaStatus(ctx context.Context, id string, status string) error {
 	data := map[string]string{"status": status}
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
-	}
-
-	req, err := http.NewRequest("PATCH", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
+	if err := r.db.From("content_ideas").Eq("id", id).Update(ctx, data, nil); err != nil {
 		return fmt.Errorf("failed to update idea: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to update idea: HTTP %d: %s", resp.StatusCode, string(body))
-	}
 
 	return nil
 }
@@ -165,54 +109,26 @@ func (r *ContentRepository) UpdateIdeaStatus(id string, status string) error {
 // GetIdeaByIDPrefix finds a content idea by UUID prefix (minimum 6 characters).
 // Returns an error if the prefix is ambiguous (matches multiple ideas) or not found.
 // Uses the find_idea_by_prefix PostgreSQL function via PostgREST RPC.
-func (r *ContentRepository) GetIdeaByIDPrefix(prefix string) (*models.ContentIdea, error) {
+func (r *ContentRepository) GetIdeaByIDPrefix(ctx context.Context, prefix string) (*models.ContentIdea, error) {
+	return ResolveIdeaPrefix(ctx, prefix, func(ctx context.Context, prefix string) ([]models.ContentIdea, error) {
+		var ideas []models.ContentIdea
+		params := map[string]string{"prefix_pattern": prefix}
+		err := r.db.RPC(ctx, "find_idea_by_prefix", params, &ideas)
+		return ideas, err
+	})
+}
+
+// ResolveIdeaPrefix applies the shared prefix rules on top of a backend's
+// prefix lookup. See ResolveBookPrefix.
+func ResolveIdeaPrefix(ctx context.Context, prefix string, find func(context.Context, string) ([]models.ContentIdea, error)) (*models.ContentIdea, error) {
 	if len(prefix) < 6 {
 		return nil, fmt.Errorf("prefix too short: must be at least 6 characters, got %d", len(prefix))
 	}
 
-	// Use PostgreSQL RPC function for UUID prefix matching
-	requestURL := fmt.Sprintf("%s/rest/v1/rpc/find_idea_by_prefix", r.config.URL)
-
-	// Create request body with prefix parameter
-	reqBody := map[string]string{"prefix_pattern": prefix}
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", requestURL, strings.NewReader(string(jsonBody)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := r.client.Do(req)
+	ideas, err := find(ctx, prefix)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get idea by prefix: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get idea by prefix: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	var ideas []models.ContentIdea
-	if err := json.Unmarshal(body, &ideas); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
 
 	switch len(ideas) {
 	case 0:
@@ -229,47 +145,10 @@ func (r *ContentRepository) GetIdeaByIDPrefix(prefix string) (*models.ContentIde
 }
 
 // CreateScript creates a new content script
-func (r *ContentRepository) CreateScript(input *models.ContentScriptInput) (*models.ContentScript, error) {
-	url := fmt.Sprintf("%s/rest/v1/content_scripts", r.config.URL)
-
-	jsonData, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal script: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create script: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create script: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
+func (r *ContentRepository) CreateScript(ctx context.Context, input *models.ContentScriptInput) (*models.ContentScript, error) {
 	var scripts []models.ContentScript
-	if err := json.Unmarshal(body, &scripts); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("content_scripts").Insert(ctx, input, &scripts); err != nil {
+		return nil, fmt.Errorf("failed to create script: %w", err)
 	}
 
 	if len(scripts) == 0 {
@@ -280,41 +159,10 @@ func (r *ContentRepository) CreateScript(input *models.ContentScriptInput) (*mod
 }
 
 // GetScriptByID gets a specific script by its ID
-func (r *ContentRepository) GetScriptByID(id string) (*models.ContentScript, error) {
-	url := fmt.Sprintf("%s/rest/v1/content_scripts?id=eq.%s&select=*", r.config.URL, id)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get script: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get script: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
+func (r *ContentRepository) GetScriptByID(ctx context.Context, id string) (*models.ContentScript, error) {
 	var scripts []models.ContentScript
-	if err := json.Unmarshal(body, &scripts); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("content_scripts").Select("*").Eq("id", id).Get(ctx, &scripts); err != nil {
+		return nil, fmt.Errorf("failed to get script: %w", err)
 	}
 
 	if len(scripts) == 0 {
@@ -324,46 +172,25 @@ func (r *ContentRepository) GetScriptByID(id string) (*models.ContentScript, err
 	return &scripts[0], nil
 }
 
-// GetScripts retrieves content scripts
-func (r *ContentRepository) GetScripts(limit int) ([]models.ContentScript, error) {
-	url := fmt.Sprintf("%s/rest/v1/content_scripts?select=*&order=created_at.desc", r.config.URL)
-
-	if limit > 0 {
-		url += fmt.Sprintf("&limit=%d", limit)
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+// GetScripts retrieves content scripts, newest first. A limit of zero or
+// less returns every script.
+func (r *ContentRepository) GetScripts(ctx context.Context, limit int) ([]models.ContentScript, error) {
+	return collectPages(ctx, limit, r.GetScriptsPage)
+}
 
-	resp, err := r.client.Do(req)
+// GetScriptsPage retrieves one page of content scripts, newest first.
+func (r *ContentRepository) GetScriptsPage(ctx context.Context, offset, limit int) ([]models.ContentScript, error) {
+	var scripts []models.ContentScript
+	err := r.db.From("content_scripts").
+		Select("*").
+		Order("created_at", false).
+		Order("id", false). // tie-breaker so pages do not overlap
+		Limit(limit).
+		Offset(offset).
+		Get(ctx, &scripts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get scripts: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get scripts: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	var scripts []models.ContentScript
-	if err := json.Unmarshal(body, &scripts); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
 
 	return scripts, nil
 }
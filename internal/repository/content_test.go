@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -25,7 +26,7 @@ func TestGetIdeaByIDPrefix_PrefixTooShort(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := repo.GetIdeaByIDPrefix(tt.prefix)
+			_, err := repo.GetIdeaByIDPrefix(context.Background(), tt.prefix)
 			if err == nil {
 				t.Fatal("expected error for short prefix, got nil")
 			}
@@ -46,7 +47,7 @@ func TestGetIdeaByIDPrefix_NoMatches(t *testing.T) {
 
 	repo := NewContentRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
 
-	_, err := repo.GetIdeaByIDPrefix("abcdef")
+	_, err := repo.GetIdeaByIDPrefix(context.Background(), "abcdef")
 	if err == nil {
 		t.Fatal("expected error for no matches, got nil")
 	}
@@ -77,7 +78,7 @@ func TestGetIdeaByIDPrefix_SingleMatch(t *testing.T) {
 
 	repo := NewContentRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
 
-	idea, err := repo.GetIdeaByIDPrefix("abcdef12")
+	idea, err := repo.GetIdeaByIDPrefix(context.Background(), "abcdef12")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -100,7 +101,7 @@ func TestGetIdeaByIDPrefix_6CharPrefix(t *testing.T) {
 
 	repo := NewContentRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
 
-	idea, err := repo.GetIdeaByIDPrefix("abcdef")
+	idea, err := repo.GetIdeaByIDPrefix(context.Background(), "abcdef")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -125,7 +126,7 @@ func TestGetIdeaByIDPrefix_MultipleMatches(t *testing.T) {
 
 	repo := NewContentRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
 
-	_, err := repo.GetIdeaByIDPrefix("abcdef12")
+	_, err := repo.GetIdeaByIDPrefix(context.Background(), "abcdef12")
 	if err == nil {
 		t.Fatal("expected error for multiple matches, got nil")
 	}
@@ -152,7 +153,7 @@ func TestGetIdeaByIDPrefix_FullUUID(t *testing.T) {
 
 	repo := NewContentRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
 
-	idea, err := repo.GetIdeaByIDPrefix(fullID)
+	idea, err := repo.GetIdeaByIDPrefix(context.Background(), fullID)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -179,7 +180,7 @@ func TestGetIdeaByIDPrefix_UsesServiceKey(t *testing.T) {
 		ServiceKey: "service-key",
 	})
 
-	_, err := repo.GetIdeaByIDPrefix("abcdef12")
+	_, err := repo.GetIdeaByIDPrefix(context.Background(), "abcdef12")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -205,7 +206,7 @@ func TestGetIdeaByIDPrefix_FallsBackToAnonKey(t *testing.T) {
 		AnonKey: "anon-key",
 	})
 
-	_, err := repo.GetIdeaByIDPrefix("abcdef12")
+	_, err := repo.GetIdeaByIDPrefix(context.Background(), "abcdef12")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
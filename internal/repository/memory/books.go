@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+type bookStore struct {
+	db *DB
+}
+
+func (r *bookStore) Create(ctx context.Context, input *models.BookInput) (*models.Book, error) {
+	var book models.Book
+	err := r.db.write(func(s *snapshot) error {
+		if err := checkBook(s, "", input); err != nil {
+			return err
+		}
+
+		now := r.db.timestamp()
+		book = models.Book{
+			ID:              repository.NewID(),
+			Title:           input.Title,
+			Genre:           input.Genre,
+			TargetAudience:  input.TargetAudience,
+			KDPASIN:         input.KDPASIN,
+			CoverImageURL:   input.CoverImageURL,
+			PublicationDate: input.PublicationDate,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		s.Books = append(s.Books, book)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create book: %w", err)
+	}
+
+	return &book, nil
+}
+
+func (r *bookStore) GetAll(ctx context.Context) ([]models.Book, error) {
+	var books []models.Book
+	r.db.read(func(s *snapshot) {
+		// Walk backwards so rows with equal timestamps stay newest first
+		for i := len(s.Books) - 1; i >= 0; i-- {
+			books = append(books, s.Books[i])
+		}
+	})
+
+	sort.SliceStable(books, func(i, j int) bool {
+		return books[i].CreatedAt.After(books[j].CreatedAt)
+	})
+	return books, nil
+}
+
+func (r *bookStore) GetByID(ctx context.Context, id string) (*models.Book, error) {
+	var book *models.Book
+	r.db.read(func(s *snapshot) {
+		if i := s.bookIndex(id); i >= 0 {
+			b := s.Books[i]
+			book = &b
+		}
+	})
+
+	if book == nil {
+		return nil, fmt.Errorf("book not found")
+	}
+	return book, nil
+}
+
+// Update mirrors a PATCH of BookInput: title and genre are always written,
+// optional fields only when set.
+func (r *bookStore) Update(ctx context.Context, id string, input *models.BookInput) (*models.Book, error) {
+	var book *models.Book
+	err := r.db.write(func(s *snapshot) error {
+		i := s.bookIndex(id)
+		if i < 0 {
+			return nil
+		}
+		if err := checkBook(s, id, input); err != nil {
+			return err
+		}
+
+		b := &s.Books[i]
+		b.Title = input.Title
+		b.Genre = input.Genre
+		if input.TargetAudience != "" {
+			b.TargetAudience = input.TargetAudience
+		}
+		if input.KDPASIN != "" {
+			b.KDPASIN = input.KDPASIN
+		}
+		if input.CoverImageURL != "" {
+			b.CoverImageURL = input.CoverImageURL
+		}
+		if input.PublicationDate != nil {
+			b.PublicationDate = input.PublicationDate
+		}
+		b.UpdatedAt = r.db.timestamp()
+
+		updated := *b
+		book = &updated
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update book: %w", err)
+	}
+
+	if book == nil {
+		return nil, fmt.Errorf("no book returned from API")
+	}
+	return book, nil
+}
+
+func (r *bookStore) GetBookByIDPrefix(ctx context.Context, prefix string) (*models.Book, error) {
+	return repository.ResolveBookPrefix(ctx, prefix, func(ctx context.Context, prefix string) ([]models.Book, error) {
+		var books []models.Book
+		r.db.read(func(s *snapshot) {
+			for _, b := range s.Books {
+				if strings.HasPrefix(b.ID, prefix) {
+					books = append(books, b)
+				}
+			}
+		})
+		return books, nil
+	})
+}
+
+func (r *bookStore) Delete(ctx context.Context, id string) error {
+	return r.db.write(func(s *snapshot) error {
+		s.deleteBook(id)
+		return nil
+	})
+}
+
+// checkBook applies the books table constraints. selfID is the row being
+// updated, if any, so it does not conflict with its own ASIN.
+func checkBook(s *snapshot, selfID string, input *models.BookInput) error {
+	if input.KDPASIN != "" {
+		for _, b := range s.Books {
+			if b.ID != selfID && b.KDPASIN == input.KDPASIN {
+				return constraintError("books.kdp_asin %q already exists", input.KDPASIN)
+			}
+		}
+	}
+	return nil
+}
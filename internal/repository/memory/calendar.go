@@ -0,0 +1,277 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+type calendarStore struct {
+	db *DB
+}
+
+func (r *calendarStore) CreateEntry(ctx context.Context, input *models.ContentCalendarInput) (*models.ContentCalendar, error) {
+	var entry models.ContentCalendar
+	err := r.db.write(func(s *snapshot) error {
+		var err error
+		entry, err = s.insertEntry(input)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func (r *calendarStore) CreateEntries(ctx context.Context, inputs []*models.ContentCalendarInput) ([]repository.RowResult[models.ContentCalendar], error) {
+	results := repository.NewResults[models.ContentCalendar](len(inputs))
+	err := r.db.write(func(s *snapshot) error {
+		for i, input := range inputs {
+			entry, err := s.insertEntry(input)
+			if err != nil {
+				results[i].Status = repository.RowFailed
+				results[i].Err = err
+				continue
+			}
+			results[i].Status = repository.RowCreated
+			results[i].Row = &entry
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create entries: %w", err)
+	}
+
+	return results, nil
+}
+
+// insertEntry checks the content_calendar constraints and appends the entry.
+func (s *snapshot) insertEntry(input *models.ContentCalendarInput) (models.ContentCalendar, error) {
+	if err := models.ValidatePlatform(input.Platform); err != nil {
+		return models.ContentCalendar{}, constraintError("content_calendar.platform %q is not registered", input.Platform)
+	}
+	if input.PostType != "reel" && input.PostType != "story" && input.PostType != "feed" {
+		return models.ContentCalendar{}, constraintError("content_calendar.post_type %q is not allowed", input.PostType)
+	}
+	if input.ScriptID != nil && s.scriptIndex(*input.ScriptID) < 0 {
+		return models.ContentCalendar{}, constraintError("content_calendar.script_id %q does not reference a script", *input.ScriptID)
+	}
+	if input.CampaignID != nil && s.campaignIndex(*input.CampaignID) < 0 {
+		return models.ContentCalendar{}, constraintError("content_calendar.campaign_id %q does not reference a campaign", *input.CampaignID)
+	}
+
+	entry := models.ContentCalendar{
+		ID:           repository.NewID(),
+		ScriptID:     input.ScriptID,
+		ScheduledFor: input.ScheduledFor.UTC(),
+		Platform:     input.Platform,
+		PostType:     input.PostType,
+		Status:       "pending_approval",
+		CampaignID:   input.CampaignID,
+	}
+	s.Calendar = append(s.Calendar, entry)
+	return entry, nil
+}
+
+func (r *calendarStore) GetEntries(ctx context.Context, status string, limit int) ([]models.ContentCalendar, error) {
+	return r.GetEntriesPage(ctx, status, 0, limit)
+}
+
+func (r *calendarStore) GetEntriesPage(ctx context.Context, status string, offset, limit int) ([]models.ContentCalendar, error) {
+	var entries []models.ContentCalendar
+	r.db.read(func(s *snapshot) {
+		for _, e := range s.Calendar {
+			if status == "" || e.Status == status {
+				entries = append(entries, e)
+			}
+		}
+	})
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].ScheduledFor.Before(entries[j].ScheduledFor)
+	})
+	return page(entries, offset, limit), nil
+}
+
+func (r *calendarStore) GetEntryByID(ctx context.Context, id string) (*models.ContentCalendar, error) {
+	var entry *models.ContentCalendar
+	r.db.read(func(s *snapshot) {
+		if i := s.entryIndex(id); i >= 0 {
+			e := s.Calendar[i]
+			entry = &e
+		}
+	})
+
+	if entry == nil {
+		return nil, fmt.Errorf("entry not found: %s", id)
+	}
+	return entry, nil
+}
+
+func (r *calendarStore) UpdateEntryStatus(ctx context.Context, id string, status string) error {
+	err := r.db.write(func(s *snapshot) error {
+		if err := models.ValidateCalendarStatus(status); err != nil {
+			return constraintError("content_calendar.status: %v", err)
+		}
+		if i := s.entryIndex(id); i >= 0 {
+			s.Calendar[i].Status = status
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update entry: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePublishState applies update, appending update.Error to the entry's
+// publish_errors. Submission IDs are unique, like the partial unique index.
+func (r *calendarStore) UpdatePublishState(ctx context.Context, id string, update *models.PublishUpdate) error {
+	err := r.db.write(func(s *snapshot) error {
+		if err := update.Validate(); err != nil {
+			return constraintError("content_calendar: %v", err)
+		}
+		i := s.entryIndex(id)
+		if i < 0 {
+			return fmt.Errorf("entry not found: %s", id)
+		}
+		if update.SubmissionID != nil && *update.SubmissionID != "" {
+			for _, e := range s.Calendar {
+				if e.ID != id && e.SubmissionID != nil && *e.SubmissionID == *update.SubmissionID {
+					return constraintError("content_calendar.submission_id %q already exists", *update.SubmissionID)
+				}
+			}
+		}
+
+		e := &s.Calendar[i]
+		if update.Status != "" {
+			e.Status = update.Status
+		}
+		if update.SubmissionID != nil {
+			e.SubmissionID, e.Publisher = update.SubmissionID, nil
+			if update.Publisher != "" {
+				publisher := update.Publisher
+				e.Publisher = &publisher
+			}
+			if *update.SubmissionID == "" {
+				e.SubmissionID, e.Publisher = nil, nil
+			}
+		}
+		if update.PostURL != nil {
+			e.PostURL = update.PostURL
+		}
+		if update.PublishedAt != nil {
+			at := update.PublishedAt.UTC()
+			e.PublishedAt = &at
+		}
+		if update.Error != nil {
+			e.PublishErrors = append(e.PublishErrors, *update.Error)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update publish state: %w", err)
+	}
+
+	return nil
+}
+
+func (r *calendarStore) GetStatusCounts(ctx context.Context) (map[string]int, error) {
+	counts := make(map[string]int)
+	r.db.read(func(s *snapshot) {
+		for _, e := range s.Calendar {
+			counts[e.Status]++
+		}
+	})
+	return counts, nil
+}
+
+func (r *calendarStore) RetryFailed(ctx context.Context) (int, error) {
+	count := 0
+	err := r.db.write(func(s *snapshot) error {
+		for i := range s.Calendar {
+			if s.Calendar[i].Status == "failed" {
+				s.Calendar[i].Status = "approved"
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to retry entries: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetEntriesNeedingMedia applies the same filter and embed as the Supabase
+// query: approved entries with generate_media set and no media_url yet.
+func (r *calendarStore) GetEntriesNeedingMedia(ctx context.Context) ([]models.ContentCalendarWithScript, error) {
+	var entries []models.ContentCalendarWithScript
+	r.db.read(func(s *snapshot) {
+		for _, e := range s.Calendar {
+			if (e.Status != "approved" && e.Status != "scheduled") || !e.GenerateMedia || e.MediaURL != nil {
+				continue
+			}
+			entries = append(entries, models.ContentCalendarWithScript{
+				ContentCalendar: e,
+				Script:          s.scriptWithIdea(e.ScriptID),
+			})
+		}
+	})
+	return entries, nil
+}
+
+func (r *calendarStore) UpdateMediaURL(ctx context.Context, entryID, mediaURL string) error {
+	err := r.db.write(func(s *snapshot) error {
+		if i := s.entryIndex(entryID); i >= 0 {
+			s.Calendar[i].MediaURL = &mediaURL
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update media URL: %w", err)
+	}
+
+	return nil
+}
+
+func (r *calendarStore) DeleteEntry(ctx context.Context, id string) error {
+	err := r.db.write(func(s *snapshot) error {
+		s.deleteEntry(id)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete entry: %w", err)
+	}
+
+	return nil
+}
+
+// scriptWithIdea builds the content_scripts(*,content_ideas(*,books(*))) embed.
+func (s *snapshot) scriptWithIdea(scriptID *string) *models.ContentScriptWithIdea {
+	if scriptID == nil {
+		return nil
+	}
+	i := s.scriptIndex(*scriptID)
+	if i < 0 {
+		return nil
+	}
+
+	script := &models.ContentScriptWithIdea{ContentScript: s.Scripts[i]}
+	if j := s.ideaIndex(script.IdeaID); j >= 0 {
+		script.Idea = &models.ContentIdeaWithBook{ContentIdea: s.Ideas[j]}
+		if bookID := script.Idea.BookID; bookID != nil {
+			if k := s.bookIndex(*bookID); k >= 0 {
+				book := s.Books[k]
+				script.Idea.Book = &book
+			}
+		}
+	}
+	return script
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+type campaignStore struct {
+	db *DB
+}
+
+func (r *campaignStore) CreateCampaign(ctx context.Context, input *models.CampaignInput) (*models.Campaign, error) {
+	var campaign models.Campaign
+	err := r.db.write(func(s *snapshot) error {
+		if input.Name == "" || input.LaunchDate.IsZero() {
+			return constraintError("campaigns.name and launch_date are required")
+		}
+		if s.bookIndex(input.BookID) < 0 {
+			return constraintError("campaigns.book_id %q does not reference a book", input.BookID)
+		}
+
+		campaign = models.Campaign{
+			ID:         repository.NewID(),
+			BookID:     input.BookID,
+			Name:       input.Name,
+			LaunchDate: input.LaunchDate,
+			CreatedAt:  r.db.timestamp(),
+		}
+		s.Campaigns = append(s.Campaigns, campaign)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	return &campaign, nil
+}
@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+// contentIdeaTypes mirrors the content_ideas.type CHECK constraint.
+var contentIdeaTypes = map[string]bool{
+	"educational":   true,
+	"entertainment": true,
+	"bts":           true,
+	"ugc":           true,
+	"trend":         true,
+}
+
+type contentStore struct {
+	db *DB
+}
+
+func (r *contentStore) CreateIdea(ctx context.Context, input *models.ContentIdeaInput) (*models.ContentIdea, error) {
+	var idea models.ContentIdea
+	err := r.db.write(func(s *snapshot) error {
+		var err error
+		idea, err = s.insertIdea(input, r.db.timestamp())
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create idea: %w", err)
+	}
+
+	return &idea, nil
+}
+
+func (r *contentStore) CreateIdeas(ctx context.Context, inputs []*models.ContentIdeaInput) ([]repository.RowResult[models.ContentIdea], error) {
+	results := repository.NewResults[models.ContentIdea](len(inputs))
+	err := r.db.write(func(s *snapshot) error {
+		now := r.db.timestamp()
+		for i, input := range inputs {
+			idea, err := s.insertIdea(input, now)
+			if err != nil {
+				results[i].Status = repository.RowFailed
+				results[i].Err = err
+				continue
+			}
+			results[i].Status = repository.RowCreated
+			results[i].Row = &idea
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ideas: %w", err)
+	}
+
+	return results, nil
+}
+
+// insertIdea checks the content_ideas constraints and appends the idea.
+func (s *snapshot) insertIdea(input *models.ContentIdeaInput, now time.Time) (models.ContentIdea, error) {
+	if !contentIdeaTypes[input.Type] {
+		return models.ContentIdea{}, constraintError("content_ideas.type %q is not allowed", input.Type)
+	}
+	if input.RelevanceScore != nil && (*input.RelevanceScore < 0 || *input.RelevanceScore > 100) {
+		return models.ContentIdea{}, constraintError("content_ideas.relevance_score must be between 0 and 100")
+	}
+	if input.BookID != nil && s.bookIndex(*input.BookID) < 0 {
+		return models.ContentIdea{}, constraintError("content_ideas.book_id %q does not reference a book", *input.BookID)
+	}
+	if input.CampaignID != nil && s.campaignIndex(*input.CampaignID) < 0 {
+		return models.ContentIdea{}, constraintError("content_ideas.campaign_id %q does not reference a campaign", *input.CampaignID)
+	}
+
+	idea := models.ContentIdea{
+		ID:               repository.NewID(),
+		Type:             input.Type,
+		BriefDescription: input.BriefDescription,
+		RelevanceScore:   input.RelevanceScore,
+		BookID:           input.BookID,
+		Status:           "pending",
+		GeneratedAt:      now,
+		Metadata:         input.Metadata,
+		CampaignID:       input.CampaignID,
+	}
+	s.Ideas = append(s.Ideas, idea)
+	return idea, nil
+}
+
+func (r *contentStore) GetIdeas(ctx context.Context, status string, limit int) ([]models.ContentIdea, error) {
+	return r.GetIdeasByBook(ctx, "", status, limit)
+}
+
+func (r *contentStore) GetIdeasByBook(ctx context.Context, bookID, status string, limit int) ([]models.ContentIdea, error) {
+	return r.GetIdeasPage(ctx, bookID, status, 0, limit)
+}
+
+func (r *contentStore) GetIdeasPage(ctx context.Context, bookID, status string, offset, limit int) ([]models.ContentIdea, error) {
+	var ideas []models.ContentIdea
+	r.db.read(func(s *snapshot) {
+		// Walk backwards so rows with equal timestamps stay newest first
+		for i := len(s.Ideas) - 1; i >= 0; i-- {
+			idea := s.Ideas[i]
+			if bookID != "" && (idea.BookID == nil || *idea.BookID != bookID) {
+				continue
+			}
+			if status != "" && idea.Status != status {
+				continue
+			}
+			ideas = append(ideas, idea)
+		}
+	})
+
+	sort.SliceStable(ideas, func(i, j int) bool {
+		return ideas[i].GeneratedAt.After(ideas[j].GeneratedAt)
+	})
+	return page(ideas, offset, limit), nil
+}
+
+func (r *contentStore) UpdateIdeaStatus(ctx context.Context, id string, status string) error {
+	err := r.db.write(func(s *snapshot) error {
+		if err := models.ValidateIdeaStatus(status); err != nil {
+			return constraintError("content_ideas.status: %v", err)
+		}
+		if i := s.ideaIndex(id); i >= 0 {
+			s.Ideas[i].Status = status
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update idea: %w", err)
+	}
+
+	return nil
+}
+
+func (r *contentStore) GetIdeaByIDPrefix(ctx context.Context, prefix string) (*models.ContentIdea, error) {
+	return repository.ResolveIdeaPrefix(ctx, prefix, func(ctx context.Context, prefix string) ([]models.ContentIdea, error) {
+		var ideas []models.ContentIdea
+		r.db.read(func(s *snapshot) {
+			for _, idea := range s.Ideas {
+				if strings.HasPrefix(idea.ID, prefix) {
+					ideas = append(ideas, idea)
+				}
+			}
+		})
+		return ideas, nil
+	})
+}
+
+func (r *contentStore) CreateScript(ctx context.Context, input *models.ContentScriptInput) (*models.ContentScript, error) {
+	var script models.ContentScript
+	err := r.db.write(func(s *snapshot) error {
+		if s.ideaIndex(input.IdeaID) < 0 {
+			return constraintError("content_scripts.idea_id %q does not reference an idea", input.IdeaID)
+		}
+		// hashtags is NOT NULL without a default, and the input omits it when empty
+		if input.Hashtags == nil {
+			return constraintError("content_scripts.hashtags is required")
+		}
+
+		script = models.ContentScript{
+			ID:                repository.NewID(),
+			IdeaID:            input.IdeaID,
+			Hook:              input.Hook,
+			FullScript:        input.FullScript,
+			CTA:               input.CTA,
+			Hashtags:          input.Hashtags,
+			EstimatedDuration: input.EstimatedDuration,
+			CreatedAt:         r.db.timestamp(),
+		}
+		s.Scripts = append(s.Scripts, script)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create script: %w", err)
+	}
+
+	return &script, nil
+}
+
+func (r *contentStore) GetScriptByID(ctx context.Context, id string) (*models.ContentScript, error) {
+	var script *models.ContentScript
+	r.db.read(func(s *snapshot) {
+		if i := s.scriptIndex(id); i >= 0 {
+			sc := s.Scripts[i]
+			script = &sc
+		}
+	})
+
+	if script == nil {
+		return nil, fmt.Errorf("script not found: %s", id)
+	}
+	return script, nil
+}
+
+func (r *contentStore) GetScripts(ctx context.Context, limit int) ([]models.ContentScript, error) {
+	return r.GetScriptsPage(ctx, 0, limit)
+}
+
+func (r *contentStore) GetScriptsPage(ctx context.Context, offset, limit int) ([]models.ContentScript, error) {
+	var scripts []models.ContentScript
+	r.db.read(func(s *snapshot) {
+		// Walk backwards so rows with equal timestamps stay newest first
+		for i := len(s.Scripts) - 1; i >= 0; i-- {
+			scripts = append(scripts, s.Scripts[i])
+		}
+	})
+
+	sort.SliceStable(scripts, func(i, j int) bool {
+		return scripts[i].CreatedAt.After(scripts[j].CreatedAt)
+	})
+	return page(scripts, offset, limit), nil
+}
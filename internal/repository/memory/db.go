@@ -0,0 +1,276 @@
+// Package memory implements the repository interfaces without a database.
+//
+// Data lives in process memory and, when opened with a file path, is written
+// to a JSON file after every change. The stores apply the same constraints as
+// the Postgres schema (CHECKs, foreign keys, unique keys and cascades) so code
+// exercised against this backend behaves the same against Supabase.
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+// ErrConstraint is wrapped by every error caused by a schema constraint
+// (CHECK, foreign key or unique key) that the database would also reject.
+var ErrConstraint = errors.New("constraint violation")
+
+func constraintError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrConstraint, fmt.Sprintf(format, args...))
+}
+
+// snapshot is the full dataset, laid out like the Postgres tables.
+type snapshot struct {
+	Books     []models.Book            `json:"books"`
+	Ideas     []models.ContentIdea     `json:"content_ideas"`
+	Scripts   []models.ContentScript   `json:"content_scripts"`
+	Calendar  []models.ContentCalendar `json:"content_calendar"`
+	Campaigns []models.Campaign        `json:"campaigns"`
+	Metrics   []models.PostMetric      `json:"post_metrics"`
+	Sales     []models.BookSale        `json:"sales_data"`
+	Usage     []models.AIUsage         `json:"ai_usage"`
+}
+
+// DB holds the data behind every memory store.
+type DB struct {
+	mu   sync.RWMutex
+	path string
+	data snapshot
+	now  func() time.Time
+}
+
+// New creates an empty, non-persistent database.
+func New() *DB {
+	return &DB{now: time.Now}
+}
+
+// Open loads the JSON file at path (if it exists) and persists every
+// subsequent change back to it.
+func Open(path string) (*DB, error) {
+	db := &DB{path: path, now: time.Now}
+
+	raw, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read data file: %w", err)
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &db.data); err != nil {
+			return nil, fmt.Errorf("failed to parse data file %s: %w", path, err)
+		}
+	}
+
+	return db, nil
+}
+
+// Stores returns repositories backed by this database.
+func (db *DB) Stores() *repository.Stores {
+	return &repository.Stores{
+		Books:     &bookStore{db: db},
+		Content:   &contentStore{db: db},
+		Calendar:  &calendarStore{db: db},
+		Campaigns: &campaignStore{db: db},
+		Metrics:   &metricsStore{db: db},
+		Sales:     &salesStore{db: db},
+		Usage:     &usageStore{db: db},
+	}
+}
+
+// read runs fn while holding the read lock.
+func (db *DB) read(fn func(*snapshot)) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	fn(&db.data)
+}
+
+// write runs fn while holding the write lock and persists the result.
+func (db *DB) write(fn func(*snapshot) error) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if err := fn(&db.data); err != nil {
+		return err
+	}
+	return db.persist()
+}
+
+// persist writes the snapshot atomically. It is a no-op for in-memory databases.
+func (db *DB) persist() error {
+	if db.path == "" {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(db.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode data file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(db.path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	tmp := db.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write data file: %w", err)
+	}
+	if err := os.Rename(tmp, db.path); err != nil {
+		return fmt.Errorf("failed to write data file: %w", err)
+	}
+
+	return nil
+}
+
+// timestamp returns the current time the way Postgres stores TIMESTAMPTZ.
+func (db *DB) timestamp() time.Time {
+	return db.now().UTC().Truncate(time.Microsecond)
+}
+
+// page returns up to limit rows starting at offset, like OFFSET/LIMIT.
+// A zero or negative limit returns every row after offset.
+func page[T any](rows []T, offset, limit int) []T {
+	if offset > 0 {
+		if offset >= len(rows) {
+			return nil
+		}
+		rows = rows[offset:]
+	}
+	if limit > 0 && len(rows) > limit {
+		return rows[:limit]
+	}
+	return rows
+}
+
+func (s *snapshot) bookIndex(id string) int {
+	for i := range s.Books {
+		if s.Books[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *snapshot) ideaIndex(id string) int {
+	for i := range s.Ideas {
+		if s.Ideas[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *snapshot) scriptIndex(id string) int {
+	for i := range s.Scripts {
+		if s.Scripts[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *snapshot) campaignIndex(id string) int {
+	for i := range s.Campaigns {
+		if s.Campaigns[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *snapshot) entryIndex(id string) int {
+	for i := range s.Calendar {
+		if s.Calendar[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// The delete helpers follow the ON DELETE CASCADE chain of the schema:
+// books → content_ideas, sales_data, campaigns; content_ideas → content_scripts →
+// content_calendar → post_metrics. ai_usage rows, and the ideas and entries of
+// a campaign, are kept with the deleted link set to null (ON DELETE SET NULL).
+
+func (s *snapshot) deleteBook(id string) {
+	for i := range s.Usage {
+		if s.Usage[i].BookID != nil && *s.Usage[i].BookID == id {
+			s.Usage[i].BookID = nil
+		}
+	}
+	s.Sales = removeWhere(s.Sales, func(sale models.BookSale) bool { return sale.BookID == id })
+	for _, idea := range s.Ideas {
+		if idea.BookID != nil && *idea.BookID == id {
+			s.deleteIdea(idea.ID)
+		}
+	}
+	for _, campaign := range s.Campaigns {
+		if campaign.BookID == id {
+			s.deleteCampaign(campaign.ID)
+		}
+	}
+	s.Books = removeWhere(s.Books, func(b models.Book) bool { return b.ID == id })
+}
+
+func (s *snapshot) deleteIdea(id string) {
+	for i := range s.Usage {
+		if s.Usage[i].IdeaID != nil && *s.Usage[i].IdeaID == id {
+			s.Usage[i].IdeaID = nil
+		}
+	}
+	for _, script := range s.Scripts {
+		if script.IdeaID == id {
+			s.deleteScript(script.ID)
+		}
+	}
+	s.Ideas = removeWhere(s.Ideas, func(i models.ContentIdea) bool { return i.ID == id })
+}
+
+func (s *snapshot) deleteScript(id string) {
+	for i := range s.Usage {
+		if s.Usage[i].ScriptID != nil && *s.Usage[i].ScriptID == id {
+			s.Usage[i].ScriptID = nil
+		}
+	}
+	for _, entry := range s.Calendar {
+		if entry.ScriptID != nil && *entry.ScriptID == id {
+			s.deleteEntry(entry.ID)
+		}
+	}
+	s.Scripts = removeWhere(s.Scripts, func(sc models.ContentScript) bool { return sc.ID == id })
+}
+
+func (s *snapshot) deleteCampaign(id string) {
+	for i := range s.Ideas {
+		if s.Ideas[i].CampaignID != nil && *s.Ideas[i].CampaignID == id {
+			s.Ideas[i].CampaignID = nil
+		}
+	}
+	for i := range s.Calendar {
+		if s.Calendar[i].CampaignID != nil && *s.Calendar[i].CampaignID == id {
+			s.Calendar[i].CampaignID = nil
+		}
+	}
+	s.Campaigns = removeWhere(s.Campaigns, func(c models.Campaign) bool { return c.ID == id })
+}
+
+func (s *snapshot) deleteEntry(id string) {
+	s.Metrics = removeWhere(s.Metrics, func(m models.PostMetric) bool { return m.CalendarID == id })
+	s.Calendar = removeWhere(s.Calendar, func(e models.ContentCalendar) bool { return e.ID == id })
+}
+
+// removeWhere returns rows without the elements matching drop.
+func removeWhere[T any](rows []T, drop func(T) bool) []T {
+	kept := rows[:0:0]
+	for _, row := range rows {
+		if !drop(row) {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
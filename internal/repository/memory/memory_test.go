@@ -0,0 +1,383 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	_ "github.com/gagipress/gagipress-cli/internal/social" // registers the platforms
+)
+
+func seedScript(t *testing.T, db *DB) (*models.Book, *models.ContentIdea, *models.ContentScript) {
+	t.Helper()
+	ctx := context.Background()
+	stores := db.Stores()
+
+	book, err := stores.Books.Create(ctx, &models.BookInput{Title: "Book", Genre: "kids", KDPASIN: "B0TEST"})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	idea, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{Type: "educational", BriefDescription: "Idea", BookID: &book.ID})
+	if err != nil {
+		t.Fatalf("create idea: %v", err)
+	}
+	script, err := stores.Content.CreateScript(ctx, &models.ContentScriptInput{
+		IdeaID: idea.ID, Hook: "h", FullScript: "s", CTA: "c", Hashtags: []string{"#a"},
+	})
+	if err != nil {
+		t.Fatalf("create script: %v", err)
+	}
+	return book, idea, script
+}
+
+func TestConstraints(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	stores := db.Stores()
+	book, idea, _ := seedScript(t, db)
+
+	missing := "00000000-0000-0000-0000-000000000000"
+	score := 101
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"duplicate ASIN", func() error {
+			_, err := stores.Books.Create(ctx, &models.BookInput{Title: "Other", Genre: "kids", KDPASIN: "B0TEST"})
+			return err
+		}()},
+		{"invalid idea type", func() error {
+			_, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{Type: "meme", BriefDescription: "x"})
+			return err
+		}()},
+		{"relevance out of range", func() error {
+			_, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{Type: "ugc", BriefDescription: "x", RelevanceScore: &score})
+			return err
+		}()},
+		{"idea for missing book", func() error {
+			_, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{Type: "ugc", BriefDescription: "x", BookID: &missing})
+			return err
+		}()},
+		{"script without hashtags", func() error {
+			_, err := stores.Content.CreateScript(ctx, &models.ContentScriptInput{IdeaID: idea.ID, Hook: "h", FullScript: "s", CTA: "c"})
+			return err
+		}()},
+		{"entry for missing script", func() error {
+			_, err := stores.Calendar.CreateEntry(ctx, &models.ContentCalendarInput{ScriptID: &missing, ScheduledFor: time.Now(), Platform: "tiktok", PostType: "reel"})
+			return err
+		}()},
+		{"invalid platform", func() error {
+			_, err := stores.Calendar.CreateEntry(ctx, &models.ContentCalendarInput{ScheduledFor: time.Now(), Platform: "myspace", PostType: "reel"})
+			return err
+		}()},
+		{"invalid idea status", stores.Content.UpdateIdeaStatus(ctx, idea.ID, "published")},
+		{"duplicate sale day", func() error {
+			day := models.Date{Time: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)}
+			if _, err := stores.Sales.CreateSale(ctx, &models.BookSaleInput{BookID: book.ID, SaleDate: day, UnitsSold: 1}); err != nil {
+				t.Fatalf("first sale: %v", err)
+			}
+			_, err := stores.Sales.CreateSale(ctx, &models.BookSaleInput{BookID: book.ID, SaleDate: day, UnitsSold: 2})
+			return err
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, ErrConstraint) {
+				t.Errorf("expected ErrConstraint, got %v", tt.err)
+			}
+		})
+	}
+}
+
+func TestDeleteBookCascades(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	stores := db.Stores()
+	book, _, script := seedScript(t, db)
+
+	entry, err := stores.Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+		ScriptID: &script.ID, ScheduledFor: time.Now(), Platform: "tiktok", PostType: "reel",
+	})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+	if _, err := stores.Metrics.CreateMetric(ctx, &models.PostMetricInput{CalendarID: entry.ID, Platform: "tiktok", Views: 10}); err != nil {
+		t.Fatalf("create metric: %v", err)
+	}
+
+	if err := stores.Books.Delete(ctx, book.ID); err != nil {
+		t.Fatalf("delete book: %v", err)
+	}
+
+	db.read(func(s *snapshot) {
+		if len(s.Ideas)+len(s.Scripts)+len(s.Calendar)+len(s.Metrics) != 0 {
+			t.Errorf("expected cascade to remove everything, got %d ideas, %d scripts, %d entries, %d metrics",
+				len(s.Ideas), len(s.Scripts), len(s.Calendar), len(s.Metrics))
+		}
+	})
+}
+
+func TestGetIdeaByIDPrefix_Ambiguous(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	db.data.Ideas = []models.ContentIdea{
+		{ID: "abcdef11-0000-0000-0000-000000000000", Type: "ugc", Status: "pending"},
+		{ID: "abcdef22-0000-0000-0000-000000000000", Type: "ugc", Status: "pending"},
+	}
+
+	_, err := db.Stores().Content.GetIdeaByIDPrefix(ctx, "abcdef")
+	if err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected ambiguous error, got %v", err)
+	}
+
+	idea, err := db.Stores().Content.GetIdeaByIDPrefix(ctx, "abcdef22")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if idea.ID != db.data.Ideas[1].ID {
+		t.Errorf("got idea %s", idea.ID)
+	}
+}
+
+func TestGetEntriesNeedingMedia_EmbedsScriptIdeaBook(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	book, _, script := seedScript(t, db)
+
+	entry, err := db.Stores().Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+		ScriptID: &script.ID, ScheduledFor: time.Now(), Platform: "instagram", PostType: "reel",
+	})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+	db.data.Calendar[0].Status = "approved"
+	db.data.Calendar[0].GenerateMedia = true
+
+	entries, err := db.Stores().Calendar.GetEntriesNeedingMedia(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != entry.ID {
+		t.Fatalf("expected entry %s, got %+v", entry.ID, entries)
+	}
+	if entries[0].Script == nil || entries[0].Script.Idea == nil || entries[0].Script.Idea.Book == nil {
+		t.Fatal("expected script, idea and book to be embedded")
+	}
+	if entries[0].Script.Idea.Book.ID != book.ID {
+		t.Errorf("embedded book = %s, want %s", entries[0].Script.Idea.Book.ID, book.ID)
+	}
+
+	if err := db.Stores().Calendar.UpdateMediaURL(ctx, entry.ID, "https://example.com/a.png"); err != nil {
+		t.Fatalf("update media: %v", err)
+	}
+	entries, _ = db.Stores().Calendar.GetEntriesNeedingMedia(ctx)
+	if len(entries) != 0 {
+		t.Errorf("expected no entries after media_url is set, got %d", len(entries))
+	}
+}
+
+func TestOpen_PersistsToJSONFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "data.json")
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	book, _, _ := seedScript(t, db)
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, err := reopened.Stores().Books.GetByID(ctx, book.ID)
+	if err != nil {
+		t.Fatalf("book missing after reopen: %v", err)
+	}
+	if got.Title != book.Title {
+		t.Errorf("title = %q, want %q", got.Title, book.Title)
+	}
+
+	scripts, _ := reopened.Stores().Content.GetScripts(ctx, 0)
+	if len(scripts) != 1 {
+		t.Errorf("expected 1 script after reopen, got %d", len(scripts))
+	}
+}
+
+func TestGetIdeasPage(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	for i := 0; i < 5; i++ {
+		if _, err := db.Stores().Content.CreateIdea(ctx, &models.ContentIdeaInput{Type: "ugc", BriefDescription: strings.Repeat("x", i+1)}); err != nil {
+			t.Fatalf("create idea: %v", err)
+		}
+	}
+
+	var seen []string
+	for offset := 0; ; offset += 2 {
+		page, err := db.Stores().Content.GetIdeasPage(ctx, "", "", offset, 2)
+		if err != nil {
+			t.Fatalf("get page: %v", err)
+		}
+		for _, idea := range page {
+			seen = append(seen, idea.BriefDescription)
+		}
+		if len(page) < 2 {
+			break
+		}
+	}
+
+	// Newest first, every idea exactly once
+	want := "xxxxx,xxxx,xxx,xx,x"
+	if got := strings.Join(seen, ","); got != want {
+		t.Errorf("pages = %s, want %s", got, want)
+	}
+}
+
+func TestUpsertSales(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	book, _, _ := seedScript(t, db)
+	sales := db.Stores().Sales
+
+	day := func(d int) models.Date { return models.Date{Time: time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC)} }
+	if _, err := sales.CreateSale(ctx, &models.BookSaleInput{BookID: book.ID, SaleDate: day(1), UnitsSold: 1, Royalty: 2.5}); err != nil {
+		t.Fatalf("create sale: %v", err)
+	}
+
+	results, err := sales.UpsertSales(ctx, []*models.BookSaleInput{
+		{BookID: book.ID, SaleDate: day(1), UnitsSold: 1, Royalty: 2.5},
+		{BookID: book.ID, SaleDate: day(2), UnitsSold: 2},
+		{BookID: "no-such-book", SaleDate: day(2), UnitsSold: 1},
+	})
+	if err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if got := repository.Summarize(results); got != (repository.BulkSummary{Created: 1, Duplicate: 1, Failed: 1}) {
+		t.Errorf("summary = %+v", got)
+	}
+	if !errors.Is(results[2].Err, ErrConstraint) {
+		t.Errorf("row 2 error = %v, want a constraint error", results[2].Err)
+	}
+
+	results, err = sales.UpsertSales(ctx, []*models.BookSaleInput{
+		{BookID: book.ID, SaleDate: day(2), UnitsSold: 5},
+	})
+	if err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if results[0].Status != repository.RowUpdated || results[0].Row.UnitsSold != 5 {
+		t.Errorf("result = %+v, want updated to 5 units", results[0])
+	}
+	stored, _ := sales.GetSalesByBook(ctx, book.ID, time.Time{}, time.Time{})
+	if len(stored) != 2 || stored[1].UnitsSold != 5 {
+		t.Errorf("stored sales = %+v", stored)
+	}
+}
+
+func TestUsageLedger_KeepsRowsWhenContentIsDeleted(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	stores := db.Stores()
+	book, idea, script := seedScript(t, db)
+
+	kind := "educational"
+	row, err := stores.Usage.RecordUsage(ctx, &models.AIUsageInput{
+		Provider: "openai", Model: "gpt-4o-mini", PromptTokens: 1200, CompletionTokens: 300,
+		CostUSD: 0.00036, Command: "generate script", BookID: &book.ID, IdeaID: &idea.ID, ContentType: &kind,
+	})
+	if err != nil {
+		t.Fatalf("record usage: %v", err)
+	}
+	if err := stores.Usage.LinkUsageToScript(ctx, []string{row.ID}, script.ID); err != nil {
+		t.Fatalf("link usage: %v", err)
+	}
+
+	rows, err := stores.Usage.GetUsage(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("get usage: %v", err)
+	}
+	if len(rows) != 1 || rows[0].ScriptID == nil || *rows[0].ScriptID != script.ID || rows[0].CostUSD != 0.00036 {
+		t.Fatalf("rows = %+v", rows)
+	}
+
+	if err := stores.Books.Delete(ctx, book.ID); err != nil {
+		t.Fatalf("delete book: %v", err)
+	}
+	rows, err = stores.Usage.GetUsage(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("get usage: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows after deleting the book, want the row kept", len(rows))
+	}
+	if rows[0].BookID != nil || rows[0].IdeaID != nil || rows[0].ScriptID != nil {
+		t.Errorf("links = %v %v %v, want all null", rows[0].BookID, rows[0].IdeaID, rows[0].ScriptID)
+	}
+	if rows[0].ContentType == nil || *rows[0].ContentType != kind || rows[0].TotalTokens() != 1500 {
+		t.Errorf("row = %+v", rows[0])
+	}
+}
+
+func TestUpdatePublishState(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	_, _, script := seedScript(t, db)
+	calendar := db.Stores().Calendar
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		entry, err := calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+			ScriptID: &script.ID, ScheduledFor: time.Now(), Platform: "tiktok", PostType: "reel",
+		})
+		if err != nil {
+			t.Fatalf("create entry: %v", err)
+		}
+		ids = append(ids, entry.ID)
+	}
+
+	at := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	if err := calendar.UpdatePublishState(ctx, ids[0], &models.PublishUpdate{
+		Status: "failed",
+		Error:  &models.PublishError{At: at, Stage: "submit", Message: "rejected"},
+	}); err != nil {
+		t.Fatalf("record error: %v", err)
+	}
+	sub := "sub-1"
+	if err := calendar.UpdatePublishState(ctx, ids[0], &models.PublishUpdate{Status: "published", SubmissionID: &sub, Publisher: "blotato", PublishedAt: &at}); err != nil {
+		t.Fatalf("record submission: %v", err)
+	}
+
+	got, _ := calendar.GetEntryByID(ctx, ids[0])
+	if got.Status != "published" || *got.SubmissionID != sub || *got.Publisher != "blotato" || len(got.PublishErrors) != 1 {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+
+	err := calendar.UpdatePublishState(ctx, ids[1], &models.PublishUpdate{SubmissionID: &sub})
+	if !errors.Is(err, ErrConstraint) {
+		t.Errorf("duplicate submission ID: got %v, want ErrConstraint", err)
+	}
+	err = calendar.UpdatePublishState(ctx, ids[1], &models.PublishUpdate{Status: "bogus"})
+	if !errors.Is(err, ErrConstraint) {
+		t.Errorf("invalid status: got %v, want ErrConstraint", err)
+	}
+
+	// Clearing the submission frees its ID
+	none := ""
+	if err := calendar.UpdatePublishState(ctx, ids[0], &models.PublishUpdate{SubmissionID: &none}); err != nil {
+		t.Fatalf("clear submission: %v", err)
+	}
+	if got, _ := calendar.GetEntryByID(ctx, ids[0]); got.SubmissionID != nil || got.Publisher != nil {
+		t.Errorf("SubmissionID, Publisher = %v, %v; want nil", got.SubmissionID, got.Publisher)
+	}
+	if err := calendar.UpdatePublishState(ctx, ids[1], &models.PublishUpdate{SubmissionID: &sub}); err != nil {
+		t.Errorf("reuse cleared submission ID: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+type metricsStore struct {
+	db *DB
+}
+
+func (r *metricsStore) CreateMetric(ctx context.Context, input *models.PostMetricInput) (*models.PostMetric, error) {
+	var metric models.PostMetric
+	err := r.db.write(func(s *snapshot) error {
+		if s.entryIndex(input.CalendarID) < 0 {
+			return constraintError("post_metrics.calendar_id %q does not reference a calendar entry", input.CalendarID)
+		}
+		if input.Platform == "" {
+			return constraintError("post_metrics.platform is required")
+		}
+
+		metric = models.PostMetric{
+			ID:         repository.NewID(),
+			CalendarID: input.CalendarID,
+			Platform:   input.Platform,
+			Views:      input.Views,
+			Likes:      input.Likes,
+			Comments:   input.Comments,
+			Shares:     input.Shares,
+			Saves:      input.Saves,
+			// engagement_rate is DECIMAL(5,2)
+			EngagementRate: math.Round(input.CalculateEngagementRate()*100) / 100,
+			CollectedAt:    r.db.timestamp(),
+		}
+		s.Metrics = append(s.Metrics, metric)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric: %w", err)
+	}
+
+	return &metric, nil
+}
+
+func (r *metricsStore) GetMetrics(ctx context.Context, platform string, from, to time.Time) ([]models.PostMetric, error) {
+	var metrics []models.PostMetric
+	r.db.read(func(s *snapshot) {
+		// Walk backwards so rows with equal timestamps stay newest first
+		for i := len(s.Metrics) - 1; i >= 0; i-- {
+			m := s.Metrics[i]
+			if platform != "" && m.Platform != platform {
+				continue
+			}
+			if !from.IsZero() && m.CollectedAt.Before(from) {
+				continue
+			}
+			if !to.IsZero() && m.CollectedAt.After(to) {
+				continue
+			}
+			metrics = append(metrics, m)
+		}
+	})
+
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].CollectedAt.After(metrics[j].CollectedAt)
+	})
+	return metrics, nil
+}
+
+func (r *metricsStore) GetEntryMetricsPage(ctx context.Context, calendarIDs []string, offset, limit int) ([]models.PostMetric, error) {
+	var metrics []models.PostMetric
+	r.db.read(func(s *snapshot) {
+		// Walk backwards so rows with equal timestamps stay newest first
+		for i := len(s.Metrics) - 1; i >= 0; i-- {
+			if slices.Contains(calendarIDs, s.Metrics[i].CalendarID) {
+				metrics = append(metrics, s.Metrics[i])
+			}
+		}
+	})
+
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].CollectedAt.After(metrics[j].CollectedAt)
+	})
+	return page(metrics, offset, limit), nil
+}
+
+func (r *metricsStore) GetAggregateMetrics(ctx context.Context, platform string, from, to time.Time) (*models.AggregateMetrics, error) {
+	metrics, err := r.GetMetrics(ctx, platform, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.AggregateMetrics(metrics), nil
+}
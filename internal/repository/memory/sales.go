@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+type salesStore struct {
+	db *DB
+}
+
+func (r *salesStore) CreateSale(ctx context.Context, input *models.BookSaleInput) (*models.BookSale, error) {
+	var sale models.BookSale
+	err := r.db.write(func(s *snapshot) error {
+		if err := s.checkSale(input); err != nil {
+			return err
+		}
+		if s.saleIndex(input.BookID, input.SaleDate) >= 0 {
+			return constraintError("sales_data already has a row for book %s on %s", input.BookID, input.SaleDate.Format(models.DateFormat))
+		}
+
+		sale = r.newSale(input)
+		s.Sales = append(s.Sales, sale)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sale: %w", err)
+	}
+
+	return &sale, nil
+}
+
+// UpsertSales classifies every row like the Supabase repository: new keys
+// are created, changed rows updated in place and identical rows reported as
+// duplicates.
+func (r *salesStore) UpsertSales(ctx context.Context, inputs []*models.BookSaleInput) ([]repository.RowResult[models.BookSale], error) {
+	results := repository.NewResults[models.BookSale](len(inputs))
+	idx := repository.UniqueSales(inputs, results)
+
+	err := r.db.write(func(s *snapshot) error {
+		stored := make(map[string]int, len(s.Sales))
+		for j, sale := range s.Sales {
+			stored[repository.SaleKey(sale.BookID, sale.SaleDate)] = j
+		}
+
+		for _, i := range idx {
+			input := inputs[i]
+			if err := s.checkSale(input); err != nil {
+				results[i].Status = repository.RowFailed
+				results[i].Err = err
+				continue
+			}
+
+			j, ok := stored[repository.SaleKey(input.BookID, input.SaleDate)]
+			switch {
+			case !ok:
+				s.Sales = append(s.Sales, r.newSale(input))
+				j = len(s.Sales) - 1
+				results[i].Status = repository.RowCreated
+			case repository.SameSale(s.Sales[j], input):
+				results[i].Status = repository.RowDuplicate
+			default:
+				s.Sales[j].UnitsSold = input.UnitsSold
+				s.Sales[j].Royalty = input.Royalty
+				s.Sales[j].PageReads = input.PageReads
+				results[i].Status = repository.RowUpdated
+			}
+			sale := s.Sales[j]
+			results[i].Row = &sale
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert sales: %w", err)
+	}
+
+	return results, nil
+}
+
+// checkSale applies the sales_data foreign key and NOT NULL date.
+func (s *snapshot) checkSale(input *models.BookSaleInput) error {
+	if s.bookIndex(input.BookID) < 0 {
+		return constraintError("sales_data.book_id %q does not reference a book", input.BookID)
+	}
+	if input.SaleDate.IsZero() {
+		return constraintError("sales_data.date is required")
+	}
+	return nil
+}
+
+// saleIndex finds the row holding UNIQUE(book_id, date), or -1.
+func (s *snapshot) saleIndex(bookID string, date models.Date) int {
+	day := date.Format(models.DateFormat)
+	for i := range s.Sales {
+		if s.Sales[i].BookID == bookID && s.Sales[i].SaleDate.Format(models.DateFormat) == day {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *salesStore) newSale(input *models.BookSaleInput) models.BookSale {
+	return models.BookSale{
+		ID:        repository.NewID(),
+		BookID:    input.BookID,
+		SaleDate:  input.SaleDate,
+		UnitsSold: input.UnitsSold,
+		Royalty:   input.Royalty,
+		PageReads: input.PageReads,
+		CreatedAt: r.db.timestamp(),
+	}
+}
+
+func (r *salesStore) GetSalesByBook(ctx context.Context, bookID string, from, to time.Time) ([]models.BookSale, error) {
+	sales := r.filter(bookID, from, to)
+	sort.SliceStable(sales, func(i, j int) bool {
+		return sales[i].SaleDate.Before(sales[j].SaleDate.Time)
+	})
+	return sales, nil
+}
+
+func (r *salesStore) GetAllSales(ctx context.Context, from, to time.Time) ([]models.BookSale, error) {
+	sales := r.filter("", from, to)
+	sort.SliceStable(sales, func(i, j int) bool {
+		return sales[i].SaleDate.After(sales[j].SaleDate.Time)
+	})
+	return sales, nil
+}
+
+// filter returns sales for bookID (any book if empty) within the inclusive date range.
+func (r *salesStore) filter(bookID string, from, to time.Time) []models.BookSale {
+	fromDay := from.Format(models.DateFormat)
+	toDay := to.Format(models.DateFormat)
+
+	var sales []models.BookSale
+	r.db.read(func(s *snapshot) {
+		for _, sale := range s.Sales {
+			day := sale.SaleDate.Format(models.DateFormat)
+			if bookID != "" && sale.BookID != bookID {
+				continue
+			}
+			if !from.IsZero() && day < fromDay {
+				continue
+			}
+			if !to.IsZero() && day > toDay {
+				continue
+			}
+			sales = append(sales, sale)
+		}
+	})
+	return sales
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+type usageStore struct {
+	db *DB
+}
+
+func (r *usageStore) RecordUsage(ctx context.Context, input *models.AIUsageInput) (*models.AIUsage, error) {
+	var row models.AIUsage
+	err := r.db.write(func(s *snapshot) error {
+		if input.Provider == "" || input.Model == "" || input.Command == "" {
+			return constraintError("ai_usage.provider, model and command are required")
+		}
+		if input.PromptTokens < 0 || input.CompletionTokens < 0 {
+			return constraintError("ai_usage token counts must be >= 0")
+		}
+		if input.BookID != nil && s.bookIndex(*input.BookID) < 0 {
+			return constraintError("ai_usage.book_id %q does not reference a book", *input.BookID)
+		}
+		if input.IdeaID != nil && s.ideaIndex(*input.IdeaID) < 0 {
+			return constraintError("ai_usage.idea_id %q does not reference an idea", *input.IdeaID)
+		}
+		if input.ScriptID != nil && s.scriptIndex(*input.ScriptID) < 0 {
+			return constraintError("ai_usage.script_id %q does not reference a script", *input.ScriptID)
+		}
+
+		row = models.AIUsage{
+			ID:               repository.NewID(),
+			Provider:         input.Provider,
+			Model:            input.Model,
+			PromptTokens:     input.PromptTokens,
+			CompletionTokens: input.CompletionTokens,
+			// cost_usd is DECIMAL(12,6)
+			CostUSD:     math.Round(input.CostUSD*1e6) / 1e6,
+			Command:     input.Command,
+			BookID:      input.BookID,
+			IdeaID:      input.IdeaID,
+			ScriptID:    input.ScriptID,
+			ContentType: input.ContentType,
+			CreatedAt:   r.db.timestamp(),
+		}
+		s.Usage = append(s.Usage, row)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record AI usage: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (r *usageStore) LinkUsageToScript(ctx context.Context, ids []string, scriptID string) error {
+	err := r.db.write(func(s *snapshot) error {
+		if s.scriptIndex(scriptID) < 0 {
+			return constraintError("ai_usage.script_id %q does not reference a script", scriptID)
+		}
+		for _, id := range ids {
+			for i := range s.Usage {
+				if s.Usage[i].ID == id {
+					s.Usage[i].ScriptID = &scriptID
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to link AI usage to script: %w", err)
+	}
+	return nil
+}
+
+func (r *usageStore) GetUsage(ctx context.Context, from, to time.Time) ([]models.AIUsage, error) {
+	var rows []models.AIUsage
+	r.db.read(func(s *snapshot) {
+		// Walk backwards so rows with equal timestamps stay newest first
+		for i := len(s.Usage) - 1; i >= 0; i-- {
+			u := s.Usage[i]
+			if !from.IsZero() && u.CreatedAt.Before(from) {
+				continue
+			}
+			if !to.IsZero() && u.CreatedAt.After(to) {
+				continue
+			}
+			rows = append(rows, u)
+		}
+	})
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].CreatedAt.After(rows[j].CreatedAt)
+	})
+	return rows, nil
+}
@@ -1,35 +1,29 @@
 package repository
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"strings"
 	"time"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/postgrest"
 )
 
 // MetricsRepository handles metrics database operations
 type MetricsRepository struct {
-	config *config.SupabaseConfig
-	client *http.Client
+	db *postgrest.Client
 }
 
 // NewMetricsRepository creates a new metrics repository
 func NewMetricsRepository(cfg *config.SupabaseConfig) *MetricsRepository {
 	return &MetricsRepository{
-		config: cfg,
-		client: &http.Client{},
+		db: postgrest.NewClient(cfg),
 	}
 }
 
 // CreateMetric creates a new post metric
-func (r *MetricsRepository) CreateMetric(input *models.PostMetricInput) (*models.PostMetric, error) {
-	url := fmt.Sprintf("%s/rest/v1/post_metrics", r.config.URL)
-
+func (r *MetricsRepository) CreateMetric(ctx context.Context, input *models.PostMetricInput) (*models.PostMetric, error) {
 	// Calculate engagement rate
 	engagementRate := input.CalculateEngagementRate()
 
@@ -44,44 +38,9 @@ func (r *MetricsRepository) CreateMetric(input *models.PostMetricInput) (*models
 		"engagement_rate": engagementRate,
 	}
 
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal metric: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create metric: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create metric: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
 	var metrics []models.PostMetric
-	if err := json.Unmarshal(body, &metrics); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("post_metrics").Insert(ctx, data, &metrics); err != nil {
+		return nil, fmt.Errorf("failed to create metric: %w", err)
 	}
 
 	if len(metrics) == 0 {
@@ -91,65 +50,78 @@ func (r *MetricsRepository) CreateMetric(input *models.PostMetricInput) (*models
 	return &metrics[0], nil
 }
 
-// GetMetrics retrieves metrics with optional filters
-func (r *MetricsRepository) GetMetrics(platform string, from, to time.Time) ([]models.PostMetric, error) {
-	url := fmt.Sprintf("%s/rest/v1/post_metrics?select=*&order=collected_at.desc", r.config.URL)
-
-	if platform != "" {
-		url += fmt.Sprintf("&platform=eq.%s", platform)
-	}
-	if !from.IsZero() {
-		url += fmt.Sprintf("&collected_at=gte.%s", from.UTC().Format(time.RFC3339))
-	}
-	if !to.IsZero() {
-		url += fmt.Sprintf("&collected_at=lte.%s", to.UTC().Format(time.RFC3339))
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+// GetMetrics retrieves every metric snapshot collected between from and to
+// (zero times leave that side open), newest first. An empty platform
+// matches every platform.
+func (r *MetricsRepository) GetMetrics(ctx context.Context, platform string, from, to time.Time) ([]models.PostMetric, error) {
+	metrics, err := collectPages(ctx, 0, func(ctx context.Context, offset, limit int) ([]models.PostMetric, error) {
+		q := r.db.From("post_metrics").
+			Select("*").
+			Order("collected_at", false).
+			Order("id", false). // tie-breaker so pages do not overlap
+			Limit(limit).
+			Offset(offset)
+
+		if platform != "" {
+			q.Eq("platform", platform)
+		}
+		if !from.IsZero() {
+			q.Gte("collected_at", from.UTC().Format(time.RFC3339))
+		}
+		if !to.IsZero() {
+			q.Lte("collected_at", to.UTC().Format(time.RFC3339))
+		}
 
-	resp, err := r.client.Do(req)
+		var page []models.PostMetric
+		err := q.Get(ctx, &page)
+		return page, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get metrics: %w", err)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+	return metrics, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get metrics: HTTP %d: %s", resp.StatusCode, string(body))
+// GetEntryMetricsPage retrieves one page of the metric snapshots of the
+// given calendar entries, newest first.
+func (r *MetricsRepository) GetEntryMetricsPage(ctx context.Context, calendarIDs []string, offset, limit int) ([]models.PostMetric, error) {
+	if len(calendarIDs) == 0 {
+		return nil, nil
 	}
 
 	var metrics []models.PostMetric
-	if err := json.Unmarshal(body, &metrics); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	err := r.db.From("post_metrics").
+		Select("*").
+		In("calendar_id", calendarIDs).
+		Order("collected_at", false).
+		Order("id", false). // tie-breaker so pages do not overlap
+		Limit(limit).
+		Offset(offset).
+		Get(ctx, &metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
 	}
 
 	return metrics, nil
 }
 
 // GetAggregateMetrics retrieves aggregated metrics for a period
-func (r *MetricsRepository) GetAggregateMetrics(platform string, from, to time.Time) (*models.AggregateMetrics, error) {
-	metrics, err := r.GetMetrics(platform, from, to)
+func (r *MetricsRepository) GetAggregateMetrics(ctx context.Context, platform string, from, to time.Time) (*models.AggregateMetrics, error) {
+	metrics, err := r.GetMetrics(ctx, platform, from, to)
 	if err != nil {
 		return nil, err
 	}
 
+	return AggregateMetrics(metrics), nil
+}
+
+// AggregateMetrics sums a set of metric snapshots and picks the top post.
+// Snapshots hold running totals, so only the latest of each post counts.
+func AggregateMetrics(metrics []models.PostMetric) *models.AggregateMetrics {
+	metrics = LatestMetrics(metrics)
 	if len(metrics) == 0 {
-		return &models.AggregateMetrics{}, nil
+		return &models.AggregateMetrics{}
 	}
 
 	agg := &models.AggregateMetrics{
@@ -177,5 +149,22 @@ func (r *MetricsRepository) GetAggregateMetrics(platform string, from, to time.T
 	agg.TopPost = topPostID
 	agg.TopEngagement = topEngagement
 
-	return agg, nil
+	return agg
+}
+
+// LatestMetrics keeps the most recent snapshot of each post, in the order
+// the posts first appear.
+func LatestMetrics(metrics []models.PostMetric) []models.PostMetric {
+	index := make(map[string]int)
+	var latest []models.PostMetric
+	for _, m := range metrics {
+		i, seen := index[m.CalendarID]
+		if !seen {
+			index[m.CalendarID] = len(latest)
+			latest = append(latest, m)
+		} else if m.CollectedAt.After(latest[i].CollectedAt) {
+			latest[i] = m
+		}
+	}
+	return latest
 }
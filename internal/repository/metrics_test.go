@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -32,7 +33,7 @@ func TestGetMetrics_TimestampHasNoPlus(t *testing.T) {
 	from := time.Date(2026, 1, 23, 10, 26, 43, 0, loc)
 	to := time.Date(2026, 1, 30, 10, 26, 43, 0, loc)
 
-	_, err := repo.GetMetrics("", from, to)
+	_, err := repo.GetMetrics(context.Background(), "", from, to)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -60,7 +61,7 @@ func TestGetMetrics_TimestampIsUTC(t *testing.T) {
 	from := time.Date(2026, 1, 23, 10, 26, 43, 0, loc) // 10:26:43+01:00 == 09:26:43Z
 	to := time.Date(2026, 1, 30, 10, 26, 43, 0, loc)   // same
 
-	_, err := repo.GetMetrics("", from, to)
+	_, err := repo.GetMetrics(context.Background(), "", from, to)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -70,3 +71,20 @@ func TestGetMetrics_TimestampIsUTC(t *testing.T) {
 		t.Errorf("expected UTC timestamp '09:26:43Z' in URL (10:26:43+01:00 converted to UTC), got: %q", capturedRawQuery)
 	}
 }
+
+func TestAggregateMetrics_LatestSnapshotPerPost(t *testing.T) {
+	day := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	metrics := []models.PostMetric{
+		{CalendarID: "a", Views: 300, Likes: 30, EngagementRate: 10, CollectedAt: day.AddDate(0, 0, 1)},
+		{CalendarID: "b", Views: 50, Likes: 10, EngagementRate: 20, CollectedAt: day},
+		{CalendarID: "a", Views: 100, Likes: 5, EngagementRate: 5, CollectedAt: day},
+	}
+
+	agg := AggregateMetrics(metrics)
+	if agg.TotalPosts != 2 || agg.TotalViews != 350 || agg.TotalLikes != 40 {
+		t.Errorf("AggregateMetrics() = %+v, want 2 posts with 350 views and 40 likes", agg)
+	}
+	if agg.TopPost != "b" || agg.AvgEngagement != 15 {
+		t.Errorf("AggregateMetrics() top = %q, avg = %v", agg.TopPost, agg.AvgEngagement)
+	}
+}
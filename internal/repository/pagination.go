@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"iter"
+	"slices"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+)
+
+// DefaultPageSize is how many rows are fetched per request when walking a
+// table. It stays below PostgREST's default max-rows (1000) so a page is
+// never silently cut short by the server.
+const DefaultPageSize = 500
+
+// PageFunc fetches up to limit rows starting at offset.
+type PageFunc[T any] func(ctx context.Context, offset, limit int) ([]T, error)
+
+// Paginate returns an iterator over every row of a paged result set. Pages
+// are fetched lazily, so only one page is held in memory at a time; the
+// iteration ends after a short page or at the first error, which is yielded
+// with a zero row.
+func Paginate[T any](ctx context.Context, pageSize int, fetch PageFunc[T]) iter.Seq2[T, error] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return func(yield func(T, error) bool) {
+		var zero T
+		for offset := 0; ; offset += pageSize {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			rows, err := fetch(ctx, offset, pageSize)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, row := range rows {
+				if !yield(row, nil) {
+					return
+				}
+			}
+			if len(rows) < pageSize {
+				return
+			}
+		}
+	}
+}
+
+// Collect drains seq into a slice, keeping at most limit rows (all rows when
+// limit is zero or negative).
+func Collect[T any](seq iter.Seq2[T, error], limit int) ([]T, error) {
+	var rows []T
+	for row, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+		if limit > 0 && len(rows) >= limit {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// collectPages fetches up to limit rows page by page, sizing pages so small
+// limits are served by a single request.
+func collectPages[T any](ctx context.Context, limit int, fetch PageFunc[T]) ([]T, error) {
+	pageSize := DefaultPageSize
+	if limit > 0 && limit < pageSize {
+		pageSize = limit
+	}
+	return Collect(Paginate(ctx, pageSize, fetch), limit)
+}
+
+// IterIdeas walks content ideas newest first. Empty bookID or status match
+// any value.
+func IterIdeas(ctx context.Context, store ContentStore, bookID, status string) iter.Seq2[models.ContentIdea, error] {
+	return Paginate(ctx, DefaultPageSize, func(ctx context.Context, offset, limit int) ([]models.ContentIdea, error) {
+		return store.GetIdeasPage(ctx, bookID, status, offset, limit)
+	})
+}
+
+// IterScripts walks content scripts newest first.
+func IterScripts(ctx context.Context, store ContentStore) iter.Seq2[models.ContentScript, error] {
+	return Paginate(ctx, DefaultPageSize, store.GetScriptsPage)
+}
+
+// entryIDBatch caps how many calendar IDs go into one IN filter, keeping
+// request URLs well below server limits.
+const entryIDBatch = 100
+
+// IterEntryMetrics walks the metric snapshots of the given calendar entries,
+// newest first within each batch of entryIDBatch entries.
+func IterEntryMetrics(ctx context.Context, store MetricsStore, calendarIDs []string) iter.Seq2[models.PostMetric, error] {
+	return func(yield func(models.PostMetric, error) bool) {
+		for batch := range slices.Chunk(calendarIDs, entryIDBatch) {
+			metrics := Paginate(ctx, DefaultPageSize, func(ctx context.Context, offset, limit int) ([]models.PostMetric, error) {
+				return store.GetEntryMetricsPage(ctx, batch, offset, limit)
+			})
+			for m, err := range metrics {
+				if !yield(m, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// IterEntries walks calendar entries in scheduled order. An empty status
+// matches every entry.
+func IterEntries(ctx context.Context, store CalendarStore, status string) iter.Seq2[models.ContentCalendar, error] {
+	return Paginate(ctx, DefaultPageSize, func(ctx context.Context, offset, limit int) ([]models.ContentCalendar, error) {
+		return store.GetEntriesPage(ctx, status, offset, limit)
+	})
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+)
+
+// fakePages serves n integer rows and records every requested page.
+type fakePages struct {
+	n     int
+	calls [][2]int
+}
+
+func (f *fakePages) fetch(ctx context.Context, offset, limit int) ([]int, error) {
+	f.calls = append(f.calls, [2]int{offset, limit})
+	var rows []int
+	for i := offset; i < f.n && i < offset+limit; i++ {
+		rows = append(rows, i)
+	}
+	return rows, nil
+}
+
+func TestPaginate_WalksAllPages(t *testing.T) {
+	f := &fakePages{n: 7}
+
+	rows, err := Collect(Paginate(context.Background(), 3, f.fetch), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 7 || rows[0] != 0 || rows[6] != 6 {
+		t.Errorf("rows = %v, want 0..6", rows)
+	}
+	want := [][2]int{{0, 3}, {3, 3}, {6, 3}}
+	if fmt.Sprint(f.calls) != fmt.Sprint(want) {
+		t.Errorf("pages requested = %v, want %v", f.calls, want)
+	}
+}
+
+func TestPaginate_StopsFetchingOnBreak(t *testing.T) {
+	f := &fakePages{n: 100}
+
+	rows, err := Collect(Paginate(context.Background(), 10, f.fetch), 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 12 {
+		t.Errorf("got %d rows, want 12", len(rows))
+	}
+	if len(f.calls) != 2 {
+		t.Errorf("expected 2 page requests, got %d", len(f.calls))
+	}
+}
+
+func TestPaginate_YieldsFetchError(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	fetch := func(ctx context.Context, offset, limit int) ([]int, error) {
+		calls++
+		if offset > 0 {
+			return nil, boom
+		}
+		return []int{1, 2}, nil
+	}
+
+	_, err := Collect(Paginate(context.Background(), 2, fetch), 0)
+	if !errors.Is(err, boom) {
+		t.Errorf("expected boom, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestPaginate_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	f := &fakePages{n: 10}
+	_, err := Collect(Paginate(ctx, 5, f.fetch), 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(f.calls) != 0 {
+		t.Errorf("expected no fetches after cancel, got %d", len(f.calls))
+	}
+}
+
+// TestGetScripts_NoLimitPagesPastRowCap checks that asking for every script
+// keeps requesting pages instead of trusting a single capped response.
+func TestGetScripts_NoLimitPagesPastRowCap(t *testing.T) {
+	const total = DefaultPageSize*2 + 20
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var scripts []models.ContentScript
+		for i := offset; i < total && i < offset+limit; i++ {
+			scripts = append(scripts, models.ContentScript{ID: fmt.Sprintf("script-%d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(scripts)
+	}))
+	defer server.Close()
+
+	repo := NewContentRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
+
+	scripts, err := repo.GetScripts(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scripts) != total {
+		t.Errorf("got %d scripts, want %d", len(scripts), total)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 page requests, got %d: %v", len(queries), queries)
+	}
+	if got := queryParam(t, queries[0], "order"); got != "created_at.desc,id.desc" {
+		t.Errorf("order = %q, want a stable created_at.desc,id.desc", got)
+	}
+}
+
+func TestGetMetrics_PagesPastRowCap(t *testing.T) {
+	const total = DefaultPageSize + 7
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var metrics []models.PostMetric
+		for i := offset; i < total && i < offset+limit; i++ {
+			metrics = append(metrics, models.PostMetric{ID: fmt.Sprintf("metric-%d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metrics)
+	}))
+	defer server.Close()
+
+	repo := NewMetricsRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
+
+	metrics, err := repo.GetMetrics(context.Background(), "tiktok", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(metrics) != total {
+		t.Errorf("got %d metrics, want %d", len(metrics), total)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 page requests, got %d: %v", len(queries), queries)
+	}
+	for _, q := range queries {
+		if got := queryParam(t, q, "platform"); got != "eq.tiktok" {
+			t.Errorf("platform = %q, want the filter on every page", got)
+		}
+	}
+	if got := queryParam(t, queries[0], "order"); got != "collected_at.desc,id.desc" {
+		t.Errorf("order = %q, want a stable collected_at.desc,id.desc", got)
+	}
+}
+
+func TestIterEntryMetrics_BatchesCalendarIDs(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]models.PostMetric{{ID: fmt.Sprintf("metric-%d", len(queries))}})
+	}))
+	defer server.Close()
+
+	repo := NewMetricsRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
+
+	ids := make([]string, entryIDBatch*2+5)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("entry-%d", i)
+	}
+	metrics, err := Collect(IterEntryMetrics(context.Background(), repo, ids), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(metrics) != 3 || len(queries) != 3 {
+		t.Fatalf("got %d metrics in %d requests, want one request per batch of %d IDs", len(metrics), len(queries), entryIDBatch)
+	}
+	if got := queryParam(t, queries[2], "calendar_id"); got != "in.(entry-200,entry-201,entry-202,entry-203,entry-204)" {
+		t.Errorf("last batch calendar_id = %q", got)
+	}
+	if got := queryParam(t, queries[0], "order"); got != "collected_at.desc,id.desc" {
+		t.Errorf("order = %q, want a stable collected_at.desc,id.desc", got)
+	}
+}
+
+func TestGetIdeas_SmallLimitIsSingleRequest(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]models.ContentIdea{{ID: "a"}, {ID: "b"}})
+	}))
+	defer server.Close()
+
+	repo := NewContentRepository(&config.SupabaseConfig{URL: server.URL, AnonKey: "test"})
+
+	ideas, err := repo.GetIdeas(context.Background(), "approved", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ideas) != 2 || len(queries) != 1 {
+		t.Fatalf("got %d ideas in %d requests, want 2 in 1", len(ideas), len(queries))
+	}
+	if got := queryParam(t, queries[0], "limit"); got != "2" {
+		t.Errorf("limit = %q, want 2", got)
+	}
+}
+
+func queryParam(t *testing.T, rawQuery, key string) string {
+	t.Helper()
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		t.Fatalf("bad query %q: %v", rawQuery, err)
+	}
+	return values.Get(key)
+}
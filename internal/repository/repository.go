@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+)
+
+// BookStore persists the book catalog.
+type BookStore interface {
+	Create(ctx context.Context, input *models.BookInput) (*models.Book, error)
+	GetAll(ctx context.Context) ([]models.Book, error)
+	GetByID(ctx context.Context, id string) (*models.Book, error)
+	Update(ctx context.Context, id string, input *models.BookInput) (*models.Book, error)
+	GetBookByIDPrefix(ctx context.Context, prefix string) (*models.Book, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// ContentStore persists content ideas and the scripts generated from them.
+type ContentStore interface {
+	CreateIdea(ctx context.Context, input *models.ContentIdeaInput) (*models.ContentIdea, error)
+	CreateIdeas(ctx context.Context, inputs []*models.ContentIdeaInput) ([]RowResult[models.ContentIdea], error)
+	GetIdeas(ctx context.Context, status string, limit int) ([]models.ContentIdea, error)
+	GetIdeasByBook(ctx context.Context, bookID, status string, limit int) ([]models.ContentIdea, error)
+	GetIdeasPage(ctx context.Context, bookID, status string, offset, limit int) ([]models.ContentIdea, error)
+	UpdateIdeaStatus(ctx context.Context, id string, status string) error
+	GetIdeaByIDPrefix(ctx context.Context, prefix string) (*models.ContentIdea, error)
+	CreateScript(ctx context.Context, input *models.ContentScriptInput) (*models.ContentScript, error)
+	GetScriptByID(ctx context.Context, id string) (*models.ContentScript, error)
+	GetScripts(ctx context.Context, limit int) ([]models.ContentScript, error)
+	GetScriptsPage(ctx context.Context, offset, limit int) ([]models.ContentScript, error)
+}
+
+// CalendarStore persists scheduled posts and their publishing state.
+type CalendarStore interface {
+	CreateEntry(ctx context.Context, input *models.ContentCalendarInput) (*models.ContentCalendar, error)
+	CreateEntries(ctx context.Context, inputs []*models.ContentCalendarInput) ([]RowResult[models.ContentCalendar], error)
+	GetEntries(ctx context.Context, status string, limit int) ([]models.ContentCalendar, error)
+	GetEntriesPage(ctx context.Context, status string, offset, limit int) ([]models.ContentCalendar, error)
+	GetEntryByID(ctx context.Context, id string) (*models.ContentCalendar, error)
+	UpdateEntryStatus(ctx context.Context, id string, status string) error
+	UpdatePublishState(ctx context.Context, id string, update *models.PublishUpdate) error
+	GetStatusCounts(ctx context.Context) (map[string]int, error)
+	RetryFailed(ctx context.Context) (int, error)
+	GetEntriesNeedingMedia(ctx context.Context) ([]models.ContentCalendarWithScript, error)
+	UpdateMediaURL(ctx context.Context, entryID, mediaURL string) error
+	DeleteEntry(ctx context.Context, id string) error
+}
+
+// CampaignStore persists launch campaigns.
+type CampaignStore interface {
+	CreateCampaign(ctx context.Context, input *models.CampaignInput) (*models.Campaign, error)
+}
+
+// MetricsStore persists post performance snapshots.
+type MetricsStore interface {
+	CreateMetric(ctx context.Context, input *models.PostMetricInput) (*models.PostMetric, error)
+	GetMetrics(ctx context.Context, platform string, from, to time.Time) ([]models.PostMetric, error)
+	GetEntryMetricsPage(ctx context.Context, calendarIDs []string, offset, limit int) ([]models.PostMetric, error)
+	GetAggregateMetrics(ctx context.Context, platform string, from, to time.Time) (*models.AggregateMetrics, error)
+}
+
+// SalesStore persists daily KDP sales.
+type SalesStore interface {
+	CreateSale(ctx context.Context, input *models.BookSaleInput) (*models.BookSale, error)
+	UpsertSales(ctx context.Context, inputs []*models.BookSaleInput) ([]RowResult[models.BookSale], error)
+	GetSalesByBook(ctx context.Context, bookID string, from, to time.Time) ([]models.BookSale, error)
+	GetAllSales(ctx context.Context, from, to time.Time) ([]models.BookSale, error)
+}
+
+// UsageStore persists the AI usage ledger.
+type UsageStore interface {
+	RecordUsage(ctx context.Context, input *models.AIUsageInput) (*models.AIUsage, error)
+	LinkUsageToScript(ctx context.Context, ids []string, scriptID string) error
+	GetUsage(ctx context.Context, from, to time.Time) ([]models.AIUsage, error)
+}
+
+// Stores groups one implementation of every repository so commands can
+// work against any storage backend.
+type Stores struct {
+	Books     BookStore
+	Content   ContentStore
+	Calendar  CalendarStore
+	Campaigns CampaignStore
+	Metrics   MetricsStore
+	Sales     SalesStore
+	Usage     UsageStore
+}
+
+// NewSupabaseStores returns the Supabase-backed repositories.
+func NewSupabaseStores(cfg *config.SupabaseConfig) *Stores {
+	return &Stores{
+		Books:     NewBooksRepository(cfg),
+		Content:   NewContentRepository(cfg),
+		Calendar:  NewCalendarRepository(cfg),
+		Campaigns: NewCampaignsRepository(cfg),
+		Metrics:   NewMetricsRepository(cfg),
+		Sales:     NewSalesRepository(cfg),
+		Usage:     NewUsageRepository(cfg),
+	}
+}
+
+// Compile-time checks that the Supabase repositories satisfy the interfaces.
+var (
+	_ BookStore     = (*BooksRepository)(nil)
+	_ ContentStore  = (*ContentRepository)(nil)
+	_ CalendarStore = (*CalendarRepository)(nil)
+	_ CampaignStore = (*CampaignsRepository)(nil)
+	_ MetricsStore  = (*MetricsRepository)(nil)
+	_ SalesStore    = (*SalesRepository)(nil)
+	_ UsageStore    = (*UsageRepository)(nil)
+)
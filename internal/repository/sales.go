@@ -1,73 +1,32 @@
 package repository
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"strings"
 	"time"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/postgrest"
 )
 
 // SalesRepository handles sales database operations
 type SalesRepository struct {
-	config *config.SupabaseConfig
-	client *http.Client
+	db *postgrest.Client
 }
 
 // NewSalesRepository creates a new sales repository
 func NewSalesRepository(cfg *config.SupabaseConfig) *SalesRepository {
 	return &SalesRepository{
-		config: cfg,
-		client: &http.Client{},
+		db: postgrest.NewClient(cfg),
 	}
 }
 
 // CreateSale creates a new book sale record
-func (r *SalesRepository) CreateSale(input *models.BookSaleInput) (*models.BookSale, error) {
-	url := fmt.Sprintf("%s/rest/v1/sales_data", r.config.URL)
-
-	jsonData, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal sale: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create sale: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create sale: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
+func (r *SalesRepository) CreateSale(ctx context.Context, input *models.BookSaleInput) (*models.BookSale, error) {
 	var sales []models.BookSale
-	if err := json.Unmarshal(body, &sales); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := r.db.From("sales_data").Insert(ctx, input, &sales); err != nil {
+		return nil, fmt.Errorf("failed to create sale: %w", err)
 	}
 
 	if len(sales) == 0 {
@@ -77,96 +36,158 @@ func (r *SalesRepository) CreateSale(input *models.BookSaleInput) (*models.BookS
 	return &sales[0], nil
 }
 
-// GetSalesByBook retrieves sales for a specific book
-func (r *SalesRepository) GetSalesByBook(bookID string, from, to time.Time) ([]models.BookSale, error) {
-	url := fmt.Sprintf("%s/rest/v1/sales_data?book_id=eq.%s&order=sale_date.asc", r.config.URL, bookID)
+// UpsertSales writes daily sales in bulk, keyed on (book_id, date). Rows
+// that are new are created, rows whose stored values differ are updated and
+// rows already stored unchanged are reported as duplicates without being
+// sent. Every row gets a result; only request-level failures such as a
+// cancelled context are returned as an error.
+func (r *SalesRepository) UpsertSales(ctx context.Context, inputs []*models.BookSaleInput) ([]RowResult[models.BookSale], error) {
+	results := NewResults[models.BookSale](len(inputs))
+	idx := UniqueSales(inputs, results)
+
+	send := func(ctx context.Context, body []*models.BookSaleInput, out *[]models.BookSale) error {
+		return r.db.From("sales_data").OnConflict("book_id", "date").Upsert(ctx, body, out)
+	}
+
+	for start := 0; start < len(idx); start += BulkChunkSize {
+		chunk := idx[start:min(start+BulkChunkSize, len(idx))]
+		stored, err := r.storedSales(ctx, inputs, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upsert sales: %w", err)
+		}
+
+		var pending []int
+		for _, i := range chunk {
+			sale, ok := stored[SaleKey(inputs[i].BookID, inputs[i].SaleDate)]
+			switch {
+			case !ok:
+				results[i].Status = RowCreated
+				pending = append(pending, i)
+			case SameSale(sale, inputs[i]):
+				results[i].Status = RowDuplicate
+				results[i].Row = &sale
+			default:
+				results[i].Status = RowUpdated
+				pending = append(pending, i)
+			}
+		}
+
+		if err := writeChunks(ctx, send, inputs, pending, results); err != nil {
+			return nil, fmt.Errorf("failed to upsert sales: %w", err)
+		}
+	}
+
+	return results, nil
+}
 
-	if !from.IsZero() {
-		url += fmt.Sprintf("&sale_date=gte.%s", from.Format("2006-01-02"))
-	}
-	if !to.IsZero() {
-		url += fmt.Sprintf("&sale_date=lte.%s", to.Format("2006-01-02"))
-	}
+// storedSales fetches the rows already stored for inputs[idx], keyed by
+// SaleKey. It asks for the books and date span of the chunk and keeps only
+// the exact (book_id, date) pairs.
+func (r *SalesRepository) storedSales(ctx context.Context, inputs []*models.BookSaleInput, idx []int) (map[string]models.BookSale, error) {
+	wanted := make(map[string]bool, len(idx))
+	seenBook := make(map[string]bool)
+	var bookIDs []string
+	var from, to time.Time
+	for _, i := range idx {
+		in := inputs[i]
+		wanted[SaleKey(in.BookID, in.SaleDate)] = true
+		if in.BookID != "" && !seenBook[in.BookID] {
+			seenBook[in.BookID] = true
+			bookIDs = append(bookIDs, in.BookID)
+		}
+		if from.IsZero() || in.SaleDate.Before(from) {
+			from = in.SaleDate.Time
+		}
+		if to.IsZero() || in.SaleDate.After(to) {
+			to = in.SaleDate.Time
+		}
+	}
+
+	stored := make(map[string]models.BookSale, len(idx))
+	if len(bookIDs) == 0 {
+		return stored, nil
+	}
+
+	pages := Paginate(ctx, DefaultPageSize, func(ctx context.Context, offset, limit int) ([]models.BookSale, error) {
+		q := r.db.From("sales_data").
+			Select("*").
+			In("book_id", bookIDs).
+			Order("id", true).
+			Limit(limit).
+			Offset(offset)
+		addDateRange(q, from, to)
+
+		var sales []models.BookSale
+		if err := q.Get(ctx, &sales); err != nil {
+			return nil, err
+		}
+		return sales, nil
+	})
+	for sale, err := range pages {
+		if err != nil {
+			return nil, err
+		}
+		if key := SaleKey(sale.BookID, sale.SaleDate); wanted[key] {
+			stored[key] = sale
+		}
+	}
+
+	return stored, nil
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// GetSalesByBook retrieves every sale of a book between from and to, oldest
+// first
+func (r *SalesRepository) GetSalesByBook(ctx context.Context, bookID string, from, to time.Time) ([]models.BookSale, error) {
+	sales, err := collectPages(ctx, 0, func(ctx context.Context, offset, limit int) ([]models.BookSale, error) {
+		q := r.db.From("sales_data").
+			Select("*").
+			Eq("book_id", bookID).
+			Order("date", true).
+			Order("id", true). // tie-breaker so pages do not overlap
+			Limit(limit).
+			Offset(offset)
+		addDateRange(q, from, to)
+
+		var page []models.BookSale
+		err := q.Get(ctx, &page)
+		return page, err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
+		return nil, fmt.Errorf("failed to get sales: %w", err)
 	}
 
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+	return sales, nil
+}
 
-	resp, err := r.client.Do(req)
+// GetAllSales retrieves every sale between from and to, newest first
+func (r *SalesRepository) GetAllSales(ctx context.Context, from, to time.Time) ([]models.BookSale, error) {
+	sales, err := collectPages(ctx, 0, func(ctx context.Context, offset, limit int) ([]models.BookSale, error) {
+		q := r.db.From("sales_data").
+			Select("*").
+			Order("date", false).
+			Order("id", false). // tie-breaker so pages do not overlap
+			Limit(limit).
+			Offset(offset)
+		addDateRange(q, from, to)
+
+		var page []models.BookSale
+		err := q.Get(ctx, &page)
+		return page, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sales: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get sales: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	var sales []models.BookSale
-	if err := json.Unmarshal(body, &sales); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
 
 	return sales, nil
 }
 
-// GetAllSales retrieves all sales
-func (r *SalesRepository) GetAllSales(from, to time.Time) ([]models.BookSale, error) {
-	url := fmt.Sprintf("%s/rest/v1/sales_data?select=*&order=sale_date.desc", r.config.URL)
-
+// addDateRange restricts a sales query to the inclusive [from, to] date range.
+// Zero times leave that side of the range open.
+func addDateRange(q *postgrest.Query, from, to time.Time) {
 	if !from.IsZero() {
-		url += fmt.Sprintf("&sale_date=gte.%s", from.Format("2006-01-02"))
+		q.Gte("date", from.Format("2006-01-02"))
 	}
 	if !to.IsZero() {
-		url += fmt.Sprintf("&sale_date=lte.%s", to.Format("2006-01-02"))
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	apiKey := r.config.ServiceKey
-	if apiKey == "" {
-		apiKey = r.config.AnonKey
+		q.Lte("date", to.Format("2006-01-02"))
 	}
-
-	req.Header.Set("apikey", apiKey)
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get sales: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get sales: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	var sales []models.BookSale
-	if err := json.Unmarshal(body, &sales); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return sales, nil
 }
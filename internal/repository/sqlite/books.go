@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+const bookColumns = `id, title, genre, target_audience, kdp_asin, cover_image_url,
+	publication_date, current_rank, total_sales, created_at, updated_at`
+
+type bookStore struct {
+	db *DB
+}
+
+func (r *bookStore) Create(ctx context.Context, input *models.BookInput) (*models.Book, error) {
+	id := repository.NewID()
+	now := formatTime(r.db.timestamp())
+
+	_, err := r.db.sql.ExecContext(ctx, `
+		INSERT INTO books (id, title, genre, target_audience, kdp_asin, cover_image_url,
+			publication_date, total_sales, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?, ?)`,
+		id, input.Title, input.Genre, nullString(input.TargetAudience), nullString(input.KDPASIN),
+		nullString(input.CoverImageURL), dateValue(input.PublicationDate), now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create book: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *bookStore) GetAll(ctx context.Context) ([]models.Book, error) {
+	books, err := r.query(ctx, `SELECT `+bookColumns+` FROM books ORDER BY created_at DESC, rowid DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get books: %w", err)
+	}
+	return books, nil
+}
+
+func (r *bookStore) GetByID(ctx context.Context, id string) (*models.Book, error) {
+	row := r.db.sql.QueryRowContext(ctx, `SELECT `+bookColumns+` FROM books WHERE id = ?`, id)
+	book, err := scanBook(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("book not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book: %w", err)
+	}
+	return &book, nil
+}
+
+// Update mirrors a PATCH of BookInput: title and genre are always written,
+// optional fields only when set.
+func (r *bookStore) Update(ctx context.Context, id string, input *models.BookInput) (*models.Book, error) {
+	res, err := r.db.sql.ExecContext(ctx, `
+		UPDATE books SET
+			title = ?,
+			genre = ?,
+			target_audience = COALESCE(?, target_audience),
+			kdp_asin = COALESCE(?, kdp_asin),
+			cover_image_url = COALESCE(?, cover_image_url),
+			publication_date = COALESCE(?, publication_date),
+			updated_at = ?
+		WHERE id = ?`,
+		input.Title, input.Genre, nullString(input.TargetAudience), nullString(input.KDPASIN),
+		nullString(input.CoverImageURL), dateValue(input.PublicationDate),
+		formatTime(r.db.timestamp()), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update book: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("book not found")
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *bookStore) GetBookByIDPrefix(ctx context.Context, prefix string) (*models.Book, error) {
+	return repository.ResolveBookPrefix(ctx, prefix, func(ctx context.Context, prefix string) ([]models.Book, error) {
+		// substr instead of LIKE: LIKE is case-insensitive in SQLite but not in Postgres
+		return r.query(ctx, `SELECT `+bookColumns+` FROM books WHERE substr(id, 1, ?) = ?`, len(prefix), prefix)
+	})
+}
+
+func (r *bookStore) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.sql.ExecContext(ctx, `DELETE FROM books WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete book: %w", err)
+	}
+	return nil
+}
+
+func (r *bookStore) query(ctx context.Context, query string, args ...any) ([]models.Book, error) {
+	rows, err := r.db.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []models.Book
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	return books, rows.Err()
+}
+
+func scanBook(row scanner) (models.Book, error) {
+	var (
+		b                             models.Book
+		audience, asin, cover, pubDay sql.NullString
+		rank                          sql.NullInt64
+		createdAt, updatedAt          string
+	)
+	err := row.Scan(&b.ID, &b.Title, &b.Genre, &audience, &asin, &cover,
+		&pubDay, &rank, &b.TotalSales, &createdAt, &updatedAt)
+	if err != nil {
+		return b, err
+	}
+
+	b.TargetAudience = audience.String
+	b.KDPASIN = asin.String
+	b.CoverImageURL = cover.String
+	if pubDay.Valid {
+		day, err := time.Parse(models.DateFormat, pubDay.String)
+		if err != nil {
+			return b, fmt.Errorf("invalid publication_date %q: %w", pubDay.String, err)
+		}
+		b.PublicationDate = &models.Date{Time: day}
+	}
+	if rank.Valid {
+		n := int(rank.Int64)
+		b.CurrentRank = &n
+	}
+	if b.CreatedAt, err = parseTime(createdAt); err != nil {
+		return b, err
+	}
+	if b.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return b, err
+	}
+	return b, nil
+}
+
+// dateValue stores a DATE column as YYYY-MM-DD, or NULL when unset.
+func dateValue(d *models.Date) any {
+	if d == nil || d.IsZero() {
+		return nil
+	}
+	return d.Format(models.DateFormat)
+}
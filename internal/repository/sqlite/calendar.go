@@ -0,0 +1,348 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+const entryColumns = `id, script_id, scheduled_for, platform, post_type, status,
+	published_at, publish_errors, generate_media, media_url, submission_id, post_url, publisher, campaign_id`
+
+type calendarStore struct {
+	db *DB
+}
+
+func (r *calendarStore) CreateEntry(ctx context.Context, input *models.ContentCalendarInput) (*models.ContentCalendar, error) {
+	entry, err := r.insertEntry(ctx, r.db.sql, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (r *calendarStore) CreateEntries(ctx context.Context, inputs []*models.ContentCalendarInput) ([]repository.RowResult[models.ContentCalendar], error) {
+	results := repository.NewResults[models.ContentCalendar](len(inputs))
+	err := r.db.inTx(ctx, func(tx *sql.Tx) error {
+		for i, input := range inputs {
+			entry, err := r.insertEntry(ctx, tx, input)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				results[i].Status = repository.RowFailed
+				results[i].Err = err
+				continue
+			}
+			results[i].Status = repository.RowCreated
+			results[i].Row = &entry
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create entries: %w", err)
+	}
+	return results, nil
+}
+
+func (r *calendarStore) insertEntry(ctx context.Context, q queryer, input *models.ContentCalendarInput) (models.ContentCalendar, error) {
+	id := repository.NewID()
+	now := formatTime(r.db.timestamp())
+
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO content_calendar (id, script_id, scheduled_for, platform, post_type, status, campaign_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, 'pending_approval', ?, ?, ?)`,
+		id, input.ScriptID, formatTime(input.ScheduledFor), input.Platform, input.PostType, input.CampaignID, now, now)
+	if err != nil {
+		return models.ContentCalendar{}, err
+	}
+
+	return scanEntry(q.QueryRowContext(ctx, `SELECT `+entryColumns+` FROM content_calendar WHERE id = ?`, id))
+}
+
+func (r *calendarStore) GetEntries(ctx context.Context, status string, limit int) ([]models.ContentCalendar, error) {
+	return r.GetEntriesPage(ctx, status, 0, limit)
+}
+
+func (r *calendarStore) GetEntriesPage(ctx context.Context, status string, offset, limit int) ([]models.ContentCalendar, error) {
+	query := `SELECT ` + entryColumns + ` FROM content_calendar`
+	var args []any
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY scheduled_for ASC, rowid ASC" + pageClause(offset, limit)
+
+	entries, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *calendarStore) GetEntryByID(ctx context.Context, id string) (*models.ContentCalendar, error) {
+	row := r.db.sql.QueryRowContext(ctx, `SELECT `+entryColumns+` FROM content_calendar WHERE id = ?`, id)
+	entry, err := scanEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("entry not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (r *calendarStore) UpdateEntryStatus(ctx context.Context, id string, status string) error {
+	_, err := r.db.sql.ExecContext(ctx, `UPDATE content_calendar SET status = ?, updated_at = ? WHERE id = ?`,
+		status, formatTime(r.db.timestamp()), id)
+	if err != nil {
+		return fmt.Errorf("failed to update entry: %w", err)
+	}
+	return nil
+}
+
+// UpdatePublishState applies update in one transaction, appending
+// update.Error to the JSON list in publish_errors.
+func (r *calendarStore) UpdatePublishState(ctx context.Context, id string, update *models.PublishUpdate) error {
+	if err := update.Validate(); err != nil {
+		return fmt.Errorf("failed to update publish state: %w", err)
+	}
+
+	err := r.db.inTx(ctx, func(tx *sql.Tx) error {
+		entry, err := scanEntry(tx.QueryRowContext(ctx, `SELECT `+entryColumns+` FROM content_calendar WHERE id = ?`, id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("entry not found: %s", id)
+		}
+		if err != nil {
+			return err
+		}
+
+		set := []string{"updated_at = ?"}
+		args := []any{formatTime(r.db.timestamp())}
+		if update.Status != "" {
+			set = append(set, "status = ?")
+			args = append(args, update.Status)
+		}
+		if update.SubmissionID != nil {
+			publisher := update.Publisher
+			if *update.SubmissionID == "" {
+				publisher = ""
+			}
+			set = append(set, "submission_id = ?", "publisher = ?")
+			args = append(args, nullString(*update.SubmissionID), nullString(publisher))
+		}
+		if update.PostURL != nil {
+			set = append(set, "post_url = ?")
+			args = append(args, nullString(*update.PostURL))
+		}
+		if update.PublishedAt != nil {
+			set = append(set, "published_at = ?")
+			args = append(args, formatTime(*update.PublishedAt))
+		}
+		if update.Error != nil {
+			errs, err := jsonText(append(entry.PublishErrors, *update.Error))
+			if err != nil {
+				return err
+			}
+			set = append(set, "publish_errors = ?")
+			args = append(args, errs)
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE content_calendar SET `+strings.Join(set, ", ")+` WHERE id = ?`, append(args, id)...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update publish state: %w", err)
+	}
+	return nil
+}
+
+func (r *calendarStore) GetStatusCounts(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.sql.QueryContext(ctx, `SELECT status, COUNT(*) FROM content_calendar GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			status sql.NullString
+			n      int
+		)
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, fmt.Errorf("failed to get status counts: %w", err)
+		}
+		counts[status.String] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get status counts: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *calendarStore) RetryFailed(ctx context.Context) (int, error) {
+	res, err := r.db.sql.ExecContext(ctx, `UPDATE content_calendar SET status = 'approved', updated_at = ? WHERE status = 'failed'`,
+		formatTime(r.db.timestamp()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to retry entries: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retry entries: %w", err)
+	}
+	return int(n), nil
+}
+
+// GetEntriesNeedingMedia applies the same filter and embed as the Supabase
+// query: approved entries with generate_media set and no media_url yet.
+func (r *calendarStore) GetEntriesNeedingMedia(ctx context.Context) ([]models.ContentCalendarWithScript, error) {
+	entries, err := r.query(ctx, `SELECT `+entryColumns+` FROM content_calendar
+		WHERE status IN ('approved', 'scheduled') AND generate_media = 1 AND media_url IS NULL
+		ORDER BY scheduled_for ASC, rowid ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entries needing media: %w", err)
+	}
+
+	result := make([]models.ContentCalendarWithScript, 0, len(entries))
+	for _, e := range entries {
+		script, err := r.scriptWithIdea(ctx, e.ScriptID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get entries needing media: %w", err)
+		}
+		result = append(result, models.ContentCalendarWithScript{ContentCalendar: e, Script: script})
+	}
+	return result, nil
+}
+
+func (r *calendarStore) UpdateMediaURL(ctx context.Context, entryID, mediaURL string) error {
+	_, err := r.db.sql.ExecContext(ctx, `UPDATE content_calendar SET media_url = ?, updated_at = ? WHERE id = ?`,
+		mediaURL, formatTime(r.db.timestamp()), entryID)
+	if err != nil {
+		return fmt.Errorf("failed to update media URL: %w", err)
+	}
+	return nil
+}
+
+func (r *calendarStore) DeleteEntry(ctx context.Context, id string) error {
+	if _, err := r.db.sql.ExecContext(ctx, `DELETE FROM content_calendar WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete entry: %w", err)
+	}
+	return nil
+}
+
+// scriptWithIdea builds the content_scripts(*,content_ideas(*,books(*))) embed.
+func (r *calendarStore) scriptWithIdea(ctx context.Context, scriptID *string) (*models.ContentScriptWithIdea, error) {
+	if scriptID == nil {
+		return nil, nil
+	}
+
+	row := r.db.sql.QueryRowContext(ctx, `SELECT `+scriptColumns+` FROM content_scripts WHERE id = ?`, *scriptID)
+	s, err := scanScript(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	script := &models.ContentScriptWithIdea{ContentScript: s}
+
+	row = r.db.sql.QueryRowContext(ctx, `SELECT `+ideaColumns+` FROM content_ideas WHERE id = ?`, s.IdeaID)
+	idea, err := scanIdea(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return script, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	script.Idea = &models.ContentIdeaWithBook{ContentIdea: idea}
+
+	if idea.BookID != nil {
+		row = r.db.sql.QueryRowContext(ctx, `SELECT `+bookColumns+` FROM books WHERE id = ?`, *idea.BookID)
+		book, err := scanBook(row)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if err == nil {
+			script.Idea.Book = &book
+		}
+	}
+	return script, nil
+}
+
+func (r *calendarStore) query(ctx context.Context, query string, args ...any) ([]models.ContentCalendar, error) {
+	rows, err := r.db.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.ContentCalendar
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func scanEntry(row scanner) (models.ContentCalendar, error) {
+	var (
+		e                          models.ContentCalendar
+		scriptID, status, mediaURL sql.NullString
+		publishedAt, publishErrors sql.NullString
+		submissionID, postURL      sql.NullString
+		publisher, campaignID      sql.NullString
+		scheduledFor               string
+	)
+	err := row.Scan(&e.ID, &scriptID, &scheduledFor, &e.Platform, &e.PostType, &status,
+		&publishedAt, &publishErrors, &e.GenerateMedia, &mediaURL, &submissionID, &postURL, &publisher, &campaignID)
+	if err != nil {
+		return e, err
+	}
+
+	if scriptID.Valid {
+		e.ScriptID = &scriptID.String
+	}
+	if e.ScheduledFor, err = parseTime(scheduledFor); err != nil {
+		return e, err
+	}
+	e.Status = status.String
+	if publishedAt.Valid {
+		t, err := parseTime(publishedAt.String)
+		if err != nil {
+			return e, err
+		}
+		e.PublishedAt = &t
+	}
+	if publishErrors.Valid {
+		if err := json.Unmarshal([]byte(publishErrors.String), &e.PublishErrors); err != nil {
+			return e, fmt.Errorf("invalid publish_errors for entry %s: %w", e.ID, err)
+		}
+	}
+	if mediaURL.Valid {
+		e.MediaURL = &mediaURL.String
+	}
+	if submissionID.Valid {
+		e.SubmissionID = &submissionID.String
+	}
+	if postURL.Valid {
+		e.PostURL = &postURL.String
+	}
+	if publisher.Valid {
+		e.Publisher = &publisher.String
+	}
+	if campaignID.Valid {
+		e.CampaignID = &campaignID.String
+	}
+	return e, nil
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+const campaignColumns = `id, book_id, name, launch_date, created_at`
+
+type campaignStore struct {
+	db *DB
+}
+
+func (r *campaignStore) CreateCampaign(ctx context.Context, input *models.CampaignInput) (*models.Campaign, error) {
+	id := repository.NewID()
+	_, err := r.db.sql.ExecContext(ctx, `
+		INSERT INTO campaigns (id, book_id, name, launch_date, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		id, input.BookID, input.Name, dateValue(&input.LaunchDate), formatTime(r.db.timestamp()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	campaign, err := scanCampaign(r.db.sql.QueryRowContext(ctx, `SELECT `+campaignColumns+` FROM campaigns WHERE id = ?`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+	return &campaign, nil
+}
+
+func scanCampaign(row scanner) (models.Campaign, error) {
+	var (
+		c              models.Campaign
+		day, createdAt string
+	)
+	if err := row.Scan(&c.ID, &c.BookID, &c.Name, &day, &createdAt); err != nil {
+		return c, err
+	}
+
+	launch, err := time.Parse(models.DateFormat, day)
+	if err != nil {
+		return c, fmt.Errorf("invalid launch_date %q: %w", day, err)
+	}
+	c.LaunchDate = models.Date{Time: launch}
+	if c.CreatedAt, err = parseTime(createdAt); err != nil {
+		return c, err
+	}
+	return c, nil
+}
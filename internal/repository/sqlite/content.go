@@ -0,0 +1,261 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+const (
+	ideaColumns   = `id, type, brief_description, relevance_score, book_id, status, generated_at, metadata, campaign_id`
+	scriptColumns = `id, idea_id, hook, full_script, cta, hashtags, estimated_duration, created_at`
+)
+
+type contentStore struct {
+	db *DB
+}
+
+func (r *contentStore) CreateIdea(ctx context.Context, input *models.ContentIdeaInput) (*models.ContentIdea, error) {
+	idea, err := r.insertIdea(ctx, r.db.sql, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create idea: %w", err)
+	}
+	return &idea, nil
+}
+
+func (r *contentStore) CreateIdeas(ctx context.Context, inputs []*models.ContentIdeaInput) ([]repository.RowResult[models.ContentIdea], error) {
+	results := repository.NewResults[models.ContentIdea](len(inputs))
+	err := r.db.inTx(ctx, func(tx *sql.Tx) error {
+		for i, input := range inputs {
+			idea, err := r.insertIdea(ctx, tx, input)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				results[i].Status = repository.RowFailed
+				results[i].Err = err
+				continue
+			}
+			results[i].Status = repository.RowCreated
+			results[i].Row = &idea
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ideas: %w", err)
+	}
+	return results, nil
+}
+
+func (r *contentStore) insertIdea(ctx context.Context, q queryer, input *models.ContentIdeaInput) (models.ContentIdea, error) {
+	metadata, err := jsonText(input.Metadata)
+	if err != nil {
+		return models.ContentIdea{}, err
+	}
+
+	id := repository.NewID()
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO content_ideas (id, type, brief_description, relevance_score, book_id, status, generated_at, metadata, campaign_id)
+		VALUES (?, ?, ?, ?, ?, 'pending', ?, ?, ?)`,
+		id, input.Type, input.BriefDescription, input.RelevanceScore, input.BookID,
+		formatTime(r.db.timestamp()), metadata, input.CampaignID)
+	if err != nil {
+		return models.ContentIdea{}, err
+	}
+
+	return scanIdea(q.QueryRowContext(ctx, `SELECT `+ideaColumns+` FROM content_ideas WHERE id = ?`, id))
+}
+
+func (r *contentStore) GetIdeas(ctx context.Context, status string, limit int) ([]models.ContentIdea, error) {
+	return r.GetIdeasByBook(ctx, "", status, limit)
+}
+
+func (r *contentStore) GetIdeasByBook(ctx context.Context, bookID, status string, limit int) ([]models.ContentIdea, error) {
+	return r.GetIdeasPage(ctx, bookID, status, 0, limit)
+}
+
+func (r *contentStore) GetIdeasPage(ctx context.Context, bookID, status string, offset, limit int) ([]models.ContentIdea, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if bookID != "" {
+		where = append(where, "book_id = ?")
+		args = append(args, bookID)
+	}
+	if status != "" {
+		where = append(where, "status = ?")
+		args = append(args, status)
+	}
+
+	query := `SELECT ` + ideaColumns + ` FROM content_ideas`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY generated_at DESC, rowid DESC" + pageClause(offset, limit)
+
+	ideas, err := r.queryIdeas(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ideas: %w", err)
+	}
+	return ideas, nil
+}
+
+func (r *contentStore) UpdateIdeaStatus(ctx context.Context, id string, status string) error {
+	if _, err := r.db.sql.ExecContext(ctx, `UPDATE content_ideas SET status = ? WHERE id = ?`, status, id); err != nil {
+		return fmt.Errorf("failed to update idea: %w", err)
+	}
+	return nil
+}
+
+func (r *contentStore) GetIdeaByIDPrefix(ctx context.Context, prefix string) (*models.ContentIdea, error) {
+	return repository.ResolveIdeaPrefix(ctx, prefix, func(ctx context.Context, prefix string) ([]models.ContentIdea, error) {
+		// substr instead of LIKE: LIKE is case-insensitive in SQLite but not in Postgres
+		return r.queryIdeas(ctx, `SELECT `+ideaColumns+` FROM content_ideas WHERE substr(id, 1, ?) = ?`, len(prefix), prefix)
+	})
+}
+
+func (r *contentStore) CreateScript(ctx context.Context, input *models.ContentScriptInput) (*models.ContentScript, error) {
+	// hashtags is NOT NULL: leave it NULL when unset so the constraint applies
+	var hashtags any
+	if input.Hashtags != nil {
+		var err error
+		if hashtags, err = jsonText(input.Hashtags); err != nil {
+			return nil, fmt.Errorf("failed to create script: %w", err)
+		}
+	}
+
+	id := repository.NewID()
+	_, err := r.db.sql.ExecContext(ctx, `
+		INSERT INTO content_scripts (id, idea_id, hook, full_script, cta, hashtags, estimated_duration, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, input.IdeaID, input.Hook, input.FullScript, input.CTA, hashtags,
+		input.EstimatedDuration, formatTime(r.db.timestamp()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create script: %w", err)
+	}
+
+	return r.GetScriptByID(ctx, id)
+}
+
+func (r *contentStore) GetScriptByID(ctx context.Context, id string) (*models.ContentScript, error) {
+	row := r.db.sql.QueryRowContext(ctx, `SELECT `+scriptColumns+` FROM content_scripts WHERE id = ?`, id)
+	script, err := scanScript(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("script not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get script: %w", err)
+	}
+	return &script, nil
+}
+
+func (r *contentStore) GetScripts(ctx context.Context, limit int) ([]models.ContentScript, error) {
+	return r.GetScriptsPage(ctx, 0, limit)
+}
+
+func (r *contentStore) GetScriptsPage(ctx context.Context, offset, limit int) ([]models.ContentScript, error) {
+	rows, err := r.db.sql.QueryContext(ctx,
+		`SELECT `+scriptColumns+` FROM content_scripts ORDER BY created_at DESC, rowid DESC`+pageClause(offset, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scripts: %w", err)
+	}
+	defer rows.Close()
+
+	var scripts []models.ContentScript
+	for rows.Next() {
+		script, err := scanScript(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get scripts: %w", err)
+		}
+		scripts = append(scripts, script)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get scripts: %w", err)
+	}
+	return scripts, nil
+}
+
+func (r *contentStore) queryIdeas(ctx context.Context, query string, args ...any) ([]models.ContentIdea, error) {
+	rows, err := r.db.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ideas []models.ContentIdea
+	for rows.Next() {
+		idea, err := scanIdea(rows)
+		if err != nil {
+			return nil, err
+		}
+		ideas = append(ideas, idea)
+	}
+	return ideas, rows.Err()
+}
+
+func scanIdea(row scanner) (models.ContentIdea, error) {
+	var (
+		idea                     models.ContentIdea
+		score                    sql.NullInt64
+		bookID, status, metadata sql.NullString
+		campaignID               sql.NullString
+		generatedAt              string
+	)
+	err := row.Scan(&idea.ID, &idea.Type, &idea.BriefDescription, &score, &bookID, &status, &generatedAt, &metadata, &campaignID)
+	if err != nil {
+		return idea, err
+	}
+
+	if score.Valid {
+		n := int(score.Int64)
+		idea.RelevanceScore = &n
+	}
+	if bookID.Valid {
+		idea.BookID = &bookID.String
+	}
+	idea.Status = status.String
+	if idea.GeneratedAt, err = parseTime(generatedAt); err != nil {
+		return idea, err
+	}
+	if metadata.Valid {
+		if err := json.Unmarshal([]byte(metadata.String), &idea.Metadata); err != nil {
+			return idea, fmt.Errorf("invalid metadata for idea %s: %w", idea.ID, err)
+		}
+	}
+	if campaignID.Valid {
+		idea.CampaignID = &campaignID.String
+	}
+	return idea, nil
+}
+
+func scanScript(row scanner) (models.ContentScript, error) {
+	var (
+		script    models.ContentScript
+		ideaID    sql.NullString
+		hashtags  string
+		duration  sql.NullInt64
+		createdAt string
+	)
+	err := row.Scan(&script.ID, &ideaID, &script.Hook, &script.FullScript, &script.CTA,
+		&hashtags, &duration, &createdAt)
+	if err != nil {
+		return script, err
+	}
+
+	script.IdeaID = ideaID.String
+	script.EstimatedDuration = int(duration.Int64)
+	if err := json.Unmarshal([]byte(hashtags), &script.Hashtags); err != nil {
+		return script, fmt.Errorf("invalid hashtags for script %s: %w", script.ID, err)
+	}
+	if script.CreatedAt, err = parseTime(createdAt); err != nil {
+		return script, err
+	}
+	return script, nil
+}
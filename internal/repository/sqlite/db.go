@@ -0,0 +1,258 @@
+// Package sqlite implements the repository interfaces on a local SQLite file.
+//
+// It is meant for small catalogs where a Supabase project is overkill. The
+// schema (schema.sql) is the Postgres schema translated to SQLite, so the
+// same CHECKs, foreign keys, unique keys and cascades apply.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/repository"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+//go:embed schema.sql
+var schema string
+
+// timeLayout stores TIMESTAMPTZ columns as fixed-width UTC text so they sort
+// and compare correctly as strings.
+const timeLayout = "2006-01-02T15:04:05.000000Z"
+
+// DB is an open SQLite database with the gagipress schema applied.
+type DB struct {
+	sql *sql.DB
+	now func() time.Time
+}
+
+// Open opens (creating if needed) the SQLite file at path and applies the
+// schema. Use ":memory:" for a throwaway database.
+func Open(path string) (*DB, error) {
+	if path != ":memory:" {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
+	}
+
+	conn, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// A single connection keeps ":memory:" databases alive and serialises
+	// writers, which is all a CLI needs.
+	conn.SetMaxOpenConns(1)
+
+	if err := widenChecks(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to upgrade schema: %w", err)
+	}
+	if err := addColumns(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to upgrade schema: %w", err)
+	}
+	if _, err := conn.Exec(schema); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to apply schema: %w", err)
+	}
+
+	return &DB{sql: conn, now: time.Now}, nil
+}
+
+// addedColumns lists columns added to tables after they first shipped.
+// CREATE TABLE IF NOT EXISTS leaves older files without them, so Open adds
+// them before the schema, whose indexes may already refer to them.
+var addedColumns = []struct{ table, column, decl string }{
+	{"content_calendar", "submission_id", "TEXT"},
+	{"content_calendar", "publisher", "TEXT"},
+	{"content_ideas", "campaign_id", "TEXT REFERENCES campaigns(id) ON DELETE SET NULL"},
+	{"content_calendar", "campaign_id", "TEXT REFERENCES campaigns(id) ON DELETE SET NULL"},
+}
+
+// addColumns adds the addedColumns missing from existing tables.
+func addColumns(conn *sql.DB) error {
+	for _, c := range addedColumns {
+		rows, err := conn.Query(`SELECT name FROM pragma_table_info(?)`, c.table)
+		if err != nil {
+			return err
+		}
+		exists, found := false, false
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return err
+			}
+			exists = true
+			found = found || name == c.column
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// A missing table is created with the column by the schema
+		if exists && !found {
+			if _, err := conn.Exec(`ALTER TABLE ` + c.table + ` ADD COLUMN ` + c.column + ` ` + c.decl); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// widenedChecks lists CHECK constraints that accept more values than when
+// their table first shipped, or were dropped. SQLite cannot alter a
+// constraint, so Open rebuilds older tables with the widened definition.
+var widenedChecks = []struct{ table, old, new string }{
+	{"content_calendar", "'publishing', 'published', 'failed'", "'publishing', 'submitted', 'published', 'failed'"},
+	{"content_calendar", "platform TEXT NOT NULL CHECK (platform IN ('instagram', 'tiktok'))", "platform TEXT NOT NULL"},
+}
+
+// widenChecks rebuilds tables whose stored definition still has an old
+// CHECK, following SQLite's copy-drop-rename procedure. Foreign keys are
+// off meanwhile so dropping the old table does not cascade.
+func widenChecks(conn *sql.DB) error {
+	for _, c := range widenedChecks {
+		var def string
+		err := conn.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, c.table).Scan(&def)
+		if err == sql.ErrNoRows {
+			continue // created by the schema
+		}
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(def, c.old) {
+			continue
+		}
+
+		tmp := c.table + "_new"
+		def = strings.Replace(strings.Replace(def, c.old, c.new, 1), c.table, tmp, 1)
+		if _, err := conn.Exec(`PRAGMA foreign_keys = OFF`); err != nil {
+			return err
+		}
+		err = func() error {
+			tx, err := conn.Begin()
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback()
+			for _, stmt := range []string{
+				def,
+				`INSERT INTO ` + tmp + ` SELECT * FROM ` + c.table,
+				`DROP TABLE ` + c.table,
+				`ALTER TABLE ` + tmp + ` RENAME TO ` + c.table,
+			} {
+				if _, err := tx.Exec(stmt); err != nil {
+					return err
+				}
+			}
+			return tx.Commit()
+		}()
+		if _, fkErr := conn.Exec(`PRAGMA foreign_keys = ON`); err == nil {
+			err = fkErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to rebuild %s: %w", c.table, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying database.
+func (db *DB) Close() error {
+	return db.sql.Close()
+}
+
+// Stores returns repositories backed by this database.
+func (db *DB) Stores() *repository.Stores {
+	return &repository.Stores{
+		Books:     &bookStore{db: db},
+		Content:   &contentStore{db: db},
+		Calendar:  &calendarStore{db: db},
+		Campaigns: &campaignStore{db: db},
+		Metrics:   &metricsStore{db: db},
+		Sales:     &salesStore{db: db},
+		Usage:     &usageStore{db: db},
+	}
+}
+
+// timestamp returns the current time the way Postgres stores TIMESTAMPTZ.
+func (db *DB) timestamp() time.Time {
+	return db.now().UTC().Truncate(time.Microsecond)
+}
+
+// scanner is satisfied by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// queryer is satisfied by *sql.DB and *sql.Tx, so row helpers can run inside
+// or outside a transaction.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// inTx runs fn in a transaction and commits it when fn returns nil. A
+// statement that fails inside fn only undoes itself, so bulk writes can
+// record a per-row error and carry on.
+func (db *DB) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := db.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// nullString maps "" to NULL, matching fields the JSON API omits when empty.
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// jsonText encodes v for a JSON/array column; nil stays NULL.
+func jsonText(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON column: %w", err)
+	}
+	return string(raw), nil
+}
+
+// pageClause returns the LIMIT/OFFSET clause for a page. A zero or negative
+// limit means no limit.
+func pageClause(offset, limit int) string {
+	if limit <= 0 && offset <= 0 {
+		return ""
+	}
+	if limit <= 0 {
+		limit = -1 // SQLite needs a LIMIT before OFFSET; -1 is unbounded
+	}
+	return fmt.Sprintf(" LIMIT %d OFFSET %d", limit, max(offset, 0))
+}
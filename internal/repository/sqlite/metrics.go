@@ -0,0 +1,137 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+const metricColumns = `id, calendar_id, platform, views, likes, comments, shares, saves, engagement_rate, collected_at`
+
+type metricsStore struct {
+	db *DB
+}
+
+func (r *metricsStore) CreateMetric(ctx context.Context, input *models.PostMetricInput) (*models.PostMetric, error) {
+	id := repository.NewID()
+	// engagement_rate is DECIMAL(5,2) in Postgres
+	rate := math.Round(input.CalculateEngagementRate()*100) / 100
+
+	_, err := r.db.sql.ExecContext(ctx, `
+		INSERT INTO post_metrics (id, calendar_id, platform, views, likes, comments, shares, saves, engagement_rate, collected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, input.CalendarID, input.Platform, input.Views, input.Likes, input.Comments,
+		input.Shares, input.Saves, rate, formatTime(r.db.timestamp()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric: %w", err)
+	}
+
+	metrics, err := r.query(ctx, `SELECT `+metricColumns+` FROM post_metrics WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric: %w", err)
+	}
+	return &metrics[0], nil
+}
+
+func (r *metricsStore) GetMetrics(ctx context.Context, platform string, from, to time.Time) ([]models.PostMetric, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if platform != "" {
+		where = append(where, "platform = ?")
+		args = append(args, platform)
+	}
+	if !from.IsZero() {
+		where = append(where, "collected_at >= ?")
+		args = append(args, formatTime(from))
+	}
+	if !to.IsZero() {
+		where = append(where, "collected_at <= ?")
+		args = append(args, formatTime(to))
+	}
+
+	query := `SELECT ` + metricColumns + ` FROM post_metrics`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY collected_at DESC, rowid DESC"
+
+	metrics, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
+	}
+	return metrics, nil
+}
+
+func (r *metricsStore) GetEntryMetricsPage(ctx context.Context, calendarIDs []string, offset, limit int) ([]models.PostMetric, error) {
+	if len(calendarIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(calendarIDs))
+	for i, id := range calendarIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(calendarIDs)), ", ")
+	query := `SELECT ` + metricColumns + ` FROM post_metrics WHERE calendar_id IN (` + placeholders + `)` +
+		" ORDER BY collected_at DESC, rowid DESC" + pageClause(offset, limit)
+
+	metrics, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
+	}
+	return metrics, nil
+}
+
+func (r *metricsStore) GetAggregateMetrics(ctx context.Context, platform string, from, to time.Time) (*models.AggregateMetrics, error) {
+	metrics, err := r.GetMetrics(ctx, platform, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.AggregateMetrics(metrics), nil
+}
+
+func (r *metricsStore) query(ctx context.Context, query string, args ...any) ([]models.PostMetric, error) {
+	rows, err := r.db.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var metrics []models.PostMetric
+	for rows.Next() {
+		var (
+			m                                     models.PostMetric
+			calendarID                            sql.NullString
+			views, likes, comments, shares, saves sql.NullInt64
+			rate                                  sql.NullFloat64
+			collectedAt                           string
+		)
+		err := rows.Scan(&m.ID, &calendarID, &m.Platform, &views, &likes, &comments,
+			&shares, &saves, &rate, &collectedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		m.CalendarID = calendarID.String
+		m.Views = int(views.Int64)
+		m.Likes = int(likes.Int64)
+		m.Comments = int(comments.Int64)
+		m.Shares = int(shares.Int64)
+		m.Saves = int(saves.Int64)
+		m.EngagementRate = rate.Float64
+		if m.CollectedAt, err = parseTime(collectedAt); err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, rows.Err()
+}
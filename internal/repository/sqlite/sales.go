@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+const saleColumns = `id, book_id, date, units_sold, royalty, page_reads, imported_at`
+
+type salesStore struct {
+	db *DB
+}
+
+func (r *salesStore) CreateSale(ctx context.Context, input *models.BookSaleInput) (*models.BookSale, error) {
+	id := repository.NewID()
+	_, err := r.db.sql.ExecContext(ctx, `
+		INSERT INTO sales_data (id, book_id, date, units_sold, royalty, page_reads, imported_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id, input.BookID, dateValue(&input.SaleDate), input.UnitsSold, input.Royalty,
+		input.PageReads, formatTime(r.db.timestamp()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sale: %w", err)
+	}
+
+	sale, err := scanSale(r.db.sql.QueryRowContext(ctx, `SELECT `+saleColumns+` FROM sales_data WHERE id = ?`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sale: %w", err)
+	}
+	return &sale, nil
+}
+
+func (r *salesStore) UpsertSales(ctx context.Context, inputs []*models.BookSaleInput) ([]repository.RowResult[models.BookSale], error) {
+	results := repository.NewResults[models.BookSale](len(inputs))
+	idx := repository.UniqueSales(inputs, results)
+
+	err := r.db.inTx(ctx, func(tx *sql.Tx) error {
+		for _, i := range idx {
+			sale, status, err := r.upsertSale(ctx, tx, inputs[i])
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				results[i].Status = repository.RowFailed
+				results[i].Err = err
+				continue
+			}
+			results[i].Status = status
+			results[i].Row = &sale
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert sales: %w", err)
+	}
+	return results, nil
+}
+
+// upsertSale writes one row keyed on UNIQUE(book_id, date) and reports
+// whether it was created, updated or already stored unchanged.
+func (r *salesStore) upsertSale(ctx context.Context, q queryer, input *models.BookSaleInput) (models.BookSale, repository.RowStatus, error) {
+	day := dateValue(&input.SaleDate)
+	stored, err := scanSale(q.QueryRowContext(ctx,
+		`SELECT `+saleColumns+` FROM sales_data WHERE book_id = ? AND date = ?`, input.BookID, day))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		id := repository.NewID()
+		_, err = q.ExecContext(ctx, `
+			INSERT INTO sales_data (id, book_id, date, units_sold, royalty, page_reads, imported_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			id, input.BookID, day, input.UnitsSold, input.Royalty, input.PageReads, formatTime(r.db.timestamp()))
+		if err != nil {
+			return stored, repository.RowFailed, err
+		}
+		sale, err := scanSale(q.QueryRowContext(ctx, `SELECT `+saleColumns+` FROM sales_data WHERE id = ?`, id))
+		return sale, repository.RowCreated, err
+	case err != nil:
+		return stored, repository.RowFailed, err
+	case repository.SameSale(stored, input):
+		return stored, repository.RowDuplicate, nil
+	}
+
+	_, err = q.ExecContext(ctx, `UPDATE sales_data SET units_sold = ?, royalty = ?, page_reads = ? WHERE id = ?`,
+		input.UnitsSold, input.Royalty, input.PageReads, stored.ID)
+	if err != nil {
+		return stored, repository.RowFailed, err
+	}
+	stored.UnitsSold, stored.Royalty, stored.PageReads = input.UnitsSold, input.Royalty, input.PageReads
+	return stored, repository.RowUpdated, nil
+}
+
+func (r *salesStore) GetSalesByBook(ctx context.Context, bookID string, from, to time.Time) ([]models.BookSale, error) {
+	where, args := dateRange(from, to)
+	where = append([]string{"book_id = ?"}, where...)
+	args = append([]any{bookID}, args...)
+
+	sales, err := r.query(ctx, `SELECT `+saleColumns+` FROM sales_data WHERE `+
+		strings.Join(where, " AND ")+` ORDER BY date ASC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sales: %w", err)
+	}
+	return sales, nil
+}
+
+func (r *salesStore) GetAllSales(ctx context.Context, from, to time.Time) ([]models.BookSale, error) {
+	query := `SELECT ` + saleColumns + ` FROM sales_data`
+	where, args := dateRange(from, to)
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY date DESC"
+
+	sales, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sales: %w", err)
+	}
+	return sales, nil
+}
+
+// dateRange restricts a sales query to the inclusive [from, to] date range.
+// Zero times leave that side of the range open.
+func dateRange(from, to time.Time) ([]string, []any) {
+	var (
+		where []string
+		args  []any
+	)
+	if !from.IsZero() {
+		where = append(where, "date >= ?")
+		args = append(args, from.Format(models.DateFormat))
+	}
+	if !to.IsZero() {
+		where = append(where, "date <= ?")
+		args = append(args, to.Format(models.DateFormat))
+	}
+	return where, args
+}
+
+func (r *salesStore) query(ctx context.Context, query string, args ...any) ([]models.BookSale, error) {
+	rows, err := r.db.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []models.BookSale
+	for rows.Next() {
+		sale, err := scanSale(rows)
+		if err != nil {
+			return nil, err
+		}
+		sales = append(sales, sale)
+	}
+	return sales, rows.Err()
+}
+
+func scanSale(row scanner) (models.BookSale, error) {
+	var (
+		s                models.BookSale
+		bookID           sql.NullString
+		units, pageReads sql.NullInt64
+		royalty          sql.NullFloat64
+		day, importedAt  string
+	)
+	if err := row.Scan(&s.ID, &bookID, &day, &units, &royalty, &pageReads, &importedAt); err != nil {
+		return s, err
+	}
+
+	s.BookID = bookID.String
+	date, err := time.Parse(models.DateFormat, day)
+	if err != nil {
+		return s, fmt.Errorf("invalid sale date %q: %w", day, err)
+	}
+	s.SaleDate = models.Date{Time: date}
+	s.UnitsSold = int(units.Int64)
+	s.Royalty = royalty.Float64
+	s.PageReads = int(pageReads.Int64)
+	if s.CreatedAt, err = parseTime(importedAt); err != nil {
+		return s, err
+	}
+	return s, nil
+}
@@ -0,0 +1,203 @@
+-- Gagipress SQLite Schema
+-- Description: migrations/001_initial_schema.sql translated to SQLite, with the
+-- table changes from 002 (collected_at), 004 (updated_at, generate_media,
+-- publishing status), 006 (media_url), 009 (page_reads), 010 (ai_usage), 011
+-- (submission_id), 012 (submitted status), 013 (publisher) and 015
+-- (campaigns) applied. Postgres-only parts
+-- (RLS, views, plpgsql functions, pg_cron, storage buckets) are left out.
+--
+-- Type mapping:
+--   UUID, TEXT[], JSONB -> TEXT (arrays and JSON are stored as JSON text)
+--   TIMESTAMPTZ         -> TEXT (UTC, RFC 3339 with microseconds, sortable)
+--   DATE                -> TEXT (YYYY-MM-DD)
+--   DECIMAL             -> REAL
+--   BOOLEAN             -> INTEGER (0/1)
+--
+-- IDs and timestamps are generated by the application.
+
+-- ============================================================================
+-- Books Table
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS books (
+  id TEXT PRIMARY KEY,
+  title TEXT NOT NULL,
+  genre TEXT NOT NULL,
+  target_audience TEXT,
+  kdp_asin TEXT UNIQUE,
+  cover_image_url TEXT,
+  publication_date TEXT,
+  current_rank INTEGER,
+  total_sales INTEGER DEFAULT 0,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_books_genre ON books(genre);
+
+-- ============================================================================
+-- Campaigns Table
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS campaigns (
+  id TEXT PRIMARY KEY,
+  book_id TEXT NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  launch_date TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_book ON campaigns(book_id);
+
+-- ============================================================================
+-- Content Ideas Table
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS content_ideas (
+  id TEXT PRIMARY KEY,
+  type TEXT NOT NULL CHECK (type IN ('educational', 'entertainment', 'bts', 'ugc', 'trend')),
+  brief_description TEXT NOT NULL,
+  relevance_score INTEGER CHECK (relevance_score >= 0 AND relevance_score <= 100),
+  book_id TEXT REFERENCES books(id) ON DELETE CASCADE,
+  status TEXT DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'scripted')),
+  generated_at TEXT NOT NULL,
+  metadata TEXT,
+  campaign_id TEXT REFERENCES campaigns(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ideas_status ON content_ideas(status);
+CREATE INDEX IF NOT EXISTS idx_ideas_score ON content_ideas(relevance_score DESC);
+CREATE INDEX IF NOT EXISTS idx_ideas_book ON content_ideas(book_id);
+CREATE INDEX IF NOT EXISTS idx_ideas_type ON content_ideas(type);
+CREATE INDEX IF NOT EXISTS idx_ideas_campaign ON content_ideas(campaign_id);
+
+-- ============================================================================
+-- Content Scripts Table
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS content_scripts (
+  id TEXT PRIMARY KEY,
+  idea_id TEXT REFERENCES content_ideas(id) ON DELETE CASCADE,
+  hook TEXT NOT NULL,
+  full_script TEXT NOT NULL,
+  cta TEXT NOT NULL,
+  hashtags TEXT NOT NULL, -- JSON array
+  visual_notes TEXT,
+  audio_suggestion TEXT,
+  estimated_duration INTEGER, -- seconds
+  status TEXT DEFAULT 'draft' CHECK (status IN ('draft', 'approved', 'used')),
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_scripts_idea ON content_scripts(idea_id);
+CREATE INDEX IF NOT EXISTS idx_scripts_status ON content_scripts(status);
+
+-- ============================================================================
+-- Content Calendar Table
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS content_calendar (
+  id TEXT PRIMARY KEY,
+  script_id TEXT REFERENCES content_scripts(id) ON DELETE CASCADE,
+  scheduled_for TEXT NOT NULL,
+  platform TEXT NOT NULL,
+  post_type TEXT NOT NULL CHECK (post_type IN ('reel', 'story', 'feed')),
+  status TEXT DEFAULT 'pending_approval' CHECK (status IN ('pending_approval', 'approved', 'publishing', 'submitted', 'published', 'failed')),
+  approved_at TEXT,
+  published_at TEXT,
+  post_url TEXT,
+  publish_errors TEXT, -- JSON
+  generate_media INTEGER NOT NULL DEFAULT 0,
+  media_url TEXT,
+  submission_id TEXT,
+  publisher TEXT,
+  campaign_id TEXT REFERENCES campaigns(id) ON DELETE SET NULL,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_scheduled ON content_calendar(scheduled_for);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_submission ON content_calendar(submission_id) WHERE submission_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_calendar_status ON content_calendar(status);
+CREATE INDEX IF NOT EXISTS idx_calendar_platform ON content_calendar(platform);
+CREATE INDEX IF NOT EXISTS idx_calendar_script ON content_calendar(script_id);
+CREATE INDEX IF NOT EXISTS idx_calendar_campaign ON content_calendar(campaign_id);
+
+-- ============================================================================
+-- Post Metrics Table (Time-Series)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS post_metrics (
+  id TEXT PRIMARY KEY,
+  calendar_id TEXT REFERENCES content_calendar(id) ON DELETE CASCADE,
+  platform TEXT NOT NULL,
+  post_url TEXT,
+  views INTEGER DEFAULT 0,
+  likes INTEGER DEFAULT 0,
+  comments INTEGER DEFAULT 0,
+  shares INTEGER DEFAULT 0,
+  saves INTEGER DEFAULT 0,
+  engagement_rate REAL,
+  watch_time_percentage REAL,
+  follower_growth INTEGER DEFAULT 0,
+  is_top_performer INTEGER DEFAULT 0,
+  collected_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_metrics_calendar ON post_metrics(calendar_id);
+CREATE INDEX IF NOT EXISTS idx_metrics_collected ON post_metrics(collected_at DESC);
+
+-- ============================================================================
+-- Sales Data Table
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS sales_data (
+  id TEXT PRIMARY KEY,
+  book_id TEXT REFERENCES books(id) ON DELETE CASCADE,
+  date TEXT NOT NULL,
+  units_sold INTEGER DEFAULT 0,
+  revenue REAL,
+  royalty REAL,
+  page_reads INTEGER DEFAULT 0,
+  source TEXT DEFAULT 'amazon_reports',
+  imported_at TEXT NOT NULL,
+  UNIQUE(book_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sales_book ON sales_data(book_id);
+CREATE INDEX IF NOT EXISTS idx_sales_date ON sales_data(date DESC);
+
+-- ============================================================================
+-- AI Usage Ledger
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS ai_usage (
+  id TEXT PRIMARY KEY,
+  provider TEXT NOT NULL,
+  model TEXT NOT NULL,
+  prompt_tokens INTEGER NOT NULL DEFAULT 0 CHECK (prompt_tokens >= 0),
+  completion_tokens INTEGER NOT NULL DEFAULT 0 CHECK (completion_tokens >= 0),
+  cost_usd REAL NOT NULL DEFAULT 0,
+  command TEXT NOT NULL,
+  book_id TEXT REFERENCES books(id) ON DELETE SET NULL,
+  idea_id TEXT REFERENCES content_ideas(id) ON DELETE SET NULL,
+  script_id TEXT REFERENCES content_scripts(id) ON DELETE SET NULL,
+  content_type TEXT,
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_usage_created ON ai_usage(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ai_usage_book ON ai_usage(book_id);
+
+-- ============================================================================
+-- Schema Version
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS schema_version (
+    version INTEGER PRIMARY KEY,
+    applied_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    description TEXT
+);
+
+INSERT OR IGNORE INTO schema_version (version, description) VALUES
+  (1, 'Initial schema with books, content pipeline, and analytics tables'),
+  (2, 'Rename scraped_at to collected_at in post_metrics table'),
+  (4, 'Add updated_at, generate_media, and publishing lock status to content_calendar'),
+  (6, 'Add media_url to content_calendar'),
+  (9, 'Add page_reads to sales_data'),
+  (10, 'Add ai_usage ledger'),
+  (11, 'Add submission_id to content_calendar'),
+  (12, 'Add submitted status to content_calendar'),
+  (13, 'Drop platform check and add publisher to content_calendar'),
+  (15, 'Add campaigns and campaign_id to content_ideas and content_calendar');
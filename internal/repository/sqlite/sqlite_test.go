@@ -0,0 +1,661 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func seedScript(t *testing.T, db *DB) (*models.Book, *models.ContentIdea, *models.ContentScript) {
+	t.Helper()
+	ctx := context.Background()
+	stores := db.Stores()
+
+	book, err := stores.Books.Create(ctx, &models.BookInput{Title: "Book", Genre: "kids", KDPASIN: "B0TEST"})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	score := 80
+	idea, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{
+		Type: "educational", BriefDescription: "Idea", BookID: &book.ID, RelevanceScore: &score,
+		Metadata: map[string]any{"source": "test"},
+	})
+	if err != nil {
+		t.Fatalf("create idea: %v", err)
+	}
+	script, err := stores.Content.CreateScript(ctx, &models.ContentScriptInput{
+		IdeaID: idea.ID, Hook: "h", FullScript: "s", CTA: "c", Hashtags: []string{"#a", "#b"}, EstimatedDuration: 30,
+	})
+	if err != nil {
+		t.Fatalf("create script: %v", err)
+	}
+	return book, idea, script
+}
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	book, idea, script := seedScript(t, db)
+
+	if book.TotalSales != 0 || book.CreatedAt.IsZero() {
+		t.Errorf("unexpected book defaults: %+v", book)
+	}
+	if idea.Status != "pending" || *idea.RelevanceScore != 80 || *idea.BookID != book.ID {
+		t.Errorf("unexpected idea: %+v", idea)
+	}
+	if meta, ok := idea.Metadata.(map[string]any); !ok || meta["source"] != "test" {
+		t.Errorf("metadata = %#v", idea.Metadata)
+	}
+	if strings.Join(script.Hashtags, ",") != "#a,#b" || script.EstimatedDuration != 30 {
+		t.Errorf("unexpected script: %+v", script)
+	}
+
+	when := time.Date(2026, 3, 29, 18, 30, 0, 0, time.FixedZone("CEST", 2*3600))
+	entry, err := db.Stores().Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+		ScriptID: &script.ID, ScheduledFor: when, Platform: "tiktok", PostType: "reel",
+	})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+	if entry.Status != "pending_approval" || !entry.ScheduledFor.Equal(when) {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	stores := db.Stores()
+	book, idea, _ := seedScript(t, db)
+
+	missing := "00000000-0000-0000-0000-000000000000"
+	score := 101
+	day := models.Date{Time: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)}
+	if _, err := stores.Sales.CreateSale(ctx, &models.BookSaleInput{BookID: book.ID, SaleDate: day, UnitsSold: 1}); err != nil {
+		t.Fatalf("first sale: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"duplicate ASIN", func() error {
+			_, err := stores.Books.Create(ctx, &models.BookInput{Title: "Other", Genre: "kids", KDPASIN: "B0TEST"})
+			return err
+		}},
+		{"invalid idea type", func() error {
+			_, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{Type: "meme", BriefDescription: "x"})
+			return err
+		}},
+		{"relevance out of range", func() error {
+			_, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{Type: "ugc", BriefDescription: "x", RelevanceScore: &score})
+			return err
+		}},
+		{"idea for missing book", func() error {
+			_, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{Type: "ugc", BriefDescription: "x", BookID: &missing})
+			return err
+		}},
+		{"script without hashtags", func() error {
+			_, err := stores.Content.CreateScript(ctx, &models.ContentScriptInput{IdeaID: idea.ID, Hook: "h", FullScript: "s", CTA: "c"})
+			return err
+		}},
+		{"entry for missing script", func() error {
+			_, err := stores.Calendar.CreateEntry(ctx, &models.ContentCalendarInput{ScriptID: &missing, ScheduledFor: time.Now(), Platform: "tiktok", PostType: "reel"})
+			return err
+		}},
+		{"invalid idea status", func() error {
+			return stores.Content.UpdateIdeaStatus(ctx, idea.ID, "published")
+		}},
+		{"duplicate sale day", func() error {
+			_, err := stores.Sales.CreateSale(ctx, &models.BookSaleInput{BookID: book.ID, SaleDate: day, UnitsSold: 2})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); err == nil {
+				t.Error("expected constraint error, got nil")
+			}
+		})
+	}
+}
+
+func TestBooksWithoutASIN(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	// An empty ASIN is stored as NULL, so it must not trip the UNIQUE constraint
+	for i := 0; i < 2; i++ {
+		if _, err := db.Stores().Books.Create(ctx, &models.BookInput{Title: "Draft", Genre: "kids"}); err != nil {
+			t.Fatalf("create book %d: %v", i, err)
+		}
+	}
+}
+
+func TestDeleteBookCascades(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	stores := db.Stores()
+	book, _, script := seedScript(t, db)
+
+	entry, err := stores.Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+		ScriptID: &script.ID, ScheduledFor: time.Now(), Platform: "tiktok", PostType: "reel",
+	})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+	if _, err := stores.Metrics.CreateMetric(ctx, &models.PostMetricInput{CalendarID: entry.ID, Platform: "tiktok", Views: 10}); err != nil {
+		t.Fatalf("create metric: %v", err)
+	}
+
+	if err := stores.Books.Delete(ctx, book.ID); err != nil {
+		t.Fatalf("delete book: %v", err)
+	}
+
+	for _, table := range []string{"content_ideas", "content_scripts", "content_calendar", "post_metrics"} {
+		var n int
+		if err := db.sql.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if n != 0 {
+			t.Errorf("%s has %d rows after cascade, want 0", table, n)
+		}
+	}
+}
+
+func TestGetIdeaByIDPrefix_CaseSensitive(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	_, idea, _ := seedScript(t, db)
+
+	if _, err := db.Stores().Content.GetIdeaByIDPrefix(ctx, idea.ID[:8]); err != nil {
+		t.Fatalf("lookup by prefix: %v", err)
+	}
+	upper := strings.ToUpper(idea.ID[:8])
+	if upper != idea.ID[:8] {
+		if _, err := db.Stores().Content.GetIdeaByIDPrefix(ctx, upper); err == nil {
+			t.Error("expected uppercase prefix not to match, like Postgres")
+		}
+	}
+}
+
+func TestGetEntriesNeedingMedia_EmbedsScriptIdeaBook(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	book, _, script := seedScript(t, db)
+
+	entry, err := db.Stores().Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+		ScriptID: &script.ID, ScheduledFor: time.Now(), Platform: "instagram", PostType: "reel",
+	})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+	if _, err := db.sql.Exec(`UPDATE content_calendar SET status = 'approved', generate_media = 1 WHERE id = ?`, entry.ID); err != nil {
+		t.Fatalf("prepare entry: %v", err)
+	}
+
+	entries, err := db.Stores().Calendar.GetEntriesNeedingMedia(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Script == nil || entries[0].Script.Idea == nil || entries[0].Script.Idea.Book == nil {
+		t.Fatalf("expected one entry with script, idea and book embedded, got %+v", entries)
+	}
+	if entries[0].Script.Idea.Book.ID != book.ID {
+		t.Errorf("embedded book = %s, want %s", entries[0].Script.Idea.Book.ID, book.ID)
+	}
+
+	if err := db.Stores().Calendar.UpdateMediaURL(ctx, entry.ID, "https://example.com/a.png"); err != nil {
+		t.Fatalf("update media: %v", err)
+	}
+	entries, _ = db.Stores().Calendar.GetEntriesNeedingMedia(ctx)
+	if len(entries) != 0 {
+		t.Errorf("expected no entries after media_url is set, got %d", len(entries))
+	}
+}
+
+func TestSalesDateRangeAndOrder(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	book, _, _ := seedScript(t, db)
+
+	for _, d := range []int{3, 1, 2} {
+		day := models.Date{Time: time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC)}
+		if _, err := db.Stores().Sales.CreateSale(ctx, &models.BookSaleInput{BookID: book.ID, SaleDate: day, UnitsSold: d, PageReads: 10 * d}); err != nil {
+			t.Fatalf("create sale: %v", err)
+		}
+	}
+
+	sales, err := db.Stores().Sales.GetSalesByBook(ctx, book.ID,
+		time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("get sales: %v", err)
+	}
+	if len(sales) != 2 || sales[0].UnitsSold != 2 || sales[1].UnitsSold != 3 {
+		t.Errorf("expected Feb 2 and Feb 3 ascending, got %+v", sales)
+	}
+	if sales[0].PageReads != 20 {
+		t.Errorf("page reads = %d, want 20", sales[0].PageReads)
+	}
+
+	all, _ := db.Stores().Sales.GetAllSales(ctx, time.Time{}, time.Time{})
+	if len(all) != 3 || all[0].UnitsSold != 3 {
+		t.Errorf("expected all sales newest first, got %+v", all)
+	}
+}
+
+func TestOpen_Reopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "data.db")
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	book, _, _ := seedScript(t, db)
+	db.Close()
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+
+	got, err := reopened.Stores().Books.GetByID(ctx, book.ID)
+	if err != nil {
+		t.Fatalf("book missing after reopen: %v", err)
+	}
+	if got.Title != book.Title || !got.CreatedAt.Equal(book.CreatedAt) {
+		t.Errorf("got %+v, want %+v", got, book)
+	}
+}
+
+func TestGetEntriesPage(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	_, _, script := seedScript(t, db)
+
+	base := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		_, err := db.Stores().Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+			ScriptID: &script.ID, ScheduledFor: base.Add(time.Duration(4-i) * time.Hour), Platform: "tiktok", PostType: "reel",
+		})
+		if err != nil {
+			t.Fatalf("create entry: %v", err)
+		}
+	}
+
+	page, err := db.Stores().Calendar.GetEntriesPage(ctx, "", 2, 2)
+	if err != nil {
+		t.Fatalf("get page: %v", err)
+	}
+	if len(page) != 2 || page[0].ScheduledFor.Hour() != 11 || page[1].ScheduledFor.Hour() != 12 {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	rest, _ := db.Stores().Calendar.GetEntriesPage(ctx, "", 4, 0)
+	if len(rest) != 1 || rest[0].ScheduledFor.Hour() != 13 {
+		t.Errorf("expected the last entry after offset 4, got %+v", rest)
+	}
+}
+
+func TestGetEntryMetricsPage(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	stores := db.Stores()
+	_, _, script := seedScript(t, db)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		entry, err := stores.Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+			ScriptID: &script.ID, ScheduledFor: time.Date(2026, 5, 1, 9+i, 0, 0, 0, time.UTC), Platform: "tiktok", PostType: "reel",
+		})
+		if err != nil {
+			t.Fatalf("create entry: %v", err)
+		}
+		ids = append(ids, entry.ID)
+		for views := 1; views <= 2; views++ {
+			if _, err := stores.Metrics.CreateMetric(ctx, &models.PostMetricInput{CalendarID: entry.ID, Platform: "tiktok", Views: views}); err != nil {
+				t.Fatalf("create metric: %v", err)
+			}
+		}
+	}
+
+	page, err := stores.Metrics.GetEntryMetricsPage(ctx, ids[1:], 1, 2)
+	if err != nil {
+		t.Fatalf("get page: %v", err)
+	}
+	if len(page) != 2 || page[0].CalendarID != ids[2] || page[0].Views != 1 || page[1].CalendarID != ids[1] || page[1].Views != 2 {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	none, err := stores.Metrics.GetEntryMetricsPage(ctx, nil, 0, 10)
+	if err != nil || len(none) != 0 {
+		t.Errorf("expected no metrics without IDs, got %+v, %v", none, err)
+	}
+}
+
+func TestUpsertSales(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	book, _, _ := seedScript(t, db)
+	sales := db.Stores().Sales
+
+	day := func(d int) models.Date { return models.Date{Time: time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC)} }
+	if _, err := sales.CreateSale(ctx, &models.BookSaleInput{BookID: book.ID, SaleDate: day(1), UnitsSold: 1, Royalty: 2.5}); err != nil {
+		t.Fatalf("create sale: %v", err)
+	}
+	if _, err := sales.CreateSale(ctx, &models.BookSaleInput{BookID: book.ID, SaleDate: day(2), UnitsSold: 1, Royalty: 2.5}); err != nil {
+		t.Fatalf("create sale: %v", err)
+	}
+
+	results, err := sales.UpsertSales(ctx, []*models.BookSaleInput{
+		{BookID: book.ID, SaleDate: day(1), UnitsSold: 1, Royalty: 2.5},  // unchanged
+		{BookID: book.ID, SaleDate: day(2), UnitsSold: 4, Royalty: 10},   // changed
+		{BookID: book.ID, SaleDate: day(3), UnitsSold: 2, PageReads: 50}, // new
+		{BookID: "no-such-book", SaleDate: day(3), UnitsSold: 1},
+		{BookID: book.ID, SaleDate: day(3), UnitsSold: 9}, // repeats row 3
+	})
+	if err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+
+	want := []repository.RowStatus{repository.RowDuplicate, repository.RowUpdated, repository.RowCreated, repository.RowFailed, repository.RowFailed}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("row %d status = %s, want %s (err: %v)", i, r.Status, want[i], r.Err)
+		}
+	}
+	if results[2].Row.PageReads != 50 {
+		t.Errorf("created row = %+v", results[2].Row)
+	}
+
+	stored, _ := sales.GetSalesByBook(ctx, book.ID, time.Time{}, time.Time{})
+	if len(stored) != 3 || stored[1].UnitsSold != 4 || stored[2].UnitsSold != 2 {
+		t.Errorf("stored sales = %+v", stored)
+	}
+}
+
+func TestCreateIdeas_KeepsGoodRows(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	results, err := db.Stores().Content.CreateIdeas(ctx, []*models.ContentIdeaInput{
+		{Type: "educational", BriefDescription: "a"},
+		{Type: "not-a-type", BriefDescription: "b"},
+		{Type: "trend", BriefDescription: "c"},
+	})
+	if err != nil {
+		t.Fatalf("create ideas: %v", err)
+	}
+	if got := repository.Summarize(results); got != (repository.BulkSummary{Created: 2, Failed: 1}) {
+		t.Errorf("summary = %+v, want 2 created and 1 failed", got)
+	}
+	if results[1].Err == nil || results[2].Row.BriefDescription != "c" {
+		t.Errorf("results = %+v", results)
+	}
+
+	ideas, _ := db.Stores().Content.GetIdeas(ctx, "", 0)
+	if len(ideas) != 2 {
+		t.Errorf("stored %d ideas, want 2", len(ideas))
+	}
+}
+
+func TestUsageLedger_KeepsRowsWhenContentIsDeleted(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	stores := db.Stores()
+	book, idea, script := seedScript(t, db)
+
+	kind := "educational"
+	row, err := stores.Usage.RecordUsage(ctx, &models.AIUsageInput{
+		Provider: "openai", Model: "gpt-4o-mini", PromptTokens: 1200, CompletionTokens: 300,
+		CostUSD: 0.00036, Command: "generate script", BookID: &book.ID, IdeaID: &idea.ID, ContentType: &kind,
+	})
+	if err != nil {
+		t.Fatalf("record usage: %v", err)
+	}
+	if err := stores.Usage.LinkUsageToScript(ctx, []string{row.ID}, script.ID); err != nil {
+		t.Fatalf("link usage: %v", err)
+	}
+
+	rows, err := stores.Usage.GetUsage(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("get usage: %v", err)
+	}
+	if len(rows) != 1 || rows[0].ScriptID == nil || *rows[0].ScriptID != script.ID || rows[0].CostUSD != 0.00036 {
+		t.Fatalf("rows = %+v", rows)
+	}
+
+	if err := stores.Books.Delete(ctx, book.ID); err != nil {
+		t.Fatalf("delete book: %v", err)
+	}
+	rows, err = stores.Usage.GetUsage(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("get usage: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows after deleting the book, want the row kept", len(rows))
+	}
+	if rows[0].BookID != nil || rows[0].IdeaID != nil || rows[0].ScriptID != nil {
+		t.Errorf("links = %v %v %v, want all null", rows[0].BookID, rows[0].IdeaID, rows[0].ScriptID)
+	}
+	if rows[0].ContentType == nil || *rows[0].ContentType != kind || rows[0].TotalTokens() != 1500 {
+		t.Errorf("row = %+v", rows[0])
+	}
+}
+
+func TestUpdatePublishState(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	_, _, script := seedScript(t, db)
+	calendar := db.Stores().Calendar
+
+	var entries []*models.ContentCalendar
+	for i := 0; i < 2; i++ {
+		entry, err := calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+			ScriptID: &script.ID, ScheduledFor: time.Now(), Platform: "tiktok", PostType: "reel",
+		})
+		if err != nil {
+			t.Fatalf("create entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	id := entries[0].ID
+
+	at := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	for _, msg := range []string{"first", "second"} {
+		err := calendar.UpdatePublishState(ctx, id, &models.PublishUpdate{
+			Status: "failed",
+			Error:  &models.PublishError{At: at, Stage: "submit", Message: msg},
+		})
+		if err != nil {
+			t.Fatalf("record error: %v", err)
+		}
+	}
+	sub, url := "sub-1", "https://tiktok.com/@me/video/1"
+	if err := calendar.UpdatePublishState(ctx, id, &models.PublishUpdate{
+		Status: "published", SubmissionID: &sub, Publisher: "tiktok", PostURL: &url, PublishedAt: &at,
+	}); err != nil {
+		t.Fatalf("record submission: %v", err)
+	}
+
+	got, err := calendar.GetEntryByID(ctx, id)
+	if err != nil {
+		t.Fatalf("get entry: %v", err)
+	}
+	if got.Status != "published" || *got.SubmissionID != sub || *got.Publisher != "tiktok" || *got.PostURL != url || !got.PublishedAt.Equal(at) {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+	if len(got.PublishErrors) != 2 || got.PublishErrors[0].Message != "first" || !got.PublishErrors[1].At.Equal(at) {
+		t.Errorf("publish_errors = %+v, want both attempts in order", got.PublishErrors)
+	}
+
+	// A submission ID belongs to one entry only
+	if err := calendar.UpdatePublishState(ctx, entries[1].ID, &models.PublishUpdate{SubmissionID: &sub}); err == nil {
+		t.Error("expected duplicate submission ID to be rejected")
+	}
+	if err := calendar.UpdatePublishState(ctx, "missing", &models.PublishUpdate{Status: "failed"}); err == nil {
+		t.Error("expected error for a missing entry")
+	}
+
+	// Clearing the submission stores NULL, which the unique index allows twice
+	none := ""
+	for _, e := range entries {
+		if err := calendar.UpdatePublishState(ctx, e.ID, &models.PublishUpdate{SubmissionID: &none}); err != nil {
+			t.Fatalf("clear submission: %v", err)
+		}
+	}
+	if got, _ := calendar.GetEntryByID(ctx, id); got.SubmissionID != nil || got.Publisher != nil {
+		t.Errorf("SubmissionID, Publisher = %v, %v; want nil", got.SubmissionID, got.Publisher)
+	}
+}
+
+func TestOpen_AddsColumnsToOlderFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// Recreate content_calendar as it was before submission_id
+	if _, err := db.sql.Exec(`DROP INDEX idx_calendar_submission; ALTER TABLE content_calendar DROP COLUMN submission_id`); err != nil {
+		t.Fatalf("downgrade: %v", err)
+	}
+	db.Close()
+
+	upgraded, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen older file: %v", err)
+	}
+	defer upgraded.Close()
+	if _, err := upgraded.Stores().Calendar.GetEntries(context.Background(), "", 0); err != nil {
+		t.Errorf("calendar unreadable after upgrade: %v", err)
+	}
+}
+
+func TestOpen_WidensChecksOfOlderFiles(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_, _, script := seedScript(t, db)
+	entry, err := db.Stores().Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+		ScriptID: &script.ID, ScheduledFor: time.Now(), Platform: "tiktok", PostType: "reel",
+	})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+	// Put back the status CHECK from before 'submitted' and the platform
+	// CHECK from before the publisher registry
+	var def string
+	if err := db.sql.QueryRow(`SELECT sql FROM sqlite_master WHERE name = 'content_calendar'`).Scan(&def); err != nil {
+		t.Fatalf("read definition: %v", err)
+	}
+	def = strings.Replace(def, "'submitted', ", "", 1)
+	def = strings.Replace(def, "platform TEXT NOT NULL,", "platform TEXT NOT NULL CHECK (platform IN ('instagram', 'tiktok')),", 1)
+	if _, err := db.sql.Exec(`PRAGMA writable_schema = ON`); err != nil {
+		t.Fatalf("downgrade: %v", err)
+	}
+	if _, err := db.sql.Exec(`UPDATE sqlite_master SET sql = ? WHERE name = 'content_calendar'`, def); err != nil {
+		t.Fatalf("downgrade: %v", err)
+	}
+	db.Close()
+
+	upgraded, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen older file: %v", err)
+	}
+	defer upgraded.Close()
+
+	calendar := upgraded.Stores().Calendar
+	if err := calendar.UpdateEntryStatus(ctx, entry.ID, "submitted"); err != nil {
+		t.Fatalf("submitted rejected after upgrade: %v", err)
+	}
+	got, err := calendar.GetEntryByID(ctx, entry.ID)
+	if err != nil || got.Status != "submitted" {
+		t.Errorf("entry after upgrade = %+v, %v", got, err)
+	}
+	if _, err := calendar.CreateEntry(ctx, &models.ContentCalendarInput{ScheduledFor: time.Now(), Platform: "bluesky", PostType: "feed"}); err != nil {
+		t.Errorf("new platform rejected after upgrade: %v", err)
+	}
+	// Foreign keys are back on and still hold
+	var fk int
+	if err := upgraded.sql.QueryRow(`PRAGMA foreign_keys`).Scan(&fk); err != nil || fk != 1 {
+		t.Errorf("foreign_keys = %d, %v; want 1", fk, err)
+	}
+	if rows, err := upgraded.sql.Query(`PRAGMA foreign_key_check`); err != nil {
+		t.Errorf("foreign_key_check: %v", err)
+	} else {
+		if rows.Next() {
+			t.Error("foreign key violations after rebuild")
+		}
+		rows.Close()
+	}
+}
+
+func TestCampaignTagsIdeasAndEntries(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	stores := db.Stores()
+	book, _, script := seedScript(t, db)
+
+	launch := models.Date{Time: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}
+	campaign, err := stores.Campaigns.CreateCampaign(ctx, &models.CampaignInput{BookID: book.ID, Name: "Launch", LaunchDate: launch})
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	if campaign.LaunchDate.String() != "2026-11-01" || campaign.CreatedAt.IsZero() {
+		t.Errorf("unexpected campaign: %+v", campaign)
+	}
+
+	idea, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{
+		Type: "bts", BriefDescription: "Teaser", BookID: &book.ID, CampaignID: &campaign.ID,
+	})
+	if err != nil {
+		t.Fatalf("create idea: %v", err)
+	}
+	entry, err := stores.Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+		ScriptID: &script.ID, ScheduledFor: time.Now(), Platform: "tiktok", PostType: "reel", CampaignID: &campaign.ID,
+	})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+	if idea.CampaignID == nil || *idea.CampaignID != campaign.ID || entry.CampaignID == nil || *entry.CampaignID != campaign.ID {
+		t.Errorf("campaign not kept: idea %v, entry %v", idea.CampaignID, entry.CampaignID)
+	}
+
+	missing := "missing"
+	if _, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{
+		Type: "bts", BriefDescription: "Orphan", CampaignID: &missing,
+	}); err == nil {
+		t.Error("expected an idea of an unknown campaign to fail")
+	}
+
+	if err := stores.Books.Delete(ctx, book.ID); err != nil {
+		t.Fatalf("delete book: %v", err)
+	}
+	var n int
+	if err := db.sql.QueryRow("SELECT COUNT(*) FROM campaigns").Scan(&n); err != nil || n != 0 {
+		t.Errorf("campaigns = %d (%v) after deleting the book, want 0", n, err)
+	}
+}
@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+)
+
+const usageColumns = `id, provider, model, prompt_tokens, completion_tokens, cost_usd, command,
+	book_id, idea_id, script_id, content_type, created_at`
+
+type usageStore struct {
+	db *DB
+}
+
+func (r *usageStore) RecordUsage(ctx context.Context, input *models.AIUsageInput) (*models.AIUsage, error) {
+	id := repository.NewID()
+	// cost_usd is DECIMAL(12,6) in Postgres
+	cost := math.Round(input.CostUSD*1e6) / 1e6
+
+	_, err := r.db.sql.ExecContext(ctx, `
+		INSERT INTO ai_usage (id, provider, model, prompt_tokens, completion_tokens, cost_usd, command,
+			book_id, idea_id, script_id, content_type, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, input.Provider, input.Model, input.PromptTokens, input.CompletionTokens, cost, input.Command,
+		input.BookID, input.IdeaID, input.ScriptID, input.ContentType, formatTime(r.db.timestamp()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record AI usage: %w", err)
+	}
+
+	row, err := scanUsage(r.db.sql.QueryRowContext(ctx, `SELECT `+usageColumns+` FROM ai_usage WHERE id = ?`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record AI usage: %w", err)
+	}
+	return &row, nil
+}
+
+func (r *usageStore) LinkUsageToScript(ctx context.Context, ids []string, scriptID string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := []any{scriptID}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	_, err := r.db.sql.ExecContext(ctx, `UPDATE ai_usage SET script_id = ? WHERE id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to link AI usage to script: %w", err)
+	}
+	return nil
+}
+
+func (r *usageStore) GetUsage(ctx context.Context, from, to time.Time) ([]models.AIUsage, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if !from.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, formatTime(from))
+	}
+	if !to.IsZero() {
+		where = append(where, "created_at <= ?")
+		args = append(args, formatTime(to))
+	}
+
+	query := `SELECT ` + usageColumns + ` FROM ai_usage`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at DESC, rowid DESC"
+
+	rows, err := r.db.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []models.AIUsage
+	for rows.Next() {
+		u, err := scanUsage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get AI usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+func scanUsage(row scanner) (models.AIUsage, error) {
+	var (
+		u                                     models.AIUsage
+		bookID, ideaID, scriptID, contentType sql.NullString
+		createdAt                             string
+	)
+	err := row.Scan(&u.ID, &u.Provider, &u.Model, &u.PromptTokens, &u.CompletionTokens, &u.CostUSD, &u.Command,
+		&bookID, &ideaID, &scriptID, &contentType, &createdAt)
+	if err != nil {
+		return u, err
+	}
+
+	u.BookID = nullableString(bookID)
+	u.IdeaID = nullableString(ideaID)
+	u.ScriptID = nullableString(scriptID)
+	u.ContentType = nullableString(contentType)
+	if u.CreatedAt, err = parseTime(createdAt); err != nil {
+		return u, err
+	}
+	return u, nil
+}
+
+// nullableString maps NULL to a nil pointer
+func nullableString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/postgrest"
+)
+
+// UsageRepository handles the AI usage ledger
+type UsageRepository struct {
+	db *postgrest.Client
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(cfg *config.SupabaseConfig) *UsageRepository {
+	return &UsageRepository{
+		db: postgrest.NewClient(cfg),
+	}
+}
+
+// RecordUsage adds an AI call to the ledger
+func (r *UsageRepository) RecordUsage(ctx context.Context, input *models.AIUsageInput) (*models.AIUsage, error) {
+	var rows []models.AIUsage
+	if err := r.db.From("ai_usage").Insert(ctx, input, &rows); err != nil {
+		return nil, fmt.Errorf("failed to record AI usage: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no usage row returned from API")
+	}
+
+	return &rows[0], nil
+}
+
+// LinkUsageToScript sets script_id on ledger rows recorded before the
+// script was saved.
+func (r *UsageRepository) LinkUsageToScript(ctx context.Context, ids []string, scriptID string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	body := map[string]string{"script_id": scriptID}
+	if err := r.db.From("ai_usage").In("id", ids).Update(ctx, body, nil); err != nil {
+		return fmt.Errorf("failed to link AI usage to script: %w", err)
+	}
+	return nil
+}
+
+// GetUsage retrieves every ledger row recorded between from and to (zero
+// times leave that side open), newest first.
+func (r *UsageRepository) GetUsage(ctx context.Context, from, to time.Time) ([]models.AIUsage, error) {
+	rows, err := collectPages(ctx, 0, func(ctx context.Context, offset, limit int) ([]models.AIUsage, error) {
+		q := r.db.From("ai_usage").
+			Select("*").
+			Order("created_at", false).
+			Order("id", false). // tie-breaker so pages do not overlap
+			Limit(limit).
+			Offset(offset)
+
+		if !from.IsZero() {
+			q.Gte("created_at", from.UTC().Format(time.RFC3339))
+		}
+		if !to.IsZero() {
+			q.Lte("created_at", to.UTC().Format(time.RFC3339))
+		}
+
+		var page []models.AIUsage
+		err := q.Get(ctx, &page)
+		return page, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI usage: %w", err)
+	}
+	return rows, nil
+}
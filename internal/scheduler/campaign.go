@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+)
+
+// Phases of a launch campaign
+const (
+	PhaseTeaser     = "teaser"
+	PhaseLaunch     = "launch"
+	PhaseReviewPush = "review_push"
+)
+
+// campaignTypes lists the idea types that fit each phase, in order of
+// preference
+var campaignTypes = map[string][]string{
+	PhaseTeaser:     {"bts", "trend", "entertainment"},
+	PhaseLaunch:     {"entertainment", "educational", "trend"},
+	PhaseReviewPush: {"ugc", "educational"},
+}
+
+// CampaignPhase is one phase of a launch campaign
+type CampaignPhase struct {
+	Name  string
+	Types []string // idea types that fit the phase
+	Days  []CampaignDay
+}
+
+// CampaignDay is a day of a campaign phase and how many posts it gets
+type CampaignDay struct {
+	Date  models.Date
+	Posts int
+}
+
+// Posts returns the number of posts of the phase
+func (p *CampaignPhase) Posts() int {
+	n := 0
+	for _, day := range p.Days {
+		n += day.Posts
+	}
+	return n
+}
+
+// CampaignPhases lays out a launch campaign: a teaser of teaserDays before
+// the launch, ramping up from 1 post a day to peak-1, peak posts on the
+// launch day, and a review push of reviewDays after it, tapering back down
+// to 1 post a day. A phase without days is left out.
+func CampaignPhases(launch models.Date, teaserDays, reviewDays, peak int) ([]CampaignPhase, error) {
+	if launch.IsZero() {
+		return nil, fmt.Errorf("launch date is required")
+	}
+	if teaserDays < 0 || reviewDays < 0 {
+		return nil, fmt.Errorf("phases cannot have a negative number of days")
+	}
+	if peak < 2 {
+		return nil, fmt.Errorf("launch day posts must be at least 2, got %d", peak)
+	}
+
+	day := func(offset int) models.Date {
+		return models.Date{Time: launch.AddDate(0, 0, offset)}
+	}
+
+	teaser := CampaignPhase{Name: PhaseTeaser, Types: campaignTypes[PhaseTeaser]}
+	for i, posts := range ramp(1, peak-1, teaserDays) {
+		teaser.Days = append(teaser.Days, CampaignDay{Date: day(i - teaserDays), Posts: posts})
+	}
+	launchDay := CampaignPhase{Name: PhaseLaunch, Types: campaignTypes[PhaseLaunch]}
+	launchDay.Days = []CampaignDay{{Date: day(0), Posts: peak}}
+	review := CampaignPhase{Name: PhaseReviewPush, Types: campaignTypes[PhaseReviewPush]}
+	for i, posts := range ramp(peak-1, 1, reviewDays) {
+		review.Days = append(review.Days, CampaignDay{Date: day(i + 1), Posts: posts})
+	}
+
+	var phases []CampaignPhase
+	for _, phase := range []CampaignPhase{teaser, launchDay, review} {
+		if len(phase.Days) > 0 {
+			phases = append(phases, phase)
+		}
+	}
+	return phases, nil
+}
+
+// ramp returns days values going evenly from from to to. A single day gets
+// to.
+func ramp(from, to, days int) []int {
+	values := make([]int, days)
+	for i := range values {
+		if days == 1 {
+			values[i] = to
+			continue
+		}
+		values[i] = from + int(math.Round(float64((to-from)*i)/float64(days-1)))
+	}
+	return values
+}
+
+// CampaignSlot is a post of a campaign, before it has a script
+type CampaignSlot struct {
+	Phase    string
+	Time     time.Time
+	Platform string
+}
+
+// ScheduleCampaign picks a time for every post of the phases, at the best
+// hours of its platform as GetPlatformTimes does. Platforms take turns post
+// by post. Days before tomorrow are past and left out; skipped counts
+// their posts. Slots come back in time order.
+func (o *Optimizer) ScheduleCampaign(phases []CampaignPhase, platforms []string) (slots []CampaignSlot, skipped int) {
+	if len(platforms) == 0 {
+		return nil, 0
+	}
+
+	// Campaign dates are UTC midnights, so days are whole multiples of 24h
+	now := o.now().In(o.Location(""))
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+
+	var dayPlatforms [][]string
+	var phaseOf []string // phase of each post, in GetPlatformTimes order
+	byDay := make(map[int][]string)
+	turn := 0
+	for _, phase := range phases {
+		for _, day := range phase.Days {
+			offset := int(day.Date.Sub(tomorrow).Hours() / 24)
+			for i := 0; i < day.Posts; i++ {
+				platform := platforms[turn%len(platforms)]
+				turn++
+				if offset < 0 {
+					skipped++
+					continue
+				}
+				for len(dayPlatforms) <= offset {
+					dayPlatforms = append(dayPlatforms, nil)
+				}
+				dayPlatforms[offset] = append(dayPlatforms[offset], platform)
+				byDay[offset] = append(byDay[offset], phase.Name)
+			}
+		}
+	}
+	for offset := range dayPlatforms {
+		phaseOf = append(phaseOf, byDay[offset]...)
+	}
+
+	for i, slot := range o.GetPlatformTimes(dayPlatforms) {
+		slots = append(slots, CampaignSlot{Phase: phaseOf[i], Time: slot.Time, Platform: slot.Platform})
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Time.Before(slots[j].Time)
+	})
+	return slots, skipped
+}
+
+// InterleaveIdeas orders ideas so that their types take turns, the most
+// relevant first within each type, so a phase does not post one type twice
+// in a row while another is left.
+func InterleaveIdeas(ideas []models.ContentIdea) []models.ContentIdea {
+	sorted := slices.Clone(ideas)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return relevance(&sorted[i]) > relevance(&sorted[j])
+	})
+
+	var types []string
+	byType := make(map[string][]models.ContentIdea)
+	for _, idea := range sorted {
+		if _, ok := byType[idea.Type]; !ok {
+			types = append(types, idea.Type)
+		}
+		byType[idea.Type] = append(byType[idea.Type], idea)
+	}
+
+	ordered := make([]models.ContentIdea, 0, len(ideas))
+	for len(ordered) < len(ideas) {
+		for _, t := range types {
+			if len(byType[t]) > 0 {
+				ordered = append(ordered, byType[t][0])
+				byType[t] = byType[t][1:]
+			}
+		}
+	}
+	return ordered
+}
+
+// relevance returns the relevance score of an idea, 0 if it has none
+func relevance(idea *models.ContentIdea) int {
+	if idea.RelevanceScore == nil {
+		return 0
+	}
+	return *idea.RelevanceScore
+}
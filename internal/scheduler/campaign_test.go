@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+)
+
+func mustDate(t *testing.T, s string) models.Date {
+	t.Helper()
+	d, err := time.Parse(models.DateFormat, s)
+	if err != nil {
+		t.Fatalf("parse %s: %v", s, err)
+	}
+	return models.Date{Time: d}
+}
+
+func TestCampaignPhases(t *testing.T) {
+	phases, err := CampaignPhases(mustDate(t, "2026-11-01"), 7, 7, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		name        string
+		first, last string
+		posts       []int
+	}{
+		{PhaseTeaser, "2026-10-25", "2026-10-31", []int{1, 1, 1, 2, 2, 2, 2}},
+		{PhaseLaunch, "2026-11-01", "2026-11-01", []int{3}},
+		{PhaseReviewPush, "2026-11-02", "2026-11-08", []int{2, 2, 2, 1, 1, 1, 1}},
+	}
+	if len(phases) != len(want) {
+		t.Fatalf("got %d phases, want %d", len(phases), len(want))
+	}
+	for i, w := range want {
+		p := phases[i]
+		var posts []int
+		for _, day := range p.Days {
+			posts = append(posts, day.Posts)
+		}
+		if p.Name != w.name || p.Days[0].Date.String() != w.first || p.Days[len(p.Days)-1].Date.String() != w.last || !slices.Equal(posts, w.posts) {
+			t.Errorf("phase %d = %s %s..%s %v, want %s %s..%s %v", i,
+				p.Name, p.Days[0].Date, p.Days[len(p.Days)-1].Date, posts, w.name, w.first, w.last, w.posts)
+		}
+		if len(p.Types) == 0 {
+			t.Errorf("phase %s has no idea types", p.Name)
+		}
+	}
+	if n := phases[0].Posts(); n != 11 {
+		t.Errorf("teaser posts = %d, want 11", n)
+	}
+}
+
+func TestCampaignPhases_Invalid(t *testing.T) {
+	launch := mustDate(t, "2026-11-01")
+	for name, fn := range map[string]func() error{
+		"no launch":      func() error { _, err := CampaignPhases(models.Date{}, 7, 7, 3); return err },
+		"negative days":  func() error { _, err := CampaignPhases(launch, -1, 7, 3); return err },
+		"peak too small": func() error { _, err := CampaignPhases(launch, 7, 7, 1); return err },
+	} {
+		if fn() == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// Without teaser and review days only the launch day is left
+	phases, err := CampaignPhases(launch, 0, 0, 2)
+	if err != nil || len(phases) != 1 || phases[0].Name != PhaseLaunch {
+		t.Errorf("phases = %+v, %v; want the launch day only", phases, err)
+	}
+}
+
+func TestOptimizer_ScheduleCampaign(t *testing.T) {
+	rome := mustLocation(t, "Europe/Rome")
+	optimizer := NewOptimizer()
+	if err := optimizer.SetTimezones("Europe/Rome", nil); err != nil {
+		t.Fatalf("set timezones: %v", err)
+	}
+	// Three days before the launch: the first teaser days are past
+	optimizer.now = func() time.Time { return time.Date(2026, 10, 29, 12, 0, 0, 0, rome) }
+
+	phases, err := CampaignPhases(mustDate(t, "2026-11-01"), 7, 2, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slots, skipped := optimizer.ScheduleCampaign(phases, []string{"tiktok", "instagram"})
+
+	// Oct 25-29 are past: 1+1+1+2+2 teaser posts
+	if skipped != 7 {
+		t.Errorf("skipped = %d, want 7", skipped)
+	}
+	// Oct 30-31 (2+2), the launch day (3) and the review push (2+1)
+	if len(slots) != 10 {
+		t.Fatalf("got %d slots, want 10", len(slots))
+	}
+
+	perDay := make(map[string]int)
+	platforms := make(map[string]int)
+	for i, slot := range slots {
+		if i > 0 && slot.Time.Before(slots[i-1].Time) {
+			t.Errorf("slot %d at %s is before the previous one", i, slot.Time)
+		}
+		day := slot.Time.In(rome).Format(models.DateFormat)
+		perDay[day]++
+		platforms[slot.Platform]++
+
+		wantPhase := PhaseTeaser
+		switch {
+		case day == "2026-11-01":
+			wantPhase = PhaseLaunch
+		case day > "2026-11-01":
+			wantPhase = PhaseReviewPush
+		}
+		if slot.Phase != wantPhase {
+			t.Errorf("slot on %s is in phase %s, want %s", day, slot.Phase, wantPhase)
+		}
+	}
+	if perDay["2026-11-01"] != 3 || perDay["2026-10-30"] != 2 || perDay["2026-11-03"] != 1 {
+		t.Errorf("posts per day = %v", perDay)
+	}
+	if platforms["tiktok"] != 5 || platforms["instagram"] != 5 {
+		t.Errorf("platforms = %v, want them to take turns", platforms)
+	}
+}
+
+func TestInterleaveIdeas(t *testing.T) {
+	score := func(n int) *int { return &n }
+	ideas := []models.ContentIdea{
+		{ID: "a", Type: "bts", RelevanceScore: score(90)},
+		{ID: "b", Type: "bts", RelevanceScore: score(80)},
+		{ID: "c", Type: "bts", RelevanceScore: score(70)},
+		{ID: "d", Type: "trend", RelevanceScore: score(85)},
+		{ID: "e", Type: "entertainment"},
+	}
+
+	var ids []string
+	for _, idea := range InterleaveIdeas(ideas) {
+		ids = append(ids, idea.ID)
+	}
+	if want := []string{"a", "d", "e", "b", "c"}; !slices.Equal(ids, want) {
+		t.Errorf("order = %v, want %v", ids, want)
+	}
+}
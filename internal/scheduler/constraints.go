@@ -0,0 +1,286 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"go.yaml.in/yaml/v3"
+)
+
+// Constraints are rules a plan must follow, read from a YAML file such as:
+//
+//	blackouts:
+//	  - name: no Sundays
+//	    weekdays: [sunday]
+//	  - name: TikTok only on weekdays
+//	    platforms: [tiktok]
+//	    weekdays: [saturday, sunday]
+//	  - name: holidays
+//	    from: 2026-12-24
+//	    to: 2026-12-26
+//	spacing:
+//	  min_hours: 3
+//	caps:
+//	  per_book_per_day: 1
+//	  platforms:
+//	    tiktok: {per_day: 1, per_week: 5}
+//	quotas:
+//	  - name: launch week
+//	    book: 3f2a9c1e
+//	    from: 2026-11-02
+//	    to: 2026-11-08
+//	    posts: 3
+//
+// Blackouts, spacing and caps are never broken; quotas and the number of
+// posts per day are met where they allow.
+type Constraints struct {
+	Blackouts []Blackout `yaml:"blackouts"`
+	Spacing   Spacing    `yaml:"spacing"`
+	Caps      Caps       `yaml:"caps"`
+	Quotas    []Quota    `yaml:"quotas"`
+}
+
+// Blackout is a window without posts. Each field narrows it down, so a
+// blackout with only weekdays covers those days on every platform.
+type Blackout struct {
+	Name      string   `yaml:"name"`
+	From      string   `yaml:"from"`      // first day, YYYY-MM-DD
+	To        string   `yaml:"to"`        // last day, YYYY-MM-DD
+	Weekdays  []string `yaml:"weekdays"`  // e.g. [saturday, sunday]
+	Hours     []int    `yaml:"hours"`     // e.g. [0, 1, 2, 3, 4, 5]
+	Platforms []string `yaml:"platforms"` // empty for all
+
+	weekdays []time.Weekday
+}
+
+// Spacing is the minimum time between posts
+type Spacing struct {
+	MinHours      float64 `yaml:"min_hours"`       // between any two posts
+	SameBookHours float64 `yaml:"same_book_hours"` // between posts of one book
+}
+
+// Caps limit the number of posts. Zero means no limit.
+type Caps struct {
+	PerDay        int                    `yaml:"per_day"`
+	PerBookPerDay int                    `yaml:"per_book_per_day"`
+	Platforms     map[string]PlatformCap `yaml:"platforms"`
+}
+
+// PlatformCap limits the posts on a platform
+type PlatformCap struct {
+	PerDay  int `yaml:"per_day"`
+	PerWeek int `yaml:"per_week"` // Monday to Sunday
+}
+
+// Quota asks for a minimum number of posts of a book within a window
+type Quota struct {
+	Name      string   `yaml:"name"`
+	Book      string   `yaml:"book"`      // book ID or ID prefix
+	From      string   `yaml:"from"`      // first day, YYYY-MM-DD; default the first planned day
+	To        string   `yaml:"to"`        // last day, YYYY-MM-DD; default the last planned day
+	Posts     int      `yaml:"posts"`     // at least this many posts
+	Platforms []string `yaml:"platforms"` // count only posts on these platforms
+}
+
+// LoadConstraints reads and validates a constraints file. A leading "~/" is
+// expanded to the home directory. Unknown keys are errors, so typos do not
+// silently drop a rule.
+func LoadConstraints(path string) (*Constraints, error) {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get home directory: %w", err)
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read constraints: %w", err)
+	}
+	return ParseConstraints(data)
+}
+
+// ParseConstraints parses and validates constraints in YAML
+func ParseConstraints(data []byte) (*Constraints, error) {
+	var c Constraints
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&c); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid constraints: %w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Validate checks the constraints and prepares them for matching
+func (c *Constraints) Validate() error {
+	for i := range c.Blackouts {
+		b := &c.Blackouts[i]
+		if err := validateWindow(b.From, b.To); err != nil {
+			return fmt.Errorf("%s: %w", b.label(i), err)
+		}
+		if err := validatePlatforms(b.Platforms); err != nil {
+			return fmt.Errorf("%s: %w", b.label(i), err)
+		}
+		b.weekdays = nil
+		for _, name := range b.Weekdays {
+			day, err := parseWeekday(name)
+			if err != nil {
+				return fmt.Errorf("%s: %w", b.label(i), err)
+			}
+			b.weekdays = append(b.weekdays, day)
+		}
+		for _, hour := range b.Hours {
+			if hour < 0 || hour > 23 {
+				return fmt.Errorf("%s: hour %d is not between 0 and 23", b.label(i), hour)
+			}
+		}
+		if b.From == "" && b.To == "" && len(b.Weekdays) == 0 && len(b.Hours) == 0 && len(b.Platforms) == 0 {
+			return fmt.Errorf("%s: covers every slot; set from/to, weekdays, hours or platforms", b.label(i))
+		}
+	}
+
+	if c.Spacing.MinHours < 0 || c.Spacing.SameBookHours < 0 {
+		return fmt.Errorf("spacing: hours cannot be negative")
+	}
+	if c.Caps.PerDay < 0 || c.Caps.PerBookPerDay < 0 {
+		return fmt.Errorf("caps: limits cannot be negative")
+	}
+	for platform, limit := range c.Caps.Platforms {
+		if err := models.ValidatePlatform(platform); err != nil {
+			return fmt.Errorf("caps.platforms: %w", err)
+		}
+		if limit.PerDay < 0 || limit.PerWeek < 0 {
+			return fmt.Errorf("caps.platforms.%s: limits cannot be negative", platform)
+		}
+	}
+
+	for i := range c.Quotas {
+		q := &c.Quotas[i]
+		if q.Book == "" {
+			return fmt.Errorf("%s: book is required", q.label(i))
+		}
+		if q.Posts <= 0 {
+			return fmt.Errorf("%s: posts must be positive", q.label(i))
+		}
+		if err := validateWindow(q.From, q.To); err != nil {
+			return fmt.Errorf("%s: %w", q.label(i), err)
+		}
+		if err := validatePlatforms(q.Platforms); err != nil {
+			return fmt.Errorf("%s: %w", q.label(i), err)
+		}
+	}
+	return nil
+}
+
+// ResolveBooks replaces the book ID prefixes of quotas with full IDs
+func (c *Constraints) ResolveBooks(ctx context.Context, books repository.BookStore) error {
+	for i := range c.Quotas {
+		book, err := books.GetBookByIDPrefix(ctx, c.Quotas[i].Book)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.Quotas[i].label(i), err)
+		}
+		c.Quotas[i].Book = book.ID
+	}
+	return nil
+}
+
+// covers reports whether the blackout covers a post on platform at t, in
+// the audience's timezone. A blackout limited to platforms covers no post
+// whose platform is not known yet.
+func (b *Blackout) covers(platform string, t time.Time) bool {
+	if len(b.Platforms) > 0 && !slices.Contains(b.Platforms, platform) {
+		return false
+	}
+	if !inWindow(t, b.From, b.To) {
+		return false
+	}
+	if len(b.weekdays) > 0 && !slices.Contains(b.weekdays, t.Weekday()) {
+		return false
+	}
+	if len(b.Hours) > 0 && !slices.Contains(b.Hours, t.Hour()) {
+		return false
+	}
+	return true
+}
+
+// label names the blackout in messages
+func (b *Blackout) label(i int) string {
+	if b.Name != "" {
+		return fmt.Sprintf("blackout %q", b.Name)
+	}
+	return fmt.Sprintf("blackouts[%d]", i)
+}
+
+// counts reports whether a post of a book on platform at t counts toward
+// the quota
+func (q *Quota) counts(bookID, platform string, t time.Time) bool {
+	if bookID != q.Book || !inWindow(t, q.From, q.To) {
+		return false
+	}
+	return len(q.Platforms) == 0 || slices.Contains(q.Platforms, platform)
+}
+
+// label names the quota in messages
+func (q *Quota) label(i int) string {
+	if q.Name != "" {
+		return fmt.Sprintf("quota %q", q.Name)
+	}
+	return fmt.Sprintf("quotas[%d]", i)
+}
+
+// inWindow reports whether the day of t is between from and to, both
+// YYYY-MM-DD and inclusive; an empty bound is open
+func inWindow(t time.Time, from, to string) bool {
+	day := t.Format(models.DateFormat)
+	return (from == "" || day >= from) && (to == "" || day <= to)
+}
+
+// validateWindow checks the bounds of a window of days
+func validateWindow(from, to string) error {
+	for _, day := range []string{from, to} {
+		if day == "" {
+			continue
+		}
+		if _, err := time.Parse(models.DateFormat, day); err != nil {
+			return fmt.Errorf("invalid date %q, want YYYY-MM-DD", day)
+		}
+	}
+	if from != "" && to != "" && to < from {
+		return fmt.Errorf("window ends (%s) before it starts (%s)", to, from)
+	}
+	return nil
+}
+
+// validatePlatforms checks a list of platforms
+func validatePlatforms(platforms []string) error {
+	for _, platform := range platforms {
+		if err := models.ValidatePlatform(platform); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseWeekday parses a weekday name, full or abbreviated, in any case
+func parseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %q", name)
+}
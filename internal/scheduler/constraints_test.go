@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const exampleConstraints = `
+blackouts:
+  - name: no Sundays
+    weekdays: [sunday]
+  - name: TikTok only on weekdays
+    platforms: [tiktok]
+    weekdays: [Sat, sun]
+  - name: holidays
+    from: 2026-12-24
+    to: 2026-12-26
+  - hours: [0, 1, 2, 3, 4, 5]
+spacing:
+  min_hours: 3
+caps:
+  per_book_per_day: 1
+  platforms:
+    tiktok: {per_day: 1, per_week: 5}
+quotas:
+  - name: launch week
+    book: 3f2a9c1e
+    from: 2026-11-02
+    to: 2026-11-08
+    posts: 3
+`
+
+func TestParseConstraints(t *testing.T) {
+	c, err := ParseConstraints([]byte(exampleConstraints))
+	if err != nil {
+		t.Fatalf("ParseConstraints() error = %v", err)
+	}
+	if len(c.Blackouts) != 4 || c.Spacing.MinHours != 3 || c.Caps.Platforms["tiktok"].PerWeek != 5 || c.Quotas[0].Posts != 3 {
+		t.Errorf("constraints = %+v", c)
+	}
+
+	saturday := time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		blackout int
+		platform string
+		at       time.Time
+		want     bool
+	}{
+		{0, "instagram", saturday.AddDate(0, 0, 1), true},
+		{0, "instagram", saturday, false},
+		{1, "tiktok", saturday, true},
+		{1, "instagram", saturday, false},
+		{1, "", saturday, false}, // the platform is not known yet
+		{2, "bluesky", time.Date(2026, 12, 26, 23, 0, 0, 0, time.UTC), true},
+		{2, "bluesky", time.Date(2026, 12, 27, 0, 0, 0, 0, time.UTC), false},
+		{3, "", time.Date(2026, 3, 7, 5, 30, 0, 0, time.UTC), true},
+		{3, "", time.Date(2026, 3, 7, 6, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := c.Blackouts[tt.blackout].covers(tt.platform, tt.at); got != tt.want {
+			t.Errorf("%s covers(%q, %v) = %v, want %v", c.Blackouts[tt.blackout].label(tt.blackout), tt.platform, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestParseConstraints_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"blackouts: [{weekdays: [sunday]}]\nspacing: {min_hour: 3}": "field min_hour not found",
+		"blackouts: [{weekdays: [someday]}]":                        `unknown weekday "someday"`,
+		"blackouts: [{name: x}]":                                    "covers every slot",
+		"blackouts: [{from: 2026-05-02, to: 2026-05-01}]":           "ends (2026-05-01) before it starts",
+		"blackouts: [{hours: [24]}]":                                "hour 24",
+		"caps: {platforms: {myspace: {per_day: 1}}}":                "myspace",
+		"quotas: [{name: launch, posts: 3}]":                        `quota "launch": book is required`,
+		"quotas: [{book: abc, posts: 3, from: 2026-13-01}]":         "invalid date",
+	}
+	for input, want := range tests {
+		_, err := ParseConstraints([]byte(input))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseConstraints(%q) error = %v, want %q", input, err, want)
+		}
+	}
+
+	if c, err := ParseConstraints(nil); err != nil || len(c.Blackouts) != 0 {
+		t.Errorf("ParseConstraints(empty) = %+v, %v, want no constraints", c, err)
+	}
+}
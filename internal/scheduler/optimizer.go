@@ -1,12 +1,42 @@
 package scheduler
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
 	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
 )
 
-// Optimizer handles posting time optimization
+// DefaultHistoryDays is how far back the optimizer looks for published posts
+const DefaultHistoryDays = 90
+
+const (
+	// priorStrength is how many posts the defaults count for. A slot needs
+	// about this many posts before its own data outweighs them.
+	priorStrength = 5.0
+
+	// minSlotGap is the minimum number of hours between posts on one day,
+	// when the day has room for it
+	minSlotGap = 2
+)
+
+// Default peak times for TikTok/Instagram Reels
+// Based on industry research:
+// - TikTok: 6-10am, 7-11pm
+// - Instagram: 11am-2pm, 7-9pm
+var peakHours = []int{7, 12, 19, 21}
+
+// Optimizer handles posting time optimization. Hours are the audience's
+// wall-clock hours, in the timezone of each platform.
 type Optimizer struct {
 	historicalData map[string][]MetricPoint
+	location       *time.Location
+	locations      map[string]*time.Location
+	now            func() time.Time
 }
 
 // MetricPoint represents historical performance data
@@ -16,55 +46,113 @@ type MetricPoint struct {
 	EngagementRate float64
 }
 
+// HeatmapCell is the engagement learned for one hour of one weekday
+type HeatmapCell struct {
+	Posts int     // posts published in this slot
+	Mean  float64 // their mean engagement rate
+	Score float64 // Mean shrunk toward the default, used for ranking
+}
+
+// Heatmap is the engagement of a platform by weekday and hour. Each cell's
+// score is a Bayesian average of its posts and a prior from the default
+// peak hours, so sparse slots stay close to the defaults.
+type Heatmap struct {
+	Platform string
+	Posts    int
+	Baseline float64 // mean engagement rate of all posts, the prior's scale
+	Cells    [7][24]HeatmapCell
+}
+
 // NewOptimizer creates a new posting time optimizer
 func NewOptimizer() *Optimizer {
 	return &Optimizer{
 		historicalData: make(map[string][]MetricPoint),
+		location:       time.Local,
+		now:            time.Now,
 	}
 }
 
+// SetTimezones sets the audience timezone, as an IANA name such as
+// "America/New_York", and the timezones of platforms whose audience lives
+// elsewhere. An empty timezone keeps the local one.
+func (o *Optimizer) SetTimezones(timezone string, platformTimezones map[string]string) error {
+	location := time.Local
+	if timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+	}
+
+	locations := make(map[string]*time.Location)
+	for platform, name := range platformTimezones {
+		if err := models.ValidatePlatform(platform); err != nil {
+			return err
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return fmt.Errorf("invalid timezone %q for %s: %w", name, platform, err)
+		}
+		locations[platform] = loc
+	}
+
+	o.location, o.locations = location, locations
+	return nil
+}
+
+// Location returns the audience timezone of a platform, or the default one
+// for an empty platform
+func (o *Optimizer) Location(platform string) *time.Location {
+	if loc, ok := o.locations[platform]; ok {
+		return loc
+	}
+	return o.location
+}
+
 // GetOptimalTimes returns optimal posting times for a period
 func (o *Optimizer) GetOptimalTimes(days int, postsPerDay int) []TimeSlot {
-	var slots []TimeSlot
+	dayPlatforms := make([][]string, days)
+	for day := range dayPlatforms {
+		dayPlatforms[day] = make([]string, postsPerDay)
+	}
+
+	slots := o.GetPlatformTimes(dayPlatforms)
+	for i := range slots {
+		// Determine platform based on time
+		slots[i].Platform = "tiktok"
+		if hour := slots[i].Time.Hour(); hour >= 11 && hour <= 14 {
+			slots[i].Platform = "instagram" // Lunch time better for Instagram
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Time.Before(slots[j].Time)
+	})
 
-	// Start from next day at midnight
-	startDate := time.Now().AddDate(0, 0, 1)
-	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
-
-	// Default peak times for TikTok/Instagram Reels
-	// Based on industry research:
-	// - TikTok: 6-10am, 7-11pm
-	// - Instagram: 11am-2pm, 7-9pm
-	peakHours := []int{7, 12, 19, 21}
-
-	for day := 0; day < days; day++ {
-		currentDate := startDate.AddDate(0, 0, day)
-
-		// Distribute posts throughout the day using peak hours
-		for i := 0; i < postsPerDay; i++ {
-			hourIndex := i % len(peakHours)
-			hour := peakHours[hourIndex]
-
-			// Add some variation to avoid exact same time every day
-			minuteVariation := (day * 7) % 60 // 0-60 minutes variation
-
-			postTime := time.Date(
-				currentDate.Year(),
-				currentDate.Month(),
-				currentDate.Day(),
-				hour,
-				minuteVariation,
-				0,
-				0,
-				currentDate.Location(),
-			)
-
-			// Determine platform based on time
-			platform := "tiktok"
-			if hour >= 11 && hour <= 14 {
-				platform = "instagram" // Lunch time better for Instagram
+	return slots
+}
+
+// GetPlatformTimes returns posting times for consecutive days starting
+// tomorrow. dayPlatforms lists the platform of each post of each day; the
+// slots come back in the same order, each at the best free hour of its
+// platform's heatmap. An empty platform uses the data of all platforms.
+//
+// Days and hours are counted on the wall clock of each platform's
+// audience, so a 19:00 slot stays at 19:00 across DST changes, and hours
+// that a DST change skips are not used.
+func (o *Optimizer) GetPlatformTimes(dayPlatforms [][]string) []TimeSlot {
+	heatmaps := make(map[string]*Heatmap)
+	var slots []TimeSlot
+	for day, platforms := range dayPlatforms {
+		var taken []time.Time
+		for _, platform := range platforms {
+			heatmap, ok := heatmaps[platform]
+			if !ok {
+				heatmap = o.Heatmap(platform)
+				heatmaps[platform] = heatmap
 			}
 
+			postTime, _ := o.pickSlot(heatmap, day, taken, nil)
+			taken = append(taken, postTime)
 			slots = append(slots, TimeSlot{
 				Time:     postTime,
 				Platform: platform,
@@ -76,33 +164,221 @@ func (o *Optimizer) GetOptimalTimes(days int, postsPerDay int) []TimeSlot {
 	return slots
 }
 
-// AnalyzeHistoricalData analyzes past performance to optimize times
+// pickSlot returns the time of a post on the given day after tomorrow, at
+// the best hour of the heatmap in its platform's timezone, preferably at
+// least minSlotGap hours from the day's taken times. check, if not nil,
+// returns why a time is not allowed, or "" if it is; when it rules out
+// every hour, pickSlot returns the zero time and the reasons.
+func (o *Optimizer) pickSlot(heatmap *Heatmap, day int, taken []time.Time, check func(time.Time) string) (time.Time, []string) {
+	// Start from next day at midnight, in the audience's timezone
+	loc := o.Location(heatmap.Platform)
+	now := o.now().In(loc)
+	date := time.Date(now.Year(), now.Month(), now.Day()+1+day, 0, 0, 0, 0, loc)
+
+	// Add some variation to avoid exact same time every day
+	minuteVariation := (day * 7) % 60
+
+	var reasons []string
+	for _, gap := range []time.Duration{minSlotGap * time.Hour, time.Hour} {
+		for _, hour := range heatmap.BestHours(date.Weekday(), 24) {
+			t := time.Date(date.Year(), date.Month(), date.Day(), hour, minuteVariation, 0, 0, loc)
+			if t.Hour() != hour {
+				continue // the hour does not exist on this day
+			}
+			if slices.ContainsFunc(taken, func(other time.Time) bool {
+				return t.Sub(other) < gap && other.Sub(t) < gap
+			}) {
+				continue
+			}
+			if check != nil {
+				if reason := check(t); reason != "" {
+					if !slices.Contains(reasons, reason) {
+						reasons = append(reasons, reason)
+					}
+					continue
+				}
+			}
+			return t, nil
+		}
+	}
+	if check != nil {
+		return time.Time{}, reasons
+	}
+
+	// The day is too full for distinct hours
+	hour := heatmap.BestHours(date.Weekday(), 1)[0]
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minuteVariation, 0, 0, loc), nil
+}
+
+// AnalyzeHistoricalData records past performance of a platform, replacing
+// what was recorded before
 func (o *Optimizer) AnalyzeHistoricalData(platform string, metrics []MetricPoint) {
 	o.historicalData[platform] = metrics
+}
+
+// LoadHistory analyzes the posts published since the given time (a zero
+// time means every post), using the latest metrics snapshot of each post
+// and the hour it was scheduled for. It returns the number of posts
+// analyzed.
+func (o *Optimizer) LoadHistory(ctx context.Context, calendarRepo repository.CalendarStore, metricsRepo repository.MetricsStore, since time.Time) (int, error) {
+	var (
+		entries []models.ContentCalendar
+		ids     []string
+	)
+	for entry, err := range repository.IterEntries(ctx, calendarRepo, "published") {
+		if err != nil {
+			return 0, fmt.Errorf("failed to get published entries: %w", err)
+		}
+		publishedAt := entry.ScheduledFor
+		if entry.PublishedAt != nil {
+			publishedAt = *entry.PublishedAt
+		}
+		if publishedAt.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+		ids = append(ids, entry.ID)
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	var metrics []models.PostMetric
+	for m, err := range repository.IterEntryMetrics(ctx, metricsRepo, ids) {
+		if err != nil {
+			return 0, fmt.Errorf("failed to get metrics: %w", err)
+		}
+		metrics = append(metrics, m)
+	}
 
-	// In a real implementation, this would:
-	// 1. Group metrics by hour and day of week
-	// 2. Calculate average engagement for each time slot
-	// 3. Identify top-performing times
-	// 4. Return personalized peak times
+	points := HistoryPoints(entries, metrics, o.Location)
+	total := 0
+	for platform, platformPoints := range points {
+		o.AnalyzeHistoricalData(platform, platformPoints)
+		total += len(platformPoints)
+	}
+	return total, nil
 }
 
-// GetPeakTimes returns the best times based on historical data
-func (o *Optimizer) GetPeakTimes(platform string, count int) []time.Time {
-	// If we have historical data, use it
-	if data, ok := o.historicalData[platform]; ok && len(data) > 0 {
-		// Sort by engagement rate
-		// Return top N times
-		_ = data // Use historical data in real implementation
+// Platforms returns the platforms with historical data, sorted
+func (o *Optimizer) Platforms() []string {
+	var platforms []string
+	for platform, points := range o.historicalData {
+		if len(points) > 0 {
+			platforms = append(platforms, platform)
+		}
 	}
+	sort.Strings(platforms)
+	return platforms
+}
 
-	// Default peak times
-	var times []time.Time
-	peakHours := []int{7, 12, 19, 21}
+// HistoryPoints joins the latest metrics snapshot of each post with the
+// weekday and hour the post was scheduled for, in the timezone location
+// returns for its platform, grouped by platform. Posts without views carry
+// no engagement rate and are left out.
+func HistoryPoints(entries []models.ContentCalendar, metrics []models.PostMetric, location func(platform string) *time.Location) map[string][]MetricPoint {
+	scheduled := make(map[string]time.Time, len(entries))
+	for _, entry := range entries {
+		scheduled[entry.ID] = entry.ScheduledFor
+	}
+
+	points := make(map[string][]MetricPoint)
+	for _, m := range repository.LatestMetrics(metrics) {
+		at, ok := scheduled[m.CalendarID]
+		if !ok || m.Views == 0 {
+			continue
+		}
+		at = at.In(location(m.Platform))
+		points[m.Platform] = append(points[m.Platform], MetricPoint{
+			Hour:           at.Hour(),
+			DayOfWeek:      at.Weekday(),
+			EngagementRate: m.EngagementRate,
+		})
+	}
+	return points
+}
+
+// Heatmap builds the engagement heatmap of a platform from the analyzed
+// data, or of all platforms for an empty platform. Without data every
+// score is the default.
+func (o *Optimizer) Heatmap(platform string) *Heatmap {
+	var data []MetricPoint
+	if platform == "" {
+		for _, points := range o.historicalData {
+			data = append(data, points...)
+		}
+	} else {
+		data = o.historicalData[platform]
+	}
+
+	h := &Heatmap{Platform: platform, Baseline: 1}
+	var sums [7][24]float64
+	var total float64
+	for _, point := range data {
+		if point.Hour < 0 || point.Hour > 23 || point.DayOfWeek < time.Sunday || point.DayOfWeek > time.Saturday {
+			continue
+		}
+		h.Posts++
+		h.Cells[point.DayOfWeek][point.Hour].Posts++
+		sums[point.DayOfWeek][point.Hour] += point.EngagementRate
+		total += point.EngagementRate
+	}
+	if total > 0 {
+		h.Baseline = total / float64(h.Posts)
+	}
+
+	for day := range h.Cells {
+		for hour := range h.Cells[day] {
+			cell := &h.Cells[day][hour]
+			prior := h.Baseline * defaultWeight(hour)
+			if cell.Posts > 0 {
+				cell.Mean = sums[day][hour] / float64(cell.Posts)
+			}
+			cell.Score = (float64(cell.Posts)*cell.Mean + priorStrength*prior) / (float64(cell.Posts) + priorStrength)
+		}
+	}
+	return h
+}
+
+// BestHours returns the count best hours of a weekday, best first. Ties go
+// to the earlier hour.
+func (h *Heatmap) BestHours(day time.Weekday, count int) []int {
+	hours := make([]int, 24)
+	for hour := range hours {
+		hours[hour] = hour
+	}
+	cells := h.Cells[day]
+	sort.SliceStable(hours, func(i, j int) bool {
+		return cells[hours[i]].Score > cells[hours[j]].Score
+	})
+	return hours[:min(count, len(hours))]
+}
+
+// defaultWeight is the prior engagement of an hour relative to the
+// baseline: peak hours do best and the small hours worst
+func defaultWeight(hour int) float64 {
+	for _, peak := range peakHours {
+		if hour == peak {
+			return 1.2
+		}
+	}
+	if hour < 6 || hour > 22 {
+		return 0.6
+	}
+	return 1.0
+}
 
-	now := time.Now()
+// GetPeakTimes returns today's best posting times for a platform, based on
+// historical data where there is enough of it
+func (o *Optimizer) GetPeakTimes(platform string, count int) []time.Time {
+	now := o.now().In(o.Location(platform))
+	heatmap := o.Heatmap(platform)
+
+	hours := heatmap.BestHours(now.Weekday(), count)
+
+	var times []time.Time
 	for i := 0; i < count; i++ {
-		hour := peakHours[i%len(peakHours)]
+		hour := hours[i%len(hours)]
 		t := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
 		times = append(times, t)
 	}
@@ -129,3 +405,54 @@ func DefaultMixStrategy() ContentMixStrategy {
 		Trend:         0.15,
 	}
 }
+
+// ParseMixStrategy builds a strategy from shares by idea type. Shares are
+// relative and scaled to add up to one; types left out get none.
+func ParseMixStrategy(shares map[string]float64) (ContentMixStrategy, error) {
+	var strategy ContentMixStrategy
+	var total float64
+	for ideaType, share := range shares {
+		field := strategy.field(ideaType)
+		if field == nil {
+			return ContentMixStrategy{}, fmt.Errorf("unknown content type %q in content mix (want educational, entertainment, bts, ugc or trend)", ideaType)
+		}
+		if share < 0 {
+			return ContentMixStrategy{}, fmt.Errorf("negative share %v for %s in content mix", share, ideaType)
+		}
+		*field = share
+		total += share
+	}
+	if total == 0 {
+		return ContentMixStrategy{}, fmt.Errorf("content mix has no positive shares")
+	}
+
+	for _, ideaType := range []string{"educational", "entertainment", "bts", "ugc", "trend"} {
+		*strategy.field(ideaType) /= total
+	}
+	return strategy, nil
+}
+
+// Share returns the share of an idea type, or zero for an unknown type
+func (s ContentMixStrategy) Share(ideaType string) float64 {
+	if field := s.field(ideaType); field != nil {
+		return *field
+	}
+	return 0
+}
+
+// field returns the share field of an idea type, or nil for an unknown type
+func (s *ContentMixStrategy) field(ideaType string) *float64 {
+	switch ideaType {
+	case "educational":
+		return &s.Educational
+	case "entertainment":
+		return &s.Entertainment
+	case "bts":
+		return &s.BTS
+	case "ugc":
+		return &s.UGC
+	case "trend":
+		return &s.Trend
+	}
+	return nil
+}
@@ -1,8 +1,13 @@
 package scheduler
 
 import (
+	"context"
 	"testing"
 	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/repository/memory"
 )
 
 func TestOptimizer_GetOptimalTimes(t *testing.T) {
@@ -165,3 +170,345 @@ func TestOptimizer_AnalyzeHistoricalData(t *testing.T) {
 		t.Errorf("Expected 3 data points stored, got %d", len(optimizer.historicalData["tiktok"]))
 	}
 }
+
+// repeatPoints returns n posts at the same hour with the same engagement
+func repeatPoints(n, hour int, day time.Weekday, rate float64) []MetricPoint {
+	points := make([]MetricPoint, n)
+	for i := range points {
+		points[i] = MetricPoint{Hour: hour, DayOfWeek: day, EngagementRate: rate}
+	}
+	return points
+}
+
+func TestHeatmap_DefaultsWithoutData(t *testing.T) {
+	heatmap := NewOptimizer().Heatmap("tiktok")
+
+	got := heatmap.BestHours(time.Wednesday, 4)
+	for i, hour := range peakHours {
+		if got[i] != hour {
+			t.Fatalf("BestHours() = %v, want the default peak hours %v", got, peakHours)
+		}
+	}
+}
+
+func TestHeatmap_ShrinksSparseSlots(t *testing.T) {
+	optimizer := NewOptimizer()
+
+	// One lucky post at 3am is not enough to beat the defaults
+	points := append(repeatPoints(1, 3, time.Monday, 20), repeatPoints(9, 19, time.Monday, 4)...)
+	optimizer.AnalyzeHistoricalData("tiktok", points)
+	heatmap := optimizer.Heatmap("tiktok")
+
+	cell := heatmap.Cells[time.Monday][3]
+	if cell.Posts != 1 || cell.Mean != 20 {
+		t.Errorf("cell = %+v, want 1 post with mean 20", cell)
+	}
+	if cell.Score >= cell.Mean || cell.Score <= heatmap.Baseline*defaultWeight(3) {
+		t.Errorf("score %.2f is not between the prior and the mean", cell.Score)
+	}
+	if best := heatmap.BestHours(time.Monday, 1)[0]; best == 3 {
+		t.Error("a single post outweighed the defaults")
+	}
+
+	// Enough posts at 3am win the slot
+	optimizer.AnalyzeHistoricalData("tiktok", append(points, repeatPoints(9, 3, time.Monday, 20)...))
+	if best := optimizer.Heatmap("tiktok").BestHours(time.Monday, 1)[0]; best != 3 {
+		t.Errorf("best hour = %d, want 3 once it has data", best)
+	}
+}
+
+func TestHeatmap_PerPlatform(t *testing.T) {
+	optimizer := NewOptimizer()
+	optimizer.AnalyzeHistoricalData("tiktok", append(
+		repeatPoints(20, 16, time.Friday, 15),
+		repeatPoints(20, 19, time.Friday, 3)...,
+	))
+
+	if best := optimizer.Heatmap("tiktok").BestHours(time.Friday, 1)[0]; best != 16 {
+		t.Errorf("tiktok best hour = %d, want 16", best)
+	}
+	if best := optimizer.Heatmap("instagram").BestHours(time.Friday, 1)[0]; best != 7 {
+		t.Errorf("instagram best hour = %d, want the default 7", best)
+	}
+	if best := optimizer.Heatmap("").BestHours(time.Friday, 1)[0]; best != 16 {
+		t.Errorf("pooled best hour = %d, want 16", best)
+	}
+}
+
+func TestOptimizer_GetPlatformTimes_UsesHeatmaps(t *testing.T) {
+	optimizer := NewOptimizer()
+	tomorrow := time.Now().AddDate(0, 0, 1).Weekday()
+	optimizer.AnalyzeHistoricalData("bluesky", append(
+		repeatPoints(20, 16, tomorrow, 15),
+		repeatPoints(20, 19, tomorrow, 3)...,
+	))
+
+	slots := optimizer.GetPlatformTimes([][]string{{"bluesky", "tiktok", "bluesky"}})
+	if len(slots) != 3 {
+		t.Fatalf("got %d slots, want 3", len(slots))
+	}
+	// The second bluesky post skips 19:00, which did badly, and 7:00,
+	// which is taken
+	wantHours := []int{16, 7, 12}
+	for i, slot := range slots {
+		if slot.Time.Hour() != wantHours[i] {
+			t.Errorf("slot %d hour = %d, want %d", i, slot.Time.Hour(), wantHours[i])
+		}
+	}
+	if slots[0].Platform != "bluesky" || slots[1].Platform != "tiktok" {
+		t.Errorf("platforms = %s, %s, want bluesky, tiktok", slots[0].Platform, slots[1].Platform)
+	}
+}
+
+func TestOptimizer_LoadHistory(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	stores := db.Stores()
+
+	monday := time.Date(2026, 3, 2, 16, 30, 0, 0, time.Local)
+	published := func(at time.Time, platform string) string {
+		entry, err := stores.Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+			ScheduledFor: at, Platform: platform, PostType: "reel",
+		})
+		if err != nil {
+			t.Fatalf("create entry: %v", err)
+		}
+		if err := stores.Calendar.UpdateEntryStatus(ctx, entry.ID, "published"); err != nil {
+			t.Fatalf("update entry: %v", err)
+		}
+		return entry.ID
+	}
+	metric := func(id, platform string, views, likes int) {
+		input := &models.PostMetricInput{CalendarID: id, Platform: platform, Views: views, Likes: likes}
+		if _, err := stores.Metrics.CreateMetric(ctx, input); err != nil {
+			t.Fatalf("create metric: %v", err)
+		}
+	}
+
+	post := published(monday, "tiktok")
+	metric(post, "tiktok", 100, 5)
+	metric(post, "tiktok", 200, 20) // the latest snapshot counts
+	metric(published(monday.AddDate(0, 0, 1), "instagram"), "instagram", 50, 5)
+	metric(published(monday, "bluesky"), "bluesky", 0, 9) // no views, no rate
+	published(monday, "tiktok")                           // no metrics yet
+
+	optimizer := NewOptimizer()
+	n, err := optimizer.LoadHistory(ctx, stores.Calendar, stores.Metrics, time.Time{})
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("LoadHistory() analyzed %d posts, want 2", n)
+	}
+
+	cell := optimizer.Heatmap("tiktok").Cells[time.Monday][16]
+	if cell.Posts != 1 || cell.Mean != 10 {
+		t.Errorf("tiktok Monday 16:00 = %+v, want 1 post at 10%%", cell)
+	}
+	if cell := optimizer.Heatmap("instagram").Cells[time.Tuesday][16]; cell.Posts != 1 {
+		t.Errorf("instagram Tuesday 16:00 = %+v, want 1 post", cell)
+	}
+	if posts := optimizer.Heatmap("bluesky").Posts; posts != 0 {
+		t.Errorf("bluesky heatmap has %d posts, want 0", posts)
+	}
+}
+
+func TestOptimizer_LoadHistory_WindowAndPages(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	stores := db.Stores()
+
+	now := time.Now()
+	published := func(at time.Time) string {
+		entry, err := stores.Calendar.CreateEntry(ctx, &models.ContentCalendarInput{
+			ScheduledFor: at, Platform: "tiktok", PostType: "reel",
+		})
+		if err != nil {
+			t.Fatalf("create entry: %v", err)
+		}
+		if err := stores.Calendar.UpdateEntryStatus(ctx, entry.ID, "published"); err != nil {
+			t.Fatalf("update entry: %v", err)
+		}
+		return entry.ID
+	}
+	metric := func(id string, views, likes int) {
+		input := &models.PostMetricInput{CalendarID: id, Platform: "tiktok", Views: views, Likes: likes}
+		if _, err := stores.Metrics.CreateMetric(ctx, input); err != nil {
+			t.Fatalf("create metric: %v", err)
+		}
+	}
+
+	// More snapshots than fit in one page; only the latest one counts
+	recent := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.Local).AddDate(0, 0, -2)
+	post := published(recent)
+	for range repository.DefaultPageSize + 10 {
+		metric(post, 100, 1)
+	}
+	metric(post, 100, 30)
+
+	// Published before the window, but measured just now
+	old := time.Date(now.Year(), now.Month(), now.Day(), 18, 0, 0, 0, time.Local).AddDate(0, 0, -200)
+	metric(published(old), 100, 50)
+
+	optimizer := NewOptimizer()
+	n, err := optimizer.LoadHistory(ctx, stores.Calendar, stores.Metrics, now.AddDate(0, 0, -DefaultHistoryDays))
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("LoadHistory() analyzed %d posts, want only the one in the window", n)
+	}
+
+	heatmap := optimizer.Heatmap("tiktok")
+	if cell := heatmap.Cells[recent.Weekday()][9]; cell.Posts != 1 || cell.Mean != 30 {
+		t.Errorf("tiktok %s 9:00 = %+v, want 1 post at the latest 30%%", recent.Weekday(), cell)
+	}
+	if cell := heatmap.Cells[old.Weekday()][18]; cell.Posts != 0 {
+		t.Errorf("tiktok %s 18:00 = %+v, want the post outside the window left out", old.Weekday(), cell)
+	}
+}
+
+func TestParseMixStrategy(t *testing.T) {
+	strategy, err := ParseMixStrategy(map[string]float64{"educational": 2, "trend": 1, "ugc": 1})
+	if err != nil {
+		t.Fatalf("ParseMixStrategy() error = %v", err)
+	}
+	if strategy.Share("educational") != 0.5 || strategy.Share("trend") != 0.25 || strategy.Share("bts") != 0 {
+		t.Errorf("strategy = %+v, want shares scaled to add up to one", strategy)
+	}
+	if strategy.Share("unknown") != 0 {
+		t.Error("unknown type has a share")
+	}
+
+	for _, shares := range []map[string]float64{
+		{"memes": 1},
+		{"educational": -1, "trend": 2},
+		{"educational": 0},
+	} {
+		if _, err := ParseMixStrategy(shares); err == nil {
+			t.Errorf("ParseMixStrategy(%v) accepted an invalid mix", shares)
+		}
+	}
+}
+
+// mustLocation loads a timezone or fails the test
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestOptimizer_GetPlatformTimes_SpringForward(t *testing.T) {
+	newYork := mustLocation(t, "America/New_York")
+	optimizer := NewOptimizer()
+	if err := optimizer.SetTimezones("America/New_York", nil); err != nil {
+		t.Fatalf("SetTimezones() error = %v", err)
+	}
+	// DST starts on Sunday 8 March 2026 at 2:00
+	optimizer.now = func() time.Time { return time.Date(2026, 3, 6, 12, 0, 0, 0, newYork) }
+
+	slots := optimizer.GetPlatformTimes([][]string{{"tiktok"}, {"tiktok"}, {"tiktok"}})
+	wantUTC := []int{12, 11, 11} // 7:00 EST, then 7:00 EDT
+	for i, slot := range slots {
+		local := slot.Time.In(newYork)
+		if local.Day() != 7+i || local.Hour() != 7 {
+			t.Errorf("slot %d = %v, want 7:xx on March %d", i, local, 7+i)
+		}
+		if slot.Time.UTC().Hour() != wantUTC[i] {
+			t.Errorf("slot %d = %v UTC, want %d:xx UTC", i, slot.Time.UTC(), wantUTC[i])
+		}
+	}
+
+	// A heatmap that loves 2:00 on Sundays cannot use it on the day the
+	// clocks skip it, but can a week later
+	optimizer.AnalyzeHistoricalData("tiktok", append(
+		repeatPoints(20, 2, time.Sunday, 15),
+		repeatPoints(20, 19, time.Sunday, 3)...,
+	))
+	days := make([][]string, 8)
+	for i := range days {
+		days[i] = []string{"tiktok"}
+	}
+	slots = optimizer.GetPlatformTimes(days)
+	if got := slots[1].Time.In(newYork); got.Day() != 8 || got.Hour() != 7 {
+		t.Errorf("slot on March 8 = %v, want the next best hour, 7:xx", got)
+	}
+	if got := slots[7].Time.In(newYork); got.Day() != 14 || got.Hour() != 7 {
+		t.Errorf("slot on March 14 = %v, want 7:xx", got)
+	}
+	optimizer.now = func() time.Time { return time.Date(2026, 3, 13, 12, 0, 0, 0, newYork) }
+	if got := optimizer.GetPlatformTimes([][]string{{"tiktok"}, {"tiktok"}})[1].Time.In(newYork); got.Day() != 15 || got.Hour() != 2 {
+		t.Errorf("slot on March 15 = %v, want 2:xx", got)
+	}
+}
+
+func TestOptimizer_GetPlatformTimes_FallBack(t *testing.T) {
+	newYork := mustLocation(t, "America/New_York")
+	optimizer := NewOptimizer()
+	if err := optimizer.SetTimezones("America/New_York", nil); err != nil {
+		t.Fatalf("SetTimezones() error = %v", err)
+	}
+	// DST ends on Sunday 1 November 2026 at 2:00, which repeats 1:00
+	optimizer.now = func() time.Time { return time.Date(2026, 10, 30, 12, 0, 0, 0, newYork) }
+
+	slots := optimizer.GetPlatformTimes([][]string{{"", "", ""}, {"", "", ""}, {"", "", ""}})
+	for i, slot := range slots {
+		local := slot.Time.In(newYork)
+		wantDay := time.Date(2026, 10, 31+i/3, 0, 0, 0, 0, newYork).Day()
+		if want := []int{7, 12, 19}[i%3]; local.Hour() != want || local.Day() != wantDay {
+			t.Errorf("slot %d = %v, want %d:xx on day %d", i, local, want, wantDay)
+		}
+	}
+	// 19:00 is 23:00 UTC on Saturday and midnight UTC after the change
+	if got := slots[2].Time.UTC(); got.Hour() != 23 {
+		t.Errorf("Saturday 19:00 = %v, want 23:xx UTC", got)
+	}
+	if got := slots[5].Time.UTC(); got.Hour() != 0 {
+		t.Errorf("Sunday 19:00 = %v, want 00:xx UTC", got)
+	}
+}
+
+func TestOptimizer_PlatformTimezones(t *testing.T) {
+	newYork := mustLocation(t, "America/New_York")
+	rome := mustLocation(t, "Europe/Rome")
+	optimizer := NewOptimizer()
+	if err := optimizer.SetTimezones("America/New_York", map[string]string{"bluesky": "Europe/Rome"}); err != nil {
+		t.Fatalf("SetTimezones() error = %v", err)
+	}
+	optimizer.now = func() time.Time { return time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC) }
+
+	slots := optimizer.GetPlatformTimes([][]string{{"tiktok", "bluesky"}})
+	if got := slots[0].Time.In(newYork); got.Hour() != 7 || got.Day() != 5 {
+		t.Errorf("tiktok slot = %v, want 7:00 on May 5 in New York", got)
+	}
+	// Each audience gets its own best hour; the gap only spaces posts
+	// within one timezone
+	if got := slots[1].Time.In(rome); got.Hour() != 7 || got.Day() != 5 {
+		t.Errorf("bluesky slot = %v, want 7:00 on May 5 in Rome", got)
+	}
+
+	// Past posts land in the heatmap by their audience's hour
+	at := time.Date(2026, 3, 2, 16, 30, 0, 0, time.UTC)
+	entries := []models.ContentCalendar{{ID: "ny", ScheduledFor: at}, {ID: "it", ScheduledFor: at}}
+	metrics := []models.PostMetric{
+		{CalendarID: "ny", Platform: "tiktok", Views: 10, EngagementRate: 1},
+		{CalendarID: "it", Platform: "bluesky", Views: 10, EngagementRate: 1},
+	}
+	points := HistoryPoints(entries, metrics, optimizer.Location)
+	if got := points["tiktok"][0].Hour; got != 11 {
+		t.Errorf("tiktok post hour = %d, want 11 in New York", got)
+	}
+	if got := points["bluesky"][0].Hour; got != 17 {
+		t.Errorf("bluesky post hour = %d, want 17 in Rome", got)
+	}
+
+	if err := optimizer.SetTimezones("Mars/Olympus", nil); err == nil {
+		t.Error("SetTimezones() accepted an unknown timezone")
+	}
+	if err := optimizer.SetTimezones("", map[string]string{"myspace": "UTC"}); err == nil {
+		t.Error("SetTimezones() accepted an unknown platform")
+	}
+}
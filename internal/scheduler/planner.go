@@ -1,89 +1,180 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/gagipress/gagipress-cli/internal/models"
 	"github.com/gagipress/gagipress-cli/internal/repository"
 )
 
+// scriptPoolFactor is how many scripts per slot the planner considers, so
+// it has room to match the content mix
+const scriptPoolFactor = 4
+
 // Planner handles content calendar planning
 type Planner struct {
-	contentRepo *repository.ContentRepository
+	contentRepo repository.ContentStore
 	optimizer   *Optimizer
+	platforms   []string
+	mix         ContentMixStrategy
+	bookMix     map[string]ContentMixStrategy
+	constraints *Constraints
+	existing    []models.ContentCalendar
 }
 
 // NewPlanner creates a new calendar planner
-func NewPlanner(contentRepo *repository.ContentRepository) *Planner {
+func NewPlanner(contentRepo repository.ContentStore) *Planner {
 	return &Planner{
 		contentRepo: contentRepo,
 		optimizer:   NewOptimizer(),
+		mix:         DefaultMixStrategy(),
 	}
 }
 
-// PlanWeek creates a weekly content plan
-func (p *Planner) PlanWeek(days int, postsPerDay int) ([]*models.ContentCalendarInput, error) {
-	// Get available scripts (from scripted ideas)
-	scripts, err := p.contentRepo.GetScripts(0)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get scripts: %w", err)
+// SetPlatforms makes the planner take turns between platforms instead of
+// choosing TikTok or Instagram by script length
+func (p *Planner) SetPlatforms(platforms []string) error {
+	for _, platform := range platforms {
+		if err := models.ValidatePlatform(platform); err != nil {
+			return err
+		}
 	}
+	p.platforms = platforms
+	return nil
+}
 
-	if len(scripts) == 0 {
-		return nil, fmt.Errorf("no scripts available for planning")
-	}
+// SetOptimizer makes the planner pick posting times with an optimizer that
+// has learned from historical data
+func (p *Planner) SetOptimizer(optimizer *Optimizer) {
+	p.optimizer = optimizer
+}
 
-	totalPosts := days * postsPerDay
-	if len(scripts) < totalPosts {
-		return nil, fmt.Errorf("not enough scripts: need %d, have %d", totalPosts, len(scripts))
-	}
+// SetContentMix sets the content mix to plan for, and the mix of books,
+// keyed by book ID, that differ from it
+func (p *Planner) SetContentMix(mix ContentMixStrategy, bookMix map[string]ContentMixStrategy) {
+	p.mix = mix
+	p.bookMix = bookMix
+}
 
-	// Group scripts by type for balanced distribution
-	scriptsByType := make(map[string][]models.ContentScript)
-	for _, script := range scripts {
-		// We need to get the idea to know the type
-		// For now, we'll just use the scripts directly
-		scriptsByType["mixed"] = append(scriptsByType["mixed"], script)
-	}
+// SetConstraints makes the planner follow constraints, which must have
+// been validated
+func (p *Planner) SetConstraints(constraints *Constraints) {
+	p.constraints = constraints
+}
 
-	// Get optimal posting times
-	postingTimes := p.optimizer.GetOptimalTimes(days, postsPerDay)
+// SetExisting makes the planner count entries already on the calendar
+// toward caps, spacing and quotas, and leave their scripts out of the pool
+func (p *Planner) SetExisting(entries []models.ContentCalendar) {
+	p.existing = entries
+}
 
-	// Create calendar entries
-	var calendar []*models.ContentCalendarInput
-	scriptIndex := 0
+// ExistingWindow returns the span whose calendar entries bear on a plan of
+// days: from the first planned day to a week past the last one
+func (p *Planner) ExistingWindow(days int) (from, to time.Time) {
+	return p.date(0), p.date(days + 7)
+}
 
-	for _, slot := range postingTimes {
-		if scriptIndex >= len(scripts) {
-			break
-		}
+// date returns the midnight starting a plan day, in the audience's default
+// timezone
+func (p *Planner) date(day int) time.Time {
+	now := p.optimizer.now().In(p.optimizer.Location(""))
+	return time.Date(now.Year(), now.Month(), now.Day()+1+day, 0, 0, 0, 0, now.Location())
+}
 
-		script := scripts[scriptIndex]
+// PlannedScript is a script with the type and book of its idea
+type PlannedScript struct {
+	models.ContentScript
+	Type   string
+	BookID string
+}
+
+// PlanWeek creates a weekly content plan
+func (p *Planner) PlanWeek(ctx context.Context, days int, postsPerDay int) ([]*models.ContentCalendarInput, error) {
+	plan, err := p.Plan(ctx, days, postsPerDay)
+	if err != nil {
+		return nil, err
+	}
+	return plan.Entries, nil
+}
 
-		// Determine platform based on script characteristics
-		platform := "tiktok"
-		if script.EstimatedDuration > 60 {
-			platform = "instagram" // Longer content for Instagram
+// scriptPool returns up to limit scripts not on the calendar yet, newest
+// first, with the type and book of their ideas. It also returns the book
+// of each script the existing entries post, by script ID.
+func (p *Planner) scriptPool(ctx context.Context, limit int) ([]PlannedScript, map[string]string, error) {
+	// Idea of each script already on the calendar, once known
+	scheduled := make(map[string]string)
+	for _, entry := range p.existing {
+		if entry.ScriptID != nil {
+			scheduled[*entry.ScriptID] = ""
 		}
+	}
 
-		entry := &models.ContentCalendarInput{
-			ScriptID:     &script.ID,
-			ScheduledFor: slot.Time,
-			Platform:     platform,
-			PostType:     "reel",
+	var scripts []PlannedScript
+	missing := make(map[string]bool)
+	for script, err := range repository.IterScripts(ctx, p.contentRepo) {
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get scripts: %w", err)
+		}
+		if _, ok := scheduled[script.ID]; ok {
+			scheduled[script.ID] = script.IdeaID
+			missing[script.IdeaID] = true
+			continue
+		}
+		scripts = append(scripts, PlannedScript{ContentScript: script})
+		missing[script.IdeaID] = true
+		if len(scripts) >= limit {
+			break
 		}
+	}
+	if len(scripts) == 0 {
+		return nil, nil, nil
+	}
 
-		calendar = append(calendar, entry)
-		scriptIndex++
+	// The walk stopped before older scheduled scripts
+	for id, ideaID := range scheduled {
+		if ideaID != "" {
+			continue
+		}
+		script, err := p.contentRepo.GetScriptByID(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get scheduled script: %w", err)
+		}
+		scheduled[id] = script.IdeaID
+		missing[script.IdeaID] = true
+	}
 
-		if len(calendar) >= totalPosts {
+	// Ideas come newest first too, so the walk usually ends early
+	ideas := make(map[string]models.ContentIdea)
+	for idea, err := range repository.IterIdeas(ctx, p.contentRepo, "", "") {
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get ideas: %w", err)
+		}
+		if !missing[idea.ID] {
+			continue
+		}
+		ideas[idea.ID] = idea
+		delete(missing, idea.ID)
+		if len(missing) == 0 {
 			break
 		}
 	}
 
-	return calendar, nil
+	for i := range scripts {
+		idea := ideas[scripts[i].IdeaID]
+		scripts[i].Type = idea.Type
+		if idea.BookID != nil {
+			scripts[i].BookID = *idea.BookID
+		}
+	}
+	books := make(map[string]string, len(scheduled))
+	for id, ideaID := range scheduled {
+		if bookID := ideas[ideaID].BookID; bookID != nil {
+			books[id] = *bookID
+		}
+	}
+	return scripts, books, nil
 }
 
 // TimeSlot represents a scheduled time slot
@@ -93,18 +184,27 @@ type TimeSlot struct {
 	Type     string
 }
 
-// BalanceContentMix ensures diverse content types in the calendar
-func (p *Planner) BalanceContentMix(scripts []models.ContentScript) []models.ContentScript {
-	// Sort by ID for now (deterministic)
-	sort.Slice(scripts, func(i, j int) bool {
-		return scripts[i].ID < scripts[j].ID
-	})
-
-	// In a real implementation, this would:
-	// 1. Group by content type (educational, entertainment, etc.)
-	// 2. Ensure balanced distribution
-	// 3. Avoid same type back-to-back
-	// 4. Rotate between books
+// BalanceContentMix picks count scripts in posting order. Books take turns,
+// each book's content types follow its content mix, and neither the type
+// nor the book repeats back-to-back while there is another choice. Within
+// a book and type, earlier scripts go first.
+func (p *Planner) BalanceContentMix(scripts []PlannedScript, count int) []PlannedScript {
+	m := newMixer(p, scripts)
+	var balanced []PlannedScript
+	for len(balanced) < count {
+		i, _ := m.next(nil)
+		if i < 0 {
+			break
+		}
+		balanced = append(balanced, m.take(i))
+	}
+	return balanced
+}
 
-	return scripts
+// mixFor returns the content mix of a book
+func (p *Planner) mixFor(bookID string) ContentMixStrategy {
+	if mix, ok := p.bookMix[bookID]; ok {
+		return mix
+	}
+	return p.mix
 }
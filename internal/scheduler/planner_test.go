@@ -1,39 +1,253 @@
 package scheduler
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository/memory"
+	_ "github.com/gagipress/gagipress-cli/internal/social" // registers the platforms
 )
 
+// seedScripts stores n scripts in an in-memory content store.
+func seedScripts(t *testing.T, n int) *memory.DB {
+	t.Helper()
+	ctx := context.Background()
+	db := memory.New()
+	content := db.Stores().Content
+
+	for i := 0; i < n; i++ {
+		idea, err := content.CreateIdea(ctx, &models.ContentIdeaInput{Type: "educational", BriefDescription: "idea"})
+		if err != nil {
+			t.Fatalf("create idea: %v", err)
+		}
+		_, err = content.CreateScript(ctx, &models.ContentScriptInput{
+			IdeaID: idea.ID, Hook: "h", FullScript: "s", CTA: "c", Hashtags: []string{},
+		})
+		if err != nil {
+			t.Fatalf("create script: %v", err)
+		}
+	}
+	return db
+}
+
+func TestPlanner_PlanWeek_UsesOnlyNeededScripts(t *testing.T) {
+	db := seedScripts(t, 10)
+	planner := NewPlanner(db.Stores().Content)
+
+	plan, err := planner.PlanWeek(context.Background(), 2, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan) != 4 {
+		t.Errorf("expected 4 entries, got %d", len(plan))
+	}
+}
+
+func TestPlanner_PlanWeek_NotEnoughScripts(t *testing.T) {
+	db := seedScripts(t, 3)
+	planner := NewPlanner(db.Stores().Content)
+
+	_, err := planner.PlanWeek(context.Background(), 2, 2)
+	if err == nil || !strings.Contains(err.Error(), "need 4, have 3") {
+		t.Errorf("expected not enough scripts error, got %v", err)
+	}
+}
+
+func TestPlanner_PlanWeek_Platforms(t *testing.T) {
+	db := seedScripts(t, 4)
+	planner := NewPlanner(db.Stores().Content)
+	if err := planner.SetPlatforms([]string{"bluesky", "tiktok"}); err != nil {
+		t.Fatalf("SetPlatforms() error = %v", err)
+	}
+
+	plan, err := planner.PlanWeek(context.Background(), 2, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, entry := range plan {
+		got = append(got, entry.Platform)
+	}
+	if strings.Join(got, ",") != "bluesky,tiktok,bluesky,tiktok" {
+		t.Errorf("platforms = %v, want bluesky and tiktok in turn", got)
+	}
+
+	if err := planner.SetPlatforms([]string{"myspace"}); err == nil {
+		t.Error("SetPlatforms() accepted an unknown platform")
+	}
+}
+
+// plannedScripts returns n scripts of a book and type, with IDs prefixed
+// by both
+func plannedScripts(bookID, ideaType string, n int) []PlannedScript {
+	scripts := make([]PlannedScript, n)
+	for i := range scripts {
+		scripts[i] = PlannedScript{
+			ContentScript: models.ContentScript{ID: fmt.Sprintf("%s-%s-%d", bookID, ideaType, i)},
+			Type:          ideaType,
+			BookID:        bookID,
+		}
+	}
+	return scripts
+}
+
+// checkNoRepeats fails if a type or book follows itself while the pool
+// held the other
+func checkNoRepeats(t *testing.T, scripts []PlannedScript) {
+	t.Helper()
+	for i := 1; i < len(scripts); i++ {
+		if scripts[i].Type == scripts[i-1].Type {
+			t.Errorf("scripts %d and %d are both %s", i-1, i, scripts[i].Type)
+		}
+		if scripts[i].BookID == scripts[i-1].BookID {
+			t.Errorf("scripts %d and %d are both from book %s", i-1, i, scripts[i].BookID)
+		}
+	}
+}
+
 func TestPlanner_BalanceContentMix(t *testing.T) {
-	planner := &Planner{
-		optimizer: NewOptimizer(),
+	planner := NewPlanner(nil)
+
+	var scripts []PlannedScript
+	for _, ideaType := range []string{"educational", "entertainment", "bts", "ugc", "trend"} {
+		scripts = append(scripts, plannedScripts("", ideaType, 20)...)
+	}
+
+	balanced := planner.BalanceContentMix(scripts, 20)
+	if len(balanced) != 20 {
+		t.Fatalf("got %d scripts, want 20", len(balanced))
+	}
+
+	counts := make(map[string]int)
+	for i, script := range balanced {
+		counts[script.Type]++
+		if i > 0 && script.Type == balanced[i-1].Type {
+			t.Errorf("scripts %d and %d are both %s", i-1, i, script.Type)
+		}
+	}
+	// The default mix: 25% educational and entertainment, 20% ugc, 15% bts
+	// and trend
+	want := map[string]int{"educational": 5, "entertainment": 5, "ugc": 4, "bts": 3, "trend": 3}
+	for ideaType, n := range want {
+		if counts[ideaType] != n {
+			t.Errorf("%s: got %d scripts, want %d (all: %v)", ideaType, counts[ideaType], n, counts)
+		}
+	}
+
+	// Earlier scripts of a type go first
+	for _, script := range balanced {
+		if script.Type == "educational" {
+			if script.ID != "-educational-0" {
+				t.Errorf("first educational script = %s, want -educational-0", script.ID)
+			}
+			break
+		}
+	}
+
+	// The same input gives the same plan
+	again := planner.BalanceContentMix(scripts, 20)
+	for i := range balanced {
+		if balanced[i].ID != again[i].ID {
+			t.Fatalf("plan differs at %d: %s vs %s", i, balanced[i].ID, again[i].ID)
+		}
+	}
+}
+
+func TestPlanner_BalanceContentMix_BooksTakeTurns(t *testing.T) {
+	planner := NewPlanner(nil)
+	trendOnly, err := ParseMixStrategy(map[string]float64{"trend": 1})
+	if err != nil {
+		t.Fatalf("ParseMixStrategy() error = %v", err)
+	}
+	planner.SetContentMix(DefaultMixStrategy(), map[string]ContentMixStrategy{"book-b": trendOnly})
+
+	var scripts []PlannedScript
+	for _, ideaType := range []string{"educational", "entertainment", "trend"} {
+		scripts = append(scripts, plannedScripts("book-a", ideaType, 5)...)
+		scripts = append(scripts, plannedScripts("book-b", ideaType, 5)...)
 	}
 
-	scripts := []models.ContentScript{
-		{ID: "script-3"},
-		{ID: "script-1"},
-		{ID: "script-2"},
+	balanced := planner.BalanceContentMix(scripts, 8)
+	checkNoRepeats(t, balanced)
+	for _, script := range balanced {
+		if script.BookID == "book-b" && script.Type != "trend" {
+			t.Errorf("book-b got %s, but its mix is all trend", script.Type)
+		}
 	}
+}
 
-	balanced := planner.BalanceContentMix(scripts)
+func TestPlanner_BalanceContentMix_RepeatsOnlyWhenForced(t *testing.T) {
+	planner := NewPlanner(nil)
 
-	// Should return same number of scripts
-	if len(balanced) != len(scripts) {
-		t.Errorf("Expected %d scripts, got %d", len(scripts), len(balanced))
+	scripts := plannedScripts("book-a", "educational", 3)
+	balanced := planner.BalanceContentMix(scripts, 5)
+	if len(balanced) != 3 {
+		t.Fatalf("got %d scripts, want all 3", len(balanced))
+	}
+	for i, script := range balanced {
+		if want := fmt.Sprintf("book-a-educational-%d", i); script.ID != want {
+			t.Errorf("script %d = %s, want %s", i, script.ID, want)
+		}
+	}
+}
+
+func TestPlanner_PlanWeek_ContentMix(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	stores := db.Stores()
+
+	var bookIDs []string
+	for _, title := range []string{"Book A", "Book B"} {
+		book, err := stores.Books.Create(ctx, &models.BookInput{Title: title, Genre: "kids"})
+		if err != nil {
+			t.Fatalf("create book: %v", err)
+		}
+		bookIDs = append(bookIDs, book.ID)
+	}
+	types := []string{"educational", "educational", "entertainment", "ugc"}
+	for i := 0; i < 16; i++ {
+		idea, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{
+			Type: types[i%len(types)], BriefDescription: "idea", BookID: &bookIDs[i/8],
+		})
+		if err != nil {
+			t.Fatalf("create idea: %v", err)
+		}
+		_, err = stores.Content.CreateScript(ctx, &models.ContentScriptInput{
+			IdeaID: idea.ID, Hook: "h", FullScript: "s", CTA: "c", Hashtags: []string{},
+		})
+		if err != nil {
+			t.Fatalf("create script: %v", err)
+		}
+	}
+
+	planner := NewPlanner(stores.Content)
+	plan, err := planner.PlanWeek(ctx, 3, 2)
+	if err != nil {
+		t.Fatalf("PlanWeek() error = %v", err)
+	}
+	if len(plan) != 6 {
+		t.Fatalf("got %d entries, want 6", len(plan))
 	}
 
-	// Should be sorted by ID (deterministic ordering)
-	if balanced[0].ID != "script-1" {
-		t.Errorf("Expected first script ID 'script-1', got '%s'", balanced[0].ID)
+	pool, _, err := planner.scriptPool(ctx, 16)
+	if err != nil {
+		t.Fatalf("scriptPool() error = %v", err)
 	}
-	if balanced[1].ID != "script-2" {
-		t.Errorf("Expected second script ID 'script-2', got '%s'", balanced[1].ID)
+	byID := make(map[string]PlannedScript)
+	for _, script := range pool {
+		byID[script.ID] = script
 	}
-	if balanced[2].ID != "script-3" {
-		t.Errorf("Expected third script ID 'script-3', got '%s'", balanced[2].ID)
+	var planned []PlannedScript
+	for i, entry := range plan {
+		if i > 0 && entry.ScheduledFor.Before(plan[i-1].ScheduledFor) {
+			t.Errorf("entry %d is scheduled before entry %d", i, i-1)
+		}
+		planned = append(planned, byID[*entry.ScriptID])
 	}
+	checkNoRepeats(t, planned)
 }
 
 func TestTimeSlot_Structure(t *testing.T) {
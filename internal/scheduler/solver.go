@@ -0,0 +1,490 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+)
+
+// Plan is a calendar plan and what it fell short of
+type Plan struct {
+	Entries     []*models.ContentCalendarInput
+	Unsatisfied []Unsatisfied
+}
+
+// Unsatisfied explains a quota or target a plan could not meet, and which
+// constraints stood in the way
+type Unsatisfied struct {
+	Constraint string // e.g. `quota "launch week"`
+	Reason     string
+}
+
+// Plan fills days × postsPerDay slots with scripts. Slots go to the best
+// hours the constraints allow, and scripts to slots by content mix, with
+// quotas first while they are behind. Entries already on the calendar
+// count toward the constraints. Slots no script fits stay empty, and the
+// plan says why.
+func (p *Planner) Plan(ctx context.Context, days int, postsPerDay int) (*Plan, error) {
+	totalPosts := days * postsPerDay
+
+	// Walk scripts newest first and stop once there are enough to choose
+	// from, instead of loading every script ever written
+	scripts, books, err := p.scriptPool(ctx, totalPosts*scriptPoolFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scripts) == 0 {
+		return nil, fmt.Errorf("no scripts available for planning")
+	}
+
+	// Constraints may leave slots empty, so they can do with fewer scripts
+	if p.constraints == nil && len(scripts) < totalPosts {
+		return nil, fmt.Errorf("not enough scripts: need %d, have %d", totalPosts, len(scripts))
+	}
+
+	s := &solver{
+		p:            p,
+		c:            p.constraints,
+		mix:          newMixer(p, scripts),
+		heatmaps:     make(map[string]*Heatmap),
+		quotaReasons: make(map[int][]string),
+	}
+	if s.c == nil {
+		s.c = &Constraints{}
+	}
+	s.seed(p.existing, books)
+	return s.solve(days, postsPerDay), nil
+}
+
+// solver fills a plan one day at a time
+type solver struct {
+	p        *Planner
+	c        *Constraints
+	mix      *mixer
+	heatmaps map[string]*Heatmap
+
+	plan  Plan
+	books []string // book of each entry
+
+	// existing is how many leading entries of plan are already on the
+	// calendar; they count toward the constraints but are not planned
+	existing int
+
+	// firstDay and lastDay bound the planned days, YYYY-MM-DD
+	firstDay, lastDay string
+
+	// quotaReasons collects why scripts counting toward a quota were
+	// turned down, by quota index
+	quotaReasons map[int][]string
+}
+
+// seed starts the plan from entries already on the calendar, given the
+// book of each script they post
+func (s *solver) seed(entries []models.ContentCalendar, books map[string]string) {
+	for _, entry := range entries {
+		bookID := ""
+		if entry.ScriptID != nil {
+			bookID = books[*entry.ScriptID]
+		}
+		s.plan.Entries = append(s.plan.Entries, &models.ContentCalendarInput{
+			ScriptID:     entry.ScriptID,
+			ScheduledFor: entry.ScheduledFor,
+			Platform:     entry.Platform,
+			PostType:     entry.PostType,
+			CampaignID:   entry.CampaignID,
+		})
+		s.books = append(s.books, bookID)
+	}
+	s.existing = len(entries)
+}
+
+func (s *solver) solve(days, postsPerDay int) *Plan {
+	perDay := postsPerDay
+	if s.c.Caps.PerDay > 0 && s.c.Caps.PerDay < perDay {
+		perDay = s.c.Caps.PerDay
+		s.unsatisfied(fmt.Sprintf("%d posts per day", postsPerDay),
+			fmt.Sprintf("caps.per_day allows %d", perDay))
+	}
+
+	s.firstDay, s.lastDay = s.p.date(0).Format(models.DateFormat), s.p.date(days-1).Format(models.DateFormat)
+	for day := 0; day < days; day++ {
+		// Posts already on the calendar that day keep their times
+		var existing []time.Time
+		for _, entry := range s.plan.Entries[:s.existing] {
+			if s.day(entry.ScheduledFor) == s.p.date(day).Format(models.DateFormat) {
+				existing = append(existing, entry.ScheduledFor)
+			}
+		}
+
+		var slots []TimeSlot
+		var reasons []string
+		for i := 0; i < perDay; i++ {
+			// Chosen platforms take turns by slot; otherwise times come
+			// from the data of all platforms
+			platform := ""
+			if len(s.p.platforms) > 0 {
+				platform = s.p.platforms[(day*postsPerDay+i)%len(s.p.platforms)]
+			}
+
+			taken := slices.Clone(existing)
+			for _, slot := range slots {
+				taken = append(taken, slot.Time)
+			}
+			t, why := s.p.optimizer.pickSlot(s.heatmap(platform), day, taken, func(t time.Time) string {
+				return s.check(platform, "", t, taken)
+			})
+			if t.IsZero() {
+				reasons = appendNew(reasons, why...)
+				continue
+			}
+			slots = append(slots, TimeSlot{Time: t, Platform: platform, Type: "scheduled"})
+		}
+		sort.SliceStable(slots, func(i, j int) bool {
+			return slots[i].Time.Before(slots[j].Time)
+		})
+
+		// Fill the slots in time order so the balanced order holds on the
+		// calendar
+		planned := 0
+		for _, slot := range slots {
+			why, ok := s.fill(slot)
+			if !ok {
+				reasons = appendNew(reasons, why...)
+				continue
+			}
+			planned++
+		}
+
+		if planned < perDay {
+			s.unsatisfied(fmt.Sprintf("%d posts on %s", perDay, s.p.date(day).Format("Mon Jan 02")),
+				fmt.Sprintf("planned %d; %s", planned, strings.Join(reasons, ", ")))
+		}
+	}
+
+	s.checkQuotas()
+	s.plan.Entries = s.plan.Entries[s.existing:]
+	return &s.plan
+}
+
+// fill assigns the best fitting script to a slot, or returns why none fit
+func (s *solver) fill(slot TimeSlot) ([]string, bool) {
+	var reasons []string
+	i, ok := s.mix.next(func(script PlannedScript) (bool, string) {
+		platform, t, exists := s.placement(slot, script)
+		if !exists {
+			reason := fmt.Sprintf("%s clocks skip %s", platform, slot.Time.Format("15:04"))
+			reasons = appendNew(reasons, reason)
+			return false, reason
+		}
+		reason := s.check(platform, script.BookID, t, nil)
+		quota := false
+		for qi := range s.c.Quotas {
+			q := &s.c.Quotas[qi]
+			if !q.counts(script.BookID, platform, t.In(s.p.optimizer.Location(platform))) {
+				continue
+			}
+			if reason != "" {
+				s.quotaReasons[qi] = appendNew(s.quotaReasons[qi], reason)
+			} else if s.quotaPosts(qi) < q.Posts {
+				quota = true
+			}
+		}
+		if reason != "" {
+			reasons = appendNew(reasons, reason)
+		}
+		return quota, reason
+	})
+	if i < 0 {
+		if !ok {
+			reasons = appendNew(reasons, "no scripts left")
+		}
+		return reasons, false
+	}
+
+	script := s.mix.take(i)
+	platform, t, _ := s.placement(slot, script)
+	s.plan.Entries = append(s.plan.Entries, &models.ContentCalendarInput{
+		ScriptID:     &script.ID,
+		ScheduledFor: t,
+		Platform:     platform,
+		PostType:     "reel",
+	})
+	s.books = append(s.books, script.BookID)
+	return nil, true
+}
+
+// placement returns the platform and time of a script in a slot. The time
+// does not exist when the slot's wall-clock hour is one a DST change skips
+// in the platform's timezone.
+func (s *solver) placement(slot TimeSlot, script PlannedScript) (string, time.Time, bool) {
+	if slot.Platform != "" {
+		return slot.Platform, slot.Time, true
+	}
+
+	// Determine platform based on script characteristics
+	platform := "tiktok"
+	if script.EstimatedDuration > 60 {
+		platform = "instagram" // Longer content for Instagram
+	}
+	// Keep the slot's wall-clock time for the platform's audience
+	wall := slot.Time
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, s.p.optimizer.Location(platform))
+	return platform, t, t.Hour() == wall.Hour()
+}
+
+// check returns the constraint a post of a book on platform at t would
+// break, or "" if none. An empty platform or book skips the checks that
+// need it. taken are times picked for posts not planned yet.
+func (s *solver) check(platform, bookID string, t time.Time, taken []time.Time) string {
+	local := t.In(s.p.optimizer.Location(platform))
+	for i := range s.c.Blackouts {
+		if s.c.Blackouts[i].covers(platform, local) {
+			return s.c.Blackouts[i].label(i)
+		}
+	}
+
+	if gap := hours(s.c.Spacing.MinHours); gap > 0 {
+		for _, other := range taken {
+			if within(t, other, gap) {
+				return "spacing.min_hours"
+			}
+		}
+		for _, entry := range s.plan.Entries {
+			if within(t, entry.ScheduledFor, gap) {
+				return "spacing.min_hours"
+			}
+		}
+	}
+
+	day := s.day(t)
+	dayPosts, bookPosts, platformDay, platformWeek := 0, 0, 0, 0
+	year, week := local.ISOWeek()
+	for i, entry := range s.plan.Entries {
+		if s.day(entry.ScheduledFor) == day {
+			dayPosts++
+			if bookID != "" && s.books[i] == bookID {
+				bookPosts++
+			}
+		}
+		if entry.Platform != platform {
+			continue
+		}
+		at := entry.ScheduledFor.In(s.p.optimizer.Location(platform))
+		if at.Format(models.DateFormat) == local.Format(models.DateFormat) {
+			platformDay++
+		}
+		if y, w := at.ISOWeek(); y == year && w == week {
+			platformWeek++
+		}
+	}
+
+	if s.c.Caps.PerDay > 0 && dayPosts >= s.c.Caps.PerDay {
+		return "caps.per_day"
+	}
+	if platform != "" {
+		limit := s.c.Caps.Platforms[platform]
+		if limit.PerDay > 0 && platformDay >= limit.PerDay {
+			return "caps.platforms." + platform + ".per_day"
+		}
+		if limit.PerWeek > 0 && platformWeek >= limit.PerWeek {
+			return "caps.platforms." + platform + ".per_week"
+		}
+	}
+	if bookID == "" {
+		return ""
+	}
+	if s.c.Caps.PerBookPerDay > 0 && bookPosts >= s.c.Caps.PerBookPerDay {
+		return "caps.per_book_per_day"
+	}
+	if gap := hours(s.c.Spacing.SameBookHours); gap > 0 {
+		for i, entry := range s.plan.Entries {
+			if s.books[i] == bookID && within(t, entry.ScheduledFor, gap) {
+				return "spacing.same_book_hours"
+			}
+		}
+	}
+	return ""
+}
+
+// checkQuotas explains the quotas the plan falls short of
+func (s *solver) checkQuotas() {
+	for qi := range s.c.Quotas {
+		q := &s.c.Quotas[qi]
+		planned := s.quotaPosts(qi)
+		if planned >= q.Posts {
+			continue
+		}
+
+		reason := fmt.Sprintf("planned %d of %d posts", planned, q.Posts)
+		switch {
+		case q.To != "" && q.To < s.firstDay || q.From != "" && q.From > s.lastDay:
+			reason += "; the window is outside the planned days"
+		case !s.mix.hasBook(q.Book, false):
+			reason += "; no scripts of the book to plan"
+		case len(s.quotaReasons[qi]) > 0:
+			reason += "; " + strings.Join(s.quotaReasons[qi], ", ")
+		case !s.mix.hasBook(q.Book, true):
+			reason += "; ran out of the book's scripts"
+		default:
+			reason += "; not enough slots in the window"
+		}
+		s.unsatisfied(q.label(qi), reason)
+	}
+}
+
+// quotaPosts counts the planned posts toward a quota
+func (s *solver) quotaPosts(qi int) int {
+	q := &s.c.Quotas[qi]
+	n := 0
+	for i, entry := range s.plan.Entries {
+		if q.counts(s.books[i], entry.Platform, entry.ScheduledFor.In(s.p.optimizer.Location(entry.Platform))) {
+			n++
+		}
+	}
+	return n
+}
+
+// day returns the plan day of t, in the audience's default timezone
+func (s *solver) day(t time.Time) string {
+	return t.In(s.p.optimizer.Location("")).Format(models.DateFormat)
+}
+
+// heatmap returns the heatmap of a platform, built once per plan
+func (s *solver) heatmap(platform string) *Heatmap {
+	heatmap, ok := s.heatmaps[platform]
+	if !ok {
+		heatmap = s.p.optimizer.Heatmap(platform)
+		s.heatmaps[platform] = heatmap
+	}
+	return heatmap
+}
+
+func (s *solver) unsatisfied(constraint, reason string) {
+	s.plan.Unsatisfied = append(s.plan.Unsatisfied, Unsatisfied{Constraint: constraint, Reason: reason})
+}
+
+// mixer picks scripts one at a time to follow the content mix
+type mixer struct {
+	p         *Planner
+	scripts   []PlannedScript
+	used      []bool
+	last      *PlannedScript
+	bookPicks map[string]int
+	typePicks map[string]map[string]int
+}
+
+func newMixer(p *Planner, scripts []PlannedScript) *mixer {
+	return &mixer{
+		p:         p,
+		scripts:   scripts,
+		used:      make([]bool, len(scripts)),
+		bookPicks: make(map[string]int),
+		typePicks: make(map[string]map[string]int),
+	}
+}
+
+// next returns the index of the best script to post next, or -1 if none
+// is left or accepted. Scripts the candidate function gives priority win;
+// then scripts that repeat neither the last type nor the last book; then
+// books with fewer posts, and the type furthest behind its book's mix.
+// candidate may be nil to accept every script. ok is false if no script
+// was left at all.
+func (m *mixer) next(candidate func(PlannedScript) (priority bool, reason string)) (best int, ok bool) {
+	best = -1
+	var bestPriority bool
+	var bestRepeats, bestBookPicks int
+	var bestDeficit float64
+	for i, script := range m.scripts {
+		if m.used[i] {
+			continue
+		}
+		ok = true
+
+		priority := false
+		if candidate != nil {
+			var reason string
+			if priority, reason = candidate(script); reason != "" {
+				continue
+			}
+		}
+
+		repeats := 0
+		if m.last != nil && script.Type == m.last.Type {
+			repeats++
+		}
+		if m.last != nil && script.BookID == m.last.BookID {
+			repeats++
+		}
+		picks := m.bookPicks[script.BookID]
+		// How far the type is behind its share of the book's posts
+		deficit := m.p.mixFor(script.BookID).Share(script.Type)*float64(picks+1) - float64(m.typePicks[script.BookID][script.Type])
+
+		var better bool
+		switch {
+		case best < 0:
+			better = true
+		case priority != bestPriority:
+			better = priority
+		case repeats != bestRepeats:
+			better = repeats < bestRepeats
+		case picks != bestBookPicks:
+			better = picks < bestBookPicks
+		default:
+			better = deficit > bestDeficit
+		}
+		if better {
+			best, bestPriority, bestRepeats, bestBookPicks, bestDeficit = i, priority, repeats, picks, deficit
+		}
+	}
+	return best, ok
+}
+
+// take marks a script as posted and returns it
+func (m *mixer) take(i int) PlannedScript {
+	script := m.scripts[i]
+	m.used[i] = true
+	m.last = &script
+	m.bookPicks[script.BookID]++
+	if m.typePicks[script.BookID] == nil {
+		m.typePicks[script.BookID] = make(map[string]int)
+	}
+	m.typePicks[script.BookID][script.Type]++
+	return script
+}
+
+// hasBook reports whether the pool holds scripts of a book, only unused
+// ones if unused is set
+func (m *mixer) hasBook(bookID string, unused bool) bool {
+	for i, script := range m.scripts {
+		if script.BookID == bookID && !(unused && m.used[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// hours converts a number of hours to a duration
+func hours(h float64) time.Duration {
+	return time.Duration(h * float64(time.Hour))
+}
+
+// within reports whether a and b are less than d apart
+func within(a, b time.Time, d time.Duration) bool {
+	return a.Sub(b) < d && b.Sub(a) < d
+}
+
+// appendNew appends the values not in list yet
+func appendNew(list []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,391 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/repository/memory"
+)
+
+// seedBook stores a book with n scripts, their ideas taking the given types
+// in turn, and returns the book's ID
+func seedBook(t *testing.T, stores *repository.Stores, title string, n int, types ...string) string {
+	t.Helper()
+	ctx := context.Background()
+	book, err := stores.Books.Create(ctx, &models.BookInput{Title: title, Genre: "kids"})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		idea, err := stores.Content.CreateIdea(ctx, &models.ContentIdeaInput{
+			Type: types[i%len(types)], BriefDescription: "idea", BookID: &book.ID,
+		})
+		if err != nil {
+			t.Fatalf("create idea: %v", err)
+		}
+		_, err = stores.Content.CreateScript(ctx, &models.ContentScriptInput{
+			IdeaID: idea.ID, Hook: "h", FullScript: "s", CTA: "c", Hashtags: []string{},
+		})
+		if err != nil {
+			t.Fatalf("create script: %v", err)
+		}
+	}
+	return book.ID
+}
+
+// constrainedPlanner returns a planner over stores that plans from Monday
+// 2 March 2026, in UTC, under the given constraints
+func constrainedPlanner(t *testing.T, stores *repository.Stores, yaml string) *Planner {
+	t.Helper()
+	c, err := ParseConstraints([]byte(yaml))
+	if err != nil {
+		t.Fatalf("ParseConstraints() error = %v", err)
+	}
+	if err := c.ResolveBooks(context.Background(), stores.Books); err != nil {
+		t.Fatalf("ResolveBooks() error = %v", err)
+	}
+
+	optimizer := NewOptimizer()
+	if err := optimizer.SetTimezones("UTC", nil); err != nil {
+		t.Fatalf("SetTimezones() error = %v", err)
+	}
+	optimizer.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	planner := NewPlanner(stores.Content)
+	planner.SetOptimizer(optimizer)
+	planner.SetConstraints(c)
+	return planner
+}
+
+// findUnsatisfied returns the explanation of a constraint, or fails
+func findUnsatisfied(t *testing.T, plan *Plan, constraint string) string {
+	t.Helper()
+	for _, u := range plan.Unsatisfied {
+		if u.Constraint == constraint {
+			return u.Reason
+		}
+	}
+	t.Fatalf("no explanation for %s in %+v", constraint, plan.Unsatisfied)
+	return ""
+}
+
+func TestPlanner_Plan_Blackouts(t *testing.T) {
+	stores := memory.New().Stores()
+	seedBook(t, stores, "Book A", 20, "educational", "entertainment")
+	planner := constrainedPlanner(t, stores, `
+blackouts:
+  - name: no Sundays
+    weekdays: [sunday]
+  - name: TikTok only on weekdays
+    platforms: [tiktok]
+    weekdays: [saturday]
+`)
+	if err := planner.SetPlatforms([]string{"tiktok", "bluesky"}); err != nil {
+		t.Fatalf("SetPlatforms() error = %v", err)
+	}
+
+	plan, err := planner.Plan(context.Background(), 7, 2)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	// Five weekdays of two posts, and Saturday's bluesky post
+	if len(plan.Entries) != 11 {
+		t.Errorf("got %d entries, want 11", len(plan.Entries))
+	}
+	for _, entry := range plan.Entries {
+		day := entry.ScheduledFor.Weekday()
+		if day == time.Sunday || day == time.Saturday && entry.Platform == "tiktok" {
+			t.Errorf("%s post on %s breaks a blackout", entry.Platform, day)
+		}
+	}
+
+	if reason := findUnsatisfied(t, plan, "2 posts on Sun Mar 08"); reason != `planned 0; blackout "no Sundays"` {
+		t.Errorf("Sunday reason = %q", reason)
+	}
+	if reason := findUnsatisfied(t, plan, "2 posts on Sat Mar 07"); !strings.Contains(reason, `blackout "TikTok only on weekdays"`) {
+		t.Errorf("Saturday reason = %q", reason)
+	}
+}
+
+func TestPlanner_Plan_CapsAndSpacing(t *testing.T) {
+	stores := memory.New().Stores()
+	seedBook(t, stores, "Book A", 20, "educational", "entertainment")
+	seedBook(t, stores, "Book B", 20, "ugc", "trend")
+	planner := constrainedPlanner(t, stores, `
+spacing:
+  min_hours: 6
+caps:
+  per_book_per_day: 1
+  platforms:
+    bluesky: {per_week: 3}
+`)
+	if err := planner.SetPlatforms([]string{"bluesky", "tiktok"}); err != nil {
+		t.Fatalf("SetPlatforms() error = %v", err)
+	}
+
+	plan, err := planner.Plan(context.Background(), 7, 3)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	perBookDay := make(map[string]int)
+	bluesky := 0
+	pool, _, err := planner.scriptPool(context.Background(), 40)
+	if err != nil {
+		t.Fatalf("scriptPool() error = %v", err)
+	}
+	books := make(map[string]string)
+	for _, script := range pool {
+		books[script.ID] = script.BookID
+	}
+	for i, entry := range plan.Entries {
+		perBookDay[books[*entry.ScriptID]+entry.ScheduledFor.Format(models.DateFormat)]++
+		if entry.Platform == "bluesky" {
+			bluesky++
+		}
+		for _, other := range plan.Entries[:i] {
+			if within(entry.ScheduledFor, other.ScheduledFor, 6*time.Hour) {
+				t.Errorf("posts at %v and %v are less than 6 hours apart", other.ScheduledFor, entry.ScheduledFor)
+			}
+		}
+	}
+	for day, n := range perBookDay {
+		if n > 1 {
+			t.Errorf("%d posts of one book on %s", n, day)
+		}
+	}
+	if bluesky != 3 {
+		t.Errorf("got %d bluesky posts, want the weekly cap of 3", bluesky)
+	}
+
+	reason := findUnsatisfied(t, plan, "3 posts on Mon Mar 02")
+	if !strings.Contains(reason, "caps.per_book_per_day") {
+		t.Errorf("Monday reason = %q, want the per-book cap", reason)
+	}
+}
+
+func TestPlanner_Plan_CountsExistingEntries(t *testing.T) {
+	ctx := context.Background()
+	stores := memory.New().Stores()
+	bookB := seedBook(t, stores, "Book B", 10, "educational", "entertainment")
+	bookA := seedBook(t, stores, "Book A", 4, "educational", "entertainment")
+	planner := constrainedPlanner(t, stores, `
+spacing:
+  same_book_hours: 48
+caps:
+  per_day: 3
+`)
+
+	pool, _, err := planner.scriptPool(ctx, 40)
+	if err != nil {
+		t.Fatalf("scriptPool() error = %v", err)
+	}
+	books := make(map[string]string)
+	var existing []models.ContentCalendar
+	for _, script := range pool {
+		books[script.ID] = script.BookID
+		if script.BookID == bookA && len(existing) < 2 {
+			existing = append(existing, models.ContentCalendar{
+				ID:           fmt.Sprintf("entry-%d", len(existing)),
+				ScriptID:     &script.ID,
+				ScheduledFor: time.Date(2026, 3, 2, 9+9*len(existing), 0, 0, 0, time.UTC),
+				Platform:     "tiktok",
+				PostType:     "reel",
+				Status:       "approved",
+			})
+		}
+	}
+	planner.SetExisting(existing)
+
+	plan, err := planner.Plan(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	// Monday already has two of its three posts, both of Book A
+	if len(plan.Entries) != 1 {
+		t.Fatalf("got %d entries, want the one the daily cap leaves: %+v", len(plan.Entries), plan.Entries)
+	}
+	entry := plan.Entries[0]
+	if books[*entry.ScriptID] != bookB {
+		t.Errorf("planned a post of book %s, want Book B while Book A is spaced out", books[*entry.ScriptID])
+	}
+	for _, e := range existing {
+		if *entry.ScriptID == *e.ScriptID {
+			t.Errorf("planned script %s again", *entry.ScriptID)
+		}
+		if entry.ScheduledFor.Hour() == e.ScheduledFor.Hour() {
+			t.Errorf("planned a post at %v, the time of an existing one", entry.ScheduledFor)
+		}
+	}
+
+	reason := findUnsatisfied(t, plan, "2 posts on Mon Mar 02")
+	if !strings.Contains(reason, "caps.per_day") {
+		t.Errorf("Monday reason = %q, want the daily cap", reason)
+	}
+}
+
+func TestPlanner_ScriptPool_SkipsScheduledScripts(t *testing.T) {
+	ctx := context.Background()
+	stores := memory.New().Stores()
+	seedBook(t, stores, "Book A", 6, "educational")
+	planner := constrainedPlanner(t, stores, "")
+
+	all, _, err := planner.scriptPool(ctx, 6)
+	if err != nil {
+		t.Fatalf("scriptPool() error = %v", err)
+	}
+	// The two newest scripts are on the calendar already
+	var existing []models.ContentCalendar
+	for _, script := range all[:2] {
+		existing = append(existing, models.ContentCalendar{ScriptID: &script.ID, Platform: "tiktok"})
+	}
+	planner.SetExisting(existing)
+
+	pool, books, err := planner.scriptPool(ctx, 3)
+	if err != nil {
+		t.Fatalf("scriptPool() error = %v", err)
+	}
+	if len(pool) != 3 {
+		t.Fatalf("got %d scripts, want 3 not on the calendar", len(pool))
+	}
+	for i, script := range pool {
+		if script.ID != all[i+2].ID {
+			t.Errorf("script %d = %s, want %s", i, script.ID, all[i+2].ID)
+		}
+	}
+	for _, e := range existing {
+		if books[*e.ScriptID] != all[0].BookID {
+			t.Errorf("book of scheduled script %s = %q, want %q", *e.ScriptID, books[*e.ScriptID], all[0].BookID)
+		}
+	}
+}
+
+func TestPlanner_Plan_Quota(t *testing.T) {
+	stores := memory.New().Stores()
+	seedBook(t, stores, "Backlist", 20, "educational", "entertainment")
+	release := seedBook(t, stores, "New Release", 4, "trend", "ugc")
+	planner := constrainedPlanner(t, stores, `
+quotas:
+  - name: launch
+    book: `+release[:8]+`
+    from: 2026-03-03
+    to: 2026-03-04
+    posts: 3
+  - name: too much
+    book: `+release[:8]+`
+    from: 2026-03-03
+    to: 2026-03-04
+    posts: 6
+`)
+
+	plan, err := planner.Plan(context.Background(), 4, 2)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	pool, _, err := planner.scriptPool(context.Background(), 40)
+	if err != nil {
+		t.Fatalf("scriptPool() error = %v", err)
+	}
+	books := make(map[string]string)
+	for _, script := range pool {
+		books[script.ID] = script.BookID
+	}
+	launch := 0
+	for _, entry := range plan.Entries {
+		day := entry.ScheduledFor.Format(models.DateFormat)
+		if books[*entry.ScriptID] == release && day >= "2026-03-03" && day <= "2026-03-04" {
+			launch++
+		}
+	}
+	if launch < 3 {
+		t.Errorf("got %d launch posts, want at least 3", launch)
+	}
+
+	if reason := findUnsatisfied(t, plan, `quota "too much"`); !strings.HasSuffix(reason, "of 6 posts; ran out of the book's scripts") {
+		t.Errorf("quota reason = %q", reason)
+	}
+	for _, u := range plan.Unsatisfied {
+		if u.Constraint == `quota "launch"` {
+			t.Errorf("launch quota reported unsatisfied: %s", u.Reason)
+		}
+	}
+}
+
+func TestPlanner_Plan_QuotaOutsideWindow(t *testing.T) {
+	stores := memory.New().Stores()
+	book := seedBook(t, stores, "Book A", 4, "educational", "entertainment")
+	planner := constrainedPlanner(t, stores, `
+quotas:
+  - name: autumn
+    book: `+book+`
+    from: 2026-10-01
+    posts: 1
+`)
+
+	plan, err := planner.Plan(context.Background(), 2, 2)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.Entries) != 4 {
+		t.Errorf("got %d entries, want 4", len(plan.Entries))
+	}
+	if reason := findUnsatisfied(t, plan, `quota "autumn"`); !strings.Contains(reason, "outside the planned days") {
+		t.Errorf("quota reason = %q", reason)
+	}
+}
+
+func TestPlanner_Plan_PlatformTimezoneSpringForward(t *testing.T) {
+	stores := memory.New().Stores()
+	bookID := seedBook(t, stores, "Book A", 3, "educational")
+	planner := constrainedPlanner(t, stores, "spacing:\n  min_hours: 3\n")
+
+	// The slots are picked in UTC, but TikTok's audience is in New York,
+	// where DST skips 2:00 on Sunday 8 March 2026
+	optimizer := planner.optimizer
+	if err := optimizer.SetTimezones("UTC", map[string]string{"tiktok": "America/New_York"}); err != nil {
+		t.Fatalf("SetTimezones() error = %v", err)
+	}
+	optimizer.now = func() time.Time { return time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC) }
+	optimizer.AnalyzeHistoricalData("tiktok", append(
+		repeatPoints(20, 2, time.Sunday, 15),
+		repeatPoints(20, 19, time.Sunday, 3)...,
+	))
+
+	plan, err := planner.Plan(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.Entries) != 0 {
+		t.Errorf("planned %s, which New York skips", plan.Entries[0].ScheduledFor.In(mustLocation(t, "America/New_York")))
+	}
+	if reason := findUnsatisfied(t, plan, "1 posts on Sun Mar 08"); !strings.Contains(reason, "tiktok clocks skip 02:00") {
+		t.Errorf("reason = %q", reason)
+	}
+
+	// A longer script goes to Instagram, whose audience has the hour
+	idea, err := stores.Content.CreateIdea(context.Background(), &models.ContentIdeaInput{
+		Type: "educational", BriefDescription: "idea", BookID: &bookID,
+	})
+	if err != nil {
+		t.Fatalf("create idea: %v", err)
+	}
+	if _, err := stores.Content.CreateScript(context.Background(), &models.ContentScriptInput{
+		IdeaID: idea.ID, Hook: "h", FullScript: "s", CTA: "c", Hashtags: []string{}, EstimatedDuration: 90,
+	}); err != nil {
+		t.Fatalf("create script: %v", err)
+	}
+	plan, err = planner.Plan(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.Entries) != 1 || plan.Entries[0].Platform != "instagram" || plan.Entries[0].ScheduledFor.UTC().Hour() != 2 {
+		t.Errorf("entries = %+v, want the Instagram script at 2:00 UTC", plan.Entries)
+	}
+}
@@ -2,12 +2,16 @@ package social
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/errors"
 )
 
@@ -18,13 +22,25 @@ const (
 // BlotatoClient is the client for interacting with the Blotato API
 type BlotatoClient struct {
 	apiKey     string
+	baseURL    string
 	httpClient *http.Client
+
+	// Targets holds the platform settings PublishPost sends along
+	Targets BlotatoTargets
+}
+
+// BlotatoTargets are the extra target fields some platforms require
+type BlotatoTargets struct {
+	FacebookPageID   string
+	PinterestBoardID string
+	YouTubePrivacy   string // public (default), unlisted or private
 }
 
 // NewBlotatoClient creates a new Blotato API client
 func NewBlotatoClient(apiKey string) *BlotatoClient {
 	return &BlotatoClient{
 		apiKey:     apiKey,
+		baseURL:    BlotatoBaseURL,
 		httpClient: &http.Client{Timeout: 30 * time.Second},
 	}
 }
@@ -43,12 +59,12 @@ type accountsListResponse struct {
 
 // GetAccountID fetches the user's connected accounts and returns the account ID for the requested platform.
 // For Facebook/LinkedIn, this is the main accountId (subaccounts handling might be needed later).
-func (c *BlotatoClient) GetAccountID(platform string) (string, error) {
+func (c *BlotatoClient) GetAccountID(ctx context.Context, platform string) (string, error) {
 	if c.apiKey == "" {
 		return "", fmt.Errorf("blotato API key is not configured")
 	}
 
-	req, err := http.NewRequest("GET", BlotatoBaseURL+"/users/me/accounts?platform="+platform, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/users/me/accounts?platform="+platform, nil)
 	if err != nil {
 		return "", err
 	}
@@ -101,7 +117,40 @@ type PostContent struct {
 
 type PostTarget struct {
 	TargetType string `json:"targetType"`
-	// Additional fields like PrivacyLevel could be added here later for TikTok, etc.
+
+	PageID                  string `json:"pageId,omitempty"`        // facebook
+	BoardID                 string `json:"boardId,omitempty"`       // pinterest
+	Title                   string `json:"title,omitempty"`         // youtube
+	PrivacyStatus           string `json:"privacyStatus,omitempty"` // youtube
+	ShouldNotifySubscribers *bool  `json:"shouldNotifySubscribers,omitempty"`
+}
+
+// youtubeTitleLength is the longest title YouTube accepts
+const youtubeTitleLength = 100
+
+// target returns the target of a post to platform with text
+func (c *BlotatoClient) target(platform, text string) PostTarget {
+	target := PostTarget{TargetType: platform}
+	switch platform {
+	case "facebook":
+		target.PageID = c.Targets.FacebookPageID
+	case "pinterest":
+		target.BoardID = c.Targets.PinterestBoardID
+	case "youtube":
+		// The title is the first line of the text
+		title, _, _ := strings.Cut(text, "\n")
+		if runes := []rune(strings.TrimSpace(title)); len(runes) > youtubeTitleLength {
+			title = string(runes[:youtubeTitleLength])
+		}
+		target.Title = strings.TrimSpace(title)
+		target.PrivacyStatus = c.Targets.YouTubePrivacy
+		if target.PrivacyStatus == "" {
+			target.PrivacyStatus = "public"
+		}
+		notify := true
+		target.ShouldNotifySubscribers = &notify
+	}
+	return target
 }
 
 type PublishResponse struct {
@@ -134,7 +183,7 @@ type VisualStatusResponse struct {
 
 // GenerateVisual requests a visual creation using a template and a prompt.
 // It returns the creation ID that can be used for polling.
-func (c *BlotatoClient) GenerateVisual(templateID string, prompt string) (string, error) {
+func (c *BlotatoClient) GenerateVisual(ctx context.Context, templateID string, prompt string) (string, error) {
 	if c.apiKey == "" {
 		return "", fmt.Errorf("blotato API key is not configured")
 	}
@@ -151,7 +200,7 @@ func (c *BlotatoClient) GenerateVisual(templateID string, prompt string) (string
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", BlotatoBaseURL+"/videos/from-templates", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/videos/from-templates", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", err
 	}
@@ -179,8 +228,8 @@ func (c *BlotatoClient) GenerateVisual(templateID string, prompt string) (string
 }
 
 // WaitForVisualCreation polls the API until the visual status is 'done' or an error occurs.
-// It returns the URL of the generated media.
-func (c *BlotatoClient) WaitForVisualCreation(creationID string) (string, error) {
+// It returns the URL of the generated media. Polling stops early if ctx is cancelled.
+func (c *BlotatoClient) WaitForVisualCreation(ctx context.Context, creationID string) (string, error) {
 	if c.apiKey == "" {
 		return "", fmt.Errorf("blotato API key is not configured")
 	}
@@ -188,7 +237,7 @@ func (c *BlotatoClient) WaitForVisualCreation(creationID string) (string, error)
 	maxAttempts := 60 // 60 attempts * 5 seconds = 5 minutes timeout
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
-		req, err := http.NewRequest("GET", BlotatoBaseURL+"/videos/creations/"+creationID, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/videos/creations/"+creationID, nil)
 		if err != nil {
 			return "", err
 		}
@@ -227,7 +276,11 @@ func (c *BlotatoClient) WaitForVisualCreation(creationID string) (string, error)
 			return "", fmt.Errorf("visual generation failed at Blotato")
 		case "queueing", "generating-script", "script-ready", "generating-media", "media-ready", "exporting":
 			// Still processing, wait and poll again
-			time.Sleep(5 * time.Second)
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(5 * time.Second):
+			}
 		default:
 			return "", fmt.Errorf("unknown visual status: %s", statusResp.Item.Status)
 		}
@@ -237,7 +290,7 @@ func (c *BlotatoClient) WaitForVisualCreation(creationID string) (string, error)
 }
 
 // PublishPost creates or schedules a post on Blotato
-func (c *BlotatoClient) PublishPost(accountId, platform, text string, mediaUrls []string, scheduledTime *time.Time) (string, error) {
+func (c *BlotatoClient) PublishPost(ctx context.Context, accountId, platform, text string, mediaUrls []string, scheduledTime *time.Time) (string, error) {
 	if c.apiKey == "" {
 		return "", fmt.Errorf("blotato API key is not configured")
 	}
@@ -254,9 +307,7 @@ func (c *BlotatoClient) PublishPost(accountId, platform, text string, mediaUrls
 				MediaURLs: mediaUrls,
 				Platform:  platform,
 			},
-			Target: PostTarget{
-				TargetType: platform,
-			},
+			Target: c.target(platform, text),
 		},
 	}
 
@@ -270,7 +321,7 @@ func (c *BlotatoClient) PublishPost(accountId, platform, text string, mediaUrls
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", BlotatoBaseURL+"/posts", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/posts", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", err
 	}
@@ -296,3 +347,116 @@ func (c *BlotatoClient) PublishPost(accountId, platform, text string, mediaUrls
 
 	return pubResp.PostSubmissionID, nil
 }
+
+// Post statuses reported by Blotato for a submission
+const (
+	PostStatusInProgress = "in-progress"
+	PostStatusPublished  = "published"
+	PostStatusFailed     = "failed"
+)
+
+// PostStatus is the delivery state of a submitted post. Blotato reports it
+// in this form; the other publishers translate theirs to it.
+type PostStatus struct {
+	PostSubmissionID string `json:"postSubmissionId"`
+	Status           string `json:"status"`                 // in-progress, published or failed
+	PublicURL        string `json:"publicUrl,omitempty"`    // set once published
+	ErrorMessage     string `json:"errorMessage,omitempty"` // set when failed
+}
+
+// GetPostStatus fetches the delivery state of a post submitted with
+// PublishPost. A scheduled post stays in-progress until its time comes.
+func (c *BlotatoClient) GetPostStatus(ctx context.Context, submissionID string) (*PostStatus, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("blotato API key is not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/posts/"+url.PathEscape(submissionID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("blotato-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeAPI, "failed to connect to Blotato API")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.New(errors.ErrorTypeNotFound, "blotato has no post submission "+submissionID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("blotato post status error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var status PostStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeValidation, "failed to parse Blotato post status response")
+	}
+	if status.PostSubmissionID == "" {
+		status.PostSubmissionID = submissionID
+	}
+
+	return &status, nil
+}
+
+func init() {
+	RegisterPublisher("blotato", []string{"instagram", "tiktok", "youtube", "facebook", "pinterest", "threads", "bluesky"}, newBlotatoPublisher)
+}
+
+// blotatoPublisher publishes through Blotato, which queues posts until
+// their scheduled time and delivers them itself
+type blotatoPublisher struct {
+	client   *BlotatoClient
+	accounts map[string]string // connected account ID per platform
+}
+
+func newBlotatoPublisher(cfg *config.Config) (Publisher, error) {
+	if cfg.Blotato.APIKey == "" {
+		return nil, fmt.Errorf("blotato API key is not configured. Please run 'gagipress config set blotato.api_key YOUR_KEY'")
+	}
+	client := NewBlotatoClient(cfg.Blotato.APIKey)
+	client.Targets = BlotatoTargets{
+		FacebookPageID:   cfg.Blotato.FacebookPageID,
+		PinterestBoardID: cfg.Blotato.PinterestBoardID,
+		YouTubePrivacy:   cfg.Blotato.YouTubePrivacy,
+	}
+	return &blotatoPublisher{client: client, accounts: map[string]string{}}, nil
+}
+
+func (p *blotatoPublisher) Publish(ctx context.Context, req *PublishRequest) (*PostStatus, error) {
+	accountID, ok := p.accounts[req.Platform]
+	if !ok {
+		var err error
+		accountID, err = p.client.GetAccountID(ctx, req.Platform)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Blotato account for %s: %w", req.Platform, err)
+		}
+		p.accounts[req.Platform] = accountID
+	}
+
+	var scheduled *time.Time
+	if !req.ScheduledFor.IsZero() {
+		scheduled = &req.ScheduledFor
+	}
+	submissionID, err := p.client.PublishPost(ctx, accountID, req.Platform, req.Text, req.MediaURLs, scheduled)
+	if err != nil {
+		return nil, err
+	}
+	return &PostStatus{PostSubmissionID: submissionID, Status: PostStatusInProgress}, nil
+}
+
+func (p *blotatoPublisher) Status(ctx context.Context, submissionID string) (*PostStatus, error) {
+	return p.client.GetPostStatus(ctx, submissionID)
+}
+
+func (p *blotatoPublisher) Delete(ctx context.Context, submissionID string) error {
+	return errors.New(errors.ErrorTypeValidation, "posts submitted through Blotato cannot be deleted from gagipress; delete them in Blotato")
+}
+
+func (p *blotatoPublisher) Capabilities() Capabilities {
+	return Capabilities{PostTypes: []string{"reel", "story", "feed"}, Schedule: true}
+}
@@ -0,0 +1,67 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagipress/gagipress-cli/internal/errors"
+)
+
+func newTestBlotato(t *testing.T, handler http.HandlerFunc) *BlotatoClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c := NewBlotatoClient("test-key")
+	c.baseURL = srv.URL
+	return c
+}
+
+func TestGetPostStatus(t *testing.T) {
+	c := newTestBlotato(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/posts/sub-1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("blotato-api-key") != "test-key" {
+			t.Errorf("missing API key header")
+		}
+		w.Write([]byte(`{"postSubmissionId":"sub-1","status":"published","publicUrl":"https://www.tiktok.com/@me/video/1"}`))
+	})
+
+	status, err := c.GetPostStatus(context.Background(), "sub-1")
+	if err != nil {
+		t.Fatalf("GetPostStatus() error = %v", err)
+	}
+	if status.Status != PostStatusPublished || status.PublicURL != "https://www.tiktok.com/@me/video/1" {
+		t.Errorf("GetPostStatus() = %+v", status)
+	}
+}
+
+func TestGetPostStatus_Failed(t *testing.T) {
+	c := newTestBlotato(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"failed","errorMessage":"video too long"}`))
+	})
+
+	status, err := c.GetPostStatus(context.Background(), "sub-2")
+	if err != nil {
+		t.Fatalf("GetPostStatus() error = %v", err)
+	}
+	if status.Status != PostStatusFailed || status.ErrorMessage != "video too long" {
+		t.Errorf("GetPostStatus() = %+v", status)
+	}
+	if status.PostSubmissionID != "sub-2" {
+		t.Errorf("PostSubmissionID = %q, want the requested ID", status.PostSubmissionID)
+	}
+}
+
+func TestGetPostStatus_NotFound(t *testing.T) {
+	c := newTestBlotato(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+	})
+
+	_, err := c.GetPostStatus(context.Background(), "missing")
+	if !errors.IsType(err, errors.ErrorTypeNotFound) {
+		t.Errorf("GetPostStatus() error = %v, want not found", err)
+	}
+}
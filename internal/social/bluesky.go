@@ -0,0 +1,800 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/errors"
+)
+
+// BlueskyPDSURL is the default server accounts log in to. It forwards
+// requests to the PDS that hosts the account.
+const BlueskyPDSURL = "https://bsky.social"
+
+// Bluesky limits. Posts are limited to 300 graphemes; the client counts
+// runes, which is never fewer.
+const (
+	blueskyPostLength = 300
+	blueskyMaxImages  = 4
+	blueskyImageSize  = 1000000
+	blueskyVideoSize  = 100 * 1024 * 1024
+)
+
+// Record types of the app.bsky lexicons
+const (
+	blueskyPostCollection = "app.bsky.feed.post"
+	blueskyLinkFeature    = "app.bsky.richtext.facet#link"
+	blueskyTagFeature     = "app.bsky.richtext.facet#tag"
+	blueskyImagesEmbed    = "app.bsky.embed.images"
+	blueskyVideoEmbed     = "app.bsky.embed.video"
+)
+
+// BlueskyClient posts to a Bluesky account through the AT Protocol XRPC
+// API of its PDS, logged in with an app password
+type BlueskyClient struct {
+	cfg        *config.BlueskyConfig
+	baseURL    string
+	httpClient *http.Client
+	session    *BlueskySession
+}
+
+// BlueskySession is a logged in session. The access token lasts a few
+// minutes; the refresh token renews it.
+type BlueskySession struct {
+	AccessJwt  string `json:"accessJwt"`
+	RefreshJwt string `json:"refreshJwt"`
+	DID        string `json:"did"`
+	Handle     string `json:"handle"`
+}
+
+// BlueskyBlob is a blob uploaded to the PDS, as records reference it
+type BlueskyBlob struct {
+	Type string `json:"$type"` // always "blob"
+	Ref  struct {
+		Link string `json:"$link"` // CID
+	} `json:"ref"`
+	MimeType string `json:"mimeType"`
+	Size     int    `json:"size"`
+}
+
+// BlueskyPost is an app.bsky.feed.post record
+type BlueskyPost struct {
+	Type      string         `json:"$type"`
+	Text      string         `json:"text"`
+	CreatedAt string         `json:"createdAt"`
+	Facets    []BlueskyFacet `json:"facets,omitempty"`
+	Embed     any            `json:"embed,omitempty"`
+	Reply     *BlueskyReply  `json:"reply,omitempty"`
+}
+
+// BlueskyFacet marks up a range of a post's text, in UTF-8 bytes
+type BlueskyFacet struct {
+	Index struct {
+		ByteStart int `json:"byteStart"`
+		ByteEnd   int `json:"byteEnd"`
+	} `json:"index"`
+	Features []BlueskyFeature `json:"features"`
+}
+
+// BlueskyFeature is a link (URI) or a hashtag (Tag, without the #)
+type BlueskyFeature struct {
+	Type string `json:"$type"`
+	URI  string `json:"uri,omitempty"`
+	Tag  string `json:"tag,omitempty"`
+}
+
+// BlueskyRef points at a specific version of a record
+type BlueskyRef struct {
+	URI string `json:"uri"`
+	CID string `json:"cid"`
+}
+
+// BlueskyReply places a post in a thread
+type BlueskyReply struct {
+	Root   BlueskyRef `json:"root"`
+	Parent BlueskyRef `json:"parent"`
+}
+
+// BlueskyError is an error returned by an XRPC endpoint
+type BlueskyError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"error"`
+	Message    string `json:"message"`
+}
+
+func (e *BlueskyError) Error() string {
+	return fmt.Sprintf("bluesky API error (%d, %s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+// TokenExpired reports whether the error means the session has to be
+// refreshed
+func (e *BlueskyError) TokenExpired() bool {
+	return e.Code == "ExpiredToken"
+}
+
+// NewBlueskyClient creates a new Bluesky client
+func NewBlueskyClient(cfg *config.BlueskyConfig) *BlueskyClient {
+	baseURL := strings.TrimRight(cfg.PDSURL, "/")
+	if baseURL == "" {
+		baseURL = BlueskyPDSURL
+	}
+	return &BlueskyClient{
+		cfg:        cfg,
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 120 * time.Second},
+	}
+}
+
+// CreateSession logs in with the configured handle and app password
+func (c *BlueskyClient) CreateSession(ctx context.Context) (*BlueskySession, error) {
+	if c.cfg.Handle == "" || c.cfg.AppPassword == "" {
+		return nil, fmt.Errorf("bluesky handle and app password are not configured")
+	}
+	input, err := json.Marshal(map[string]string{"identifier": c.cfg.Handle, "password": c.cfg.AppPassword})
+	if err != nil {
+		return nil, err
+	}
+	var session BlueskySession
+	if err := c.request(ctx, http.MethodPost, "com.atproto.server.createSession", nil, "application/json", input, "", &session); err != nil {
+		return nil, fmt.Errorf("failed to log in to Bluesky as %s: %w", c.cfg.Handle, err)
+	}
+	c.session = &session
+	return &session, nil
+}
+
+// refreshSession renews the access token, or logs in again if the refresh
+// token is no longer valid either
+func (c *BlueskyClient) refreshSession(ctx context.Context) error {
+	var session BlueskySession
+	err := c.request(ctx, http.MethodPost, "com.atproto.server.refreshSession", nil, "", nil, c.session.RefreshJwt, &session)
+	if err == nil {
+		c.session = &session
+		return nil
+	}
+	_, err = c.CreateSession(ctx)
+	return err
+}
+
+// TestConnection logs in and checks the session
+func (c *BlueskyClient) TestConnection(ctx context.Context) (*BlueskySession, error) {
+	if _, err := c.CreateSession(ctx); err != nil {
+		return nil, err
+	}
+	var session BlueskySession
+	if err := c.query(ctx, "com.atproto.server.getSession", nil, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UploadBlob uploads an image or video and returns the blob to embed
+func (c *BlueskyClient) UploadBlob(ctx context.Context, data []byte, mimeType string) (*BlueskyBlob, error) {
+	var out struct {
+		Blob BlueskyBlob `json:"blob"`
+	}
+	if err := c.call(ctx, http.MethodPost, "com.atproto.repo.uploadBlob", nil, mimeType, data, &out); err != nil {
+		return nil, fmt.Errorf("failed to upload %s to Bluesky: %w", mimeType, err)
+	}
+	return &out.Blob, nil
+}
+
+// CreatePost creates a post record in the account's repo
+func (c *BlueskyClient) CreatePost(ctx context.Context, post *BlueskyPost) (*BlueskyRef, error) {
+	post.Type = blueskyPostCollection
+	if post.CreatedAt == "" {
+		post.CreatedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	if err := c.ensureSession(ctx); err != nil {
+		return nil, err
+	}
+	var ref BlueskyRef
+	input := map[string]any{"repo": c.session.DID, "collection": blueskyPostCollection, "record": post}
+	if err := c.procedure(ctx, "com.atproto.repo.createRecord", input, &ref); err != nil {
+		return nil, err
+	}
+	return &ref, nil
+}
+
+// PublishThread posts text, threaded into replies when it is longer than
+// a post, with the media at mediaURLs attached to the first post. It
+// returns the refs of the posts, root first. If a reply fails, the posts
+// already created are deleted.
+func (c *BlueskyClient) PublishThread(ctx context.Context, text string, mediaURLs []string) ([]BlueskyRef, error) {
+	parts := ThreadPosts(text, blueskyPostLength)
+	if len(parts) == 0 && len(mediaURLs) == 0 {
+		return nil, errors.New(errors.ErrorTypeValidation, "bluesky post is empty")
+	}
+	if len(parts) == 0 {
+		parts = []*BlueskyPost{{}}
+	}
+
+	embed, err := c.mediaEmbed(ctx, mediaURLs)
+	if err != nil {
+		return nil, err
+	}
+	parts[0].Embed = embed
+
+	var refs []BlueskyRef
+	for _, post := range parts {
+		if len(refs) > 0 {
+			post.Reply = &BlueskyReply{Root: refs[0], Parent: refs[len(refs)-1]}
+		}
+		ref, err := c.CreatePost(ctx, post)
+		if err != nil {
+			for i := len(refs) - 1; i >= 0; i-- {
+				_ = c.DeleteRecord(ctx, refs[i].URI)
+			}
+			return nil, fmt.Errorf("failed to post to Bluesky (part %d/%d): %w", len(refs)+1, len(parts), err)
+		}
+		refs = append(refs, *ref)
+	}
+	return refs, nil
+}
+
+// GetPost fetches a post record by its at:// URI
+func (c *BlueskyClient) GetPost(ctx context.Context, uri string) (*BlueskyPost, error) {
+	repo, collection, rkey, err := parseATURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Value BlueskyPost `json:"value"`
+	}
+	params := url.Values{"repo": {repo}, "collection": {collection}, "rkey": {rkey}}
+	if err := c.query(ctx, "com.atproto.repo.getRecord", params, &out); err != nil {
+		return nil, err
+	}
+	return &out.Value, nil
+}
+
+// BlueskyCounts are the interaction counts of a post, as the AppView
+// reports them
+type BlueskyCounts struct {
+	URI         string `json:"uri"`
+	LikeCount   int    `json:"likeCount"`
+	RepostCount int    `json:"repostCount"`
+	ReplyCount  int    `json:"replyCount"`
+	QuoteCount  int    `json:"quoteCount"`
+}
+
+// GetPostCounts fetches the interaction counts of a post through the PDS,
+// which forwards app.bsky queries to the AppView
+func (c *BlueskyClient) GetPostCounts(ctx context.Context, uri string) (*BlueskyCounts, error) {
+	var out struct {
+		Posts []BlueskyCounts `json:"posts"`
+	}
+	if err := c.query(ctx, "app.bsky.feed.getPosts", url.Values{"uris": {uri}}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Posts) == 0 {
+		return nil, errors.New(errors.ErrorTypeNotFound, "bluesky post not found: "+uri)
+	}
+	return &out.Posts[0], nil
+}
+
+// DeleteRecord deletes a record by its at:// URI
+func (c *BlueskyClient) DeleteRecord(ctx context.Context, uri string) error {
+	repo, collection, rkey, err := parseATURI(uri)
+	if err != nil {
+		return err
+	}
+	input := map[string]string{"repo": repo, "collection": collection, "rkey": rkey}
+	return c.procedure(ctx, "com.atproto.repo.deleteRecord", input, nil)
+}
+
+// DeleteThread deletes a post and the replies to it the account made. Record
+// keys are timestamps, so the account's posts are listed newest first
+// until the root's key comes up.
+func (c *BlueskyClient) DeleteThread(ctx context.Context, rootURI string) error {
+	repo, _, rootKey, err := parseATURI(rootURI)
+	if err != nil {
+		return err
+	}
+
+	var replies []string
+	cursor := ""
+	for {
+		params := url.Values{"repo": {repo}, "collection": {blueskyPostCollection}, "limit": {"100"}}
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+		var page struct {
+			Cursor  string `json:"cursor"`
+			Records []struct {
+				URI   string      `json:"uri"`
+				Value BlueskyPost `json:"value"`
+			} `json:"records"`
+		}
+		if err := c.query(ctx, "com.atproto.repo.listRecords", params, &page); err != nil {
+			return err
+		}
+
+		done := page.Cursor == "" || len(page.Records) == 0
+		for _, record := range page.Records {
+			_, _, rkey, err := parseATURI(record.URI)
+			if err != nil || rkey <= rootKey {
+				done = true
+				break
+			}
+			if record.Value.Reply != nil && record.Value.Reply.Root.URI == rootURI {
+				replies = append(replies, record.URI)
+			}
+		}
+		if done {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	for _, uri := range replies {
+		if err := c.DeleteRecord(ctx, uri); err != nil {
+			return err
+		}
+	}
+	return c.DeleteRecord(ctx, rootURI)
+}
+
+// postURI turns a bsky.app post address into its at:// URI. The profile
+// part may be a handle, which the AppView resolves.
+func postURI(postURL string) (string, error) {
+	var parts []string
+	if u, err := url.Parse(postURL); err == nil {
+		parts = strings.Split(strings.Trim(u.Path, "/"), "/")
+	}
+	if len(parts) != 4 || parts[0] != "profile" || parts[2] != "post" {
+		return "", errors.New(errors.ErrorTypeValidation, "not a Bluesky post URL: "+postURL)
+	}
+	return "at://" + parts[1] + "/" + blueskyPostCollection + "/" + parts[3], nil
+}
+
+// PostURL returns the bsky.app address of a post
+func (c *BlueskyClient) PostURL(uri string) string {
+	repo, _, rkey, err := parseATURI(uri)
+	if err != nil {
+		return ""
+	}
+	if c.session != nil && c.session.DID == repo && c.session.Handle != "" {
+		repo = c.session.Handle
+	}
+	return "https://bsky.app/profile/" + repo + "/post/" + rkey
+}
+
+// mediaEmbed downloads the media at urls and uploads it as blobs: one
+// video, or up to four images
+func (c *BlueskyClient) mediaEmbed(ctx context.Context, urls []string) (any, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	var images []map[string]any
+	for _, mediaURL := range urls {
+		data, mimeType, err := c.download(ctx, mediaURL)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(mimeType, "video/") {
+			if len(images) > 0 || len(urls) > 1 {
+				return nil, errors.New(errors.ErrorTypeValidation, "bluesky posts take one video or up to four images, not both")
+			}
+			if len(data) > blueskyVideoSize {
+				return nil, errors.New(errors.ErrorTypeValidation, fmt.Sprintf("video %s is larger than Bluesky's %d MB limit", mediaURL, blueskyVideoSize/1024/1024))
+			}
+			blob, err := c.UploadBlob(ctx, data, mimeType)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"$type": blueskyVideoEmbed, "video": blob}, nil
+		}
+
+		if !strings.HasPrefix(mimeType, "image/") {
+			return nil, errors.New(errors.ErrorTypeValidation, fmt.Sprintf("%s is %s, not an image or video", mediaURL, mimeType))
+		}
+		if len(images) == blueskyMaxImages {
+			return nil, errors.New(errors.ErrorTypeValidation, fmt.Sprintf("bluesky posts take up to %d images", blueskyMaxImages))
+		}
+		if len(data) > blueskyImageSize {
+			return nil, errors.New(errors.ErrorTypeValidation, fmt.Sprintf("image %s is larger than Bluesky's 1 MB limit", mediaURL))
+		}
+		blob, err := c.UploadBlob(ctx, data, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, map[string]any{"alt": "", "image": blob})
+	}
+	return map[string]any{"$type": blueskyImagesEmbed, "images": images}, nil
+}
+
+// download fetches a media file and its MIME type
+func (c *BlueskyClient) download(ctx context.Context, mediaURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mediaURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", errors.Wrap(err, errors.ErrorTypeNetwork, "failed to download "+mediaURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", errors.New(errors.ErrorTypeNetwork, fmt.Sprintf("failed to download %s: %s", mediaURL, resp.Status))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, blueskyVideoSize+1))
+	if err != nil {
+		return nil, "", errors.Wrap(err, errors.ErrorTypeNetwork, "failed to download "+mediaURL)
+	}
+	mimeType, _, _ := strings.Cut(resp.Header.Get("Content-Type"), ";")
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType, _, _ = strings.Cut(http.DetectContentType(data), ";")
+	}
+	return data, mimeType, nil
+}
+
+// ensureSession logs in on first use
+func (c *BlueskyClient) ensureSession(ctx context.Context) error {
+	if c.session != nil {
+		return nil
+	}
+	_, err := c.CreateSession(ctx)
+	return err
+}
+
+// query sends an XRPC query (GET) and decodes its output into out
+func (c *BlueskyClient) query(ctx context.Context, nsid string, params url.Values, out any) error {
+	return c.call(ctx, http.MethodGet, nsid, params, "", nil, out)
+}
+
+// procedure sends an XRPC procedure (POST) with a JSON input and decodes
+// its output into out, if not nil
+func (c *BlueskyClient) procedure(ctx context.Context, nsid string, input any, out any) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, http.MethodPost, nsid, nil, "application/json", data, out)
+}
+
+// call sends an XRPC request with the session's access token, logging in
+// first if needed and refreshing the session once if it expired
+func (c *BlueskyClient) call(ctx context.Context, method, nsid string, params url.Values, contentType string, body []byte, out any) error {
+	if err := c.ensureSession(ctx); err != nil {
+		return err
+	}
+	err := c.request(ctx, method, nsid, params, contentType, body, c.session.AccessJwt, out)
+	if xrpcErr, ok := err.(*BlueskyError); ok && xrpcErr.TokenExpired() {
+		if err := c.refreshSession(ctx); err != nil {
+			return err
+		}
+		err = c.request(ctx, method, nsid, params, contentType, body, c.session.AccessJwt, out)
+	}
+	return err
+}
+
+// request sends one XRPC request, authorized with token if set
+func (c *BlueskyClient) request(ctx context.Context, method, nsid string, params url.Values, contentType string, body []byte, token string, out any) error {
+	endpoint := c.baseURL + "/xrpc/" + nsid
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeAPI, "failed to connect to Bluesky")
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeNetwork, "failed to read Bluesky response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		xrpcErr := &BlueskyError{StatusCode: resp.StatusCode}
+		if json.Unmarshal(raw, xrpcErr) != nil || xrpcErr.Code == "" {
+			xrpcErr.Code = http.StatusText(resp.StatusCode)
+			xrpcErr.Message = string(raw)
+		}
+		return xrpcErr
+	}
+
+	if out == nil || len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeValidation, "failed to parse Bluesky response")
+	}
+	return nil
+}
+
+// parseATURI splits an at://repo/collection/rkey URI
+func parseATURI(uri string) (repo, collection, rkey string, err error) {
+	parts := strings.Split(strings.TrimPrefix(uri, "at://"), "/")
+	if !strings.HasPrefix(uri, "at://") || len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", errors.New(errors.ErrorTypeValidation, "invalid at:// URI: "+uri)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// linkPattern finds links in post text: URLs with a scheme, and bare Amazon
+// links, which scripts sometimes write without one
+var linkPattern = regexp.MustCompile(`https?://[^\s]+|\b(?:www\.)?(?:amazon\.(?:[a-z]{2,3}|co\.[a-z]{2}|com\.[a-z]{2})|amzn\.(?:to|eu))/[^\s]*`)
+
+// tagPattern finds hashtags: a # at the start of the text or after a space
+var tagPattern = regexp.MustCompile(`(?:^|\s)([#＃][^\s#＃]+)`)
+
+// trailingPunctuation is trimmed from the end of links and hashtags
+const trailingPunctuation = `.,;:!?)]}"'`
+
+// linkDisplayLength is how much of a link a post shows. The facet keeps the
+// full URL, so long affiliate links do not eat into the post length.
+const linkDisplayLength = 32
+
+// textLink is a link in post text, by byte offsets
+type textLink struct {
+	start, end int
+	uri        string
+}
+
+// ThreadPosts turns text into the posts of a thread of at most limit runes
+// each, with link and hashtag facets. Links are shortened to their host and
+// path; the thread breaks at paragraphs, then sentences, then words.
+func ThreadPosts(text string, limit int) []*BlueskyPost {
+	text, links := shortenLinks(strings.TrimSpace(text))
+
+	var posts []*BlueskyPost
+	for _, span := range splitThread(text, limit) {
+		post := &BlueskyPost{Text: text[span[0]:span[1]]}
+		for _, link := range links {
+			if link.start >= span[0] && link.end <= span[1] {
+				post.Facets = append(post.Facets, newFacet(link.start-span[0], link.end-span[0],
+					BlueskyFeature{Type: blueskyLinkFeature, URI: link.uri}))
+			}
+		}
+		for _, m := range tagPattern.FindAllStringSubmatchIndex(post.Text, -1) {
+			start, end := m[2], m[3]
+			tag := strings.TrimRight(post.Text[start:end], trailingPunctuation)
+			name := tag[utf8.RuneLen([]rune(tag)[0]):]
+			if !validTag(name) || insideLink(post.Facets, start) {
+				continue
+			}
+			post.Facets = append(post.Facets, newFacet(start, start+len(tag),
+				BlueskyFeature{Type: blueskyTagFeature, Tag: name}))
+		}
+		posts = append(posts, post)
+	}
+	return posts
+}
+
+// shortenLinks replaces the links in text with their display form and
+// returns where they ended up
+func shortenLinks(text string) (string, []textLink) {
+	var out strings.Builder
+	var links []textLink
+	last := 0
+	for _, m := range linkPattern.FindAllStringIndex(text, -1) {
+		raw := strings.TrimRight(text[m[0]:m[1]], trailingPunctuation)
+		uri := raw
+		if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+			uri = "https://" + uri
+		}
+
+		out.WriteString(text[last:m[0]])
+		display := linkDisplay(uri)
+		links = append(links, textLink{start: out.Len(), end: out.Len() + len(display), uri: uri})
+		out.WriteString(display)
+		last = m[0] + len(raw)
+	}
+	out.WriteString(text[last:])
+	return out.String(), links
+}
+
+// linkDisplay is a link without scheme, www. and query, cut to
+// linkDisplayLength runes
+func linkDisplay(uri string) string {
+	display := uri
+	if u, err := url.Parse(uri); err == nil && u.Host != "" {
+		display = strings.TrimPrefix(u.Host, "www.") + strings.TrimRight(u.EscapedPath(), "/")
+		if u.RawQuery != "" || u.Fragment != "" {
+			display += "..."
+		}
+	}
+	if runes := []rune(display); len(runes) > linkDisplayLength {
+		display = string(runes[:linkDisplayLength-3]) + "..."
+	}
+	return display
+}
+
+// splitThread splits text into byte spans of at most limit runes, trimmed
+// of surrounding whitespace
+func splitThread(text string, limit int) [][2]int {
+	var spans [][2]int
+	start := skipSpace(text, 0)
+	for start < len(text) {
+		rest := text[start:]
+		if utf8.RuneCountInString(rest) <= limit {
+			spans = append(spans, [2]int{start, start + len(strings.TrimRightFunc(rest, unicode.IsSpace))})
+			break
+		}
+
+		// The window is what fits, plus the next character if it is a
+		// space, as breaking there loses nothing
+		cut := runeOffset(rest, limit)
+		window := rest[:cut]
+		if r, size := utf8.DecodeRuneInString(rest[cut:]); unicode.IsSpace(r) {
+			window = rest[:cut+size]
+		}
+
+		brk := breakPoint(window)
+		if brk <= 0 {
+			brk = cut // a single word longer than a post
+		}
+		spans = append(spans, [2]int{start, start + len(strings.TrimRightFunc(rest[:brk], unicode.IsSpace))})
+		start = skipSpace(text, start+brk)
+	}
+	return spans
+}
+
+// breakPoint returns where to end a post within window: after the last
+// paragraph or sentence in its second half, or else at the last space
+func breakPoint(window string) int {
+	half := len(window) / 2
+	if i := strings.LastIndex(window, "\n\n"); i >= half {
+		return i
+	}
+	best := -1
+	for _, end := range []string{". ", "! ", "? ", ".\n", "!\n", "?\n"} {
+		if i := strings.LastIndex(window, end); i+1 > best {
+			best = i + 1
+		}
+	}
+	if best >= half {
+		return best
+	}
+	return strings.LastIndexFunc(window, unicode.IsSpace)
+}
+
+// runeOffset returns the byte offset of the n-th rune of s
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+func skipSpace(s string, i int) int {
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// validTag reports whether a hashtag name is one Bluesky recognizes: up to
+// 64 characters and not just digits
+func validTag(name string) bool {
+	if name == "" || utf8.RuneCountInString(name) > 64 {
+		return false
+	}
+	return strings.TrimFunc(name, unicode.IsDigit) != ""
+}
+
+// insideLink reports whether a byte offset falls within a link facet
+func insideLink(facets []BlueskyFacet, offset int) bool {
+	for _, f := range facets {
+		if offset >= f.Index.ByteStart && offset < f.Index.ByteEnd {
+			return true
+		}
+	}
+	return false
+}
+
+func newFacet(start, end int, feature BlueskyFeature) BlueskyFacet {
+	var f BlueskyFacet
+	f.Index.ByteStart, f.Index.ByteEnd = start, end
+	f.Features = []BlueskyFeature{feature}
+	return f
+}
+
+func init() {
+	RegisterPublisher("bluesky", []string{"bluesky"}, newBlueskyPublisher)
+}
+
+// blueskyPublisher posts natively over the AT Protocol. Posts are live as
+// soon as they are created, so Publish reports them published; long texts
+// become threads, identified by their first post.
+type blueskyPublisher struct {
+	client *BlueskyClient
+}
+
+func newBlueskyPublisher(cfg *config.Config) (Publisher, error) {
+	if cfg.Bluesky.Handle == "" || cfg.Bluesky.AppPassword == "" {
+		return nil, fmt.Errorf("bluesky is not configured; run 'gagipress auth bluesky --handle HANDLE --app-password PASSWORD'")
+	}
+	return &blueskyPublisher{client: NewBlueskyClient(&cfg.Bluesky)}, nil
+}
+
+func (p *blueskyPublisher) Publish(ctx context.Context, req *PublishRequest) (*PostStatus, error) {
+	if err := p.Capabilities().Check(req); err != nil {
+		return nil, err
+	}
+	refs, err := p.client.PublishThread(ctx, req.Text, req.MediaURLs)
+	if err != nil {
+		return nil, err
+	}
+	return &PostStatus{PostSubmissionID: refs[0].URI, Status: PostStatusPublished, PublicURL: p.client.PostURL(refs[0].URI)}, nil
+}
+
+func (p *blueskyPublisher) Status(ctx context.Context, submissionID string) (*PostStatus, error) {
+	result := &PostStatus{PostSubmissionID: submissionID, Status: PostStatusPublished}
+	if _, err := p.client.GetPost(ctx, submissionID); err != nil {
+		if xrpcErr, ok := err.(*BlueskyError); !ok || xrpcErr.Code != "RecordNotFound" {
+			return nil, err
+		}
+		result.Status = PostStatusFailed
+		result.ErrorMessage = "the post no longer exists on Bluesky"
+		return result, nil
+	}
+	result.PublicURL = p.client.PostURL(submissionID)
+	return result, nil
+}
+
+// PostMetrics reads the likes, replies and reposts of a thread's first
+// post; quotes count as shares. Bluesky reports no views.
+func (p *blueskyPublisher) PostMetrics(ctx context.Context, post *PublishedPost) (*PostMetrics, error) {
+	uri := post.SubmissionID
+	if uri == "" {
+		var err error
+		if uri, err = postURI(post.URL); err != nil {
+			return nil, err
+		}
+	}
+	counts, err := p.client.GetPostCounts(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	return &PostMetrics{
+		Likes:    counts.LikeCount,
+		Comments: counts.ReplyCount,
+		Shares:   counts.RepostCount + counts.QuoteCount,
+	}, nil
+}
+
+func (p *blueskyPublisher) Delete(ctx context.Context, submissionID string) error {
+	return p.client.DeleteThread(ctx, submissionID)
+}
+
+func (p *blueskyPublisher) Capabilities() Capabilities {
+	return Capabilities{PostTypes: []string{"feed", "reel"}, Delete: true}
+}
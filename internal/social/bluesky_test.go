@@ -0,0 +1,414 @@
+package social
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+)
+
+// fakePDS is a minimal PDS for one account, did:plc:gagi, that also serves
+// media files under /media/. Access tokens are access-N; after expireAfter
+// authorized calls the current one expires and refreshSession issues the
+// next.
+type fakePDS struct {
+	t           *testing.T
+	url         string
+	expireAfter int // 0: never
+	calls       int
+	token       int
+	refreshes   int
+	blobs       []string // MIME types of uploaded blobs
+	records     map[string]BlueskyPost
+	keys        int
+	failAfter   int // createRecord fails after this many records; 0: never
+}
+
+func newFakePDS(t *testing.T) *fakePDS {
+	pds := &fakePDS{t: t, token: 1, records: map[string]BlueskyPost{}}
+	srv := httptest.NewServer(pds)
+	t.Cleanup(srv.Close)
+	pds.url = srv.URL
+	return pds
+}
+
+func (f *fakePDS) fail(w http.ResponseWriter, status int, code, message string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": code, "message": message})
+}
+
+func (f *fakePDS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/media/cover.jpg":
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("jpeg"))
+		return
+	case "/media/reel.mp4":
+		w.Header().Set("Content-Type", "video/mp4")
+		w.Write([]byte("mp4"))
+		return
+	case "/xrpc/com.atproto.server.createSession":
+		var input struct{ Identifier, Password string }
+		json.NewDecoder(r.Body).Decode(&input)
+		if input.Identifier != "gagipress.bsky.social" || input.Password != "app-pass" {
+			f.fail(w, http.StatusUnauthorized, "AuthenticationRequired", "Invalid identifier or password")
+			return
+		}
+		f.session(w)
+		return
+	case "/xrpc/com.atproto.server.refreshSession":
+		if r.Header.Get("Authorization") != "Bearer refresh-1" {
+			f.fail(w, http.StatusBadRequest, "ExpiredToken", "Token has expired")
+			return
+		}
+		f.refreshes++
+		f.token++
+		f.calls = 0
+		f.session(w)
+		return
+	}
+
+	if r.Header.Get("Authorization") != fmt.Sprintf("Bearer access-%d", f.token) {
+		f.fail(w, http.StatusUnauthorized, "InvalidToken", "Bad token")
+		return
+	}
+	f.calls++
+	if f.expireAfter > 0 && f.calls > f.expireAfter {
+		f.fail(w, http.StatusBadRequest, "ExpiredToken", "Token has expired")
+		return
+	}
+
+	reply := func(v any) { json.NewEncoder(w).Encode(v) }
+	q := r.URL.Query()
+	switch r.URL.Path {
+	case "/xrpc/com.atproto.server.getSession":
+		reply(map[string]string{"did": "did:plc:gagi", "handle": "gagipress.bsky.social"})
+	case "/xrpc/com.atproto.repo.uploadBlob":
+		data, _ := io.ReadAll(r.Body)
+		mimeType := r.Header.Get("Content-Type")
+		f.blobs = append(f.blobs, mimeType)
+		reply(map[string]any{"blob": map[string]any{
+			"$type": "blob", "ref": map[string]string{"$link": fmt.Sprintf("bafkblob%d", len(f.blobs))},
+			"mimeType": mimeType, "size": len(data),
+		}})
+	case "/xrpc/com.atproto.repo.createRecord":
+		var input struct {
+			Repo, Collection string
+			Record           BlueskyPost
+		}
+		json.NewDecoder(r.Body).Decode(&input)
+		if input.Repo != "did:plc:gagi" || input.Collection != blueskyPostCollection || input.Record.Type != blueskyPostCollection {
+			f.t.Errorf("unexpected createRecord input: %+v", input)
+		}
+		if f.failAfter > 0 && len(f.records) >= f.failAfter {
+			f.fail(w, http.StatusBadRequest, "InvalidRecord", "Record is invalid")
+			return
+		}
+		f.keys++
+		uri := fmt.Sprintf("at://did:plc:gagi/%s/3lpost%05d", blueskyPostCollection, f.keys)
+		f.records[uri] = input.Record
+		reply(BlueskyRef{URI: uri, CID: fmt.Sprintf("bafyrec%d", f.keys)})
+	case "/xrpc/com.atproto.repo.getRecord":
+		uri := fmt.Sprintf("at://%s/%s/%s", q.Get("repo"), q.Get("collection"), q.Get("rkey"))
+		record, ok := f.records[uri]
+		if !ok {
+			f.fail(w, http.StatusBadRequest, "RecordNotFound", "Could not locate record: "+uri)
+			return
+		}
+		reply(map[string]any{"uri": uri, "value": record})
+	case "/xrpc/com.atproto.repo.listRecords":
+		var uris []string
+		for uri := range f.records {
+			uris = append(uris, uri)
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(uris)))
+		var records []map[string]any
+		for _, uri := range uris {
+			records = append(records, map[string]any{"uri": uri, "value": f.records[uri]})
+		}
+		reply(map[string]any{"records": records})
+	case "/xrpc/app.bsky.feed.getPosts":
+		uri := strings.Replace(q.Get("uris"), "at://gagipress.bsky.social/", "at://did:plc:gagi/", 1)
+		if _, ok := f.records[uri]; !ok {
+			reply(map[string]any{"posts": []any{}})
+			return
+		}
+		reply(map[string]any{"posts": []BlueskyCounts{{URI: uri, LikeCount: 12, RepostCount: 3, ReplyCount: 2, QuoteCount: 1}}})
+	case "/xrpc/com.atproto.repo.deleteRecord":
+		var input struct{ Repo, Collection, Rkey string }
+		json.NewDecoder(r.Body).Decode(&input)
+		delete(f.records, fmt.Sprintf("at://%s/%s/%s", input.Repo, input.Collection, input.Rkey))
+		w.Write([]byte(`{}`))
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakePDS) session(w http.ResponseWriter) {
+	json.NewEncoder(w).Encode(BlueskySession{
+		AccessJwt: fmt.Sprintf("access-%d", f.token), RefreshJwt: "refresh-1",
+		DID: "did:plc:gagi", Handle: "gagipress.bsky.social",
+	})
+}
+
+func newTestBluesky(t *testing.T) (*BlueskyClient, *fakePDS) {
+	t.Helper()
+	pds := newFakePDS(t)
+	c := NewBlueskyClient(&config.BlueskyConfig{Handle: "gagipress.bsky.social", AppPassword: "app-pass", PDSURL: pds.url})
+	return c, pds
+}
+
+// thread returns the fake's posts in the order they were created
+func (f *fakePDS) thread() []BlueskyPost {
+	var uris []string
+	for uri := range f.records {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	posts := make([]BlueskyPost, len(uris))
+	for i, uri := range uris {
+		posts[i] = f.records[uri]
+	}
+	return posts
+}
+
+func TestThreadPosts_Facets(t *testing.T) {
+	text := "Città di carta 📚 qui: https://www.amazon.it/dp/B0ABCDEFGH?tag=gagipress-21&utm_source=bluesky. Anche amazon.it/dp/B0XYZ #libri #2026 #LettureEstive!"
+	posts := ThreadPosts(text, 300)
+	if len(posts) != 1 {
+		t.Fatalf("ThreadPosts() = %d posts, want 1", len(posts))
+	}
+	post := posts[0]
+	if strings.Contains(post.Text, "tag=gagipress") {
+		t.Errorf("Text = %q, want the long link shortened", post.Text)
+	}
+
+	type facet struct{ text, uri, tag string }
+	var got []facet
+	for _, f := range post.Facets {
+		got = append(got, facet{post.Text[f.Index.ByteStart:f.Index.ByteEnd], f.Features[0].URI, f.Features[0].Tag})
+	}
+	want := []facet{
+		{"amazon.it/dp/B0ABCDEFGH...", "https://www.amazon.it/dp/B0ABCDEFGH?tag=gagipress-21&utm_source=bluesky", ""},
+		{"amazon.it/dp/B0XYZ", "https://amazon.it/dp/B0XYZ", ""},
+		{"#libri", "", "libri"},
+		{"#LettureEstive", "", "LettureEstive"},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("facets = %q\nwant     %q", got, want)
+	}
+}
+
+func TestThreadPosts_SplitsLongText(t *testing.T) {
+	sentence := "Ogni sera leggiamo una pagina insieme e scopriamo un mondo nuovo. "
+	text := strings.Repeat(sentence, 6) + "\n\n" + strings.Repeat(sentence, 3) + "Link: https://amazon.it/dp/B0ABC #libri"
+
+	posts := ThreadPosts(text, 300)
+	if len(posts) < 2 {
+		t.Fatalf("ThreadPosts() = %d posts, want a thread", len(posts))
+	}
+	var words []string
+	for i, post := range posts {
+		if n := utf8.RuneCountInString(post.Text); n > 300 {
+			t.Errorf("post %d has %d characters", i, n)
+		}
+		if i < len(posts)-1 && !strings.HasSuffix(post.Text, ".") {
+			t.Errorf("post %d = %q, want it to end at a sentence", i, post.Text)
+		}
+		words = append(words, strings.Fields(post.Text)...)
+	}
+	shown := strings.Replace(text, "https://", "", 1) // links show without their scheme
+	if strings.Join(words, " ") != strings.Join(strings.Fields(shown), " ") {
+		t.Errorf("thread lost text:\n%v", words)
+	}
+
+	last := posts[len(posts)-1]
+	if len(last.Facets) != 2 || last.Facets[0].Features[0].URI != "https://amazon.it/dp/B0ABC" {
+		t.Errorf("last post facets = %+v, want the link and hashtag", last.Facets)
+	}
+}
+
+func TestThreadPosts_LongWord(t *testing.T) {
+	posts := ThreadPosts(strings.Repeat("a", 350), 300)
+	if len(posts) != 2 || len(posts[0].Text) != 300 || len(posts[1].Text) != 50 {
+		t.Errorf("ThreadPosts() = %d posts", len(posts))
+	}
+}
+
+func TestBlueskyPublishThread(t *testing.T) {
+	c, pds := newTestBluesky(t)
+	text := strings.Repeat("Una storia per bambini che amano i draghi. ", 10) + "#libri"
+
+	refs, err := c.PublishThread(context.Background(), text, []string{pds.url + "/media/cover.jpg", pds.url + "/media/cover.jpg"})
+	if err != nil {
+		t.Fatalf("PublishThread() error = %v", err)
+	}
+	posts := pds.thread()
+	if len(refs) != 2 || len(posts) != 2 {
+		t.Fatalf("PublishThread() = %d refs, %d records; want a thread of 2", len(refs), len(posts))
+	}
+
+	embed, _ := posts[0].Embed.(map[string]any)
+	images, _ := embed["images"].([]any)
+	if embed["$type"] != blueskyImagesEmbed || len(images) != 2 {
+		t.Errorf("root embed = %v, want two images", posts[0].Embed)
+	}
+	if len(pds.blobs) != 2 || pds.blobs[0] != "image/jpeg" {
+		t.Errorf("uploaded blobs = %v", pds.blobs)
+	}
+	if posts[0].Reply != nil {
+		t.Errorf("root post is a reply: %+v", posts[0].Reply)
+	}
+	if reply := posts[1].Reply; reply == nil || reply.Root != refs[0] || reply.Parent != refs[0] || posts[1].Embed != nil {
+		t.Errorf("second post = %+v, want a reply to the root without media", posts[1])
+	}
+	if posts[0].CreatedAt == "" {
+		t.Error("posts have no createdAt")
+	}
+}
+
+func TestBlueskyPublishThread_Video(t *testing.T) {
+	c, pds := newTestBluesky(t)
+
+	if _, err := c.PublishThread(context.Background(), "Guarda il trailer", []string{pds.url + "/media/reel.mp4"}); err != nil {
+		t.Fatalf("PublishThread() error = %v", err)
+	}
+	embed, _ := pds.thread()[0].Embed.(map[string]any)
+	if embed["$type"] != blueskyVideoEmbed || len(pds.blobs) != 1 || pds.blobs[0] != "video/mp4" {
+		t.Errorf("embed = %v, blobs = %v; want one video", embed, pds.blobs)
+	}
+
+	_, err := c.PublishThread(context.Background(), "Mixed", []string{pds.url + "/media/cover.jpg", pds.url + "/media/reel.mp4"})
+	if err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("PublishThread() with an image and a video error = %v", err)
+	}
+}
+
+func TestBlueskyPublishThread_FailedReplyRemovesThread(t *testing.T) {
+	c, pds := newTestBluesky(t)
+	pds.failAfter = 1
+
+	_, err := c.PublishThread(context.Background(), strings.Repeat("Una frase lunga abbastanza. ", 20), nil)
+	if err == nil || !strings.Contains(err.Error(), "part 2/") {
+		t.Fatalf("PublishThread() error = %v, want the failed part", err)
+	}
+	if len(pds.records) != 0 {
+		t.Errorf("%d posts left behind", len(pds.records))
+	}
+}
+
+func TestBlueskyRefreshesExpiredSession(t *testing.T) {
+	c, pds := newTestBluesky(t)
+	pds.expireAfter = 1
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.CreatePost(context.Background(), &BlueskyPost{Text: "ciao"}); err != nil {
+			t.Fatalf("CreatePost() error = %v", err)
+		}
+	}
+	if pds.refreshes != 2 || len(pds.records) != 3 {
+		t.Errorf("refreshes = %d, records = %d; want 2 and 3", pds.refreshes, len(pds.records))
+	}
+}
+
+func TestBlueskyBadPassword(t *testing.T) {
+	c, _ := newTestBluesky(t)
+	c.cfg.AppPassword = "wrong"
+
+	_, err := c.TestConnection(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "AuthenticationRequired") {
+		t.Errorf("TestConnection() error = %v, want the login error", err)
+	}
+}
+
+func TestBlueskyPublisher(t *testing.T) {
+	pds := newFakePDS(t)
+	cfg := &config.Config{Bluesky: config.BlueskyConfig{Handle: "gagipress.bsky.social", AppPassword: "app-pass", PDSURL: pds.url}}
+	cfg.Publishing.Platforms = map[string]string{"bluesky": "bluesky"}
+	ctx := context.Background()
+
+	name, p, err := NewPublisherSet(cfg).For("bluesky")
+	if err != nil || name != "bluesky" {
+		t.Fatalf("For(bluesky) = %q, %v", name, err)
+	}
+	if caps := p.Capabilities(); caps.Schedule || !caps.Delete {
+		t.Errorf("Capabilities() = %+v, want immediate posts that can be deleted", caps)
+	}
+
+	text := strings.Repeat("Un libro da colorare per le vacanze. ", 12) + "https://amazon.it/dp/B0ABC"
+	status, err := p.Publish(ctx, &PublishRequest{Platform: "bluesky", PostType: "reel", Text: text})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if status.Status != PostStatusPublished || status.PublicURL != "https://bsky.app/profile/gagipress.bsky.social/post/3lpost00001" {
+		t.Errorf("Publish() = %+v", status)
+	}
+	if len(pds.records) < 2 {
+		t.Fatalf("Publish() created %d posts, want a thread", len(pds.records))
+	}
+
+	status, err = p.Status(ctx, status.PostSubmissionID)
+	if err != nil || status.Status != PostStatusPublished {
+		t.Errorf("Status() = %+v, %v", status, err)
+	}
+
+	// An unrelated post made after the thread survives its deletion
+	if _, err := NewBlueskyClient(&cfg.Bluesky).CreatePost(ctx, &BlueskyPost{Text: "altro"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Delete(ctx, status.PostSubmissionID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(pds.records) != 1 {
+		t.Errorf("%d posts left after Delete(), want only the unrelated one", len(pds.records))
+	}
+
+	status, err = p.Status(ctx, status.PostSubmissionID)
+	if err != nil || status.Status != PostStatusFailed {
+		t.Errorf("Status() of a deleted post = %+v, %v", status, err)
+	}
+
+	if _, err := p.Publish(ctx, &PublishRequest{Platform: "bluesky", PostType: "story", Text: "x"}); err == nil {
+		t.Error("Publish() accepted a story")
+	}
+}
+
+func TestBlueskyPublisherPostMetrics(t *testing.T) {
+	c, _ := newTestBluesky(t)
+	p := &blueskyPublisher{client: c}
+	ctx := context.Background()
+
+	refs, err := c.PublishThread(ctx, "Nuovo libro!", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PostMetrics{Likes: 12, Comments: 2, Shares: 4}
+	for _, post := range []*PublishedPost{
+		{SubmissionID: refs[0].URI},
+		{URL: "https://bsky.app/profile/gagipress.bsky.social/post/3lpost00001"},
+	} {
+		metrics, err := p.PostMetrics(ctx, post)
+		if err != nil {
+			t.Fatalf("PostMetrics(%+v) error = %v", post, err)
+		}
+		if *metrics != want {
+			t.Errorf("PostMetrics(%+v) = %+v, want %+v", post, *metrics, want)
+		}
+	}
+
+	if _, err := p.PostMetrics(ctx, &PublishedPost{URL: "https://bsky.app/profile/gagipress.bsky.social/post/missing"}); err == nil {
+		t.Error("PostMetrics() of a missing post succeeded")
+	}
+	if _, err := p.PostMetrics(ctx, &PublishedPost{URL: "https://example.com/"}); err == nil {
+		t.Error("PostMetrics() accepted a non-Bluesky URL")
+	}
+}
@@ -1,32 +1,76 @@
 package social
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/errors"
+	"github.com/gagipress/gagipress-cli/internal/oauth"
 )
 
+const (
+	InstagramGraphBaseURL = "https://graph.facebook.com/v21.0"
+	InstagramAuthURL      = "https://www.facebook.com/v21.0/dialog/oauth"
+)
+
+// InstagramScopes are the permissions 'auth instagram' asks for
+var InstagramScopes = []string{
+	"instagram_basic",
+	"instagram_content_publish",
+	"instagram_manage_insights",
+	"pages_show_list",
+	"pages_read_engagement",
+}
+
+// instagramRefreshWindow is how long before expiry a long-lived token
+// (valid for 60 days) is exchanged for a fresh one
+const instagramRefreshWindow = 7 * 24 * time.Hour
+
+// graphTimeLayout is how the Graph API formats timestamps
+const graphTimeLayout = "2006-01-02T15:04:05-0700"
+
+// postFields are the media fields read into a Post
+const postFields = "id,caption,media_type,media_url,permalink,timestamp,like_count,comments_count"
+
 // InstagramClient handles Instagram Graph API interactions
 type InstagramClient struct {
-	accessToken string
-	accountID   string
+	cfg        *config.InstagramConfig
+	baseURL    string
+	httpClient *http.Client
+
+	// OnTokenRefresh is called after the access token in the config was
+	// refreshed, to save it
+	OnTokenRefresh func() error
+
+	// Reels are processed asynchronously; their container is polled every
+	// pollInterval until it is ready or pollTimeout has passed
+	pollInterval time.Duration
+	pollTimeout  time.Duration
 }
 
 // Post represents an Instagram post
 type Post struct {
-	ID           string    `json:"id"`
-	Caption      string    `json:"caption"`
-	MediaType    string    `json:"media_type"` // IMAGE, VIDEO, CAROUSEL_ALBUM
-	MediaURL     string    `json:"media_url"`
-	Permalink    string    `json:"permalink"`
-	Timestamp    time.Time `json:"timestamp"`
-	LikesCount   int       `json:"like_count"`
-	CommentsCount int      `json:"comments_count"`
+	ID            string    `json:"id"`
+	Caption       string    `json:"caption"`
+	MediaType     string    `json:"media_type"` // IMAGE, VIDEO, CAROUSEL_ALBUM
+	MediaURL      string    `json:"media_url"`
+	Permalink     string    `json:"permalink"`
+	Timestamp     time.Time `json:"timestamp"`
+	LikesCount    int       `json:"like_count"`
+	CommentsCount int       `json:"comments_count"`
 }
 
 // Metrics represents post performance metrics
 type Metrics struct {
+	Plays       int `json:"plays"`
 	Impressions int `json:"impressions"`
 	Reach       int `json:"reach"`
 	Engagement  int `json:"engagement"`
@@ -34,47 +78,560 @@ type Metrics struct {
 	Shares      int `json:"shares"`
 }
 
+// Container statuses reported while a media container is processed
+const (
+	ContainerInProgress = "IN_PROGRESS"
+	ContainerFinished   = "FINISHED"
+	ContainerPublished  = "PUBLISHED"
+	ContainerError      = "ERROR"
+	ContainerExpired    = "EXPIRED"
+)
+
+// GraphError is an error returned by the Graph API
+type GraphError struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
+	Type       string `json:"type"`
+	Code       int    `json:"code"`
+	Subcode    int    `json:"error_subcode"`
+}
+
+func (e *GraphError) Error() string {
+	return fmt.Sprintf("instagram graph API error (%d, code %d): %s", e.StatusCode, e.Code, e.Message)
+}
+
+// TokenExpired reports whether the error means the access token is no
+// longer valid (OAuthException code 190)
+func (e *GraphError) TokenExpired() bool {
+	return e.Code == 190
+}
+
 // NewInstagramClient creates a new Instagram API client
 func NewInstagramClient(cfg *config.InstagramConfig) *InstagramClient {
 	return &InstagramClient{
-		accessToken: cfg.AccessToken,
-		accountID:   cfg.AccountID,
+		cfg:          cfg,
+		baseURL:      InstagramGraphBaseURL,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		pollInterval: 5 * time.Second,
+		pollTimeout:  5 * time.Minute,
+	}
+}
+
+// PublishPost publishes a Reel: it creates a media container for the video
+// at videoURL, waits for Instagram to process it, then publishes it. Once
+// published the Reel is live, so if looking it up fails the returned Post
+// has only its ID and no error: reporting a failure would get it posted
+// again.
+func (c *InstagramClient) PublishPost(ctx context.Context, caption string, videoURL string) (*Post, error) {
+	containerID, err := c.CreateReelContainer(ctx, caption, videoURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.WaitForContainer(ctx, containerID); err != nil {
+		return nil, err
+	}
+	mediaID, err := c.PublishContainer(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := c.GetPost(ctx, mediaID)
+	if err != nil {
+		return &Post{ID: mediaID}, nil
 	}
+	return post, nil
 }
 
-// PublishPost publishes a post to Instagram
-// TODO: Implement actual API call
-func (c *InstagramClient) PublishPost(caption string, mediaURL string) (*Post, error) {
-	// Placeholder implementation
-	return nil, fmt.Errorf("not implemented: Instagram publishing requires OAuth flow setup")
+// CreateReelContainer creates the media container for a Reel and returns
+// its ID. Instagram downloads the video from videoURL, which must be public.
+func (c *InstagramClient) CreateReelContainer(ctx context.Context, caption, videoURL string) (string, error) {
+	params := url.Values{
+		"media_type": {"REELS"},
+		"video_url":  {videoURL},
+		"caption":    {caption},
+	}
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := c.call(ctx, http.MethodPost, c.cfg.AccountID+"/media", params, &resp); err != nil {
+		return "", err
+	}
+	if resp.ID == "" {
+		return "", errors.New(errors.ErrorTypeAPI, "instagram returned no container ID")
+	}
+	return resp.ID, nil
 }
 
-// GetPostMetrics retrieves metrics for a specific post
-// TODO: Implement actual API call
-func (c *InstagramClient) GetPostMetrics(postID string) (*Metrics, error) {
-	// Placeholder implementation
-	return nil, fmt.Errorf("not implemented: requires Instagram Graph API access")
+// ContainerStatus returns the status code of a media container and, on
+// ERROR, the reason Instagram gave
+func (c *InstagramClient) ContainerStatus(ctx context.Context, containerID string) (string, string, error) {
+	var resp struct {
+		StatusCode string `json:"status_code"`
+		Status     string `json:"status"`
+	}
+	if err := c.call(ctx, http.MethodGet, containerID, url.Values{"fields": {"status_code,status"}}, &resp); err != nil {
+		return "", "", err
+	}
+	return resp.StatusCode, resp.Status, nil
 }
 
-// GetRecentPosts retrieves recent posts
-// TODO: Implement actual API call
-func (c *InstagramClient) GetRecentPosts(limit int) ([]Post, error) {
-	// Placeholder implementation
-	return nil, fmt.Errorf("not implemented: requires Instagram Graph API access")
+// WaitForContainer polls a container until it is ready to publish. Polling
+// stops early if ctx is cancelled.
+func (c *InstagramClient) WaitForContainer(ctx context.Context, containerID string) error {
+	deadline := time.Now().Add(c.pollTimeout)
+	for {
+		status, detail, err := c.ContainerStatus(ctx, containerID)
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case ContainerFinished:
+			return nil
+		case ContainerPublished:
+			return fmt.Errorf("instagram container %s was already published", containerID)
+		case ContainerError:
+			return errors.New(errors.ErrorTypeAPI, "instagram could not process the video: "+detail)
+		case ContainerExpired:
+			return fmt.Errorf("instagram container %s expired before it was published", containerID)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for instagram to process container %s", containerID)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+// PublishContainer publishes a processed container and returns the ID of
+// the new media
+func (c *InstagramClient) PublishContainer(ctx context.Context, containerID string) (string, error) {
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := c.call(ctx, http.MethodPost, c.cfg.AccountID+"/media_publish", url.Values{"creation_id": {containerID}}, &resp); err != nil {
+		return "", err
+	}
+	if resp.ID == "" {
+		return "", errors.New(errors.ErrorTypeAPI, "instagram returned no media ID")
+	}
+	return resp.ID, nil
+}
+
+// GetPost retrieves a published post
+func (c *InstagramClient) GetPost(ctx context.Context, mediaID string) (*Post, error) {
+	var media graphMedia
+	if err := c.call(ctx, http.MethodGet, mediaID, url.Values{"fields": {postFields}}, &media); err != nil {
+		return nil, err
+	}
+	post := media.post()
+	return &post, nil
+}
+
+// GetPostMetrics retrieves the lifetime insights of a post. Reels report
+// plays, reach, saves, shares and total interactions.
+func (c *InstagramClient) GetPostMetrics(ctx context.Context, postID string) (*Metrics, error) {
+	var resp struct {
+		Data []struct {
+			Name   string `json:"name"`
+			Values []struct {
+				Value int `json:"value"`
+			} `json:"values"`
+			TotalValue *struct {
+				Value int `json:"value"`
+			} `json:"total_value"`
+		} `json:"data"`
+	}
+	params := url.Values{"metric": {"plays,reach,saved,shares,total_interactions"}}
+	if err := c.call(ctx, http.MethodGet, postID+"/insights", params, &resp); err != nil {
+		return nil, err
+	}
+
+	metrics := &Metrics{}
+	for _, m := range resp.Data {
+		value := 0
+		if m.TotalValue != nil {
+			value = m.TotalValue.Value
+		} else if len(m.Values) > 0 {
+			value = m.Values[0].Value
+		}
+		switch m.Name {
+		case "plays":
+			metrics.Plays = value
+		case "impressions":
+			metrics.Impressions = value
+		case "reach":
+			metrics.Reach = value
+		case "saved":
+			metrics.Saves = value
+		case "shares":
+			metrics.Shares = value
+		case "total_interactions":
+			metrics.Engagement = value
+		}
+	}
+	return metrics, nil
+}
+
+// GetRecentPosts retrieves the account's most recent posts, newest first
+func (c *InstagramClient) GetRecentPosts(ctx context.Context, limit int) ([]Post, error) {
+	var resp struct {
+		Data []graphMedia `json:"data"`
+	}
+	params := url.Values{"fields": {postFields}}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	if err := c.call(ctx, http.MethodGet, c.cfg.AccountID+"/media", params, &resp); err != nil {
+		return nil, err
+	}
+
+	posts := make([]Post, len(resp.Data))
+	for i, media := range resp.Data {
+		posts[i] = media.post()
+	}
+	return posts, nil
+}
+
+// instagramSearchLimit is how many recent posts FindPost looks through
+const instagramSearchLimit = 100
+
+// FindPost finds one of the account's recent posts by its permalink. Posts
+// and Reels share shortcodes, so /p/ and /reel/ links both match.
+func (c *InstagramClient) FindPost(ctx context.Context, permalink string) (*Post, error) {
+	shortcode := instagramShortcode(permalink)
+	if shortcode == "" {
+		return nil, errors.New(errors.ErrorTypeValidation, "not an Instagram post URL: "+permalink)
+	}
+	posts, err := c.GetRecentPosts(ctx, instagramSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range posts {
+		if instagramShortcode(posts[i].Permalink) == shortcode {
+			return &posts[i], nil
+		}
+	}
+	return nil, errors.New(errors.ErrorTypeNotFound, "instagram post not found among the latest "+strconv.Itoa(instagramSearchLimit)+": "+permalink)
+}
+
+// instagramShortcode returns the last path segment of a post URL
+func instagramShortcode(permalink string) string {
+	u, err := url.Parse(permalink)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 {
+		return ""
+	}
+	return segments[len(segments)-1]
 }
 
 // TestConnection tests the Instagram API connection
-func (c *InstagramClient) TestConnection() error {
-	if c.accessToken == "" {
+func (c *InstagramClient) TestConnection(ctx context.Context) error {
+	if c.cfg.AccessToken == "" {
+		return fmt.Errorf("Instagram access token not configured")
+	}
+	if c.cfg.AccountID == "" {
+		return fmt.Errorf("Instagram account ID not configured")
+	}
+	var resp struct {
+		ID string `json:"id"`
+	}
+	return c.call(ctx, http.MethodGet, c.cfg.AccountID, url.Values{"fields": {"id,username"}}, &resp)
+}
+
+// graphMedia is a media object as the Graph API returns it; its timestamp
+// is not RFC 3339, so it cannot be decoded into a Post directly
+type graphMedia struct {
+	ID            string `json:"id"`
+	Caption       string `json:"caption"`
+	MediaType     string `json:"media_type"`
+	MediaURL      string `json:"media_url"`
+	Permalink     string `json:"permalink"`
+	Timestamp     string `json:"timestamp"`
+	LikesCount    int    `json:"like_count"`
+	CommentsCount int    `json:"comments_count"`
+}
+
+func (m graphMedia) post() Post {
+	ts, _ := time.Parse(graphTimeLayout, m.Timestamp)
+	return Post{
+		ID:            m.ID,
+		Caption:       m.Caption,
+		MediaType:     m.MediaType,
+		MediaURL:      m.MediaURL,
+		Permalink:     m.Permalink,
+		Timestamp:     ts,
+		LikesCount:    m.LikesCount,
+		CommentsCount: m.CommentsCount,
+	}
+}
+
+// OAuthProvider describes the Facebook Login dialog for this app
+func (c *InstagramClient) OAuthProvider() *oauth.Provider {
+	return &oauth.Provider{
+		Name:     "Instagram",
+		AuthURL:  InstagramAuthURL,
+		ClientID: c.cfg.AppID,
+		Scopes:   InstagramScopes,
+	}
+}
+
+// ExchangeCode turns an authorization code into a long-lived (60 day)
+// access token and stores it in the config. When no account ID is
+// configured it also looks up the Instagram account linked to the user's
+// first Facebook Page.
+func (c *InstagramClient) ExchangeCode(ctx context.Context, grant *oauth.Grant) error {
+	if c.cfg.AppID == "" || c.cfg.AppSecret == "" {
+		return fmt.Errorf("instagram app_id and app_secret are not configured")
+	}
+
+	var short graphToken
+	err := c.request(ctx, http.MethodGet, "oauth/access_token", url.Values{
+		"client_id":     {c.cfg.AppID},
+		"client_secret": {c.cfg.AppSecret},
+		"redirect_uri":  {grant.RedirectURI},
+		"code":          {grant.Code},
+		"code_verifier": {grant.Verifier},
+	}, &short)
+	if err != nil {
+		return fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	if err := c.exchangeToken(ctx, short.AccessToken); err != nil {
+		return err
+	}
+
+	if c.cfg.AccountID == "" {
+		var pages struct {
+			Data []struct {
+				Name    string `json:"name"`
+				Account *struct {
+					ID string `json:"id"`
+				} `json:"instagram_business_account"`
+			} `json:"data"`
+		}
+		if err := c.call(ctx, http.MethodGet, "me/accounts", url.Values{"fields": {"name,instagram_business_account"}}, &pages); err != nil {
+			return fmt.Errorf("failed to look up the Instagram account: %w", err)
+		}
+		for _, page := range pages.Data {
+			if page.Account != nil {
+				c.cfg.AccountID = page.Account.ID
+				break
+			}
+		}
+		if c.cfg.AccountID == "" {
+			return fmt.Errorf("none of your Facebook Pages has a linked Instagram professional account")
+		}
+	}
+	return nil
+}
+
+// RefreshToken exchanges the current long-lived token for a fresh one and
+// reports it through OnTokenRefresh
+func (c *InstagramClient) RefreshToken(ctx context.Context) error {
+	if c.cfg.AppID == "" || c.cfg.AppSecret == "" {
+		return fmt.Errorf("instagram app_id and app_secret are needed to refresh the access token")
+	}
+	if err := c.exchangeToken(ctx, c.cfg.AccessToken); err != nil {
+		return err
+	}
+	if c.OnTokenRefresh != nil {
+		return c.OnTokenRefresh()
+	}
+	return nil
+}
+
+// graphToken is a token response of the Graph API
+type graphToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// exchangeToken stores a long-lived token in exchange for token
+func (c *InstagramClient) exchangeToken(ctx context.Context, token string) error {
+	var long graphToken
+	err := c.request(ctx, http.MethodGet, "oauth/access_token", url.Values{
+		"grant_type":        {"fb_exchange_token"},
+		"client_id":         {c.cfg.AppID},
+		"client_secret":     {c.cfg.AppSecret},
+		"fb_exchange_token": {token},
+	}, &long)
+	if err != nil {
+		return fmt.Errorf("failed to get a long-lived token: %w", err)
+	}
+
+	c.cfg.AccessToken = long.AccessToken
+	c.cfg.TokenExpiresAt = time.Time{}
+	if long.ExpiresIn > 0 {
+		c.cfg.TokenExpiresAt = time.Now().Add(time.Duration(long.ExpiresIn) * time.Second).UTC().Truncate(time.Second)
+	}
+	return nil
+}
+
+// ensureToken refreshes a token that expires soon. A failed refresh is
+// only an error once the token has expired; until then the next call
+// retries it.
+func (c *InstagramClient) ensureToken(ctx context.Context) error {
+	if c.cfg.AccessToken == "" {
 		return fmt.Errorf("Instagram access token not configured")
 	}
-	// TODO: Implement actual connection test with API
-	return fmt.Errorf("not implemented: OAuth flow required first")
+	if !oauth.ExpiresWithin(c.cfg.TokenExpiresAt, instagramRefreshWindow) {
+		return nil
+	}
+	err := c.RefreshToken(ctx)
+	if err != nil && oauth.ExpiresWithin(c.cfg.TokenExpiresAt, 0) {
+		return fmt.Errorf("instagram access token expired (%w); run 'gagipress auth instagram'", err)
+	}
+	return nil
+}
+
+// call sends a Graph API request for path with params, authenticated with
+// the access token, and decodes the JSON response into out. POST params are
+// sent form-encoded.
+func (c *InstagramClient) call(ctx context.Context, method, path string, params url.Values, out any) error {
+	if err := c.ensureToken(ctx); err != nil {
+		return err
+	}
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("access_token", c.cfg.AccessToken)
+	return c.request(ctx, method, path, params, out)
 }
 
-// Note: OAuth flow will be implemented in Week 3
-// Requires:
-// 1. Facebook App setup
-// 2. Instagram Business Account
-// 3. OAuth redirect handling
-// 4. Token refresh mechanism
+// request sends a Graph API request as is
+func (c *InstagramClient) request(ctx context.Context, method, path string, params url.Values, out any) error {
+	endpoint := c.baseURL + "/" + path
+	var body io.Reader
+	if method == http.MethodGet {
+		endpoint += "?" + params.Encode()
+	} else {
+		body = strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeAPI, "failed to connect to Instagram Graph API")
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeNetwork, "failed to read Instagram Graph API response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var envelope struct {
+			Error *GraphError `json:"error"`
+		}
+		if json.Unmarshal(data, &envelope) == nil && envelope.Error != nil {
+			envelope.Error.StatusCode = resp.StatusCode
+			return envelope.Error
+		}
+		return &GraphError{StatusCode: resp.StatusCode, Message: string(data)}
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeValidation, "failed to parse Instagram Graph API response")
+	}
+	return nil
+}
+
+func init() {
+	RegisterPublisher("instagram", []string{"instagram"}, newInstagramPublisher)
+}
+
+// instagramCaptionLength is the longest caption Instagram accepts
+const instagramCaptionLength = 2200
+
+// instagramPublisher publishes Reels natively through the Graph API. The
+// API cannot schedule, so posts go out when they are published.
+type instagramPublisher struct {
+	client *InstagramClient
+}
+
+func newInstagramPublisher(cfg *config.Config) (Publisher, error) {
+	if cfg.Instagram.AccessToken == "" || cfg.Instagram.AccountID == "" {
+		return nil, fmt.Errorf("instagram is not authorized; run 'gagipress auth instagram'")
+	}
+	client := NewInstagramClient(&cfg.Instagram)
+	client.OnTokenRefresh = func() error { return config.Save(cfg) }
+	return &instagramPublisher{client: client}, nil
+}
+
+func (p *instagramPublisher) Publish(ctx context.Context, req *PublishRequest) (*PostStatus, error) {
+	if err := p.Capabilities().Check(req); err != nil {
+		return nil, err
+	}
+	post, err := p.client.PublishPost(ctx, req.Text, req.MediaURLs[0])
+	if err != nil {
+		return nil, err
+	}
+	// Without a permalink the Reel is live but could not be looked up; left
+	// in progress, Status fills in its URL later
+	if post.Permalink == "" {
+		return &PostStatus{PostSubmissionID: post.ID, Status: PostStatusInProgress}, nil
+	}
+	return &PostStatus{PostSubmissionID: post.ID, Status: PostStatusPublished, PublicURL: post.Permalink}, nil
+}
+
+// Status looks up a published Reel; Publish only returns once it is live,
+// so a Reel it could not look up yet is published too
+func (p *instagramPublisher) Status(ctx context.Context, submissionID string) (*PostStatus, error) {
+	post, err := p.client.GetPost(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	return &PostStatus{PostSubmissionID: post.ID, Status: PostStatusPublished, PublicURL: post.Permalink}, nil
+}
+
+// PostMetrics reads a Reel's likes and comments and its insights. Reels
+// posted through another publisher are looked up among the account's
+// recent posts by their permalink.
+func (p *instagramPublisher) PostMetrics(ctx context.Context, post *PublishedPost) (*PostMetrics, error) {
+	mediaID := post.SubmissionID
+	if mediaID == "" {
+		found, err := p.client.FindPost(ctx, post.URL)
+		if err != nil {
+			return nil, err
+		}
+		mediaID = found.ID
+	}
+
+	media, err := p.client.GetPost(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	insights, err := p.client.GetPostMetrics(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	return &PostMetrics{
+		Views:    insights.Plays,
+		Likes:    media.LikesCount,
+		Comments: media.CommentsCount,
+		Shares:   insights.Shares,
+		Saves:    insights.Saves,
+	}, nil
+}
+
+func (p *instagramPublisher) Delete(ctx context.Context, submissionID string) error {
+	return errors.New(errors.ErrorTypeValidation, "the Instagram Graph API cannot delete posts; delete it in the Instagram app")
+}
+
+func (p *instagramPublisher) Capabilities() Capabilities {
+	return Capabilities{PostTypes: []string{"reel"}, MediaRequired: true, MaxTextLength: instagramCaptionLength}
+}
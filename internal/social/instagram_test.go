@@ -0,0 +1,300 @@
+package social
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/oauth"
+)
+
+// fakeGraph is a minimal Graph API for one Instagram account. Containers
+// report IN_PROGRESS for their first polls, then finalStatus.
+type fakeGraph struct {
+	t           *testing.T
+	polls       int // IN_PROGRESS answers before finalStatus
+	finalStatus string
+	published   []string // creation IDs passed to media_publish
+	captions    []string
+	exchanges   []string // tokens passed to fb_exchange_token
+	lookupDown  bool     // GET on the published media fails
+}
+
+func (f *fakeGraph) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		f.t.Fatalf("parse form: %v", err)
+	}
+	if r.URL.Path == "/oauth/access_token" {
+		f.token(w, r)
+		return
+	}
+	if r.Form.Get("access_token") != "ig-token" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"Invalid OAuth access token.","type":"OAuthException","code":190}}`))
+		return
+	}
+
+	reply := func(v any) { json.NewEncoder(w).Encode(v) }
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/1784/media":
+		if r.Form.Get("media_type") != "REELS" || r.Form.Get("video_url") == "" {
+			f.t.Errorf("unexpected container params: %v", r.Form)
+		}
+		f.captions = append(f.captions, r.Form.Get("caption"))
+		reply(map[string]string{"id": "container-1"})
+	case r.Method == http.MethodGet && r.URL.Path == "/container-1":
+		if f.polls > 0 {
+			f.polls--
+			reply(map[string]string{"status_code": ContainerInProgress, "status": "In Progress"})
+			return
+		}
+		reply(map[string]string{"status_code": f.finalStatus, "status": "Error: video codec not supported"})
+	case r.Method == http.MethodPost && r.URL.Path == "/1784/media_publish":
+		f.published = append(f.published, r.Form.Get("creation_id"))
+		reply(map[string]string{"id": "media-1"})
+	case r.Method == http.MethodGet && r.URL.Path == "/media-1" && f.lookupDown:
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":{"message":"An unexpected error has occurred.","type":"OAuthException","code":2}}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/media-1":
+		reply(map[string]any{
+			"id": "media-1", "caption": "hello", "media_type": "VIDEO",
+			"permalink": "https://www.instagram.com/reel/abc/", "timestamp": "2026-05-01T10:00:00+0000",
+			"like_count": 3, "comments_count": 1,
+		})
+	case r.Method == http.MethodGet && r.URL.Path == "/media-1/insights":
+		if !strings.Contains(r.Form.Get("metric"), "plays") {
+			f.t.Errorf("insights metrics = %q", r.Form.Get("metric"))
+		}
+		w.Write([]byte(`{"data":[
+			{"name":"plays","period":"lifetime","values":[{"value":1200}]},
+			{"name":"reach","period":"lifetime","values":[{"value":900}]},
+			{"name":"saved","period":"lifetime","values":[{"value":40}]},
+			{"name":"shares","period":"lifetime","values":[{"value":12}]},
+			{"name":"total_interactions","period":"lifetime","total_value":{"value":95}}
+		]}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/1784/media":
+		switch r.Form.Get("limit") {
+		case "2":
+			w.Write([]byte(`{"data":[
+				{"id":"m2","media_type":"VIDEO","timestamp":"2026-05-02T08:30:00+0000"},
+				{"id":"m1","media_type":"IMAGE","timestamp":"2026-05-01T10:00:00+0000"}
+			]}`))
+		case "100": // FindPost
+			w.Write([]byte(`{"data":[
+				{"id":"m2","media_type":"VIDEO","permalink":"https://www.instagram.com/reel/xyz/","timestamp":"2026-05-02T08:30:00+0000"},
+				{"id":"media-1","media_type":"VIDEO","permalink":"https://www.instagram.com/reel/abc/","timestamp":"2026-05-01T10:00:00+0000"}
+			]}`))
+		default:
+			f.t.Errorf("limit = %q", r.Form.Get("limit"))
+		}
+	case r.Method == http.MethodGet && r.URL.Path == "/me/accounts":
+		w.Write([]byte(`{"data":[
+			{"id":"p1","name":"Unlinked page"},
+			{"id":"p2","name":"Gagipress","instagram_business_account":{"id":"1784"}}
+		]}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/1784":
+		reply(map[string]string{"id": "1784", "username": "gagipress"})
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	}
+}
+
+// token answers the code exchange with a short-lived token and the
+// fb_exchange_token grant with ig-token
+func (f *fakeGraph) token(w http.ResponseWriter, r *http.Request) {
+	if r.Form.Get("client_id") != "app-1" || r.Form.Get("client_secret") != "secret" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"Error validating client secret.","type":"OAuthException","code":1}}`))
+		return
+	}
+	if r.Form.Get("grant_type") == "fb_exchange_token" {
+		f.exchanges = append(f.exchanges, r.Form.Get("fb_exchange_token"))
+		w.Write([]byte(`{"access_token":"ig-token","token_type":"bearer","expires_in":5184000}`))
+		return
+	}
+	if r.Form.Get("code") != "code-1" || r.Form.Get("code_verifier") != "verifier" || r.Form.Get("redirect_uri") == "" {
+		f.t.Errorf("unexpected code exchange params: %v", r.Form)
+	}
+	w.Write([]byte(`{"access_token":"short-token","token_type":"bearer","expires_in":3600}`))
+}
+
+func newTestInstagram(t *testing.T, graph *fakeGraph, token string) *InstagramClient {
+	t.Helper()
+	graph.t = t
+	srv := httptest.NewServer(graph)
+	t.Cleanup(srv.Close)
+	c := NewInstagramClient(&config.InstagramConfig{AccessToken: token, AccountID: "1784"})
+	c.baseURL = srv.URL
+	c.pollInterval = time.Millisecond
+	return c
+}
+
+func TestInstagramPublishPost(t *testing.T) {
+	graph := &fakeGraph{polls: 2, finalStatus: ContainerFinished}
+	c := newTestInstagram(t, graph, "ig-token")
+
+	post, err := c.PublishPost(context.Background(), "hello", "https://cdn.example.com/reel.mp4")
+	if err != nil {
+		t.Fatalf("PublishPost() error = %v", err)
+	}
+	if post.ID != "media-1" || post.Permalink != "https://www.instagram.com/reel/abc/" || post.LikesCount != 3 {
+		t.Errorf("PublishPost() = %+v", post)
+	}
+	if !post.Timestamp.Equal(time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Timestamp = %v", post.Timestamp)
+	}
+	if graph.polls != 0 {
+		t.Errorf("published before the container finished processing")
+	}
+	if len(graph.published) != 1 || graph.published[0] != "container-1" || graph.captions[0] != "hello" {
+		t.Errorf("published = %v, captions = %v", graph.published, graph.captions)
+	}
+}
+
+func TestInstagramPublishPost_ContainerError(t *testing.T) {
+	graph := &fakeGraph{finalStatus: ContainerError}
+	c := newTestInstagram(t, graph, "ig-token")
+
+	_, err := c.PublishPost(context.Background(), "hello", "https://cdn.example.com/reel.mp4")
+	if err == nil || !strings.Contains(err.Error(), "video codec not supported") {
+		t.Fatalf("PublishPost() error = %v, want the processing error", err)
+	}
+	if len(graph.published) != 0 {
+		t.Error("a failed container was published")
+	}
+}
+
+func TestInstagramPublishPost_LookupFails(t *testing.T) {
+	graph := &fakeGraph{finalStatus: ContainerFinished, lookupDown: true}
+	c := newTestInstagram(t, graph, "ig-token")
+
+	// The Reel is live, so its ID comes back even though the lookup failed
+	post, err := c.PublishPost(context.Background(), "hello", "https://cdn.example.com/reel.mp4")
+	if err != nil {
+		t.Fatalf("PublishPost() error = %v, want the media ID", err)
+	}
+	if post.ID != "media-1" || post.Permalink != "" {
+		t.Errorf("PublishPost() = %+v", post)
+	}
+}
+
+func TestInstagramWaitForContainer_Timeout(t *testing.T) {
+	graph := &fakeGraph{polls: 1000}
+	c := newTestInstagram(t, graph, "ig-token")
+	c.pollTimeout = 10 * time.Millisecond
+
+	if err := c.WaitForContainer(context.Background(), "container-1"); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("WaitForContainer() error = %v, want timeout", err)
+	}
+}
+
+func TestInstagramGetPostMetrics(t *testing.T) {
+	c := newTestInstagram(t, &fakeGraph{}, "ig-token")
+
+	metrics, err := c.GetPostMetrics(context.Background(), "media-1")
+	if err != nil {
+		t.Fatalf("GetPostMetrics() error = %v", err)
+	}
+	want := Metrics{Plays: 1200, Reach: 900, Saves: 40, Shares: 12, Engagement: 95}
+	if *metrics != want {
+		t.Errorf("GetPostMetrics() = %+v, want %+v", *metrics, want)
+	}
+}
+
+func TestInstagramGetRecentPosts(t *testing.T) {
+	c := newTestInstagram(t, &fakeGraph{}, "ig-token")
+
+	posts, err := c.GetRecentPosts(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("GetRecentPosts() error = %v", err)
+	}
+	if len(posts) != 2 || posts[0].ID != "m2" || posts[0].Timestamp.Hour() != 8 {
+		t.Errorf("GetRecentPosts() = %+v", posts)
+	}
+}
+
+func TestInstagramGraphError(t *testing.T) {
+	c := newTestInstagram(t, &fakeGraph{}, "stale-token")
+
+	err := c.TestConnection(context.Background())
+	graphErr, ok := err.(*GraphError)
+	if !ok {
+		t.Fatalf("TestConnection() error = %v, want *GraphError", err)
+	}
+	if !graphErr.TokenExpired() || graphErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("GraphError = %+v", graphErr)
+	}
+}
+
+func TestInstagramTestConnection(t *testing.T) {
+	c := newTestInstagram(t, &fakeGraph{}, "ig-token")
+	if err := c.TestConnection(context.Background()); err != nil {
+		t.Errorf("TestConnection() error = %v", err)
+	}
+}
+
+func TestInstagramExchangeCode(t *testing.T) {
+	graph := &fakeGraph{}
+	c := newTestInstagram(t, graph, "")
+	c.cfg.AccountID = ""
+	c.cfg.AppID, c.cfg.AppSecret = "app-1", "secret"
+
+	grant := &oauth.Grant{Code: "code-1", Verifier: "verifier", RedirectURI: "http://localhost:8585/callback"}
+	if err := c.ExchangeCode(context.Background(), grant); err != nil {
+		t.Fatalf("ExchangeCode() error = %v", err)
+	}
+	if c.cfg.AccessToken != "ig-token" || len(graph.exchanges) != 1 || graph.exchanges[0] != "short-token" {
+		t.Errorf("AccessToken = %q after exchanging %v, want the long-lived token", c.cfg.AccessToken, graph.exchanges)
+	}
+	if left := time.Until(c.cfg.TokenExpiresAt); left < 59*24*time.Hour || left > 60*24*time.Hour {
+		t.Errorf("TokenExpiresAt = %v, want in 60 days", c.cfg.TokenExpiresAt)
+	}
+	if c.cfg.AccountID != "1784" {
+		t.Errorf("AccountID = %q, want the linked account", c.cfg.AccountID)
+	}
+}
+
+func TestInstagramRefreshesExpiringToken(t *testing.T) {
+	graph := &fakeGraph{}
+	c := newTestInstagram(t, graph, "ig-token")
+	c.cfg.AppID, c.cfg.AppSecret = "app-1", "secret"
+	saved := 0
+	c.OnTokenRefresh = func() error { saved++; return nil }
+
+	// Far from expiry: no refresh
+	c.cfg.TokenExpiresAt = time.Now().Add(30 * 24 * time.Hour)
+	if err := c.TestConnection(context.Background()); err != nil {
+		t.Fatalf("TestConnection() error = %v", err)
+	}
+	if len(graph.exchanges) != 0 {
+		t.Fatalf("refreshed a token with 30 days left")
+	}
+
+	c.cfg.TokenExpiresAt = time.Now().Add(2 * 24 * time.Hour)
+	if err := c.TestConnection(context.Background()); err != nil {
+		t.Fatalf("TestConnection() error = %v", err)
+	}
+	if len(graph.exchanges) != 1 || saved != 1 {
+		t.Errorf("exchanges = %v, saved %d times; want one refresh", graph.exchanges, saved)
+	}
+	if time.Until(c.cfg.TokenExpiresAt) < 59*24*time.Hour {
+		t.Errorf("TokenExpiresAt = %v, want extended", c.cfg.TokenExpiresAt)
+	}
+
+	// A failed refresh is fine while the token is valid, not after
+	c.cfg.AppSecret = "wrong"
+	c.cfg.TokenExpiresAt = time.Now().Add(time.Hour)
+	if err := c.TestConnection(context.Background()); err != nil {
+		t.Errorf("TestConnection() with a valid token error = %v", err)
+	}
+	c.cfg.TokenExpiresAt = time.Now().Add(-time.Hour)
+	if err := c.TestConnection(context.Background()); err == nil || !strings.Contains(err.Error(), "auth instagram") {
+		t.Errorf("TestConnection() with an expired token error = %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+)
+
+// DefaultPublisher serves every platform publishing.platforms does not
+// assign to another publisher
+const DefaultPublisher = "blotato"
+
+// Publisher sends posts to one or more platforms and follows them until
+// they are live. Publishers register themselves with RegisterPublisher.
+type Publisher interface {
+	// Publish submits a post. The returned status carries the submission
+	// ID that Status and Delete take, and is in-progress for posts the
+	// publisher delivers later.
+	Publish(ctx context.Context, req *PublishRequest) (*PostStatus, error)
+
+	// Status returns the delivery state of a submitted post
+	Status(ctx context.Context, submissionID string) (*PostStatus, error)
+
+	// Delete removes a submitted or published post, if Capabilities allow
+	Delete(ctx context.Context, submissionID string) error
+
+	// Capabilities describes what the publisher can post
+	Capabilities() Capabilities
+}
+
+// PublishRequest is a post to publish
+type PublishRequest struct {
+	Platform     string
+	PostType     string // reel, story or feed
+	Text         string
+	MediaURLs    []string
+	ScheduledFor time.Time // publishers that can schedule post at this time
+}
+
+// Capabilities describes what a publisher supports
+type Capabilities struct {
+	PostTypes     []string // post types it can publish
+	Schedule      bool     // posts can be handed over ahead of their time
+	Delete        bool     // posts can be deleted
+	MediaRequired bool     // every post needs a media URL
+	MaxTextLength int      // 0 for no limit
+}
+
+// Check reports why req cannot be published with these capabilities
+func (c Capabilities) Check(req *PublishRequest) error {
+	supported := false
+	for _, t := range c.PostTypes {
+		supported = supported || t == req.PostType
+	}
+	if !supported {
+		return fmt.Errorf("%s post type %q is not supported (supported: %s)", req.Platform, req.PostType, strings.Join(c.PostTypes, ", "))
+	}
+	if c.MediaRequired && len(req.MediaURLs) == 0 {
+		return fmt.Errorf("%s posts need a video or image; generate one with 'gagipress calendar generate-media' or use --with-media", req.Platform)
+	}
+	if c.MaxTextLength > 0 && len([]rune(req.Text)) > c.MaxTextLength {
+		return fmt.Errorf("%s posts are limited to %d characters, this one has %d", req.Platform, c.MaxTextLength, len([]rune(req.Text)))
+	}
+	return nil
+}
+
+// MetricsReader is implemented by publishers that can read the public
+// metrics of published posts
+type MetricsReader interface {
+	// PostMetrics returns the current counts of a post, found by the
+	// publisher's submission ID or, if that is empty, by its public URL
+	PostMetrics(ctx context.Context, post *PublishedPost) (*PostMetrics, error)
+}
+
+// PublishedPost identifies a published post
+type PublishedPost struct {
+	SubmissionID string // the reading publisher's own ID, if it made the post
+	URL          string // public URL
+}
+
+// PostMetrics are the counts of a post. Those a platform does not report
+// stay zero.
+type PostMetrics struct {
+	Views    int
+	Likes    int
+	Comments int
+	Shares   int
+	Saves    int
+}
+
+// PublisherFactory creates a publisher from the config
+type PublisherFactory func(cfg *config.Config) (Publisher, error)
+
+type registeredPublisher struct {
+	platforms []string
+	factory   PublisherFactory
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]registeredPublisher{}
+)
+
+// RegisterPublisher makes a publisher available under name for platforms.
+// Publishers own the models platform registry: content can only be
+// scheduled for platforms some publisher posts to.
+func RegisterPublisher(name string, platforms []string, factory PublisherFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[name]; dup {
+		panic("social: publisher " + name + " registered twice")
+	}
+	registry[name] = registeredPublisher{platforms: platforms, factory: factory}
+	for _, p := range platforms {
+		if _, ok := models.LookupPlatform(p); !ok {
+			models.RegisterPlatform(models.Platform{Name: p})
+		}
+	}
+}
+
+// PublisherNames returns the names of the registered publishers, sorted
+func PublisherNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PublisherPlatforms returns the platforms a registered publisher posts to
+func PublisherPlatforms(name string) []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[name].platforms
+}
+
+// Platforms returns the platforms at least one registered publisher posts
+// to, sorted
+func Platforms() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	seen := map[string]bool{}
+	var platforms []string
+	for _, p := range registry {
+		for _, name := range p.platforms {
+			if !seen[name] {
+				seen[name] = true
+				platforms = append(platforms, name)
+			}
+		}
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// PublisherFor returns the name of the publisher cfg assigns to platform:
+// its entry in publishing.platforms, or publishing.default
+func PublisherFor(cfg *config.Config, platform string) (string, error) {
+	name := cfg.Publishing.Platforms[platform]
+	if name == "" {
+		name = cfg.Publishing.Default
+	}
+	if name == "" {
+		name = DefaultPublisher
+	}
+
+	registryMu.RLock()
+	p, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown publisher %q for %s (available: %s)", name, platform, strings.Join(PublisherNames(), ", "))
+	}
+	for _, supported := range p.platforms {
+		if supported == platform {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("publisher %q cannot post to %s (it posts to: %s)", name, platform, strings.Join(p.platforms, ", "))
+}
+
+// EntryPublisher returns the publisher an entry's submission went to.
+// Submissions from before the publisher registry all went to Blotato.
+func EntryPublisher(entry *models.ContentCalendar) string {
+	if entry.Publisher == nil || *entry.Publisher == "" {
+		return DefaultPublisher
+	}
+	return *entry.Publisher
+}
+
+// PublisherSet creates publishers from a config on first use and reuses
+// them, so commands that post to several platforms share clients
+type PublisherSet struct {
+	cfg        *config.Config
+	publishers map[string]Publisher
+}
+
+// NewPublisherSet creates a publisher set for cfg
+func NewPublisherSet(cfg *config.Config) *PublisherSet {
+	return &PublisherSet{cfg: cfg, publishers: map[string]Publisher{}}
+}
+
+// For returns the name and publisher configured for platform
+func (s *PublisherSet) For(platform string) (string, Publisher, error) {
+	name, err := PublisherFor(s.cfg, platform)
+	if err != nil {
+		return "", nil, err
+	}
+	p, err := s.Named(name)
+	return name, p, err
+}
+
+// Named returns the publisher registered as name
+func (s *PublisherSet) Named(name string) (Publisher, error) {
+	if p, ok := s.publishers[name]; ok {
+		return p, nil
+	}
+
+	registryMu.RLock()
+	registered, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown publisher %q (available: %s)", name, strings.Join(PublisherNames(), ", "))
+	}
+	p, err := registered.factory(s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up the %s publisher: %w", name, err)
+	}
+	s.publishers[name] = p
+	return p, nil
+}
+
+// MetricsReader returns what reads the metrics of a post publisher made on
+// platform: the publisher itself if it can, or else the configured native
+// publisher of the platform, which finds the post by its URL (byURL). It
+// returns nil if neither can. The error is for a publisher that is not set
+// up, and only when no native publisher reads the post instead.
+func (s *PublisherSet) MetricsReader(publisher, platform string) (reader MetricsReader, byURL bool, err error) {
+	p, namedErr := s.Named(publisher)
+	if namedErr == nil {
+		if reader, ok := p.(MetricsReader); ok {
+			return reader, false, nil
+		}
+	}
+
+	if platform != publisher {
+		registryMu.RLock()
+		native, ok := registry[platform]
+		registryMu.RUnlock()
+		if ok && slices.Contains(native.platforms, platform) {
+			if p, err := s.Named(platform); err == nil {
+				if reader, ok := p.(MetricsReader); ok {
+					return reader, true, nil
+				}
+			}
+		}
+	}
+	return nil, false, namedErr
+}
@@ -0,0 +1,306 @@
+package social
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/models"
+)
+
+func TestPublisherFor(t *testing.T) {
+	cfg := &config.Config{}
+	if name, err := PublisherFor(cfg, "youtube"); err != nil || name != "blotato" {
+		t.Errorf("PublisherFor(youtube) = %q, %v; want blotato by default", name, err)
+	}
+
+	cfg.Publishing.Platforms = map[string]string{"instagram": "instagram"}
+	if name, err := PublisherFor(cfg, "instagram"); err != nil || name != "instagram" {
+		t.Errorf("PublisherFor(instagram) = %q, %v; want the configured publisher", name, err)
+	}
+	if name, _ := PublisherFor(cfg, "tiktok"); name != "blotato" {
+		t.Errorf("PublisherFor(tiktok) = %q, want blotato", name)
+	}
+
+	cfg.Publishing.Platforms["youtube"] = "tiktok"
+	if _, err := PublisherFor(cfg, "youtube"); err == nil || !strings.Contains(err.Error(), "cannot post to youtube") {
+		t.Errorf("PublisherFor(youtube) error = %v, want unsupported platform", err)
+	}
+	cfg.Publishing.Default = "myspace"
+	if _, err := PublisherFor(cfg, "threads"); err == nil || !strings.Contains(err.Error(), "unknown publisher") {
+		t.Errorf("PublisherFor(threads) error = %v, want unknown publisher", err)
+	}
+}
+
+func TestRegisterPublisher_AddsPlatforms(t *testing.T) {
+	RegisterPublisher("test-mastodon", []string{"mastodon"}, func(*config.Config) (Publisher, error) {
+		return &blotatoPublisher{}, nil
+	})
+
+	if err := models.ValidatePlatform("mastodon"); err != nil {
+		t.Errorf("platform of a new publisher not registered: %v", err)
+	}
+	found := false
+	for _, p := range Platforms() {
+		found = found || p == "mastodon"
+	}
+	if !found {
+		t.Errorf("Platforms() = %v, want mastodon", Platforms())
+	}
+	// Known platforms keep their label
+	if models.PlatformLabel("youtube") != "YouTube Shorts" {
+		t.Errorf("PlatformLabel(youtube) = %q", models.PlatformLabel("youtube"))
+	}
+}
+
+func TestPublisherSet(t *testing.T) {
+	set := NewPublisherSet(&config.Config{Blotato: config.BlotatoConfig{APIKey: "test-key"}})
+
+	name, first, err := set.For("pinterest")
+	if err != nil || name != "blotato" {
+		t.Fatalf("For(pinterest) = %q, %v", name, err)
+	}
+	_, second, _ := set.For("threads")
+	if first != second {
+		t.Error("publishers are not reused across platforms")
+	}
+
+	if _, _, err := set.For("instagram"); err != nil {
+		t.Fatalf("For(instagram) error = %v", err)
+	}
+	if _, err := set.Named("instagram"); err == nil || !strings.Contains(err.Error(), "auth instagram") {
+		t.Errorf("Named(instagram) error = %v, want a login hint", err)
+	}
+}
+
+func TestCapabilitiesCheck(t *testing.T) {
+	caps := Capabilities{PostTypes: []string{"reel"}, MediaRequired: true, MaxTextLength: 5}
+	tests := []struct {
+		name string
+		req  PublishRequest
+		err  string
+	}{
+		{"ok", PublishRequest{PostType: "reel", Text: "hello", MediaURLs: []string{"v.mp4"}}, ""},
+		{"post type", PublishRequest{PostType: "story", MediaURLs: []string{"v.mp4"}}, "not supported"},
+		{"media", PublishRequest{PostType: "reel"}, "need a video"},
+		{"length", PublishRequest{PostType: "reel", Text: "héllo!", MediaURLs: []string{"v.mp4"}}, "limited to 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := caps.Check(&tt.req)
+			if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("Check() = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestBlotatoPublisher(t *testing.T) {
+	var posts []PublishPostRequest
+	accountLookups := 0
+	c := newTestBlotato(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/me/accounts":
+			accountLookups++
+			w.Write([]byte(`{"items":[{"id":"acc-` + r.URL.Query().Get("platform") + `"}]}`))
+		case "/posts":
+			var req PublishPostRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			posts = append(posts, req)
+			w.Write([]byte(`{"postSubmissionId":"sub-1"}`))
+		}
+	})
+	c.Targets = BlotatoTargets{PinterestBoardID: "board-1"}
+	p := &blotatoPublisher{client: c, accounts: map[string]string{}}
+
+	ctx := context.Background()
+	for _, platform := range []string{"youtube", "youtube", "pinterest"} {
+		status, err := p.Publish(ctx, &PublishRequest{Platform: platform, PostType: "reel", Text: "Three puzzles for rainy days\n\nMore text"})
+		if err != nil {
+			t.Fatalf("Publish(%s) error = %v", platform, err)
+		}
+		if status.PostSubmissionID != "sub-1" || status.Status != PostStatusInProgress {
+			t.Errorf("Publish(%s) = %+v", platform, status)
+		}
+	}
+	if accountLookups != 2 {
+		t.Errorf("account lookups = %d, want one per platform", accountLookups)
+	}
+
+	yt := posts[0].Post
+	if yt.AccountID != "acc-youtube" || yt.Target.Title != "Three puzzles for rainy days" || yt.Target.PrivacyStatus != "public" {
+		t.Errorf("youtube post = %+v", yt)
+	}
+	if posts[0].ScheduledTime != "" {
+		t.Errorf("ScheduledTime = %q, want none for a zero time", posts[0].ScheduledTime)
+	}
+	if pin := posts[2].Post.Target; pin.BoardID != "board-1" || pin.Title != "" {
+		t.Errorf("pinterest target = %+v", pin)
+	}
+}
+
+func TestInstagramPublisher(t *testing.T) {
+	graph := &fakeGraph{finalStatus: ContainerFinished}
+	p := &instagramPublisher{client: newTestInstagram(t, graph, "ig-token")}
+
+	req := &PublishRequest{Platform: "instagram", PostType: "reel", Text: "hello"}
+	if _, err := p.Publish(context.Background(), req); err == nil {
+		t.Error("Publish() without a video succeeded")
+	}
+
+	req.MediaURLs = []string{"https://cdn.example.com/reel.mp4"}
+	status, err := p.Publish(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if status.PostSubmissionID != "media-1" || status.Status != PostStatusPublished || status.PublicURL != "https://www.instagram.com/reel/abc/" {
+		t.Errorf("Publish() = %+v", status)
+	}
+}
+
+func TestInstagramPublisher_LookupFails(t *testing.T) {
+	graph := &fakeGraph{finalStatus: ContainerFinished, lookupDown: true}
+	p := &instagramPublisher{client: newTestInstagram(t, graph, "ig-token")}
+
+	// A Reel that is live but could not be looked up keeps its media ID, so
+	// a retry does not post it again, and stays in progress for its URL
+	req := &PublishRequest{Platform: "instagram", PostType: "reel", Text: "hello", MediaURLs: []string{"https://cdn.example.com/reel.mp4"}}
+	status, err := p.Publish(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if status.PostSubmissionID != "media-1" || status.Status != PostStatusInProgress || status.PublicURL != "" {
+		t.Errorf("Publish() = %+v", status)
+	}
+	if len(graph.published) != 1 {
+		t.Errorf("published %d times, want 1", len(graph.published))
+	}
+
+	graph.lookupDown = false
+	status, err = p.Status(context.Background(), status.PostSubmissionID)
+	if err != nil || status.Status != PostStatusPublished || status.PublicURL != "https://www.instagram.com/reel/abc/" {
+		t.Errorf("Status() = %+v, %v", status, err)
+	}
+}
+
+func TestTikTokPublisherStatus(t *testing.T) {
+	tests := []struct {
+		fixture string
+		status  string
+	}{
+		{"status_processing", PostStatusInProgress},
+		{"status_complete", PostStatusPublished},
+		{"status_failed", PostStatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			p := &tiktokPublisher{client: newTestTikTok(t, &fakeTikTok{statuses: []string{tt.fixture}}, "act.tiktok")}
+			status, err := p.Status(context.Background(), "v_pub_url~v2.7346282935727915050")
+			if err != nil {
+				t.Fatalf("Status() error = %v", err)
+			}
+			if status.Status != tt.status {
+				t.Errorf("Status() = %+v, want %s", status, tt.status)
+			}
+			switch tt.status {
+			case PostStatusPublished:
+				if status.PublicURL != "https://www.tiktok.com/@gagipress/video/7346282935727915099" {
+					t.Errorf("PublicURL = %q", status.PublicURL)
+				}
+			case PostStatusFailed:
+				if status.ErrorMessage != "file_format_check_failed" {
+					t.Errorf("ErrorMessage = %q", status.ErrorMessage)
+				}
+			}
+		})
+	}
+}
+
+func TestInstagramPublisherPostMetrics(t *testing.T) {
+	p := &instagramPublisher{client: newTestInstagram(t, &fakeGraph{}, "ig-token")}
+
+	want := PostMetrics{Views: 1200, Likes: 3, Comments: 1, Shares: 12, Saves: 40}
+	for _, post := range []*PublishedPost{
+		{SubmissionID: "media-1"},
+		{URL: "https://www.instagram.com/p/abc/"}, // posted through Blotato
+	} {
+		metrics, err := p.PostMetrics(context.Background(), post)
+		if err != nil {
+			t.Fatalf("PostMetrics(%+v) error = %v", post, err)
+		}
+		if *metrics != want {
+			t.Errorf("PostMetrics(%+v) = %+v, want %+v", post, *metrics, want)
+		}
+	}
+
+	if _, err := p.PostMetrics(context.Background(), &PublishedPost{URL: "https://www.instagram.com/reel/gone/"}); err == nil {
+		t.Error("PostMetrics() found a post that is not on the account")
+	}
+}
+
+func TestTikTokPublisherPostMetrics(t *testing.T) {
+	p := &tiktokPublisher{client: newTestTikTok(t, &fakeTikTok{statuses: []string{"status_complete"}}, "act.tiktok")}
+
+	want := PostMetrics{Views: 4210, Likes: 301, Comments: 17, Shares: 22}
+	for _, post := range []*PublishedPost{
+		{SubmissionID: "v_pub_url~v2.7346282935727915050"},
+		{URL: "https://www.tiktok.com/@gagipress/video/7346282935727915099"},
+	} {
+		metrics, err := p.PostMetrics(context.Background(), post)
+		if err != nil {
+			t.Fatalf("PostMetrics(%+v) error = %v", post, err)
+		}
+		if *metrics != want {
+			t.Errorf("PostMetrics(%+v) = %+v, want %+v", post, *metrics, want)
+		}
+	}
+}
+
+func TestPublisherSetMetricsReader(t *testing.T) {
+	cfg := &config.Config{
+		Blotato:   config.BlotatoConfig{APIKey: "test-key"},
+		Instagram: config.InstagramConfig{AccessToken: "ig-token", AccountID: "1784"},
+	}
+	set := NewPublisherSet(cfg)
+
+	reader, byURL, err := set.MetricsReader("instagram", "instagram")
+	if _, ok := reader.(*instagramPublisher); !ok || byURL || err != nil {
+		t.Errorf("MetricsReader(instagram) = %T, %v, %v; want the publisher itself", reader, byURL, err)
+	}
+
+	// Blotato reports no metrics: its posts are read natively by URL
+	reader, byURL, err = set.MetricsReader("blotato", "instagram")
+	if _, ok := reader.(*instagramPublisher); !ok || !byURL || err != nil {
+		t.Errorf("MetricsReader(blotato, instagram) = %T, %v, %v; want instagram by URL", reader, byURL, err)
+	}
+
+	// ... unless the platform has no native publisher or it is not set up
+	for _, platform := range []string{"youtube", "tiktok"} {
+		if reader, _, err := set.MetricsReader("blotato", platform); reader != nil || err != nil {
+			t.Errorf("MetricsReader(blotato, %s) = %T, %v; want none", platform, reader, err)
+		}
+	}
+
+	if _, _, err := set.MetricsReader("bluesky", "bluesky"); err == nil {
+		t.Error("MetricsReader() of an unconfigured publisher succeeded")
+	}
+}
+
+func TestPublisherSetMetricsReader_PublisherNotSetUp(t *testing.T) {
+	// Blotato's key is gone, but the native publisher still reads its posts
+	cfg := &config.Config{Instagram: config.InstagramConfig{AccessToken: "ig-token", AccountID: "1784"}}
+	set := NewPublisherSet(cfg)
+
+	reader, byURL, err := set.MetricsReader("blotato", "instagram")
+	if _, ok := reader.(*instagramPublisher); !ok || !byURL || err != nil {
+		t.Errorf("MetricsReader(blotato, instagram) = %T, %v, %v; want instagram by URL", reader, byURL, err)
+	}
+
+	// Without a native publisher either, the setup error is reported
+	if reader, _, err := set.MetricsReader("blotato", "tiktok"); reader != nil || err == nil {
+		t.Errorf("MetricsReader(blotato, tiktok) = %T, %v; want the blotato error", reader, err)
+	}
+}
@@ -1,16 +1,62 @@
 package social
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/errors"
+	"github.com/gagipress/gagipress-cli/internal/oauth"
+)
+
+const (
+	TikTokBaseURL = "https://open.tiktokapis.com/v2"
+	TikTokAuthURL = "https://www.tiktok.com/v2/auth/authorize/"
+)
+
+// TikTokScopes are the scopes 'auth tiktok' asks for
+var TikTokScopes = []string{"user.info.basic", "video.publish", "video.upload", "video.list"}
+
+// tiktokRefreshWindow is how long before expiry an access token (valid for
+// 24 hours) is refreshed
+const tiktokRefreshWindow = 5 * time.Minute
+
+// videoFields are the Display API video fields read into a TikTokPost
+const videoFields = "id,title,video_description,create_time,share_url,view_count,like_count,comment_count,share_count"
+
+// Upload chunk limits of the Content Posting API. Files up to
+// maxSingleChunk are sent whole; larger ones in chunkSize pieces, the last
+// of which takes the remainder.
+const (
+	chunkSize      = 10 * 1024 * 1024
+	maxSingleChunk = 64 * 1024 * 1024
 )
 
 // TikTokClient handles TikTok API interactions
 type TikTokClient struct {
-	accessToken string
-	accountID   string
+	cfg          *config.TikTokConfig
+	privacyLevel string
+	baseURL      string
+	httpClient   *http.Client
+
+	// OnTokenRefresh is called after the tokens in the config were
+	// refreshed, to save them
+	OnTokenRefresh func() error
+
+	// Posts are processed asynchronously; their status is fetched every
+	// pollInterval until they are done or pollTimeout has passed
+	pollInterval time.Duration
+	pollTimeout  time.Duration
 }
 
 // TikTokPost represents a TikTok post
@@ -26,60 +72,675 @@ type TikTokPost struct {
 	SharesCount   int       `json:"share_count"`
 }
 
-// TikTokMetrics represents TikTok post metrics
+// TikTokMetrics represents TikTok post metrics. The Display API only
+// reports the public counts; profile views and watch time stay zero.
 type TikTokMetrics struct {
-	VideoViews        int     `json:"video_views"`
-	ProfileViews      int     `json:"profile_views"`
-	Likes             int     `json:"likes"`
-	Comments          int     `json:"comments"`
-	Shares            int     `json:"shares"`
-	EngagementRate    float64 `json:"engagement_rate"`
-	AverageWatchTime  float64 `json:"average_watch_time"`
-	TotalWatchTime    int     `json:"total_watch_time"`
+	VideoViews       int     `json:"video_views"`
+	ProfileViews     int     `json:"profile_views"`
+	Likes            int     `json:"likes"`
+	Comments         int     `json:"comments"`
+	Shares           int     `json:"shares"`
+	EngagementRate   float64 `json:"engagement_rate"`
+	AverageWatchTime float64 `json:"average_watch_time"`
+	TotalWatchTime   int     `json:"total_watch_time"`
+}
+
+// Publish statuses reported by the Content Posting API
+const (
+	TikTokProcessingUpload   = "PROCESSING_UPLOAD"
+	TikTokProcessingDownload = "PROCESSING_DOWNLOAD"
+	TikTokSentToInbox        = "SEND_TO_USER_INBOX"
+	TikTokPublishComplete    = "PUBLISH_COMPLETE"
+	TikTokPublishFailed      = "FAILED"
+)
+
+// TikTokPublishStatus is the state of a post being published
+type TikTokPublishStatus struct {
+	Status     string   `json:"status"`
+	FailReason string   `json:"fail_reason"`
+	PostIDs    []string `json:"-"` // public post IDs, once published
+}
+
+// TikTokUpload is an initialized file upload
+type TikTokUpload struct {
+	PublishID  string
+	UploadURL  string
+	Size       int64
+	ChunkSize  int64
+	ChunkCount int
+}
+
+// TikTokError is an error returned by the TikTok API
+type TikTokError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	LogID      string `json:"log_id"`
+}
+
+func (e *TikTokError) Error() string {
+	return fmt.Sprintf("tiktok API error (%d, %s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+// TokenExpired reports whether the error means the access token is no
+// longer valid
+func (e *TikTokError) TokenExpired() bool {
+	return e.Code == "access_token_invalid"
 }
 
 // NewTikTokClient creates a new TikTok API client
 func NewTikTokClient(cfg *config.TikTokConfig) *TikTokClient {
+	privacy := cfg.PrivacyLevel
+	if privacy == "" {
+		privacy = "PUBLIC_TO_EVERYONE"
+	}
 	return &TikTokClient{
-		accessToken: cfg.AccessToken,
-		accountID:   cfg.AccountID,
+		cfg:          cfg,
+		privacyLevel: privacy,
+		baseURL:      TikTokBaseURL,
+		httpClient:   &http.Client{Timeout: 60 * time.Second},
+		pollInterval: 5 * time.Second,
+		pollTimeout:  10 * time.Minute,
+	}
+}
+
+// PublishVideo publishes the video at videoURL, which TikTok pulls itself
+// (the URL's domain must be verified in the developer portal), and waits
+// until it is live.
+func (c *TikTokClient) PublishVideo(ctx context.Context, caption string, videoURL string, hashtags []string) (*TikTokPost, error) {
+	title := tiktokTitle(caption, hashtags)
+	publishID, err := c.InitPullFromURL(ctx, title, videoURL)
+	if err != nil {
+		return nil, err
+	}
+	return c.finishPublish(ctx, publishID, title)
+}
+
+// PublishFile uploads and publishes a local video file, and waits until it
+// is live.
+func (c *TikTokClient) PublishFile(ctx context.Context, caption string, path string, hashtags []string) (*TikTokPost, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open video: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open video: %w", err)
+	}
+
+	title := tiktokTitle(caption, hashtags)
+	upload, err := c.InitFileUpload(ctx, title, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Upload(ctx, upload, f); err != nil {
+		return nil, err
+	}
+	return c.finishPublish(ctx, upload.PublishID, title)
+}
+
+// finishPublish waits for a post and looks up the public video it became
+func (c *TikTokClient) finishPublish(ctx context.Context, publishID, title string) (*TikTokPost, error) {
+	status, err := c.WaitForPublish(ctx, publishID)
+	if err != nil {
+		return nil, err
+	}
+
+	post := &TikTokPost{ID: publishID, Caption: title}
+	if len(status.PostIDs) == 0 {
+		return post, nil // e.g. private posts have no public ID
+	}
+	post.VideoID = status.PostIDs[0]
+	videos, err := c.QueryVideos(ctx, status.PostIDs[:1])
+	if err == nil && len(videos) > 0 {
+		post.ShareURL = videos[0].ShareURL
+		post.CreatedAt = videos[0].CreatedAt
+	}
+	return post, nil
+}
+
+// InitPullFromURL starts a direct post of the video at videoURL and returns
+// its publish ID
+func (c *TikTokClient) InitPullFromURL(ctx context.Context, title, videoURL string) (string, error) {
+	var data struct {
+		PublishID string `json:"publish_id"`
+	}
+	body := map[string]any{
+		"post_info":   c.postInfo(title),
+		"source_info": map[string]any{"source": "PULL_FROM_URL", "video_url": videoURL},
 	}
+	if err := c.call(ctx, "/post/publish/video/init/", "", body, &data); err != nil {
+		return "", err
+	}
+	return data.PublishID, nil
 }
 
-// PublishVideo publishes a video to TikTok
-// TODO: Implement actual API call
-func (c *TikTokClient) PublishVideo(caption string, videoURL string, hashtags []string) (*TikTokPost, error) {
-	// Placeholder implementation
-	return nil, fmt.Errorf("not implemented: TikTok publishing requires OAuth flow setup")
+// InitFileUpload starts a direct post of a video file of size bytes
+func (c *TikTokClient) InitFileUpload(ctx context.Context, title string, size int64) (*TikTokUpload, error) {
+	if size <= 0 {
+		return nil, errors.New(errors.ErrorTypeValidation, "video file is empty")
+	}
+	upload := &TikTokUpload{Size: size}
+	upload.ChunkSize, upload.ChunkCount = planChunks(size)
+
+	var data struct {
+		PublishID string `json:"publish_id"`
+		UploadURL string `json:"upload_url"`
+	}
+	body := map[string]any{
+		"post_info": c.postInfo(title),
+		"source_info": map[string]any{
+			"source":            "FILE_UPLOAD",
+			"video_size":        size,
+			"chunk_size":        upload.ChunkSize,
+			"total_chunk_count": upload.ChunkCount,
+		},
+	}
+	if err := c.call(ctx, "/post/publish/video/init/", "", body, &data); err != nil {
+		return nil, err
+	}
+	upload.PublishID = data.PublishID
+	upload.UploadURL = data.UploadURL
+	return upload, nil
+}
+
+// Upload sends the video to upload.UploadURL, one chunk per request
+func (c *TikTokClient) Upload(ctx context.Context, upload *TikTokUpload, video io.ReaderAt) error {
+	for i := 0; i < upload.ChunkCount; i++ {
+		first := int64(i) * upload.ChunkSize
+		last := first + upload.ChunkSize - 1
+		if i == upload.ChunkCount-1 {
+			last = upload.Size - 1
+		}
+
+		chunk := make([]byte, last-first+1)
+		if _, err := video.ReadAt(chunk, first); err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read video: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, upload.UploadURL, bytes.NewReader(chunk))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "video/mp4")
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", first, last, upload.Size))
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return errors.Wrap(err, errors.ErrorTypeAPI, "failed to upload video to TikTok")
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusCreated {
+			return fmt.Errorf("tiktok upload error (chunk %d/%d, %d): %s", i+1, upload.ChunkCount, resp.StatusCode, string(body))
+		}
+	}
+	return nil
+}
+
+// FetchPublishStatus returns the state of a post
+func (c *TikTokClient) FetchPublishStatus(ctx context.Context, publishID string) (*TikTokPublishStatus, error) {
+	var data struct {
+		TikTokPublishStatus
+		// sic: the API spells it this way
+		PostIDs []json.Number `json:"publicaly_available_post_id"`
+	}
+	if err := c.call(ctx, "/post/publish/status/fetch/", "", map[string]string{"publish_id": publishID}, &data); err != nil {
+		return nil, err
+	}
+	status := data.TikTokPublishStatus
+	for _, id := range data.PostIDs {
+		status.PostIDs = append(status.PostIDs, id.String())
+	}
+	return &status, nil
+}
+
+// WaitForPublish polls a post until it is published or failed. Polling
+// stops early if ctx is cancelled.
+func (c *TikTokClient) WaitForPublish(ctx context.Context, publishID string) (*TikTokPublishStatus, error) {
+	deadline := time.Now().Add(c.pollTimeout)
+	for {
+		status, err := c.FetchPublishStatus(ctx, publishID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch status.Status {
+		case TikTokPublishComplete, TikTokSentToInbox:
+			return status, nil
+		case TikTokPublishFailed:
+			return nil, errors.New(errors.ErrorTypeAPI, "tiktok could not publish the video: "+status.FailReason)
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for tiktok to publish %s", publishID)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+	}
 }
 
 // GetVideoMetrics retrieves metrics for a specific video
-// TODO: Implement actual API call
-func (c *TikTokClient) GetVideoMetrics(videoID string) (*TikTokMetrics, error) {
-	// Placeholder implementation
-	return nil, fmt.Errorf("not implemented: requires TikTok API access")
+func (c *TikTokClient) GetVideoMetrics(ctx context.Context, videoID string) (*TikTokMetrics, error) {
+	videos, err := c.QueryVideos(ctx, []string{videoID})
+	if err != nil {
+		return nil, err
+	}
+	if len(videos) == 0 {
+		return nil, errors.New(errors.ErrorTypeNotFound, "tiktok video not found: "+videoID)
+	}
+
+	v := videos[0]
+	metrics := &TikTokMetrics{
+		VideoViews: v.ViewsCount,
+		Likes:      v.LikesCount,
+		Comments:   v.CommentsCount,
+		Shares:     v.SharesCount,
+	}
+	if v.ViewsCount > 0 {
+		metrics.EngagementRate = float64(v.LikesCount+v.CommentsCount+v.SharesCount) / float64(v.ViewsCount) * 100
+	}
+	return metrics, nil
 }
 
-// GetRecentVideos retrieves recent videos
-// TODO: Implement actual API call
-func (c *TikTokClient) GetRecentVideos(limit int) ([]TikTokPost, error) {
-	// Placeholder implementation
-	return nil, fmt.Errorf("not implemented: requires TikTok API access")
+// QueryVideos retrieves videos of the user by ID (at most 20 per call)
+func (c *TikTokClient) QueryVideos(ctx context.Context, videoIDs []string) ([]TikTokPost, error) {
+	var data struct {
+		Videos []tiktokVideo `json:"videos"`
+	}
+	body := map[string]any{"filters": map[string]any{"video_ids": videoIDs}}
+	if err := c.call(ctx, "/video/query/", videoFields, body, &data); err != nil {
+		return nil, err
+	}
+	return posts(data.Videos), nil
+}
+
+// GetRecentVideos retrieves the user's most recent videos, newest first
+func (c *TikTokClient) GetRecentVideos(ctx context.Context, limit int) ([]TikTokPost, error) {
+	var videos []tiktokVideo
+	var cursor int64
+	for {
+		pageSize := 20 // the API maximum
+		if limit > 0 {
+			pageSize = min(pageSize, limit-len(videos))
+		}
+		body := map[string]any{"max_count": pageSize}
+		if cursor != 0 {
+			body["cursor"] = cursor
+		}
+
+		var data struct {
+			Videos  []tiktokVideo `json:"videos"`
+			Cursor  int64         `json:"cursor"`
+			HasMore bool          `json:"has_more"`
+		}
+		if err := c.call(ctx, "/video/list/", videoFields, body, &data); err != nil {
+			return nil, err
+		}
+		videos = append(videos, data.Videos...)
+
+		if !data.HasMore || len(data.Videos) == 0 || (limit > 0 && len(videos) >= limit) {
+			break
+		}
+		cursor = data.Cursor
+	}
+	return posts(videos), nil
 }
 
 // TestConnection tests the TikTok API connection
-func (c *TikTokClient) TestConnection() error {
-	if c.accessToken == "" {
+func (c *TikTokClient) TestConnection(ctx context.Context) error {
+	if c.cfg.AccessToken == "" {
 		return fmt.Errorf("TikTok access token not configured")
 	}
-	// TODO: Implement actual connection test with API
-	return fmt.Errorf("not implemented: OAuth flow required first")
+	var data struct {
+		User struct {
+			OpenID string `json:"open_id"`
+		} `json:"user"`
+	}
+	return c.call(ctx, "/user/info/", "open_id,display_name", nil, &data)
+}
+
+// planChunks splits an upload of size bytes into chunks. The last chunk
+// takes the remainder, so it can be up to twice chunkSize.
+func planChunks(size int64) (int64, int) {
+	if size <= maxSingleChunk {
+		return size, 1
+	}
+	return chunkSize, int(size / chunkSize)
+}
+
+// postInfo is the post_info of a direct post
+func (c *TikTokClient) postInfo(title string) map[string]any {
+	return map[string]any{"title": title, "privacy_level": c.privacyLevel}
 }
 
-// Note: OAuth flow will be implemented in Week 3
-// Requires:
-// 1. TikTok Developer Account
-// 2. App creation in TikTok Developer Portal
-// 3. OAuth redirect handling
-// 4. Token refresh mechanism
-// 5. Video upload endpoint configuration
+// tiktokTitle appends the hashtags to a caption
+func tiktokTitle(caption string, hashtags []string) string {
+	if len(hashtags) == 0 {
+		return caption
+	}
+	return caption + "\n\n" + strings.Join(hashtags, " ")
+}
+
+// tiktokVideo is a video as the Display API returns it
+type tiktokVideo struct {
+	ID               string `json:"id"`
+	Title            string `json:"title"`
+	VideoDescription string `json:"video_description"`
+	CreateTime       int64  `json:"create_time"`
+	ShareURL         string `json:"share_url"`
+	ViewCount        int    `json:"view_count"`
+	LikeCount        int    `json:"like_count"`
+	CommentCount     int    `json:"comment_count"`
+	ShareCount       int    `json:"share_count"`
+}
+
+func posts(videos []tiktokVideo) []TikTokPost {
+	out := make([]TikTokPost, len(videos))
+	for i, v := range videos {
+		caption := v.VideoDescription
+		if caption == "" {
+			caption = v.Title
+		}
+		out[i] = TikTokPost{
+			ID:            v.ID,
+			VideoID:       v.ID,
+			Caption:       caption,
+			ShareURL:      v.ShareURL,
+			CreatedAt:     time.Unix(v.CreateTime, 0).UTC(),
+			ViewsCount:    v.ViewCount,
+			LikesCount:    v.LikeCount,
+			CommentsCount: v.CommentCount,
+			SharesCount:   v.ShareCount,
+		}
+	}
+	return out
+}
+
+// OAuthProvider describes TikTok's authorization page for this app. Desktop
+// apps send the PKCE challenge hex-encoded.
+func (c *TikTokClient) OAuthProvider() *oauth.Provider {
+	return &oauth.Provider{
+		Name:          "TikTok",
+		AuthURL:       TikTokAuthURL,
+		ClientID:      c.cfg.ClientKey,
+		ClientIDParam: "client_key",
+		Scopes:        TikTokScopes,
+		ScopeSep:      ",",
+		HexChallenge:  true,
+	}
+}
+
+// ExchangeCode turns an authorization code into access and refresh tokens
+// and stores them, with the user's open_id, in the config
+func (c *TikTokClient) ExchangeCode(ctx context.Context, grant *oauth.Grant) error {
+	err := c.requestToken(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {grant.Code},
+		"redirect_uri":  {grant.RedirectURI},
+		"code_verifier": {grant.Verifier},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	return nil
+}
+
+// RefreshToken gets a new access token with the refresh token and reports
+// it through OnTokenRefresh
+func (c *TikTokClient) RefreshToken(ctx context.Context) error {
+	if c.cfg.RefreshToken == "" || oauth.ExpiresWithin(c.cfg.RefreshExpiresAt, 0) {
+		return fmt.Errorf("tiktok refresh token missing or expired; run 'gagipress auth tiktok'")
+	}
+	err := c.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {c.cfg.RefreshToken},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refresh TikTok access token: %w", err)
+	}
+	if c.OnTokenRefresh != nil {
+		return c.OnTokenRefresh()
+	}
+	return nil
+}
+
+// requestToken posts a token request and stores the tokens it returns.
+// Unlike the rest of the API, the token endpoint does not wrap its
+// response in data/error.
+func (c *TikTokClient) requestToken(ctx context.Context, form url.Values) error {
+	if c.cfg.ClientKey == "" || c.cfg.ClientSecret == "" {
+		return fmt.Errorf("tiktok client_key and client_secret are not configured")
+	}
+	form.Set("client_key", c.cfg.ClientKey)
+	form.Set("client_secret", c.cfg.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/oauth/token/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeAPI, "failed to connect to TikTok API")
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		OpenID           string `json:"open_id"`
+		RefreshToken     string `json:"refresh_token"`
+		RefreshExpiresIn int    `json:"refresh_expires_in"`
+		Error            string `json:"error"`
+		Description      string `json:"error_description"`
+		LogID            string `json:"log_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeValidation, "failed to parse TikTok token response")
+	}
+	if token.Error != "" || token.AccessToken == "" {
+		return &TikTokError{StatusCode: resp.StatusCode, Code: token.Error, Message: token.Description, LogID: token.LogID}
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	c.cfg.AccessToken = token.AccessToken
+	c.cfg.TokenExpiresAt = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	if token.RefreshToken != "" {
+		c.cfg.RefreshToken = token.RefreshToken
+		c.cfg.RefreshExpiresAt = now.Add(time.Duration(token.RefreshExpiresIn) * time.Second)
+	}
+	if token.OpenID != "" {
+		c.cfg.AccountID = token.OpenID
+	}
+	return nil
+}
+
+// ensureToken refreshes the access token shortly before it expires. A
+// failed refresh is only an error once the token has expired.
+func (c *TikTokClient) ensureToken(ctx context.Context) error {
+	if c.cfg.AccessToken == "" {
+		return fmt.Errorf("TikTok access token not configured")
+	}
+	if !oauth.ExpiresWithin(c.cfg.TokenExpiresAt, tiktokRefreshWindow) {
+		return nil
+	}
+	if err := c.RefreshToken(ctx); err != nil && oauth.ExpiresWithin(c.cfg.TokenExpiresAt, 0) {
+		return err
+	}
+	return nil
+}
+
+// call POSTs body as JSON to path (with fields as the fields query
+// parameter, if set) and decodes the data of the response into out. A nil
+// body sends a GET.
+func (c *TikTokClient) call(ctx context.Context, path, fields string, body any, out any) error {
+	if err := c.ensureToken(ctx); err != nil {
+		return err
+	}
+
+	endpoint := c.baseURL + path
+	if fields != "" {
+		endpoint += "?fields=" + fields
+	}
+	method := http.MethodGet
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		method = http.MethodPost
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.cfg.AccessToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeAPI, "failed to connect to TikTok API")
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeNetwork, "failed to read TikTok API response")
+	}
+
+	// Every response carries an error object; its code is "ok" on success
+	var envelope struct {
+		Data  json.RawMessage `json:"data"`
+		Error *TikTokError    `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return &TikTokError{StatusCode: resp.StatusCode, Code: strconv.Itoa(resp.StatusCode), Message: string(raw)}
+		}
+		return errors.Wrap(err, errors.ErrorTypeValidation, "failed to parse TikTok API response")
+	}
+	if envelope.Error != nil && envelope.Error.Code != "ok" {
+		envelope.Error.StatusCode = resp.StatusCode
+		return envelope.Error
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &TikTokError{StatusCode: resp.StatusCode, Code: strconv.Itoa(resp.StatusCode), Message: string(raw)}
+	}
+
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeValidation, "failed to parse TikTok API response")
+	}
+	return nil
+}
+
+func init() {
+	RegisterPublisher("tiktok", []string{"tiktok"}, newTikTokPublisher)
+}
+
+// tiktokCaptionLength is the longest caption TikTok accepts
+const tiktokCaptionLength = 2200
+
+// tiktokPublisher posts videos natively with the Content Posting API. TikTok
+// pulls and processes the video after Publish returns; Status follows it.
+// The API cannot schedule, so posts go out when they are published.
+type tiktokPublisher struct {
+	client *TikTokClient
+}
+
+func newTikTokPublisher(cfg *config.Config) (Publisher, error) {
+	if cfg.TikTok.AccessToken == "" {
+		return nil, fmt.Errorf("tiktok is not authorized; run 'gagipress auth tiktok'")
+	}
+	client := NewTikTokClient(&cfg.TikTok)
+	client.OnTokenRefresh = func() error { return config.Save(cfg) }
+	return &tiktokPublisher{client: client}, nil
+}
+
+func (p *tiktokPublisher) Publish(ctx context.Context, req *PublishRequest) (*PostStatus, error) {
+	if err := p.Capabilities().Check(req); err != nil {
+		return nil, err
+	}
+	publishID, err := p.client.InitPullFromURL(ctx, req.Text, req.MediaURLs[0])
+	if err != nil {
+		return nil, err
+	}
+	return &PostStatus{PostSubmissionID: publishID, Status: PostStatusInProgress}, nil
+}
+
+func (p *tiktokPublisher) Status(ctx context.Context, submissionID string) (*PostStatus, error) {
+	status, err := p.client.FetchPublishStatus(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PostStatus{PostSubmissionID: submissionID, Status: PostStatusInProgress}
+	switch status.Status {
+	case TikTokPublishComplete:
+		result.Status = PostStatusPublished
+		if len(status.PostIDs) > 0 {
+			videos, err := p.client.QueryVideos(ctx, status.PostIDs[:1])
+			if err == nil && len(videos) > 0 {
+				result.PublicURL = videos[0].ShareURL
+			}
+		}
+	case TikTokPublishFailed:
+		result.Status = PostStatusFailed
+		result.ErrorMessage = status.FailReason
+	}
+	return result, nil
+}
+
+// PostMetrics reads a video's public counts. TikTok reports no saves.
+// Videos posted through another publisher are found by the ID in their
+// share URL.
+func (p *tiktokPublisher) PostMetrics(ctx context.Context, post *PublishedPost) (*PostMetrics, error) {
+	var videoID string
+	if post.SubmissionID != "" {
+		status, err := p.client.FetchPublishStatus(ctx, post.SubmissionID)
+		if err != nil {
+			return nil, err
+		}
+		if len(status.PostIDs) == 0 {
+			return nil, errors.New(errors.ErrorTypeNotFound, "tiktok post "+post.SubmissionID+" has no public video")
+		}
+		videoID = status.PostIDs[0]
+	} else if m := tiktokVideoURL.FindStringSubmatch(post.URL); m != nil {
+		videoID = m[1]
+	} else {
+		return nil, errors.New(errors.ErrorTypeValidation, "not a TikTok video URL: "+post.URL)
+	}
+
+	metrics, err := p.client.GetVideoMetrics(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	return &PostMetrics{
+		Views:    metrics.VideoViews,
+		Likes:    metrics.Likes,
+		Comments: metrics.Comments,
+		Shares:   metrics.Shares,
+	}, nil
+}
+
+// tiktokVideoURL matches share URLs such as
+// https://www.tiktok.com/@gagipress/video/7234567890123456789
+var tiktokVideoURL = regexp.MustCompile(`/video/(\d+)`)
+
+func (p *tiktokPublisher) Delete(ctx context.Context, submissionID string) error {
+	return errors.New(errors.ErrorTypeValidation, "the TikTok API cannot delete posts; delete it in the TikTok app")
+}
+
+func (p *tiktokPublisher) Capabilities() Capabilities {
+	return Capabilities{PostTypes: []string{"reel"}, MediaRequired: true, MaxTextLength: tiktokCaptionLength}
+}
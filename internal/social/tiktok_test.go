@@ -0,0 +1,340 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/oauth"
+)
+
+// fakeTikTok replays the responses recorded in testdata/tiktok. Status
+// fetches return the fixtures in statuses in turn, repeating the last one.
+type fakeTikTok struct {
+	t        *testing.T
+	url      string
+	statuses []string
+
+	inits    []map[string]any // init request bodies
+	ranges   []string         // Content-Range of each uploaded chunk
+	uploaded bytes.Buffer
+	listMax  []float64    // max_count of each list request
+	tokens   []url.Values // token requests
+}
+
+func (f *fakeTikTok) fixture(w http.ResponseWriter, status int, name string) {
+	data, err := os.ReadFile(filepath.Join("testdata", "tiktok", name+".json"))
+	if err != nil {
+		f.t.Fatalf("read fixture: %v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(bytes.ReplaceAll(data, []byte("{{server}}"), []byte(f.url)))
+}
+
+func (f *fakeTikTok) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut && r.URL.Path == "/upload/" {
+		f.ranges = append(f.ranges, r.Header.Get("Content-Range"))
+		io.Copy(&f.uploaded, r.Body)
+		if strings.HasSuffix(r.Header.Get("Content-Range"), "-9/10") {
+			w.WriteHeader(http.StatusCreated)
+		} else {
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		return
+	}
+
+	if r.URL.Path == "/oauth/token/" {
+		r.ParseForm()
+		f.tokens = append(f.tokens, r.PostForm)
+		if r.PostForm.Get("client_secret") != "secret" {
+			f.fixture(w, http.StatusUnauthorized, "token_error")
+		} else {
+			f.fixture(w, http.StatusOK, "token")
+		}
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer act.tiktok" {
+		f.fixture(w, http.StatusUnauthorized, "token_invalid")
+		return
+	}
+	var body map[string]any
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			f.t.Fatalf("decode body: %v", err)
+		}
+	}
+
+	switch r.URL.Path {
+	case "/post/publish/video/init/":
+		f.inits = append(f.inits, body)
+		source := body["source_info"].(map[string]any)["source"]
+		if source == "FILE_UPLOAD" {
+			f.fixture(w, http.StatusOK, "init_upload")
+		} else {
+			f.fixture(w, http.StatusOK, "init_pull")
+		}
+	case "/post/publish/status/fetch/":
+		name := f.statuses[0]
+		if len(f.statuses) > 1 {
+			f.statuses = f.statuses[1:]
+		}
+		f.fixture(w, http.StatusOK, name)
+	case "/video/list/":
+		f.listMax = append(f.listMax, body["max_count"].(float64))
+		if _, ok := body["cursor"]; ok {
+			f.fixture(w, http.StatusOK, "video_list_2")
+		} else {
+			f.fixture(w, http.StatusOK, "video_list_1")
+		}
+	case "/video/query/":
+		if !strings.Contains(r.URL.Query().Get("fields"), "share_url") {
+			f.t.Errorf("query fields = %q", r.URL.Query().Get("fields"))
+		}
+		f.fixture(w, http.StatusOK, "video_query")
+	case "/user/info/":
+		f.fixture(w, http.StatusOK, "user_info")
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	}
+}
+
+func newTestTikTok(t *testing.T, fake *fakeTikTok, token string) *TikTokClient {
+	t.Helper()
+	fake.t = t
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	fake.url = srv.URL
+	c := NewTikTokClient(&config.TikTokConfig{AccessToken: token, PrivacyLevel: "SELF_ONLY"})
+	c.baseURL = srv.URL
+	c.pollInterval = time.Millisecond
+	return c
+}
+
+func TestTikTokPublishVideo(t *testing.T) {
+	fake := &fakeTikTok{statuses: []string{"status_processing", "status_processing", "status_complete"}}
+	c := newTestTikTok(t, fake, "act.tiktok")
+
+	post, err := c.PublishVideo(context.Background(), "Three puzzles", "https://media.gagipress.com/reel.mp4", []string{"#kidsbooks"})
+	if err != nil {
+		t.Fatalf("PublishVideo() error = %v", err)
+	}
+	if post.ID != "v_pub_url~v2.7346282935727915050" || post.VideoID != "7346282935727915099" {
+		t.Errorf("PublishVideo() IDs = %q, %q", post.ID, post.VideoID)
+	}
+	if post.ShareURL != "https://www.tiktok.com/@gagipress/video/7346282935727915099" {
+		t.Errorf("ShareURL = %q", post.ShareURL)
+	}
+
+	postInfo := fake.inits[0]["post_info"].(map[string]any)
+	if postInfo["title"] != "Three puzzles\n\n#kidsbooks" || postInfo["privacy_level"] != "SELF_ONLY" {
+		t.Errorf("post_info = %v", postInfo)
+	}
+	if url := fake.inits[0]["source_info"].(map[string]any)["video_url"]; url != "https://media.gagipress.com/reel.mp4" {
+		t.Errorf("video_url = %v", url)
+	}
+}
+
+func TestTikTokPublishVideo_Failed(t *testing.T) {
+	fake := &fakeTikTok{statuses: []string{"status_processing", "status_failed"}}
+	c := newTestTikTok(t, fake, "act.tiktok")
+
+	_, err := c.PublishVideo(context.Background(), "Three puzzles", "https://media.gagipress.com/reel.mp4", nil)
+	if err == nil || !strings.Contains(err.Error(), "file_format_check_failed") {
+		t.Errorf("PublishVideo() error = %v, want the fail reason", err)
+	}
+}
+
+func TestTikTokPublishFile(t *testing.T) {
+	fake := &fakeTikTok{statuses: []string{"status_complete"}}
+	c := newTestTikTok(t, fake, "act.tiktok")
+
+	path := filepath.Join(t.TempDir(), "reel.mp4")
+	if err := os.WriteFile(path, []byte("0123456789"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.PublishFile(context.Background(), "Behind the cover", path, nil); err != nil {
+		t.Fatalf("PublishFile() error = %v", err)
+	}
+
+	source := fake.inits[0]["source_info"].(map[string]any)
+	if source["video_size"] != 10.0 || source["chunk_size"] != 10.0 || source["total_chunk_count"] != 1.0 {
+		t.Errorf("source_info = %v", source)
+	}
+	if fake.uploaded.String() != "0123456789" || len(fake.ranges) != 1 || fake.ranges[0] != "bytes 0-9/10" {
+		t.Errorf("uploaded %q in ranges %v", fake.uploaded.String(), fake.ranges)
+	}
+}
+
+func TestTikTokUpload_Chunks(t *testing.T) {
+	fake := &fakeTikTok{}
+	c := newTestTikTok(t, fake, "act.tiktok")
+
+	// The last chunk takes the remainder
+	upload := &TikTokUpload{UploadURL: fake.url + "/upload/", Size: 10, ChunkSize: 4, ChunkCount: 2}
+	if err := c.Upload(context.Background(), upload, strings.NewReader("0123456789")); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	want := []string{"bytes 0-3/10", "bytes 4-9/10"}
+	if strings.Join(fake.ranges, ",") != strings.Join(want, ",") || fake.uploaded.String() != "0123456789" {
+		t.Errorf("ranges = %v, uploaded %q", fake.ranges, fake.uploaded.String())
+	}
+}
+
+func TestPlanChunks(t *testing.T) {
+	tests := []struct {
+		size      int64
+		chunkSize int64
+		count     int
+	}{
+		{size: 1024, chunkSize: 1024, count: 1},
+		{size: maxSingleChunk, chunkSize: maxSingleChunk, count: 1},
+		{size: maxSingleChunk + 1, chunkSize: chunkSize, count: 6},
+		{size: 125 * chunkSize, chunkSize: chunkSize, count: 125},
+	}
+	for _, tt := range tests {
+		size, count := planChunks(tt.size)
+		if size != tt.chunkSize || count != tt.count {
+			t.Errorf("planChunks(%d) = %d, %d; want %d, %d", tt.size, size, count, tt.chunkSize, tt.count)
+		}
+		if last := tt.size - int64(count-1)*size; last < size || last >= 2*size {
+			t.Errorf("planChunks(%d) leaves a last chunk of %d", tt.size, last)
+		}
+	}
+}
+
+func TestTikTokGetRecentVideos(t *testing.T) {
+	fake := &fakeTikTok{}
+	c := newTestTikTok(t, fake, "act.tiktok")
+
+	videos, err := c.GetRecentVideos(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("GetRecentVideos() error = %v", err)
+	}
+	if len(videos) != 3 || videos[2].ID != "7346282935727915001" {
+		t.Fatalf("GetRecentVideos() = %+v, want both pages", videos)
+	}
+	first := videos[0]
+	if first.Caption != "Three puzzles for rainy days #kidsbooks" || first.ViewsCount != 4210 || first.SharesCount != 22 {
+		t.Errorf("first video = %+v", first)
+	}
+	if !first.CreatedAt.Equal(time.Unix(1777629600, 0)) {
+		t.Errorf("CreatedAt = %v", first.CreatedAt)
+	}
+	if videos[1].Caption != "Behind the cover" {
+		t.Errorf("caption falls back to the title, got %q", videos[1].Caption)
+	}
+
+	// A limit caps the page size and stops paging
+	fake.listMax = nil
+	videos, err = c.GetRecentVideos(context.Background(), 2)
+	if err != nil || len(videos) != 2 {
+		t.Fatalf("GetRecentVideos(2) = %d videos, %v", len(videos), err)
+	}
+	if len(fake.listMax) != 1 || fake.listMax[0] != 2 {
+		t.Errorf("max_count = %v, want one request for 2", fake.listMax)
+	}
+}
+
+func TestTikTokGetVideoMetrics(t *testing.T) {
+	c := newTestTikTok(t, &fakeTikTok{}, "act.tiktok")
+
+	metrics, err := c.GetVideoMetrics(context.Background(), "7346282935727915099")
+	if err != nil {
+		t.Fatalf("GetVideoMetrics() error = %v", err)
+	}
+	if metrics.VideoViews != 4210 || metrics.Likes != 301 || metrics.Comments != 17 || metrics.Shares != 22 {
+		t.Errorf("GetVideoMetrics() = %+v", metrics)
+	}
+	if want := float64(301+17+22) / 4210 * 100; metrics.EngagementRate != want {
+		t.Errorf("EngagementRate = %v, want %v", metrics.EngagementRate, want)
+	}
+}
+
+func TestTikTokError(t *testing.T) {
+	c := newTestTikTok(t, &fakeTikTok{}, "expired")
+
+	err := c.TestConnection(context.Background())
+	apiErr, ok := err.(*TikTokError)
+	if !ok {
+		t.Fatalf("TestConnection() error = %v, want *TikTokError", err)
+	}
+	if !apiErr.TokenExpired() || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("TikTokError = %+v", apiErr)
+	}
+
+	c = newTestTikTok(t, &fakeTikTok{}, "act.tiktok")
+	if err := c.TestConnection(context.Background()); err != nil {
+		t.Errorf("TestConnection() error = %v", err)
+	}
+}
+
+func TestTikTokExchangeCode(t *testing.T) {
+	fake := &fakeTikTok{}
+	c := newTestTikTok(t, fake, "")
+	c.cfg.ClientKey, c.cfg.ClientSecret = "aw-key", "secret"
+
+	grant := &oauth.Grant{Code: "code-1", Verifier: "verifier", RedirectURI: "http://localhost:8585/callback"}
+	if err := c.ExchangeCode(context.Background(), grant); err != nil {
+		t.Fatalf("ExchangeCode() error = %v", err)
+	}
+	form := fake.tokens[0]
+	if form.Get("grant_type") != "authorization_code" || form.Get("client_key") != "aw-key" || form.Get("code_verifier") != "verifier" {
+		t.Errorf("token request = %v", form)
+	}
+	if c.cfg.AccessToken != "act.tiktok" || c.cfg.RefreshToken != "rft.tiktok" || c.cfg.AccountID != "_000gagipress" {
+		t.Errorf("config = %+v", c.cfg)
+	}
+	if left := time.Until(c.cfg.TokenExpiresAt); left < 23*time.Hour || left > 24*time.Hour {
+		t.Errorf("TokenExpiresAt = %v, want in 24 hours", c.cfg.TokenExpiresAt)
+	}
+	if left := time.Until(c.cfg.RefreshExpiresAt); left < 364*24*time.Hour {
+		t.Errorf("RefreshExpiresAt = %v, want in a year", c.cfg.RefreshExpiresAt)
+	}
+
+	c.cfg.ClientSecret = "wrong"
+	err := c.ExchangeCode(context.Background(), grant)
+	var apiErr *TikTokError
+	if !errors.As(err, &apiErr) || apiErr.Code != "invalid_client" {
+		t.Errorf("ExchangeCode() error = %v, want invalid_client", err)
+	}
+}
+
+func TestTikTokRefreshesExpiringToken(t *testing.T) {
+	fake := &fakeTikTok{}
+	c := newTestTikTok(t, fake, "stale")
+	c.cfg.ClientKey, c.cfg.ClientSecret = "aw-key", "secret"
+	c.cfg.RefreshToken = "rft.old"
+	c.cfg.TokenExpiresAt = time.Now().Add(time.Minute)
+	saved := 0
+	c.OnTokenRefresh = func() error { saved++; return nil }
+
+	if err := c.TestConnection(context.Background()); err != nil {
+		t.Fatalf("TestConnection() error = %v", err)
+	}
+	if len(fake.tokens) != 1 || fake.tokens[0].Get("grant_type") != "refresh_token" || fake.tokens[0].Get("refresh_token") != "rft.old" {
+		t.Fatalf("token requests = %v, want one refresh", fake.tokens)
+	}
+	if c.cfg.AccessToken != "act.tiktok" || c.cfg.RefreshToken != "rft.tiktok" || saved != 1 {
+		t.Errorf("after refresh config = %+v, saved %d times", c.cfg, saved)
+	}
+
+	// An expired refresh token needs a new login
+	c.cfg.TokenExpiresAt = time.Now().Add(-time.Minute)
+	c.cfg.RefreshExpiresAt = time.Now().Add(-time.Minute)
+	if err := c.TestConnection(context.Background()); err == nil || !strings.Contains(err.Error(), "auth tiktok") {
+		t.Errorf("TestConnection() error = %v, want a login hint", err)
+	}
+}
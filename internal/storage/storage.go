@@ -0,0 +1,85 @@
+// Package storage opens the repositories for the configured backend.
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/repository/memory"
+	"github.com/gagipress/gagipress-cli/internal/repository/sqlite"
+)
+
+// Backend names accepted by storage.backend.
+const (
+	BackendSupabase = "supabase"
+	BackendJSON     = "json"
+	BackendMemory   = "memory"
+	BackendSQLite   = "sqlite"
+)
+
+// Default file locations used when storage.path is empty.
+const (
+	DefaultJSONPath   = "~/.gagipress/data.json"
+	DefaultSQLitePath = "~/.gagipress/gagipress.db"
+)
+
+// Open returns the repositories for cfg.Storage.Backend. Supabase is the
+// default so existing configs keep working unchanged.
+func Open(cfg *config.Config) (*repository.Stores, error) {
+	switch cfg.Storage.Backend {
+	case "", BackendSupabase:
+		return repository.NewSupabaseStores(&cfg.Supabase), nil
+
+	case BackendJSON:
+		path := cfg.Storage.Path
+		if path == "" {
+			path = DefaultJSONPath
+		}
+		path, err := expandHome(path)
+		if err != nil {
+			return nil, err
+		}
+		db, err := memory.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open json storage: %w", err)
+		}
+		return db.Stores(), nil
+
+	case BackendMemory:
+		return memory.New().Stores(), nil
+
+	case BackendSQLite:
+		path := cfg.Storage.Path
+		if path == "" {
+			path = DefaultSQLitePath
+		}
+		path, err := expandHome(path)
+		if err != nil {
+			return nil, err
+		}
+		db, err := sqlite.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open sqlite storage: %w", err)
+		}
+		return db.Stores(), nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (must be %s, %s, %s or %s)",
+			cfg.Storage.Backend, BackendSupabase, BackendSQLite, BackendJSON, BackendMemory)
+	}
+}
+
+// expandHome replaces a leading "~/" with the user's home directory.
+func expandHome(path string) (string, error) {
+	if len(path) < 2 || path[:2] != "~/" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to get home directory: %w", err)
+	}
+	return filepath.Join(home, path[2:]), nil
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	// Embedded so audience timezones load on systems without a zoneinfo
+	// database, such as Windows
+	_ "time/tzdata"
+
 	"github.com/gagipress/gagipress-cli/cmd"
 )
 
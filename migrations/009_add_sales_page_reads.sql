@@ -0,0 +1,9 @@
+-- Store KDP page reads (KENP) alongside units and royalty. The importers
+-- upsert one row per (book_id, date), so re-importing a report updates the
+-- existing rows in place instead of failing on UNIQUE(book_id, date).
+ALTER TABLE sales_data
+  ADD COLUMN IF NOT EXISTS page_reads INTEGER DEFAULT 0;
+
+-- Schema version bump
+INSERT INTO schema_version (version, description)
+VALUES (9, 'Add page_reads to sales_data');
@@ -0,0 +1,25 @@
+-- Migration 012: Add 'submitted' status to content_calendar
+-- Description: Blotato accepting a post only means it is queued; the platform
+--       can still reject it when it goes out. Accepted posts are now
+--       'submitted' until 'gagipress publish reconcile' confirms them as
+--       'published' (with their post_url) or marks them 'failed'.
+
+ALTER TABLE content_calendar DROP CONSTRAINT IF EXISTS content_calendar_status_check;
+ALTER TABLE content_calendar
+  ADD CONSTRAINT content_calendar_status_check
+  CHECK (status IN ('pending_approval', 'approved', 'publishing', 'submitted', 'published', 'failed'));
+
+-- Posts marked published on acceptance since 011 have not been confirmed
+UPDATE content_calendar
+SET status = 'submitted', published_at = NULL
+WHERE status = 'published'
+  AND submission_id IS NOT NULL
+  AND post_url IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_calendar_submitted
+  ON content_calendar(scheduled_for)
+  WHERE status = 'submitted';
+
+-- Schema version bump
+INSERT INTO schema_version (version, description)
+VALUES (12, 'Add submitted status to content_calendar');
@@ -0,0 +1,21 @@
+-- Migration 013: Pluggable publishers
+-- Description: Platforms come from the publisher registry in the CLI
+--       instead of a fixed list, so content_calendar.platform loses its
+--       CHECK constraint. Entries also remember which publisher (blotato,
+--       instagram, tiktok, ...) their submission_id belongs to, so its
+--       delivery is checked with the same one.
+
+ALTER TABLE content_calendar DROP CONSTRAINT IF EXISTS content_calendar_platform_check;
+
+ALTER TABLE content_calendar
+  ADD COLUMN IF NOT EXISTS publisher TEXT;
+
+-- Every submission so far went through Blotato
+UPDATE content_calendar
+SET publisher = 'blotato'
+WHERE submission_id IS NOT NULL
+  AND publisher IS NULL;
+
+-- Schema version bump
+INSERT INTO schema_version (version, description)
+VALUES (13, 'Drop platform check and add publisher to content_calendar');
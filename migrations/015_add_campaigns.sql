@@ -0,0 +1,33 @@
+-- Migration 015: Launch campaigns
+-- Description: A campaign is a phased posting plan around a book launch
+--       (teaser, launch day, review push). The ideas and calendar entries
+--       it creates carry its campaign_id so they can be reported on
+--       together. They outlive a deleted campaign (ON DELETE SET NULL).
+
+CREATE TABLE IF NOT EXISTS campaigns (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  launch_date DATE NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_book ON campaigns(book_id);
+
+ALTER TABLE campaigns ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Enable all access for authenticated users" ON campaigns
+    FOR ALL USING (true);
+
+ALTER TABLE content_ideas
+  ADD COLUMN IF NOT EXISTS campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL;
+
+ALTER TABLE content_calendar
+  ADD COLUMN IF NOT EXISTS campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ideas_campaign ON content_ideas(campaign_id);
+CREATE INDEX IF NOT EXISTS idx_calendar_campaign ON content_calendar(campaign_id);
+
+-- Schema version bump
+INSERT INTO schema_version (version, description)
+VALUES (15, 'Add campaigns and campaign_id to content_ideas and content_calendar');
@@ -0,0 +1,182 @@
+import "jsr:@supabase/functions-js/edge-runtime.d.ts";
+import { createClient } from "jsr:@supabase/supabase-js@2";
+
+const GRAPH_BASE_URL = "https://graph.facebook.com/v21.0";
+const BLUESKY_APPVIEW_URL = "https://public.api.bsky.app";
+const INSTAGRAM_SEARCH_LIMIT = 100; // mirrors instagramSearchLimit
+const DEFAULT_COLLECT_DAYS = 30;
+
+// ─── Types ────────────────────────────────────────────────────────────────────
+
+interface CalendarEntry {
+  id: string;
+  platform: string;
+  publisher: string | null;
+  submission_id: string | null;
+  post_url: string | null;
+}
+
+// Mirrors social.PostMetrics
+interface PostMetrics {
+  views: number;
+  likes: number;
+  comments: number;
+  shares: number;
+  saves: number;
+}
+
+// Reads the metrics of an entry, or returns null if it cannot
+type MetricsReader = (entry: CalendarEntry) => Promise<PostMetrics | null>;
+
+// ─── Readers (mirror the PostMetrics methods in internal/social) ──────────────
+
+async function graphGet(
+  token: string,
+  path: string,
+  params: Record<string, string>,
+  // deno-lint-ignore no-explicit-any
+): Promise<any> {
+  const query = new URLSearchParams({ ...params, access_token: token });
+  const res = await fetch(`${GRAPH_BASE_URL}/${path}?${query}`);
+  const data = await res.json();
+  if (!res.ok || data.error) {
+    throw new Error(`Instagram Graph API error: ${data.error?.message ?? res.statusText}`);
+  }
+  return data;
+}
+
+function instagramShortcode(permalink: string): string {
+  const segments = new URL(permalink).pathname.split("/").filter((s) => s !== "");
+  return segments.length >= 2 ? segments[segments.length - 1] : "";
+}
+
+function instagramReader(token: string, accountId: string): MetricsReader {
+  return async (entry) => {
+    let mediaId = entry.publisher === "instagram" ? entry.submission_id : null;
+    if (!mediaId) {
+      if (!entry.post_url || !accountId) return null;
+      const shortcode = instagramShortcode(entry.post_url);
+      const recent = await graphGet(token, `${accountId}/media`, {
+        fields: "id,permalink",
+        limit: String(INSTAGRAM_SEARCH_LIMIT),
+      });
+      // deno-lint-ignore no-explicit-any
+      const found = recent.data?.find((m: any) => instagramShortcode(m.permalink) === shortcode);
+      if (!found) throw new Error(`Instagram post not found: ${entry.post_url}`);
+      mediaId = found.id;
+    }
+
+    const media = await graphGet(token, mediaId!, { fields: "like_count,comments_count" });
+    const insights = await graphGet(token, `${mediaId}/insights`, {
+      metric: "plays,saved,shares",
+    });
+    const value = (name: string): number => {
+      // deno-lint-ignore no-explicit-any
+      const m = insights.data?.find((d: any) => d.name === name);
+      return m?.total_value?.value ?? m?.values?.[0]?.value ?? 0;
+    };
+    return {
+      views: value("plays"),
+      likes: media.like_count ?? 0,
+      comments: media.comments_count ?? 0,
+      shares: value("shares"),
+      saves: value("saved"),
+    };
+  };
+}
+
+// Bluesky counts are public, so any post can be read without a login
+const blueskyReader: MetricsReader = async (entry) => {
+  let uri = entry.publisher === "bluesky" ? entry.submission_id : null;
+  if (!uri) {
+    const match = entry.post_url?.match(/\/profile\/([^/]+)\/post\/([^/?#]+)/);
+    if (!match) return null;
+    uri = `at://${match[1]}/app.bsky.feed.post/${match[2]}`;
+  }
+  const res = await fetch(
+    `${BLUESKY_APPVIEW_URL}/xrpc/app.bsky.feed.getPosts?uris=${encodeURIComponent(uri)}`,
+  );
+  if (!res.ok) throw new Error(`Bluesky getPosts error: ${await res.text()}`);
+  const post = (await res.json()).posts?.[0];
+  if (!post) throw new Error(`Bluesky post not found: ${uri}`);
+  return {
+    views: 0,
+    likes: post.likeCount ?? 0,
+    comments: post.replyCount ?? 0,
+    shares: (post.repostCount ?? 0) + (post.quoteCount ?? 0),
+    saves: 0,
+  };
+};
+
+// Mirrors PostMetricInput.CalculateEngagementRate, rounded for DECIMAL(5,2)
+function engagementRate(m: PostMetrics): number {
+  if (m.views === 0) return 0;
+  const rate = ((m.likes + m.comments + m.shares + m.saves) / m.views) * 100;
+  return Math.round(rate * 100) / 100;
+}
+
+// ─── Main handler ─────────────────────────────────────────────────────────────
+
+Deno.serve(async (_req) => {
+  const supabaseUrl = Deno.env.get("SUPABASE_URL")!;
+  const serviceRoleKey = Deno.env.get("SUPABASE_SERVICE_ROLE_KEY")!;
+  const instagramToken = Deno.env.get("INSTAGRAM_ACCESS_TOKEN") ?? "";
+  const instagramAccountId = Deno.env.get("INSTAGRAM_ACCOUNT_ID") ?? "";
+  const collectDays = Number(Deno.env.get("COLLECT_DAYS") ?? DEFAULT_COLLECT_DAYS);
+
+  // TikTok access tokens last a day and are refreshed by the CLI, so TikTok
+  // posts are left to `gagipress stats collect`.
+  const readers: Record<string, MetricsReader> = { bluesky: blueskyReader };
+  if (instagramToken) readers.instagram = instagramReader(instagramToken, instagramAccountId);
+
+  const supabase = createClient(supabaseUrl, serviceRoleKey);
+
+  const since = new Date(Date.now() - collectDays * 24 * 60 * 60 * 1000).toISOString();
+  const { data: entries, error: queryError } = await supabase
+    .from("content_calendar")
+    .select("id, platform, publisher, submission_id, post_url")
+    .eq("status", "published")
+    .gte("published_at", since)
+    .in("platform", Object.keys(readers))
+    .returns<CalendarEntry[]>();
+
+  if (queryError) {
+    return new Response(
+      JSON.stringify({ error: `Query failed: ${queryError.message}` }),
+      { status: 500, headers: { "Content-Type": "application/json" } },
+    );
+  }
+
+  let collected = 0;
+  let skipped = 0;
+  let failed = 0;
+
+  for (const entry of entries ?? []) {
+    try {
+      const metrics = await readers[entry.platform](entry);
+      if (!metrics) {
+        skipped++;
+        continue;
+      }
+
+      const { error: insertError } = await supabase.from("post_metrics").insert({
+        calendar_id: entry.id,
+        platform: entry.platform,
+        ...metrics,
+        engagement_rate: engagementRate(metrics),
+      });
+      if (insertError) throw new Error(insertError.message);
+      collected++;
+    } catch (err) {
+      const msg = err instanceof Error ? err.message : String(err);
+      console.error(`Failed entry ${entry.id}:`, msg);
+      failed++;
+    }
+  }
+
+  const result = { processed: entries?.length ?? 0, collected, skipped, failed };
+  console.log("Run complete:", result);
+  return new Response(JSON.stringify(result), {
+    headers: { "Content-Type": "application/json" },
+  });
+});
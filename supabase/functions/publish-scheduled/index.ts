@@ -3,6 +3,11 @@ import { createClient } from "jsr:@supabase/supabase-js@2";
 
 const BLOTATO_BASE_URL = "https://backend.blotato.com/v2";
 const MEDIA_TIMEOUT_MS = 2 * 60 * 1000; // 2 minutes
+const MARK_ATTEMPTS = 3;
+
+// Platforms the blotato publisher is registered for (internal/social/blotato.go),
+// without bluesky, which has a native publisher
+const BLOTATO_DEFAULT_PLATFORMS = ["instagram", "tiktok", "youtube", "facebook", "pinterest", "threads"];
 
 // ─── Types ────────────────────────────────────────────────────────────────────
 
@@ -13,6 +18,15 @@ interface CalendarEntry {
   scheduled_for: string;
   generate_media: boolean;
   media_url: string | null;
+  publish_errors: PublishError[] | null;
+}
+
+// Mirrors models.PublishError
+interface PublishError {
+  at: string;
+  stage: "submit" | "delivery" | "media";
+  message: string;
+  submission_id?: string;
 }
 
 interface ContentScript {
@@ -107,6 +121,27 @@ async function publishPost(
   return data.postSubmissionId;
 }
 
+// ─── Status writes ────────────────────────────────────────────────────────────
+
+type Supabase = ReturnType<typeof createClient>;
+
+// markEntry writes the outcome of a locked entry, retrying a few times.
+// It returns the last error, or null once the write succeeds.
+async function markEntry(
+  supabase: Supabase,
+  id: string,
+  update: Record<string, unknown>,
+): Promise<string | null> {
+  let lastError = "";
+  for (let attempt = 1; attempt <= MARK_ATTEMPTS; attempt++) {
+    const { error } = await supabase.from("content_calendar").update(update).eq("id", id);
+    if (!error) return null;
+    lastError = error.message;
+    if (attempt < MARK_ATTEMPTS) await new Promise((r) => setTimeout(r, attempt * 1000));
+  }
+  return lastError;
+}
+
 // ─── Main handler ─────────────────────────────────────────────────────────────
 
 Deno.serve(async (_req) => {
@@ -114,6 +149,16 @@ Deno.serve(async (_req) => {
   const serviceRoleKey = Deno.env.get("SUPABASE_SERVICE_ROLE_KEY")!;
   const blotatoApiKey = Deno.env.get("BLOTATO_API_KEY") ?? "";
   const blotatoTemplateId = Deno.env.get("BLOTATO_TEMPLATE_ID") ?? "";
+  // Platforms this function posts through Blotato, comma separated. Leave
+  // platforms with a native publisher out and run `gagipress publish batch`
+  // for them instead. Unset means BLOTATO_DEFAULT_PLATFORMS.
+  const blotatoPlatformsEnv = (Deno.env.get("BLOTATO_PLATFORMS") ?? "")
+    .split(",")
+    .map((p) => p.trim())
+    .filter((p) => p !== "");
+  const blotatoPlatforms = blotatoPlatformsEnv.length > 0
+    ? blotatoPlatformsEnv
+    : BLOTATO_DEFAULT_PLATFORMS;
 
   if (!blotatoApiKey) {
     return new Response(
@@ -125,17 +170,34 @@ Deno.serve(async (_req) => {
   const supabase = createClient(supabaseUrl, serviceRoleKey);
 
   // ── Step 1: Rollback stale locks (entries stuck in 'publishing' > 10 min) ──
+  // Entries with a submission are already at Blotato, so they go on to
+  // 'submitted' for `gagipress publish reconcile` instead of being resent.
+  const staleBefore = new Date(Date.now() - 10 * 60 * 1000).toISOString();
   const { error: rollbackError } = await supabase
     .from("content_calendar")
     .update({ status: "approved" })
     .eq("status", "publishing")
-    .lt("updated_at", new Date(Date.now() - 10 * 60 * 1000).toISOString());
+    .is("submission_id", null)
+    .lt("updated_at", staleBefore);
 
   if (rollbackError) {
     console.error("Rollback stale locks error:", rollbackError.message);
   }
 
+  const { error: staleSubmittedError } = await supabase
+    .from("content_calendar")
+    .update({ status: "submitted" })
+    .eq("status", "publishing")
+    .not("submission_id", "is", null)
+    .lt("updated_at", staleBefore);
+
+  if (staleSubmittedError) {
+    console.error("Release stale submitted locks error:", staleSubmittedError.message);
+  }
+
   // ── Step 2: Atomic lock — grab entries that are due now ───────────────────
+  // Entries with a submission are already queued at Blotato and never resent,
+  // and entries assigned to another publisher are left to the CLI.
   const now = new Date().toISOString();
   const { data: entries, error: lockError } = await supabase
     .from("content_calendar")
@@ -143,7 +205,10 @@ Deno.serve(async (_req) => {
     .eq("status", "approved")
     .lte("scheduled_for", now)
     .is("published_at", null)
-    .select("id, script_id, platform, scheduled_for, generate_media, media_url")
+    .is("submission_id", null)
+    .in("platform", blotatoPlatforms)
+    .or("publisher.is.null,publisher.eq.blotato")
+    .select("id, script_id, platform, scheduled_for, generate_media, media_url, publish_errors")
     .returns<CalendarEntry[]>();
 
   if (lockError) {
@@ -155,7 +220,7 @@ Deno.serve(async (_req) => {
 
   if (!entries || entries.length === 0) {
     return new Response(
-      JSON.stringify({ processed: 0, published: 0, failed: 0 }),
+      JSON.stringify({ processed: 0, submitted: 0, failed: 0 }),
       { headers: { "Content-Type": "application/json" } },
     );
   }
@@ -163,7 +228,7 @@ Deno.serve(async (_req) => {
   console.log(`Processing ${entries.length} entries`);
 
   // ── Step 3: Publish each locked entry ────────────────────────────────────
-  let published = 0;
+  let submitted = 0;
   let failed = 0;
   const accountIdCache: Record<string, string> = {};
 
@@ -221,30 +286,61 @@ Deno.serve(async (_req) => {
       const scheduledTime = entry.scheduled_for
         ? new Date(entry.scheduled_for).toISOString()
         : null;
-      await publishPost(blotatoApiKey, accountId, entry.platform, postText, mediaUrls, scheduledTime);
-
-      // Mark published
-      await supabase
-        .from("content_calendar")
-        .update({ status: "published", published_at: new Date().toISOString() })
-        .eq("id", entry.id);
+      const submissionId = await publishPost(
+        blotatoApiKey,
+        accountId,
+        entry.platform,
+        postText,
+        mediaUrls,
+        scheduledTime,
+      );
+
+      // Mark submitted, keeping the submission so it is never sent twice.
+      // `gagipress publish reconcile` confirms delivery and sets published.
+      let markError = await markEntry(supabase, entry.id, {
+        status: "submitted",
+        submission_id: submissionId,
+        publisher: "blotato",
+      });
+      if (markError) {
+        // The publisher defaults to blotato, so the submission alone is
+        // enough for reconcile
+        markError = await markEntry(supabase, entry.id, { status: "submitted", submission_id: submissionId });
+      }
+      if (markError) {
+        // Not a failed publish: the post is queued at Blotato, so log the
+        // submission for manual follow-up instead of marking the entry failed.
+        console.error(`Entry ${entry.id} submitted as ${submissionId} but not marked:`, markError);
+      }
 
-      published++;
-      console.log(`Published entry ${entry.id}`);
+      submitted++;
+      console.log(`Submitted entry ${entry.id} as ${submissionId}`);
     } catch (err) {
       const msg = err instanceof Error ? err.message : String(err);
       console.error(`Failed entry ${entry.id}:`, msg);
 
-      await supabase
-        .from("content_calendar")
-        .update({ status: "failed", publish_errors: msg })
-        .eq("id", entry.id);
+      const publishError: PublishError = {
+        at: new Date().toISOString(),
+        stage: "submit",
+        message: msg,
+      };
+      let markError = await markEntry(supabase, entry.id, {
+        status: "failed",
+        publish_errors: [...(entry.publish_errors ?? []), publishError],
+      });
+      if (markError) {
+        // Nothing reached Blotato, so release the lock for the next run
+        markError = await markEntry(supabase, entry.id, { status: "approved" });
+      }
+      if (markError) {
+        console.error(`Entry ${entry.id} failed but not marked:`, markError);
+      }
 
       failed++;
     }
   }
 
-  const result = { processed: entries.length, published, failed };
+  const result = { processed: entries.length, submitted, failed };
   console.log("Run complete:", result);
   return new Response(JSON.stringify(result), {
     headers: { "Content-Type": "application/json" },
@@ -0,0 +1,32 @@
+-- Migration 010: AI usage ledger
+-- One row per AI call made by a generate command, with the token counts the
+-- provider reported and the cost estimated from the configured price table.
+-- Links are kept when ideas, scripts or books are deleted so spend history
+-- stays complete.
+
+CREATE TABLE IF NOT EXISTS ai_usage (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  provider TEXT NOT NULL,
+  model TEXT NOT NULL,
+  prompt_tokens INTEGER NOT NULL DEFAULT 0 CHECK (prompt_tokens >= 0),
+  completion_tokens INTEGER NOT NULL DEFAULT 0 CHECK (completion_tokens >= 0),
+  cost_usd DECIMAL(12,6) NOT NULL DEFAULT 0,
+  command TEXT NOT NULL,
+  book_id UUID REFERENCES books(id) ON DELETE SET NULL,
+  idea_id UUID REFERENCES content_ideas(id) ON DELETE SET NULL,
+  script_id UUID REFERENCES content_scripts(id) ON DELETE SET NULL,
+  content_type TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_usage_created ON ai_usage(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ai_usage_book ON ai_usage(book_id);
+
+ALTER TABLE ai_usage ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Enable all access for authenticated users" ON ai_usage
+    FOR ALL USING (true);
+
+-- Schema version bump
+INSERT INTO schema_version (version, description)
+VALUES (10, 'Add ai_usage ledger');
@@ -0,0 +1,22 @@
+-- Keep the Blotato postSubmissionId of every calendar entry that was
+-- submitted, so an entry is never submitted twice and its delivery can be
+-- checked later. post_url and publish_errors already exist; publish_errors
+-- becomes a JSON list of {at, stage, message, submission_id} objects.
+ALTER TABLE content_calendar
+  ADD COLUMN IF NOT EXISTS submission_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_submission
+  ON content_calendar(submission_id)
+  WHERE submission_id IS NOT NULL;
+
+-- publish-scheduled used to store a bare error message; wrap it in the list
+UPDATE content_calendar
+SET publish_errors = jsonb_build_array(jsonb_build_object(
+  'at', updated_at,
+  'stage', 'submit',
+  'message', publish_errors #>> '{}'))
+WHERE jsonb_typeof(publish_errors) = 'string';
+
+-- Schema version bump
+INSERT INTO schema_version (version, description)
+VALUES (11, 'Add submission_id to content_calendar');
@@ -0,0 +1,24 @@
+-- Migration 014: Schedule collect-metrics edge function
+-- Description: Snapshot the metrics of published posts into post_metrics
+--       once a day, after the morning posts are out. The function reads
+--       Instagram (INSTAGRAM_ACCESS_TOKEN and INSTAGRAM_ACCOUNT_ID secrets)
+--       and Bluesky; run `gagipress stats collect` for the other platforms.
+-- NOTE: needs pg_cron and pg_net, enabled by migration 005
+
+-- This migration is idempotent (safe to re-run)
+
+SELECT cron.schedule(
+    'gagipress-collect-metrics',
+    '30 5 * * *',
+    $$
+    SELECT net.http_post(
+        url     := 'https://nhsthucdmjgodfrduzfb.supabase.co/functions/v1/collect-metrics',
+        headers := '{"Content-Type": "application/json"}'::jsonb,
+        body    := '{}'::jsonb
+    );
+    $$
+);
+
+-- Schema version bump
+INSERT INTO schema_version (version, description)
+VALUES (14, 'Schedule collect-metrics edge function via pg_cron daily');
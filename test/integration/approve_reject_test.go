@@ -9,8 +9,6 @@ import (
 )
 
 func TestIdeasApprove_ResolvesByPrefix(t *testing.T) {
-	SkipIfNoSupabase(t)
-
 	fixture := NewTestFixture(t)
 
 	// Arrange: Create a pending idea
@@ -21,7 +19,7 @@ func TestIdeasApprove_ResolvesByPrefix(t *testing.T) {
 
 	// Act: Resolve by 8-character prefix
 	prefix := idea.ID[:8]
-	resolved, err := fixture.contentRepo.GetIdeaByIDPrefix(prefix)
+	resolved, err := fixture.contentRepo.GetIdeaByIDPrefix(t.Context(), prefix)
 
 	// Assert: Should find the idea
 	testutil.AssertNoError(t, err)
@@ -29,8 +27,6 @@ func TestIdeasApprove_ResolvesByPrefix(t *testing.T) {
 }
 
 func TestIdeasApprove_UpdatesStatusToApproved(t *testing.T) {
-	SkipIfNoSupabase(t)
-
 	fixture := NewTestFixture(t)
 
 	// Arrange
@@ -40,18 +36,16 @@ func TestIdeasApprove_UpdatesStatusToApproved(t *testing.T) {
 	})
 
 	// Act: Approve the idea
-	err := fixture.contentRepo.UpdateIdeaStatus(idea.ID, "approved")
+	err := fixture.contentRepo.UpdateIdeaStatus(t.Context(), idea.ID, "approved")
 	testutil.AssertNoError(t, err)
 
 	// Assert: Verify status changed by re-fetching
-	updated, err := fixture.contentRepo.GetIdeaByIDPrefix(idea.ID)
+	updated, err := fixture.contentRepo.GetIdeaByIDPrefix(t.Context(), idea.ID)
 	testutil.AssertNoError(t, err)
 	testutil.AssertEqual(t, "approved", updated.Status)
 }
 
 func TestCalendarApprove_UpdatesStatus(t *testing.T) {
-	SkipIfNoSupabase(t)
-
 	fixture := NewTestFixture(t)
 
 	// Arrange: Create calendar entry
@@ -63,11 +57,11 @@ func TestCalendarApprove_UpdatesStatus(t *testing.T) {
 	})
 
 	// Act: Approve the entry
-	err := fixture.calendarRepo.UpdateEntryStatus(entry.ID, "approved")
+	err := fixture.calendarRepo.UpdateEntryStatus(t.Context(), entry.ID, "approved")
 	testutil.AssertNoError(t, err)
 
 	// Assert: Verify status
-	entries, err := fixture.calendarRepo.GetEntries("approved", 10)
+	entries, err := fixture.calendarRepo.GetEntries(t.Context(), "approved", 10)
 	testutil.AssertNoError(t, err)
 
 	found := false
@@ -81,16 +75,14 @@ func TestCalendarApprove_UpdatesStatus(t *testing.T) {
 }
 
 func TestIdeasApprove_PrefixTooShort(t *testing.T) {
-	SkipIfNoSupabase(t)
 	fixture := NewTestFixture(t)
 
-	_, err := fixture.contentRepo.GetIdeaByIDPrefix("abc")
+	_, err := fixture.contentRepo.GetIdeaByIDPrefix(t.Context(), "abc")
 	testutil.AssertError(t, err)
 	// Error message should mention minimum length
 }
 
 func TestIdeasReject_UpdatesStatusToRejected(t *testing.T) {
-	SkipIfNoSupabase(t)
 	fixture := NewTestFixture(t)
 
 	idea := fixture.CreateIdea(&models.ContentIdeaInput{
@@ -98,16 +90,15 @@ func TestIdeasReject_UpdatesStatusToRejected(t *testing.T) {
 		BriefDescription: "Test rejection",
 	})
 
-	err := fixture.contentRepo.UpdateIdeaStatus(idea.ID, "rejected")
+	err := fixture.contentRepo.UpdateIdeaStatus(t.Context(), idea.ID, "rejected")
 	testutil.AssertNoError(t, err)
 
-	updated, err := fixture.contentRepo.GetIdeaByIDPrefix(idea.ID[:8])
+	updated, err := fixture.contentRepo.GetIdeaByIDPrefix(t.Context(), idea.ID[:8])
 	testutil.AssertNoError(t, err)
 	testutil.AssertEqual(t, "rejected", updated.Status)
 }
 
 func TestCalendarReject_DeletesEntry(t *testing.T) {
-	SkipIfNoSupabase(t)
 	fixture := NewTestFixture(t)
 
 	entry := fixture.CreateCalendarEntry(&models.ContentCalendarInput{
@@ -116,11 +107,11 @@ func TestCalendarReject_DeletesEntry(t *testing.T) {
 		PostType:     "reel",
 	})
 
-	err := fixture.calendarRepo.DeleteEntry(entry.ID)
+	err := fixture.calendarRepo.DeleteEntry(t.Context(), entry.ID)
 	testutil.AssertNoError(t, err)
 
 	// Verify deleted
-	entries, _ := fixture.calendarRepo.GetEntries("pending_approval", 100)
+	entries, _ := fixture.calendarRepo.GetEntries(t.Context(), "pending_approval", 100)
 	for _, e := range entries {
 		if e.ID == entry.ID {
 			t.Fatal("Entry should have been deleted")
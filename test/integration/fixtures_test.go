@@ -1,9 +1,9 @@
 package integration
 
 import (
+	"context"
 	"testing"
 
-	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
 	"github.com/gagipress/gagipress-cli/internal/repository"
 )
@@ -11,9 +11,9 @@ import (
 // TestFixture provides test data creation and automatic cleanup
 type TestFixture struct {
 	t               *testing.T
-	contentRepo     *repository.ContentRepository
-	calendarRepo    *repository.CalendarRepository
-	booksRepo       *repository.BooksRepository
+	contentRepo     repository.ContentStore
+	calendarRepo    repository.CalendarStore
+	booksRepo       repository.BookStore
 	createdIdeaIDs  []string
 	createdEntryIDs []string
 	createdBookIDs  []string
@@ -21,19 +21,13 @@ type TestFixture struct {
 
 // NewTestFixture creates a fixture with automatic cleanup on test completion
 func NewTestFixture(t *testing.T) *TestFixture {
-	cfg := &config.Config{
-		Supabase: config.SupabaseConfig{
-			URL:        GetTestSupabaseURL(),
-			AnonKey:    GetTestSupabaseKey(),
-			ServiceKey: GetTestSupabaseServiceKey(), // Prefer service key for tests
-		},
-	}
+	stores := OpenTestStores(t)
 
 	fixture := &TestFixture{
 		t:            t,
-		contentRepo:  repository.NewContentRepository(&cfg.Supabase),
-		calendarRepo: repository.NewCalendarRepository(&cfg.Supabase),
-		booksRepo:    repository.NewBooksRepository(&cfg.Supabase),
+		contentRepo:  stores.Content,
+		calendarRepo: stores.Calendar,
+		booksRepo:    stores.Books,
 	}
 
 	// Register cleanup function
@@ -44,9 +38,20 @@ func NewTestFixture(t *testing.T) *TestFixture {
 	return fixture
 }
 
+// CreateBook creates a test book and tracks it for cleanup. Deleting the
+// book cascades to its ideas, scripts and calendar entries.
+func (f *TestFixture) CreateBook(input *models.BookInput) *models.Book {
+	book, err := f.booksRepo.Create(f.t.Context(), input)
+	if err != nil {
+		f.t.Fatalf("failed to create test book: %v", err)
+	}
+	f.createdBookIDs = append(f.createdBookIDs, book.ID)
+	return book
+}
+
 // CreateIdea creates a test idea and tracks it for cleanup
 func (f *TestFixture) CreateIdea(input *models.ContentIdeaInput) *models.ContentIdea {
-	idea, err := f.contentRepo.CreateIdea(input)
+	idea, err := f.contentRepo.CreateIdea(f.t.Context(), input)
 	if err != nil {
 		f.t.Fatalf("failed to create test idea: %v", err)
 	}
@@ -56,7 +61,7 @@ func (f *TestFixture) CreateIdea(input *models.ContentIdeaInput) *models.Content
 
 // CreateCalendarEntry creates a test calendar entry and tracks it for cleanup
 func (f *TestFixture) CreateCalendarEntry(input *models.ContentCalendarInput) *models.ContentCalendar {
-	entry, err := f.calendarRepo.CreateEntry(input)
+	entry, err := f.calendarRepo.CreateEntry(f.t.Context(), input)
 	if err != nil {
 		f.t.Fatalf("failed to create test calendar entry: %v", err)
 	}
@@ -64,11 +69,19 @@ func (f *TestFixture) CreateCalendarEntry(input *models.ContentCalendarInput) *m
 	return entry
 }
 
-// Cleanup deletes all test data created by this fixture
+// Cleanup deletes all test data created by this fixture.
+// It runs after the test's context is cancelled, so it uses its own.
 func (f *TestFixture) Cleanup() {
+	ctx := context.Background()
+
 	// Clean up calendar entries
 	for _, id := range f.createdEntryIDs {
-		_ = f.calendarRepo.DeleteEntry(id) // Ignore errors during cleanup
+		_ = f.calendarRepo.DeleteEntry(ctx, id) // Ignore errors during cleanup
+	}
+
+	// Clean up books (cascades to their content)
+	for _, id := range f.createdBookIDs {
+		_ = f.booksRepo.Delete(ctx, id)
 	}
 
 	// Clean up ideas (no delete method exists, so leave for manual cleanup)
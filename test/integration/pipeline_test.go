@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gagipress/gagipress-cli/internal/models"
+	"github.com/gagipress/gagipress-cli/internal/scheduler"
+	"github.com/gagipress/gagipress-cli/internal/testutil"
+)
+
+// TestPipeline_IdeaToPublished walks one piece of content through the whole
+// idea → script → calendar → publish lifecycle.
+func TestPipeline_IdeaToPublished(t *testing.T) {
+	fixture := NewTestFixture(t)
+	ctx := t.Context()
+
+	book := fixture.CreateBook(&models.BookInput{
+		Title: "Pipeline Test Book",
+		Genre: "children",
+	})
+
+	// Idea: created pending, then approved
+	idea := fixture.CreateIdea(&models.ContentIdeaInput{
+		Type:             "educational",
+		BriefDescription: "Pipeline idea",
+		BookID:           &book.ID,
+	})
+	testutil.AssertEqual(t, "pending", idea.Status)
+	testutil.AssertNoError(t, fixture.contentRepo.UpdateIdeaStatus(ctx, idea.ID, "approved"))
+
+	approved, err := fixture.contentRepo.GetIdeasByBook(ctx, book.ID, "approved", 0)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(approved))
+
+	// Script: saved for the idea, idea marked scripted
+	script, err := fixture.contentRepo.CreateScript(ctx, &models.ContentScriptInput{
+		IdeaID:            idea.ID,
+		Hook:              "Did you know?",
+		FullScript:        "A short script.",
+		CTA:               "Link in bio",
+		Hashtags:          []string{"#booktok"},
+		EstimatedDuration: 30,
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertNoError(t, fixture.contentRepo.UpdateIdeaStatus(ctx, idea.ID, "scripted"))
+
+	// Calendar: planner schedules the script, entry starts pending approval
+	plan, err := scheduler.NewPlanner(fixture.contentRepo).PlanWeek(ctx, 1, 1)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(plan))
+	testutil.AssertEqual(t, script.ID, *plan[0].ScriptID)
+
+	entry := fixture.CreateCalendarEntry(plan[0])
+	testutil.AssertEqual(t, "pending_approval", entry.Status)
+
+	// Publish: approved → publishing → published
+	for _, status := range []string{"approved", "publishing", "published"} {
+		testutil.AssertNoError(t, fixture.calendarRepo.UpdateEntryStatus(ctx, entry.ID, status))
+	}
+
+	published, err := fixture.calendarRepo.GetEntryByID(ctx, entry.ID)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "published", published.Status)
+}
+
+func TestStatusRules_RejectUnknownStatuses(t *testing.T) {
+	fixture := NewTestFixture(t)
+	ctx := t.Context()
+
+	idea := fixture.CreateIdea(&models.ContentIdeaInput{
+		Type:             "trend",
+		BriefDescription: "Status rules",
+	})
+	testutil.AssertError(t, fixture.contentRepo.UpdateIdeaStatus(ctx, idea.ID, "published"))
+
+	entry := fixture.CreateCalendarEntry(&models.ContentCalendarInput{
+		ScheduledFor: time.Now().Add(24 * time.Hour),
+		Platform:     "tiktok",
+		PostType:     "reel",
+	})
+	testutil.AssertError(t, fixture.calendarRepo.UpdateEntryStatus(ctx, entry.ID, "scheduled"))
+}
+
+func TestCalendarRetry_ResetsFailedEntries(t *testing.T) {
+	fixture := NewTestFixture(t)
+	ctx := t.Context()
+
+	entry := fixture.CreateCalendarEntry(&models.ContentCalendarInput{
+		ScheduledFor: time.Now().Add(24 * time.Hour),
+		Platform:     "instagram",
+		PostType:     "reel",
+	})
+	testutil.AssertNoError(t, fixture.calendarRepo.UpdateEntryStatus(ctx, entry.ID, "failed"))
+
+	count, err := fixture.calendarRepo.RetryFailed(ctx)
+	testutil.AssertNoError(t, err)
+	if count < 1 {
+		t.Fatalf("expected at least 1 entry reset, got %d", count)
+	}
+
+	updated, err := fixture.calendarRepo.GetEntryByID(ctx, entry.ID)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "approved", updated.Status)
+}
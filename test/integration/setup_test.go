@@ -2,9 +2,39 @@ package integration
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/gagipress/gagipress-cli/internal/config"
+	"github.com/gagipress/gagipress-cli/internal/repository"
+	"github.com/gagipress/gagipress-cli/internal/repository/memory"
+	"github.com/gagipress/gagipress-cli/internal/repository/sqlite"
+	_ "github.com/gagipress/gagipress-cli/internal/social" // registers the platforms
 )
 
+// OpenTestStores returns Supabase repositories when SUPABASE_URL is set and
+// a fresh local backend otherwise, so the suite always runs in CI. The local
+// backend is in-memory unless GAGIPRESS_TEST_BACKEND=sqlite.
+func OpenTestStores(t *testing.T) *repository.Stores {
+	t.Helper()
+	if GetTestSupabaseURL() == "" {
+		if os.Getenv("GAGIPRESS_TEST_BACKEND") == "sqlite" {
+			db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatalf("failed to open sqlite backend: %v", err)
+			}
+			t.Cleanup(func() { db.Close() })
+			return db.Stores()
+		}
+		return memory.New().Stores()
+	}
+	return repository.NewSupabaseStores(&config.SupabaseConfig{
+		URL:        GetTestSupabaseURL(),
+		AnonKey:    GetTestSupabaseKey(),
+		ServiceKey: GetTestSupabaseServiceKey(), // Prefer service key for tests
+	})
+}
+
 // SkipIfNoSupabase skips test if Supabase credentials not available
 func SkipIfNoSupabase(t *testing.T) {
 	if os.Getenv("SUPABASE_URL") == "" {
@@ -3,24 +3,12 @@ package integration
 import (
 	"testing"
 
-	"github.com/gagipress/gagipress-cli/internal/config"
 	"github.com/gagipress/gagipress-cli/internal/models"
-	"github.com/gagipress/gagipress-cli/internal/repository"
 )
 
 func TestBookWorkflow_CreateAndRetrieve(t *testing.T) {
-	SkipIfNoSupabase(t)
-
-	// Create config
-	cfg := &config.Config{
-		Supabase: config.SupabaseConfig{
-			URL:     GetTestSupabaseURL(),
-			AnonKey: GetTestSupabaseKey(),
-		},
-	}
-
 	// Create repository
-	repo := repository.NewBooksRepository(&cfg.Supabase)
+	repo := OpenTestStores(t).Books
 
 	// Create test book
 	bookInput := &models.BookInput{
@@ -31,7 +19,7 @@ func TestBookWorkflow_CreateAndRetrieve(t *testing.T) {
 	}
 
 	// Test Create
-	created, err := repo.Create(bookInput)
+	created, err := repo.Create(t.Context(), bookInput)
 	if err != nil {
 		t.Fatalf("Failed to create book: %v", err)
 	}
@@ -45,7 +33,7 @@ func TestBookWorkflow_CreateAndRetrieve(t *testing.T) {
 	}
 
 	// Test GetByID
-	retrieved, err := repo.GetByID(created.ID)
+	retrieved, err := repo.GetByID(t.Context(), created.ID)
 	if err != nil {
 		t.Fatalf("Failed to retrieve book: %v", err)
 	}
@@ -63,16 +51,7 @@ func TestBookWorkflow_CreateAndRetrieve(t *testing.T) {
 }
 
 func TestContentIdeaWorkflow_CreateAndValidate(t *testing.T) {
-	SkipIfNoSupabase(t)
-
-	cfg := &config.Config{
-		Supabase: config.SupabaseConfig{
-			URL:     GetTestSupabaseURL(),
-			AnonKey: GetTestSupabaseKey(),
-		},
-	}
-
-	repo := repository.NewContentRepository(&cfg.Supabase)
+	repo := OpenTestStores(t).Content
 
 	// Create test idea
 	score := 85
@@ -89,7 +68,7 @@ func TestContentIdeaWorkflow_CreateAndValidate(t *testing.T) {
 	}
 
 	// Test Create
-	created, err := repo.CreateIdea(ideaInput)
+	created, err := repo.CreateIdea(t.Context(), ideaInput)
 	if err != nil {
 		t.Fatalf("Failed to create idea: %v", err)
 	}